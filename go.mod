@@ -1,3 +1,5 @@
 module github.com/cristianjonhson/GO-API
 
 go 1.25.5
+
+require github.com/google/uuid v1.6.0
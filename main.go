@@ -3,34 +3,106 @@ package main
 
 // Importamos las librerías necesarias
 import (
+	"bytes"         // Para generar los archivos exportados
+	"context"       // Para pasar el usuario autenticado a los handlers
+	"crypto/subtle" // Para comparar tokens sin filtrar su contenido
 	"encoding/json" // Para codificar/decodificar JSON
+	"errors"        // Para inspeccionar errores tipados
 	"fmt"           // Para formatear strings
 	"log"           // Para registrar errores
 	"net/http"      // Para crear el servidor HTTP
+	"os"            // Para leer los tokens de la variable de entorno
+	"strconv"       // Para convertir el ID de la URL a entero
+	"strings"       // Para limpiar los parámetros de búsqueda
+
+	// Lógica de negocio de tareas compartida con la CLI
+	"github.com/cristianjonhson/GO-API/proyecto-final-todo/tareas"
 )
 
+// archivoTareas es el archivo JSON donde la API persiste las tareas
+const archivoTareas = "tareas.json"
+
+// variableTokens es la variable de entorno con los usuarios de la API y sus
+// tokens, como "ana:token1,luis:token2"; sin ella la API no pide autenticación
+const variableTokens = "TAREAS_TOKENS"
+
 // Response define la estructura estándar de respuesta de la API
 // Los tags `json` indican cómo se serializarán los campos en JSON
 type Response struct {
 	Message string `json:"message"` // Mensaje de respuesta
 	Status  string `json:"status"`  // Estado de la operación
+	// Data contiene el recurso solicitado (una tarea o una lista de tareas)
+	// Se omite del JSON cuando la respuesta no transporta datos
+	Data interface{} `json:"data,omitempty"`
 }
 
 // main es el punto de entrada de la aplicación
 func main() {
-	// Registramos los manejadores (handlers) para cada ruta
-	// Cada ruta se asocia con una función que procesará las peticiones
-	http.HandleFunc("/", homeHandler)           // Ruta raíz
-	http.HandleFunc("/api/health", healthHandler) // Verificación de salud
-	http.HandleFunc("/api/hello", helloHandler)   // Saludo personalizado
+	// Creamos el gestor de tareas que respaldará los endpoints /api/tareas
+	// La auditoría conserva quién cambió cada tarea, cuándo y sus valores previos
+	gestor, err := tareas.NuevoGestorTareas(archivoTareas, tareas.ConAuditoria(archivoTareas+".auditoria"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	tokens, err := parsearTokens(os.Getenv(variableTokens))
+	if err != nil {
+		log.Fatal(err)
+	}
+	api := &tareasAPI{gestor: gestor, tokens: tokens}
+	if len(tokens) == 0 {
+		fmt.Printf("⚠️  Sin %s: las peticiones no se autentican ni se comprueban permisos\n", variableTokens)
+	}
 
 	// Definimos el puerto donde escuchará el servidor
 	port := ":8080"
 	fmt.Printf("🚀 Servidor corriendo en http://localhost%s\n", port)
 	
-	// Iniciamos el servidor HTTP
+	// Iniciamos el servidor HTTP con todas las rutas
 	// log.Fatal registrará cualquier error y detendrá el programa si falla
-	log.Fatal(http.ListenAndServe(port, nil))
+	log.Fatal(http.ListenAndServe(port, nuevoServidor(api)))
+}
+
+// nuevoServidor registra las rutas de la API en un nuevo ServeMux
+// Las rutas de tareas y listas las atiende api, tras autenticar la petición
+func nuevoServidor(api *tareasAPI) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	// Registramos los manejadores (handlers) para cada ruta
+	// Cada ruta se asocia con una función que procesará las peticiones
+	mux.HandleFunc("/", homeHandler)             // Ruta raíz
+	mux.HandleFunc("/api/health", healthHandler) // Verificación de salud
+	mux.HandleFunc("/api/hello", helloHandler)   // Saludo personalizado
+
+	// Rutas REST de tareas (patrones con método y comodín, Go 1.22+)
+	mux.HandleFunc("GET /api/tareas", api.autenticar(api.listar))                     // Listar tareas
+	mux.HandleFunc("GET /api/tareas/buscar", api.autenticar(api.buscar))              // Buscar por relevancia
+	mux.HandleFunc("GET /api/tareas/exportar", api.autenticar(api.exportar))          // Exportar a CSV, Markdown o todo.txt
+	mux.HandleFunc("POST /api/tareas/importar", api.autenticar(api.importar))         // Importar (o simular la importación)
+	mux.HandleFunc("GET /api/tareas/{id}", api.autenticar(api.obtener))               // Obtener una tarea
+	mux.HandleFunc("POST /api/tareas", api.autenticar(api.crear))                     // Crear tarea
+	mux.HandleFunc("PATCH /api/tareas/{id}", api.autenticar(api.actualizar))          // Editar tarea
+	mux.HandleFunc("PATCH /api/tareas/{id}/completar", api.autenticar(api.completar)) // Completar tarea
+	mux.HandleFunc("DELETE /api/tareas/{id}", api.autenticar(api.eliminar))           // Eliminar tarea
+	mux.HandleFunc("GET /api/tareas/{id}/historial", api.autenticar(api.historial))   // Cambios de una tarea
+	mux.HandleFunc("GET /api/auditoria", api.autenticar(api.auditoria))               // Todos los cambios (JSON, CSV o Markdown)
+	mux.HandleFunc("GET /api/tareas/informe", api.autenticar(api.informe))            // Informe de productividad
+
+	// Dependencias: {id} no puede completarse hasta completar {requisito}
+	mux.HandleFunc("PUT /api/tareas/{id}/dependencias/{requisito}", api.autenticar(api.dependencia))    // Agregar dependencia
+	mux.HandleFunc("DELETE /api/tareas/{id}/dependencias/{requisito}", api.autenticar(api.dependencia)) // Quitar dependencia
+
+	// Asignados: además del propietario, pueden modificar la tarea
+	mux.HandleFunc("PUT /api/tareas/{id}/asignados/{usuario}", api.autenticar(api.asignado))    // Asignar a un usuario
+	mux.HandleFunc("DELETE /api/tareas/{id}/asignados/{usuario}", api.autenticar(api.asignado)) // Desasignar
+
+	// Listas: cada tarea pertenece a una lista ("general" por defecto)
+	mux.HandleFunc("GET /api/listas", api.autenticar(api.listarListas))              // Listas con sus recuentos
+	mux.HandleFunc("POST /api/listas", api.autenticar(api.crearLista))               // Crear lista
+	mux.HandleFunc("PATCH /api/listas/{nombre}", api.autenticar(api.renombrarLista)) // Renombrar lista
+	mux.HandleFunc("DELETE /api/listas/{nombre}", api.autenticar(api.eliminarLista)) // Eliminar lista (sus tareas pasan a "general")
+	mux.HandleFunc("PUT /api/tareas/{id}/lista/{lista}", api.autenticar(api.mover))  // Mover una tarea, con sus subtareas
+
+	return mux
 }
 
 // homeHandler maneja las peticiones a la ruta principal "/"
@@ -89,3 +161,860 @@ func helloHandler(w http.ResponseWriter, r *http.Request) {
 	// Convertimos la respuesta a JSON y la enviamos
 	json.NewEncoder(w).Encode(response)
 }
+
+// tareasAPI agrupa los handlers REST de /api/tareas
+// Todos comparten el mismo gestor, respaldado por el archivo archivoTareas
+// El gestor es seguro para uso concurrente, por lo que no se necesita
+// sincronización adicional entre peticiones
+type tareasAPI struct {
+	gestor *tareas.GestorTareas // Lógica CRUD compartida con la CLI
+	tokens map[string]string    // Token -> usuario; vacío si no se autentica
+}
+
+// claveUsuario es la clave del contexto de la petición donde autenticar
+// guarda el usuario autenticado
+type claveUsuario struct{}
+
+// parsearTokens interpreta el valor de variableTokens: pares usuario:token
+// separados por comas. Retorna el mapa token -> usuario
+func parsearTokens(valor string) (map[string]string, error) {
+	tokens := make(map[string]string)
+	for _, par := range strings.Split(valor, ",") {
+		if strings.TrimSpace(par) == "" {
+			continue
+		}
+		usuario, token, ok := strings.Cut(strings.TrimSpace(par), ":")
+		if !ok || token == "" {
+			return nil, fmt.Errorf("%s: se esperaba usuario:token, se obtuvo %q", variableTokens, par)
+		}
+		if err := tareas.ValidarUsuario(usuario); err != nil {
+			return nil, fmt.Errorf("%s: %v", variableTokens, err)
+		}
+		tokens[token] = strings.ToLower(usuario)
+	}
+	return tokens, nil
+}
+
+// autenticar exige la cabecera "Authorization: Bearer <token>" con uno de
+// los tokens configurados y guarda su usuario en el contexto de la petición
+// Sin token válido responde 401; si no hay tokens configurados, deja pasar
+// todas las peticiones sin usuario
+func (a *tareasAPI) autenticar(siguiente http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(a.tokens) == 0 {
+			siguiente(w, r)
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		usuario := ""
+		for valido, u := range a.tokens {
+			// Comparación en tiempo constante para no filtrar los tokens
+			if subtle.ConstantTimeCompare([]byte(token), []byte(valido)) == 1 {
+				usuario = u
+			}
+		}
+		if !ok || usuario == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="tareas"`)
+			responderError(w, http.StatusUnauthorized, "se necesita un token válido en la cabecera Authorization: Bearer <token>")
+			return
+		}
+
+		siguiente(w, r.WithContext(context.WithValue(r.Context(), claveUsuario{}, usuario)))
+	}
+}
+
+// gestorDe retorna el gestor que actúa en nombre del usuario autenticado en
+// la petición: crea sus tareas y solo modifica las suyas o las asignadas
+func (a *tareasAPI) gestorDe(r *http.Request) *tareas.GestorTareas {
+	usuario, _ := r.Context().Value(claveUsuario{}).(string)
+	return a.gestor.ComoUsuario(usuario)
+}
+
+// listar devuelve todas las tareas
+// Acepta parámetros opcionales para filtrar: "estado" (pendientes,
+// completadas, vencidas o siguientes), "lista" (404 si no existe),
+// "etiqueta", "prioridad", "propietario", "asignada" (a ese usuario),
+// "mias=true" (propias o asignadas al usuario autenticado) y "q", una
+// consulta con la sintaxis de tareas.ParsearConsulta (400 si no es válida)
+// Para ordenar y paginar acepta "orden" (sintaxis de tareas.ParsearOrden),
+// "limite" y, o bien "desplazamiento", o bien "cursor"; la cabecera
+// X-Total-Count indica el total y, si hay más, Link apunta a la página
+// siguiente (rel="next")
+// Ejemplo: /api/tareas?estado=pendientes&etiqueta=trabajo&q=informe%20-borrador&orden=-prioridad&limite=20
+func (a *tareasAPI) listar(w http.ResponseWriter, r *http.Request) {
+	// El usuario autenticado decide cuáles son sus tareas (?mias=true)
+	gestor := a.gestorDe(r)
+
+	// Elegimos el listado según el filtro solicitado
+	var lista []tareas.Tarea
+	switch r.URL.Query().Get("estado") {
+	case "":
+		lista = gestor.Listar()
+	case "pendientes":
+		lista = gestor.ListarPendientes()
+	case "completadas":
+		lista = gestor.ListarCompletadas()
+	case "vencidas":
+		lista = gestor.ListarVencidas()
+	case "siguientes":
+		lista = gestor.ListarSiguientes()
+	default:
+		responderError(w, http.StatusBadRequest, "estado debe ser 'pendientes', 'completadas', 'vencidas' o 'siguientes'")
+		return
+	}
+
+	// Consulta con el lenguaje de búsqueda (?q=), combinable con los filtros
+	if q := r.URL.Query().Get("q"); q != "" {
+		consulta, err := tareas.ParsearConsulta(q)
+		if err != nil {
+			responderError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		lista = gestor.Filtrar(lista, consulta)
+	}
+
+	// Filtros adicionales sobre el listado elegido
+	nombreLista := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("lista")))
+	if nombreLista != "" {
+		if _, _, _, err := gestor.EstadisticasLista(nombreLista); err != nil {
+			responderErrorTarea(w, err)
+			return
+		}
+	}
+	etiqueta := r.URL.Query().Get("etiqueta")
+	prioridad := tareas.Prioridad(r.URL.Query().Get("prioridad"))
+	propietario := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("propietario")))
+	asignada := r.URL.Query().Get("asignada")
+	mias := r.URL.Query().Get("mias") == "true"
+	if mias && gestor.Usuario() == "" {
+		responderError(w, http.StatusBadRequest, "mias=true necesita un usuario autenticado")
+		return
+	}
+	if nombreLista != "" || etiqueta != "" || prioridad != "" || propietario != "" || asignada != "" || mias {
+		var filtradas []tareas.Tarea
+		for _, tarea := range lista {
+			if nombreLista != "" && tarea.NombreLista() != nombreLista {
+				continue
+			}
+			if propietario != "" && tarea.Propietario != propietario {
+				continue
+			}
+			if asignada != "" && !tarea.EstaAsignada(asignada) {
+				continue
+			}
+			if mias && tarea.Propietario != gestor.Usuario() && !tarea.EstaAsignada(gestor.Usuario()) {
+				continue
+			}
+			if etiqueta != "" && !tarea.TieneEtiqueta(etiqueta) {
+				continue
+			}
+			if prioridad != "" && tarea.Prioridad != prioridad {
+				continue
+			}
+			filtradas = append(filtradas, tarea)
+		}
+		lista = filtradas
+	}
+
+	// Orden y paginación sobre el listado ya filtrado
+	paginacion, err := paginacionDesdeConsulta(r)
+	if err != nil {
+		responderError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	pagina, err := tareas.Paginar(lista, paginacion)
+	if err != nil {
+		responderError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(pagina.Total))
+	if pagina.Siguiente != "" {
+		siguiente := *r.URL
+		parametros := siguiente.Query()
+		parametros.Del("desplazamiento")
+		parametros.Set("cursor", pagina.Siguiente)
+		siguiente.RawQuery = parametros.Encode()
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", siguiente.RequestURI()))
+	}
+
+	// Garantizamos que se serialice [] y no null cuando no hay tareas
+	lista = pagina.Tareas
+	if lista == nil {
+		lista = []tareas.Tarea{}
+	}
+
+	responderJSON(w, http.StatusOK, Response{
+		Message: fmt.Sprintf("%d de %d tarea(s)", len(lista), pagina.Total),
+		Status:  "success",
+		Data:    lista,
+	})
+}
+
+// paginacionDesdeConsulta lee los parámetros orden, desplazamiento, limite
+// y cursor de la petición
+func paginacionDesdeConsulta(r *http.Request) (tareas.Paginacion, error) {
+	consulta := r.URL.Query()
+	orden, err := tareas.ParsearOrden(consulta.Get("orden"))
+	if err != nil {
+		return tareas.Paginacion{}, err
+	}
+	paginacion := tareas.Paginacion{Orden: orden, Cursor: consulta.Get("cursor")}
+
+	for nombre, destino := range map[string]*int{"desplazamiento": &paginacion.Desplazamiento, "limite": &paginacion.Limite} {
+		if valor := consulta.Get(nombre); valor != "" {
+			n, err := strconv.Atoi(valor)
+			if err != nil {
+				return tareas.Paginacion{}, fmt.Errorf("%s debe ser un número entero", nombre)
+			}
+			*destino = n
+		}
+	}
+	return paginacion, nil
+}
+
+// buscar devuelve las tareas que coinciden con "texto", de más a menos
+// relevante, sin distinguir tildes ni mayúsculas y tolerando errores de
+// escritura. "tolerancia" fija los errores admitidos por palabra
+// (tareas.ToleranciaPorDefecto si se omite, 0 para desactivarlos)
+// Cada resultado incluye la tarea y su puntuación
+// Ejemplo: GET /api/tareas/buscar?texto=matematicas&tolerancia=2
+func (a *tareasAPI) buscar(w http.ResponseWriter, r *http.Request) {
+	gestor := a.gestorDe(r)
+	texto := strings.TrimSpace(r.URL.Query().Get("texto"))
+	if texto == "" {
+		responderError(w, http.StatusBadRequest, "el parámetro texto es obligatorio")
+		return
+	}
+
+	tolerancia := tareas.ToleranciaPorDefecto
+	if valor := r.URL.Query().Get("tolerancia"); valor != "" {
+		n, err := strconv.Atoi(valor)
+		if err != nil || n < 0 {
+			responderError(w, http.StatusBadRequest, "tolerancia debe ser un número entero mayor o igual que 0")
+			return
+		}
+		tolerancia = n
+	}
+
+	resultados := gestor.BuscarPorRelevancia(texto, tolerancia)
+
+	// Garantizamos que se serialice [] y no null cuando no hay resultados
+	if resultados == nil {
+		resultados = []tareas.ResultadoBusqueda{}
+	}
+
+	responderJSON(w, http.StatusOK, Response{
+		Message: fmt.Sprintf("%d tarea(s)", len(resultados)),
+		Status:  "success",
+		Data:    resultados,
+	})
+}
+
+// tiposContenido son los Content-Type de cada formato de exportación
+var tiposContenido = map[tareas.Formato]string{
+	tareas.FormatoCSV:       "text/csv; charset=utf-8",
+	tareas.FormatoMarkdown:  "text/markdown; charset=utf-8",
+	tareas.FormatoTodoTxt:   "text/plain; charset=utf-8",
+	tareas.FormatoICalendar: "text/calendar; charset=utf-8",
+}
+
+// exportar descarga todas las tareas en el "formato" indicado (csv,
+// markdown, todotxt o ical; 400 si falta o no existe), sin el envoltorio JSON
+// Ejemplo: GET /api/tareas/exportar?formato=todotxt
+func (a *tareasAPI) exportar(w http.ResponseWriter, r *http.Request) {
+	gestor := a.gestorDe(r)
+	formato, err := tareas.ParsearFormato(r.URL.Query().Get("formato"))
+	if err != nil {
+		responderError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Generamos el archivo antes de escribir la cabecera, por si falla
+	var archivo bytes.Buffer
+	if err := tareas.Exportar(&archivo, gestor.Listar(), formato); err != nil {
+		responderError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", tiposContenido[formato])
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"tareas%s\"", formato.Extension()))
+	w.WriteHeader(http.StatusOK)
+	w.Write(archivo.Bytes())
+}
+
+// importar crea las tareas del cuerpo de la petición, un archivo en el
+// "formato" indicado (csv, markdown, todotxt o ical)
+// Con simular=true no crea nada y responde 200 con lo que se crearía; si
+// no, responde 201 con el informe: tareas creadas, duplicadas por título y
+// errores por línea
+// Ejemplo: POST /api/tareas/importar?formato=csv&simular=true
+func (a *tareasAPI) importar(w http.ResponseWriter, r *http.Request) {
+	// Las modificaciones se hacen en nombre del usuario autenticado
+	gestor := a.gestorDe(r)
+
+	formato, err := tareas.ParsearFormato(r.URL.Query().Get("formato"))
+	if err != nil {
+		responderError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	simular := false
+	if valor := r.URL.Query().Get("simular"); valor != "" {
+		if simular, err = strconv.ParseBool(valor); err != nil {
+			responderError(w, http.StatusBadRequest, "simular debe ser true o false")
+			return
+		}
+	}
+
+	informe, err := gestor.Importar(r.Body, formato, simular)
+	if informe == nil {
+		responderError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		responderErrorTarea(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if !simular {
+		if !a.guardar(w) {
+			return
+		}
+		status = http.StatusCreated
+	}
+
+	// Garantizamos que se serialice [] y no null en las listas vacías
+	if informe.Creadas == nil {
+		informe.Creadas = []tareas.Tarea{}
+	}
+	if informe.Duplicadas == nil {
+		informe.Duplicadas = []tareas.Duplicada{}
+	}
+	if informe.Errores == nil {
+		informe.Errores = []tareas.ErrorLinea{}
+	}
+
+	responderJSON(w, status, Response{
+		Message: informe.String(),
+		Status:  "success",
+		Data:    informe,
+	})
+}
+
+// obtener devuelve una tarea por su ID
+// Ejemplo: GET /api/tareas/3
+func (a *tareasAPI) obtener(w http.ResponseWriter, r *http.Request) {
+	gestor := a.gestorDe(r)
+	id, ok := a.idDesdeRuta(w, r, "id")
+	if !ok {
+		return
+	}
+
+	tarea, err := gestor.BuscarPorID(id)
+	if err != nil {
+		responderErrorTarea(w, err)
+		return
+	}
+
+	responderJSON(w, http.StatusOK, Response{
+		Message: "Tarea encontrada",
+		Status:  "success",
+		Data:    tarea,
+	})
+}
+
+// historial devuelve los cambios de una tarea, del más antiguo al más
+// reciente: tipo, fecha, usuario y la tarea antes y después de cada uno
+// Responde 404 si no hay ninguno (la tarea nunca existió)
+// Ejemplo: GET /api/tareas/3/historial
+func (a *tareasAPI) historial(w http.ResponseWriter, r *http.Request) {
+	gestor := a.gestorDe(r)
+	id, ok := a.idDesdeRuta(w, r, "id")
+	if !ok {
+		return
+	}
+
+	eventos := gestor.AuditoriaTarea(id)
+	if len(eventos) == 0 {
+		responderErrorTarea(w, &tareas.ErrorNoEncontrada{ID: id})
+		return
+	}
+
+	responderJSON(w, http.StatusOK, Response{
+		Message: fmt.Sprintf("%d cambio(s) de la tarea %d", len(eventos), id),
+		Status:  "success",
+		Data:    eventos,
+	})
+}
+
+// auditoria devuelve todos los cambios registrados, o solo los de un
+// usuario con ?usuario=ana
+// Con ?formato=csv o markdown los descarga como archivo en lugar de JSON
+// Ejemplo: GET /api/auditoria?usuario=ana&formato=csv
+func (a *tareasAPI) auditoria(w http.ResponseWriter, r *http.Request) {
+	gestor := a.gestorDe(r)
+	eventos := gestor.Auditoria()
+	if usuario := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("usuario"))); usuario != "" {
+		var filtrados []tareas.Evento
+		for _, evento := range eventos {
+			if evento.Usuario == usuario {
+				filtrados = append(filtrados, evento)
+			}
+		}
+		eventos = filtrados
+	}
+
+	nombreFormato := r.URL.Query().Get("formato")
+	if nombreFormato == "" {
+		if eventos == nil {
+			eventos = []tareas.Evento{}
+		}
+		responderJSON(w, http.StatusOK, Response{
+			Message: fmt.Sprintf("%d cambio(s)", len(eventos)),
+			Status:  "success",
+			Data:    eventos,
+		})
+		return
+	}
+
+	formato, err := tareas.ParsearFormato(nombreFormato)
+	if err != nil {
+		responderError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var archivo bytes.Buffer
+	if err := tareas.ExportarAuditoria(&archivo, eventos, formato); err != nil {
+		responderError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", tiposContenido[formato])
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"auditoria%s\"", formato.Extension()))
+	w.WriteHeader(http.StatusOK)
+	w.Write(archivo.Bytes())
+}
+
+// informe devuelve el informe de productividad: tareas completadas por día
+// y por semana, tiempo medio y mediano de entrega, antigüedad de las
+// pendientes y rachas. "dias" y "semanas" fijan los periodos (por defecto
+// tareas.DiasInformePorDefecto y tareas.SemanasInformePorDefecto) y
+// "lista" lo limita a una lista (404 si no existe)
+// Ejemplo: GET /api/tareas/informe?dias=7&semanas=4&lista=trabajo
+func (a *tareasAPI) informe(w http.ResponseWriter, r *http.Request) {
+	gestor := a.gestorDe(r)
+	var p tareas.ParametrosInforme
+	for _, parametro := range []struct {
+		nombre string
+		valor  *int
+	}{{"dias", &p.Dias}, {"semanas", &p.Semanas}} {
+		if valor := r.URL.Query().Get(parametro.nombre); valor != "" {
+			n, err := strconv.Atoi(valor)
+			if err != nil || n <= 0 {
+				responderError(w, http.StatusBadRequest, parametro.nombre+" debe ser un número entero mayor que 0")
+				return
+			}
+			*parametro.valor = n
+		}
+	}
+
+	p.Lista = strings.TrimSpace(r.URL.Query().Get("lista"))
+	informe, err := gestor.InformeProductividad(p)
+	if err != nil {
+		responderErrorTarea(w, err)
+		return
+	}
+	responderJSON(w, http.StatusOK, Response{
+		Message: fmt.Sprintf("Informe de %d tarea(s)", informe.Total),
+		Status:  "success",
+		Data:    informe,
+	})
+}
+
+// crear añade una nueva tarea a partir de un JSON {"titulo": "..."}
+// Acepta además los campos opcionales descripcion, prioridad,
+// fecha_vencimiento (RFC 3339), etiquetas y asignados; el usuario
+// autenticado queda como propietario
+// Responde 201 con la tarea creada o 422 si algún campo no es válido
+func (a *tareasAPI) crear(w http.ResponseWriter, r *http.Request) {
+	// Las modificaciones se hacen en nombre del usuario autenticado
+	gestor := a.gestorDe(r)
+
+	var datos tareas.DatosTarea
+	if err := json.NewDecoder(r.Body).Decode(&datos); err != nil {
+		responderError(w, http.StatusBadRequest, "JSON inválido: "+err.Error())
+		return
+	}
+
+	tarea, err := gestor.CrearConDatos(datos)
+	if err != nil {
+		responderErrorTarea(w, err)
+		return
+	}
+	if !a.guardar(w) {
+		return
+	}
+
+	responderJSON(w, http.StatusCreated, Response{
+		Message: fmt.Sprintf("Tarea creada con ID: %d", tarea.ID),
+		Status:  "success",
+		Data:    tarea,
+	})
+}
+
+// actualizar modifica parcialmente una tarea
+// El cuerpo JSON solo incluye los campos a cambiar, por ejemplo
+// {"titulo": "Nuevo título", "completada": false} para renombrar y reabrir
+// Ejemplo: PATCH /api/tareas/3
+func (a *tareasAPI) actualizar(w http.ResponseWriter, r *http.Request) {
+	// Las modificaciones se hacen en nombre del usuario autenticado
+	gestor := a.gestorDe(r)
+
+	id, ok := a.idDesdeRuta(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var cambios tareas.CambiosTarea
+	if err := json.NewDecoder(r.Body).Decode(&cambios); err != nil {
+		responderError(w, http.StatusBadRequest, "JSON inválido: "+err.Error())
+		return
+	}
+
+	tarea, err := gestor.Actualizar(id, cambios)
+	if err != nil {
+		responderErrorTarea(w, err)
+		return
+	}
+	if !a.guardar(w) {
+		return
+	}
+
+	responderJSON(w, http.StatusOK, Response{
+		Message: fmt.Sprintf("Tarea %d actualizada", id),
+		Status:  "success",
+		Data:    tarea,
+	})
+}
+
+// completar marca una tarea como completada; si es recurrente, crea su
+// siguiente ocurrencia e indica su ID en el mensaje
+// Ejemplo: PATCH /api/tareas/3/completar
+func (a *tareasAPI) completar(w http.ResponseWriter, r *http.Request) {
+	// Las modificaciones se hacen en nombre del usuario autenticado
+	gestor := a.gestorDe(r)
+
+	id, ok := a.idDesdeRuta(w, r, "id")
+	if !ok {
+		return
+	}
+
+	siguiente, err := gestor.CompletarConSiguiente(id)
+	if err != nil {
+		responderErrorTarea(w, err)
+		return
+	}
+	if !a.guardar(w) {
+		return
+	}
+
+	mensaje := fmt.Sprintf("Tarea %d marcada como completada", id)
+	if siguiente != nil {
+		mensaje += fmt.Sprintf("; próxima ocurrencia: tarea %d", siguiente.ID)
+	}
+
+	tarea, _ := gestor.BuscarPorID(id)
+	responderJSON(w, http.StatusOK, Response{
+		Message: mensaje,
+		Status:  "success",
+		Data:    tarea,
+	})
+}
+
+// eliminar mueve una tarea a la papelera por su ID
+// Con ?cascada=true se eliminan también sus subtareas; sin él, una tarea
+// con subtareas responde 409
+// Ejemplo: DELETE /api/tareas/3?cascada=true
+func (a *tareasAPI) eliminar(w http.ResponseWriter, r *http.Request) {
+	// Las modificaciones se hacen en nombre del usuario autenticado
+	gestor := a.gestorDe(r)
+
+	id, ok := a.idDesdeRuta(w, r, "id")
+	if !ok {
+		return
+	}
+
+	eliminadas := 1
+	var err error
+	if r.URL.Query().Get("cascada") == "true" {
+		eliminadas, err = gestor.EliminarConSubtareas(id)
+	} else {
+		err = gestor.Eliminar(id)
+	}
+	if err != nil {
+		responderErrorTarea(w, err)
+		return
+	}
+	if !a.guardar(w) {
+		return
+	}
+
+	responderJSON(w, http.StatusOK, Response{
+		Message: fmt.Sprintf("%d tarea(s) movida(s) a la papelera", eliminadas),
+		Status:  "success",
+	})
+}
+
+// dependencia agrega (PUT) o quita (DELETE) una dependencia entre tareas
+// Ejemplo: PUT /api/tareas/3/dependencias/2
+func (a *tareasAPI) dependencia(w http.ResponseWriter, r *http.Request) {
+	// Las modificaciones se hacen en nombre del usuario autenticado
+	gestor := a.gestorDe(r)
+
+	id, ok := a.idDesdeRuta(w, r, "id")
+	if !ok {
+		return
+	}
+	requisito, ok := a.idDesdeRuta(w, r, "requisito")
+	if !ok {
+		return
+	}
+
+	var err error
+	mensaje := fmt.Sprintf("La tarea %d depende de la tarea %d", id, requisito)
+	if r.Method == http.MethodDelete {
+		err = gestor.QuitarDependencia(id, requisito)
+		mensaje = fmt.Sprintf("La tarea %d ya no depende de la tarea %d", id, requisito)
+	} else {
+		err = gestor.AgregarDependencia(id, requisito)
+	}
+	if err != nil {
+		responderErrorTarea(w, err)
+		return
+	}
+	if !a.guardar(w) {
+		return
+	}
+
+	tarea, _ := gestor.BuscarPorID(id)
+	responderJSON(w, http.StatusOK, Response{
+		Message: mensaje,
+		Status:  "success",
+		Data:    tarea,
+	})
+}
+
+// listarListas devuelve las listas con el recuento de sus tareas
+// Ejemplo: GET /api/listas
+func (a *tareasAPI) listarListas(w http.ResponseWriter, r *http.Request) {
+	gestor := a.gestorDe(r)
+	listas := gestor.Listas()
+	responderJSON(w, http.StatusOK, Response{
+		Message: fmt.Sprintf("%d lista(s)", len(listas)),
+		Status:  "success",
+		Data:    listas,
+	})
+}
+
+// cuerpoLista es el cuerpo JSON para crear o renombrar una lista
+type cuerpoLista struct {
+	Nombre string `json:"nombre"`
+}
+
+// crearLista crea una lista vacía
+// Ejemplo: POST /api/listas con {"nombre": "trabajo"}
+// Responde 201 con las listas o 422 si el nombre no es válido o ya existe
+func (a *tareasAPI) crearLista(w http.ResponseWriter, r *http.Request) {
+	gestor := a.gestorDe(r)
+	var cuerpo cuerpoLista
+	if err := json.NewDecoder(r.Body).Decode(&cuerpo); err != nil {
+		responderError(w, http.StatusBadRequest, "JSON inválido: "+err.Error())
+		return
+	}
+	if err := gestor.CrearLista(cuerpo.Nombre); err != nil {
+		responderErrorTarea(w, err)
+		return
+	}
+	if !a.guardar(w) {
+		return
+	}
+
+	responderJSON(w, http.StatusCreated, Response{
+		Message: fmt.Sprintf("Lista '%s' creada", strings.ToLower(strings.TrimSpace(cuerpo.Nombre))),
+		Status:  "success",
+		Data:    gestor.Listas(),
+	})
+}
+
+// renombrarLista cambia el nombre de una lista y el de sus tareas
+// Responde 403 si el usuario autenticado no puede modificar alguna de ellas
+// Ejemplo: PATCH /api/listas/trabajo con {"nombre": "oficina"}
+func (a *tareasAPI) renombrarLista(w http.ResponseWriter, r *http.Request) {
+	gestor := a.gestorDe(r)
+	var cuerpo cuerpoLista
+	if err := json.NewDecoder(r.Body).Decode(&cuerpo); err != nil {
+		responderError(w, http.StatusBadRequest, "JSON inválido: "+err.Error())
+		return
+	}
+	if err := gestor.RenombrarLista(r.PathValue("nombre"), cuerpo.Nombre); err != nil {
+		responderErrorTarea(w, err)
+		return
+	}
+	if !a.guardar(w) {
+		return
+	}
+
+	responderJSON(w, http.StatusOK, Response{
+		Message: fmt.Sprintf("Lista '%s' renombrada", r.PathValue("nombre")),
+		Status:  "success",
+		Data:    gestor.Listas(),
+	})
+}
+
+// eliminarLista elimina una lista; sus tareas pasan a la lista por defecto
+// Responde 403 si el usuario autenticado no puede modificar alguna de ellas
+// Ejemplo: DELETE /api/listas/casa
+func (a *tareasAPI) eliminarLista(w http.ResponseWriter, r *http.Request) {
+	gestor := a.gestorDe(r)
+	movidas, err := gestor.EliminarLista(r.PathValue("nombre"))
+	if err != nil {
+		responderErrorTarea(w, err)
+		return
+	}
+	if !a.guardar(w) {
+		return
+	}
+
+	responderJSON(w, http.StatusOK, Response{
+		Message: fmt.Sprintf("Lista eliminada; %d tarea(s) pasaron a '%s'", movidas, tareas.ListaPorDefecto),
+		Status:  "success",
+		Data:    gestor.Listas(),
+	})
+}
+
+// mover mueve una tarea de primer nivel, con sus subtareas, a otra lista
+// Ejemplo: PUT /api/tareas/3/lista/casa
+func (a *tareasAPI) mover(w http.ResponseWriter, r *http.Request) {
+	// Las modificaciones se hacen en nombre del usuario autenticado
+	gestor := a.gestorDe(r)
+
+	id, ok := a.idDesdeRuta(w, r, "id")
+	if !ok {
+		return
+	}
+	if err := gestor.MoverALista(id, r.PathValue("lista")); err != nil {
+		responderErrorTarea(w, err)
+		return
+	}
+	if !a.guardar(w) {
+		return
+	}
+
+	tarea, _ := gestor.BuscarPorID(id)
+	responderJSON(w, http.StatusOK, Response{
+		Message: fmt.Sprintf("Tarea %d movida a la lista '%s'", id, tarea.NombreLista()),
+		Status:  "success",
+		Data:    tarea,
+	})
+}
+
+// asignado asigna (PUT) o desasigna (DELETE) un usuario a una tarea
+// Solo puede hacerlo el propietario de la tarea (403 si no)
+// Ejemplo: PUT /api/tareas/3/asignados/luis
+func (a *tareasAPI) asignado(w http.ResponseWriter, r *http.Request) {
+	gestor := a.gestorDe(r)
+
+	id, ok := a.idDesdeRuta(w, r, "id")
+	if !ok {
+		return
+	}
+
+	usuario := strings.ToLower(r.PathValue("usuario"))
+	cambiar := gestor.Asignar
+	mensaje := fmt.Sprintf("Tarea %d asignada a %s", id, usuario)
+	if r.Method == http.MethodDelete {
+		cambiar = gestor.Desasignar
+		mensaje = fmt.Sprintf("%s ya no está asignado a la tarea %d", usuario, id)
+	}
+	tarea, err := cambiar(id, usuario)
+	if err != nil {
+		responderErrorTarea(w, err)
+		return
+	}
+	if !a.guardar(w) {
+		return
+	}
+
+	responderJSON(w, http.StatusOK, Response{
+		Message: mensaje,
+		Status:  "success",
+		Data:    tarea,
+	})
+}
+
+// guardar persiste los cambios tras una mutación
+// Si falla, responde 500 y retorna false para que el handler termine
+func (a *tareasAPI) guardar(w http.ResponseWriter) bool {
+	if err := a.gestor.Guardar(); err != nil {
+		responderError(w, http.StatusInternalServerError, err.Error())
+		return false
+	}
+	return true
+}
+
+// idDesdeRuta resuelve el comodín indicado de la URL ({id} o {requisito})
+// al ID de una tarea: acepta tanto el ID numérico como el UID de la tarea
+// Si no corresponde a ninguna tarea responde 404 y retorna false
+func (a *tareasAPI) idDesdeRuta(w http.ResponseWriter, r *http.Request, comodin string) (int, bool) {
+	valor := r.PathValue(comodin)
+	if id, err := strconv.Atoi(valor); err == nil {
+		return id, true
+	}
+
+	tarea, err := a.gestor.BuscarPorUID(valor)
+	if err != nil {
+		responderErrorTarea(w, err)
+		return 0, false
+	}
+	return tarea.ID, true
+}
+
+// responderErrorTarea traduce los errores del gestor a códigos HTTP:
+// tarea o lista inexistente -> 404, validación -> 422, ya completada, con subtareas
+// o bloqueada por dependencias -> 409, tarea ajena y sin asignar -> 403
+func responderErrorTarea(w http.ResponseWriter, err error) {
+	var noEncontrada *tareas.ErrorNoEncontrada
+	var validacion *tareas.ErrorValidacion
+	var bloqueada *tareas.ErrorBloqueada
+	var permiso *tareas.ErrorPermiso
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.As(err, &noEncontrada):
+		status = http.StatusNotFound
+	case errors.As(err, &validacion):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, tareas.ErrTareaYaCompletada), errors.Is(err, tareas.ErrTieneSubtareas), errors.As(err, &bloqueada):
+		status = http.StatusConflict
+	case errors.As(err, &permiso):
+		status = http.StatusForbidden
+	}
+
+	responderError(w, status, err.Error())
+}
+
+// responderError envía una respuesta de error con el envoltorio estándar
+func responderError(w http.ResponseWriter, status int, mensaje string) {
+	responderJSON(w, status, Response{
+		Message: mensaje,
+		Status:  "error",
+	})
+}
+
+// responderJSON escribe la cabecera, el código de estado y el cuerpo JSON
+func responderJSON(w http.ResponseWriter, status int, response Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,448 @@
+// Tests de las rutas, las respuestas y la autenticación de la API REST
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cristianjonhson/GO-API/proyecto-final-todo/tareas"
+)
+
+// nuevoServidorDePrueba retorna el servidor de la API sobre un gestor en
+// memoria, con los tokens dados (nil: sin autenticación)
+func nuevoServidorDePrueba(t *testing.T, tokens map[string]string) http.Handler {
+	t.Helper()
+	gestor, err := tareas.NuevoGestorTareas("",
+		tareas.ConAlmacenamiento(tareas.NuevoAlmacenamientoMemoria()),
+		tareas.ConSalida(io.Discard))
+	if err != nil {
+		t.Fatalf("Error al crear el gestor: %v", err)
+	}
+	return nuevoServidor(&tareasAPI{gestor: gestor, tokens: tokens})
+}
+
+// pedir envía una petición al servidor, con el token si no está vacío, y
+// retorna la respuesta y su cuerpo decodificado como Response
+func pedir(t *testing.T, servidor http.Handler, metodo, ruta, cuerpo, token string) (*httptest.ResponseRecorder, Response) {
+	t.Helper()
+	peticion := httptest.NewRequest(metodo, ruta, strings.NewReader(cuerpo))
+	if token != "" {
+		peticion.Header.Set("Authorization", "Bearer "+token)
+	}
+	grabadora := httptest.NewRecorder()
+	servidor.ServeHTTP(grabadora, peticion)
+
+	var respuesta Response
+	if strings.HasPrefix(grabadora.Header().Get("Content-Type"), "application/json") {
+		if err := json.Unmarshal(grabadora.Body.Bytes(), &respuesta); err != nil {
+			t.Fatalf("%s %s: cuerpo JSON inválido: %v\n%s", metodo, ruta, err, grabadora.Body)
+		}
+	}
+	return grabadora, respuesta
+}
+
+// idsDeRespuesta retorna los IDs de las tareas de data, en orden
+func idsDeRespuesta(t *testing.T, respuesta Response) []int {
+	t.Helper()
+	elementos, ok := respuesta.Data.([]any)
+	if !ok {
+		t.Fatalf("data debería ser una lista de tareas: %#v", respuesta.Data)
+	}
+	ids := []int{}
+	for _, elemento := range elementos {
+		tarea, _ := elemento.(map[string]any)
+		id, _ := tarea["id"].(float64)
+		ids = append(ids, int(id))
+	}
+	return ids
+}
+
+// TestRutas verifica el código de estado y el envoltorio de cada ruta
+func TestRutas(t *testing.T) {
+	servidor := nuevoServidorDePrueba(t, nil)
+	pedir(t, servidor, "POST", "/api/tareas", `{"titulo": "Preparar informe"}`, "")
+	pedir(t, servidor, "POST", "/api/tareas", `{"titulo": "Revisar cifras", "padre_id": 1}`, "")
+
+	tests := []struct {
+		nombre  string
+		metodo  string
+		ruta    string
+		cuerpo  string
+		estado  int
+		mensaje string // prefijo del mensaje
+	}{
+		{"inicio", "GET", "/", "", http.StatusOK, "Bienvenido"},
+		{"salud", "GET", "/api/health", "", http.StatusOK, "API funcionando"},
+		{"saludo", "GET", "/api/hello?name=Ana", "", http.StatusOK, "¡Hola, Ana!"},
+		{"listar", "GET", "/api/tareas", "", http.StatusOK, "2 de 2 tarea(s)"},
+		{"obtener", "GET", "/api/tareas/1", "", http.StatusOK, "Tarea encontrada"},
+		{"crear", "POST", "/api/tareas", `{"titulo": "Comprar pan"}`, http.StatusCreated, "Tarea creada con ID: 3"},
+		{"completar", "PATCH", "/api/tareas/2/completar", "", http.StatusOK, ""},
+		{"listas", "GET", "/api/listas", "", http.StatusOK, "1 lista(s)"},
+		{"crear lista", "POST", "/api/listas", `{"nombre": "Casa"}`, http.StatusCreated, "Lista 'casa' creada"},
+		{"informe", "GET", "/api/tareas/informe?dias=7", "", http.StatusOK, "Informe de 3 tarea(s)"},
+		{"historial", "GET", "/api/tareas/1/historial", "", http.StatusOK, "1 cambio(s)"},
+
+		{"JSON inválido", "POST", "/api/tareas", `{"titulo": `, http.StatusBadRequest, "JSON inválido"},
+		{"búsqueda sin texto", "GET", "/api/tareas/buscar", "", http.StatusBadRequest, "el parámetro texto"},
+		{"formato desconocido", "GET", "/api/tareas/exportar?formato=pdf", "", http.StatusBadRequest, ""},
+		{"informe con días inválidos", "GET", "/api/tareas/informe?dias=0", "", http.StatusBadRequest, "dias debe ser"},
+		{"tarea inexistente", "GET", "/api/tareas/99", "", http.StatusNotFound, ""},
+		{"UID inexistente", "GET", "/api/tareas/uid-desconocido", "", http.StatusNotFound, ""},
+		{"lista inexistente", "GET", "/api/tareas/informe?lista=ocio", "", http.StatusNotFound, ""},
+		{"título vacío", "POST", "/api/tareas", `{"titulo": ""}`, http.StatusUnprocessableEntity, ""},
+		{"con subtareas", "DELETE", "/api/tareas/1", "", http.StatusConflict, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			grabadora, respuesta := pedir(t, servidor, tt.metodo, tt.ruta, tt.cuerpo, "")
+			if grabadora.Code != tt.estado {
+				t.Fatalf("Estado = %d, se esperaba %d: %s", grabadora.Code, tt.estado, grabadora.Body)
+			}
+			if tipo := grabadora.Header().Get("Content-Type"); tipo != "application/json" {
+				t.Errorf("Content-Type = %q", tipo)
+			}
+			if !strings.HasPrefix(respuesta.Message, tt.mensaje) {
+				t.Errorf("Mensaje = %q, se esperaba que empezara por %q", respuesta.Message, tt.mensaje)
+			}
+			esError := tt.estado >= http.StatusBadRequest
+			if esError && (respuesta.Status != "error" || respuesta.Data != nil) {
+				t.Errorf("Un error debería tener status error y no tener data: %+v", respuesta)
+			}
+			if !esError && respuesta.Status == "error" {
+				t.Errorf("Status inesperado: %+v", respuesta)
+			}
+		})
+	}
+
+	// El cuerpo de una tarea va en data
+	_, respuesta := pedir(t, servidor, "GET", "/api/tareas/1", "", "")
+	if tarea, ok := respuesta.Data.(map[string]any); !ok || tarea["titulo"] != "Preparar informe" {
+		t.Errorf("data debería contener la tarea: %#v", respuesta.Data)
+	}
+
+	// Las exportaciones no llevan envoltorio
+	if grabadora, _ := pedir(t, servidor, "GET", "/api/tareas/exportar?formato=csv", "", ""); grabadora.Code != http.StatusOK || grabadora.Header().Get("Content-Type") != "text/csv; charset=utf-8" {
+		t.Errorf("Exportación inesperada: %d %q", grabadora.Code, grabadora.Header().Get("Content-Type"))
+	}
+}
+
+// TestResponderErrorTarea verifica la traducción de los errores del gestor
+// a códigos HTTP, también cuando vienen envueltos
+func TestResponderErrorTarea(t *testing.T) {
+	tests := []struct {
+		nombre string
+		err    error
+		estado int
+	}{
+		{"no encontrada", &tareas.ErrorNoEncontrada{ID: 3}, http.StatusNotFound},
+		{"lista no encontrada", &tareas.ErrorNoEncontrada{Lista: "ocio"}, http.StatusNotFound},
+		{"validación", &tareas.ErrorValidacion{Mensaje: "título vacío"}, http.StatusUnprocessableEntity},
+		{"ya completada", tareas.ErrTareaYaCompletada, http.StatusConflict},
+		{"con subtareas", tareas.ErrTieneSubtareas, http.StatusConflict},
+		{"bloqueada", &tareas.ErrorBloqueada{ID: 3, Pendientes: []int{1}}, http.StatusConflict},
+		{"sin permiso", &tareas.ErrorPermiso{ID: 3, Usuario: "luis"}, http.StatusForbidden},
+		{"envuelto", fmt.Errorf("al completar: %w", tareas.ErrTareaYaCompletada), http.StatusConflict},
+		{"inesperado", errors.New("disco lleno"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			grabadora := httptest.NewRecorder()
+			responderErrorTarea(grabadora, tt.err)
+			if grabadora.Code != tt.estado {
+				t.Errorf("Estado = %d, se esperaba %d", grabadora.Code, tt.estado)
+			}
+			var cuerpo map[string]any
+			if err := json.Unmarshal(grabadora.Body.Bytes(), &cuerpo); err != nil {
+				t.Fatalf("Cuerpo JSON inválido: %v", err)
+			}
+			esperado := map[string]any{"message": tt.err.Error(), "status": "error"}
+			if !reflect.DeepEqual(cuerpo, esperado) {
+				t.Errorf("Cuerpo = %v, se esperaba %v", cuerpo, esperado)
+			}
+		})
+	}
+}
+
+// TestAutenticar verifica que con tokens las rutas de tareas exijan uno
+// válido y actúen en nombre de su usuario
+func TestAutenticar(t *testing.T) {
+	tokens, err := parsearTokens(" ana:t-ana, Luis:t-luis ,")
+	if err != nil || len(tokens) != 2 || tokens["t-luis"] != "luis" {
+		t.Fatalf("Tokens inesperados: %v %v", tokens, err)
+	}
+	servidor := nuevoServidorDePrueba(t, tokens)
+
+	for _, tt := range []struct {
+		nombre   string
+		cabecera string
+	}{
+		{"sin cabecera", ""},
+		{"token desconocido", "Bearer otro"},
+		{"otro esquema", "Basic t-ana"},
+	} {
+		t.Run(tt.nombre, func(t *testing.T) {
+			peticion := httptest.NewRequest("GET", "/api/tareas", nil)
+			if tt.cabecera != "" {
+				peticion.Header.Set("Authorization", tt.cabecera)
+			}
+			grabadora := httptest.NewRecorder()
+			servidor.ServeHTTP(grabadora, peticion)
+			if grabadora.Code != http.StatusUnauthorized || grabadora.Header().Get("WWW-Authenticate") == "" {
+				t.Errorf("Se esperaba 401 con WWW-Authenticate: %d %v", grabadora.Code, grabadora.Header())
+			}
+		})
+	}
+
+	// Las rutas generales no se autentican
+	if grabadora, _ := pedir(t, servidor, "GET", "/api/health", "", ""); grabadora.Code != http.StatusOK {
+		t.Errorf("/api/health = %d, se esperaba 200 sin token", grabadora.Code)
+	}
+
+	// Cada petición actúa en nombre del usuario de su token
+	_, respuesta := pedir(t, servidor, "POST", "/api/tareas", `{"titulo": "Preparar informe"}`, "t-ana")
+	if tarea, ok := respuesta.Data.(map[string]any); !ok || tarea["propietario"] != "ana" {
+		t.Errorf("La tarea debería ser de ana: %#v", respuesta.Data)
+	}
+	pedir(t, servidor, "POST", "/api/listas", `{"nombre": "casa"}`, "t-ana")
+	pedir(t, servidor, "PUT", "/api/tareas/1/lista/casa", "", "t-ana")
+	for _, tt := range []struct {
+		metodo, ruta, cuerpo string
+	}{
+		{"PATCH", "/api/tareas/1/completar", ""},
+		{"PATCH", "/api/listas/casa", `{"nombre": "hogar"}`},
+		{"DELETE", "/api/listas/casa", ""},
+	} {
+		if grabadora, _ := pedir(t, servidor, tt.metodo, tt.ruta, tt.cuerpo, "t-luis"); grabadora.Code != http.StatusForbidden {
+			t.Errorf("%s %s como luis = %d, se esperaba 403", tt.metodo, tt.ruta, grabadora.Code)
+		}
+	}
+	if grabadora, _ := pedir(t, servidor, "PATCH", "/api/tareas/1/completar", "", "t-ana"); grabadora.Code != http.StatusOK {
+		t.Errorf("ana debería poder completar su tarea: %d", grabadora.Code)
+	}
+
+	// Tokens mal configurados
+	for _, valor := range []string{"ana", "ana:", "ana luis:token"} {
+		if _, err := parsearTokens(valor); err == nil {
+			t.Errorf("parsearTokens(%q) debería fallar", valor)
+		}
+	}
+}
+
+// TestListarConConsulta verifica el parámetro q de GET /api/tareas, solo y
+// combinado con los demás filtros
+func TestListarConConsulta(t *testing.T) {
+	servidor := nuevoServidorDePrueba(t, nil)
+	for _, cuerpo := range []string{
+		`{"titulo": "Preparar informe anual", "prioridad": "alta", "etiquetas": ["trabajo"]}`,
+		`{"titulo": "Borrador del informe", "prioridad": "media", "etiquetas": ["trabajo"]}`,
+		`{"titulo": "Comprar pan", "etiquetas": ["casa"]}`,
+	} {
+		pedir(t, servidor, "POST", "/api/tareas", cuerpo, "")
+	}
+	pedir(t, servidor, "PATCH", "/api/tareas/3/completar", "", "")
+
+	tests := []struct {
+		nombre     string
+		parametros url.Values
+		ids        []int
+	}{
+		{"palabra y negación", url.Values{"q": {"informe -borrador"}}, []int{1}},
+		{"con estado", url.Values{"q": {"tag:casa"}, "estado": {"pendientes"}}, []int{}},
+		{"con etiqueta", url.Values{"q": {"prioridad:>=media"}, "etiqueta": {"trabajo"}}, []int{1, 2}},
+		{"sin tildes ni mayúsculas", url.Values{"q": {"COMPRAR estado:completada"}}, []int{3}},
+		{"vacía", url.Values{"q": {""}}, []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			grabadora, respuesta := pedir(t, servidor, "GET", "/api/tareas?"+tt.parametros.Encode(), "", "")
+			if grabadora.Code != http.StatusOK {
+				t.Fatalf("Estado = %d: %s", grabadora.Code, grabadora.Body)
+			}
+			if ids := idsDeRespuesta(t, respuesta); !reflect.DeepEqual(ids, tt.ids) {
+				t.Errorf("IDs = %v, se esperaban %v", ids, tt.ids)
+			}
+		})
+	}
+
+	// Una consulta inválida es un 400 con la posición del error
+	grabadora, respuesta := pedir(t, servidor, "GET", "/api/tareas?"+url.Values{"q": {`"sin cerrar`}}.Encode(), "", "")
+	if grabadora.Code != http.StatusBadRequest || !strings.Contains(respuesta.Message, "posición") {
+		t.Errorf("Se esperaba 400 con la posición del error: %d %q", grabadora.Code, respuesta.Message)
+	}
+}
+
+// TestListarPaginado verifica el orden, las cabeceras X-Total-Count y Link
+// y los errores de paginación de GET /api/tareas
+func TestListarPaginado(t *testing.T) {
+	servidor := nuevoServidorDePrueba(t, nil)
+	for _, titulo := range []string{"Comprar pan", "Llamar a Luis", "Pagar la luz", "Regar plantas", "Sacar al perro"} {
+		pedir(t, servidor, "POST", "/api/tareas", `{"titulo": "`+titulo+`"}`, "")
+	}
+
+	// Recorremos las páginas siguiendo Link; una tarea eliminada entre
+	// páginas no desplaza las siguientes
+	ruta := "/api/tareas?orden=-id&limite=2"
+	var paginas [][]int
+	for ruta != "" && len(paginas) < 5 {
+		grabadora, respuesta := pedir(t, servidor, "GET", ruta, "", "")
+		if grabadora.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d: %s", ruta, grabadora.Code, grabadora.Body)
+		}
+		paginas = append(paginas, idsDeRespuesta(t, respuesta))
+		if len(paginas) == 1 {
+			if total := grabadora.Header().Get("X-Total-Count"); total != "5" {
+				t.Errorf("X-Total-Count = %q, se esperaba 5", total)
+			}
+			pedir(t, servidor, "DELETE", "/api/tareas/4", "", "")
+		}
+
+		ruta = ""
+		if enlace := grabadora.Header().Get("Link"); enlace != "" {
+			siguiente, resto, _ := strings.Cut(strings.TrimPrefix(enlace, "<"), ">")
+			if resto != `; rel="next"` || !strings.Contains(siguiente, "cursor=") || !strings.Contains(siguiente, "orden=-id") {
+				t.Fatalf("Link inesperado: %q", enlace)
+			}
+			ruta = siguiente
+		}
+	}
+	if esperadas := [][]int{{5, 4}, {3, 2}, {1}}; !reflect.DeepEqual(paginas, esperadas) {
+		t.Errorf("Páginas = %v, se esperaban %v", paginas, esperadas)
+	}
+
+	// Por desplazamiento, la última página no tiene Link
+	grabadora, respuesta := pedir(t, servidor, "GET", "/api/tareas?desplazamiento=3&limite=2", "", "")
+	if ids := idsDeRespuesta(t, respuesta); !reflect.DeepEqual(ids, []int{5}) || grabadora.Header().Get("Link") != "" {
+		t.Errorf("Última página = %v con Link %q", ids, grabadora.Header().Get("Link"))
+	}
+
+	for _, consulta := range []string{"limite=muchos", "limite=-1", "orden=color", "desplazamiento=1&cursor=abc", "cursor=abc"} {
+		if grabadora, _ := pedir(t, servidor, "GET", "/api/tareas?"+consulta, "", ""); grabadora.Code != http.StatusBadRequest {
+			t.Errorf("GET ?%s = %d, se esperaba 400", consulta, grabadora.Code)
+		}
+	}
+}
+
+// TestPermisosPorUsuario verifica que cada ruta que modifica una tarea
+// responda 403 a quien no puede modificarla, y los filtros por usuario
+func TestPermisosPorUsuario(t *testing.T) {
+	servidor := nuevoServidorDePrueba(t, map[string]string{"t-ana": "ana", "t-luis": "luis"})
+	pedir(t, servidor, "POST", "/api/tareas", `{"titulo": "Preparar informe"}`, "t-ana")
+	pedir(t, servidor, "POST", "/api/tareas", `{"titulo": "Comprar pan"}`, "t-luis")
+	pedir(t, servidor, "POST", "/api/listas", `{"nombre": "casa"}`, "t-luis")
+
+	rutas := []struct {
+		metodo, ruta, cuerpo string
+	}{
+		{"PATCH", "/api/tareas/1", `{"titulo": "Preparar el informe"}`},
+		{"DELETE", "/api/tareas/1", ""},
+		{"PUT", "/api/tareas/1/dependencias/2", ""},
+		{"PUT", "/api/tareas/1/lista/casa", ""},
+		{"PUT", "/api/tareas/1/asignados/luis", ""},
+	}
+	for _, tt := range rutas {
+		grabadora, respuesta := pedir(t, servidor, tt.metodo, tt.ruta, tt.cuerpo, "t-luis")
+		if grabadora.Code != http.StatusForbidden || !strings.Contains(respuesta.Message, "luis") {
+			t.Errorf("%s %s como luis = %d %q, se esperaba 403", tt.metodo, tt.ruta, grabadora.Code, respuesta.Message)
+		}
+	}
+	if grabadora, _ := pedir(t, servidor, "GET", "/api/tareas/1", "", "t-luis"); grabadora.Code != http.StatusOK {
+		t.Errorf("luis debería poder leer la tarea de ana: %d", grabadora.Code)
+	}
+
+	// Asignada, luis puede modificarla, pero no asignarla a otros
+	if grabadora, _ := pedir(t, servidor, "PUT", "/api/tareas/1/asignados/luis", "", "t-ana"); grabadora.Code != http.StatusOK {
+		t.Fatalf("ana debería poder asignar su tarea: %d", grabadora.Code)
+	}
+	if grabadora, _ := pedir(t, servidor, "PATCH", "/api/tareas/1", `{"titulo": "Preparar el informe"}`, "t-luis"); grabadora.Code != http.StatusOK {
+		t.Errorf("luis debería poder editar una tarea asignada: %d", grabadora.Code)
+	}
+	if grabadora, _ := pedir(t, servidor, "PUT", "/api/tareas/1/asignados/eva", "", "t-luis"); grabadora.Code != http.StatusForbidden {
+		t.Errorf("Solo el propietario puede asignar: %d", grabadora.Code)
+	}
+
+	filtros := []struct {
+		consulta, token string
+		ids             []int
+	}{
+		{"mias=true", "t-luis", []int{1, 2}},
+		{"mias=true", "t-ana", []int{1}},
+		{"propietario=luis", "t-ana", []int{2}},
+		{"asignada=luis", "t-ana", []int{1}},
+	}
+	for _, tt := range filtros {
+		_, respuesta := pedir(t, servidor, "GET", "/api/tareas?"+tt.consulta, "", tt.token)
+		if ids := idsDeRespuesta(t, respuesta); !reflect.DeepEqual(ids, tt.ids) {
+			t.Errorf("GET ?%s con %s = %v, se esperaban %v", tt.consulta, tt.token, ids, tt.ids)
+		}
+	}
+
+	// Sin autenticación no hay usuario para mias=true
+	sinTokens := nuevoServidorDePrueba(t, nil)
+	if grabadora, _ := pedir(t, sinTokens, "GET", "/api/tareas?mias=true", "", ""); grabadora.Code != http.StatusBadRequest {
+		t.Errorf("mias=true sin usuario = %d, se esperaba 400", grabadora.Code)
+	}
+}
+
+// TestInforme verifica los parámetros de GET /api/tareas/informe y el
+// informe limitado a una lista
+func TestInforme(t *testing.T) {
+	servidor := nuevoServidorDePrueba(t, nil)
+	for _, titulo := range []string{"Preparar informe", "Revisar cifras", "Comprar pan"} {
+		pedir(t, servidor, "POST", "/api/tareas", `{"titulo": "`+titulo+`"}`, "")
+	}
+	pedir(t, servidor, "POST", "/api/listas", `{"nombre": "trabajo"}`, "")
+	pedir(t, servidor, "PUT", "/api/tareas/1/lista/trabajo", "", "")
+	pedir(t, servidor, "PUT", "/api/tareas/2/lista/trabajo", "", "")
+	pedir(t, servidor, "PATCH", "/api/tareas/1/completar", "", "")
+
+	// informeDe pide el informe y decodifica su data
+	informeDe := func(consulta string) tareas.InformeProductividad {
+		t.Helper()
+		grabadora, respuesta := pedir(t, servidor, "GET", "/api/tareas/informe?"+consulta, "", "")
+		if grabadora.Code != http.StatusOK {
+			t.Fatalf("GET ?%s = %d: %s", consulta, grabadora.Code, grabadora.Body)
+		}
+		datos, _ := json.Marshal(respuesta.Data)
+		var informe tareas.InformeProductividad
+		if err := json.Unmarshal(datos, &informe); err != nil {
+			t.Fatalf("data no es un informe: %v", err)
+		}
+		return informe
+	}
+
+	informe := informeDe("")
+	if informe.Total != 3 || len(informe.PorDia) != tareas.DiasInformePorDefecto || len(informe.PorSemana) != tareas.SemanasInformePorDefecto {
+		t.Errorf("Informe por defecto inesperado: total %d, %d días, %d semanas", informe.Total, len(informe.PorDia), len(informe.PorSemana))
+	}
+
+	informe = informeDe("dias=3&semanas=2&lista=Trabajo")
+	if informe.Total != 2 || informe.Completadas != 1 || informe.Pendientes != 1 || len(informe.PorDia) != 3 || len(informe.PorSemana) != 2 {
+		t.Fatalf("Informe de la lista inesperado: %+v", informe)
+	}
+	if hoy := informe.PorDia[len(informe.PorDia)-1]; hoy.Completadas != 1 || informe.RachaActual != 1 {
+		t.Errorf("La tarea completada hoy debería contar en el día y en la racha: %+v, racha %d", hoy, informe.RachaActual)
+	}
+
+	for _, tt := range []struct {
+		consulta string
+		estado   int
+	}{
+		{"semanas=muchas", http.StatusBadRequest},
+		{"dias=-1", http.StatusBadRequest},
+		{"lista=ocio", http.StatusNotFound},
+	} {
+		if grabadora, _ := pedir(t, servidor, "GET", "/api/tareas/informe?"+tt.consulta, "", ""); grabadora.Code != tt.estado {
+			t.Errorf("GET ?%s = %d, se esperaba %d", tt.consulta, grabadora.Code, tt.estado)
+		}
+	}
+}
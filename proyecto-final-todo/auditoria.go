@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cristianjonhson/GO-API/proyecto-final-todo/consola"
+	"github.com/cristianjonhson/GO-API/proyecto-final-todo/tareas"
+)
+
+// maxValorEvento es la longitud a partir de la que se recortan los valores
+// de los campos cambiados al mostrarlos (las descripciones pueden ser largas).
+const maxValorEvento = 60
+
+// MostrarEventos imprime los eventos de la auditoría, cada uno con sus
+// campos cambiados.
+func MostrarEventos(eventos []tareas.Evento, titulo string) {
+	fmt.Println("\n" + strings.Repeat("=", 70))
+	fmt.Printf("%s (%d evento(s))\n", titulo, len(eventos))
+	fmt.Println(strings.Repeat("=", 70))
+	if len(eventos) == 0 {
+		fmt.Println("📭 No hay cambios registrados")
+	}
+	for _, evento := range eventos {
+		fmt.Printf("🕓 %s\n", evento)
+		for _, campo := range evento.CamposCambiados() {
+			fmt.Printf("      %s: %s → %s\n", campo.Campo, formatearValorEvento(campo.Antes), formatearValorEvento(campo.Despues))
+		}
+	}
+	fmt.Println(strings.Repeat("=", 70))
+}
+
+// formatearValorEvento entrecomilla el valor de un campo cambiado,
+// recortándolo si es largo, y muestra los vacíos como "(vacío)".
+func formatearValorEvento(valor string) string {
+	if valor == "" {
+		return "(vacío)"
+	}
+	if runas := []rune(valor); len(runas) > maxValorEvento {
+		valor = string(runas[:maxValorEvento-1]) + "…"
+	}
+	return fmt.Sprintf("%q", valor)
+}
+
+// mostrarHistorial pregunta por una tarea y muestra sus cambios, incluso si
+// está en la papelera o ya se purgó.
+func mostrarHistorial(entrada *consola.Entrada, gestor *tareas.GestorTareas) {
+	id := entrada.LeerEntero("🕓 ID de la tarea: ", 0)
+	eventos := gestor.AuditoriaTarea(id)
+	if len(eventos) == 0 {
+		fmt.Printf("❌ No hay cambios registrados de la tarea %d\n", id)
+		return
+	}
+	MostrarEventos(eventos, fmt.Sprintf("🕓 HISTORIAL DE LA TAREA %d", id))
+}
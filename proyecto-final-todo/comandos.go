@@ -0,0 +1,1005 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cristianjonhson/GO-API/proyecto-final-todo/tareas"
+)
+
+// Códigos de salida de los subcomandos. Los errores del gestor se traducen
+// igual que en la API REST: tarea inexistente, datos inválidos y conflicto.
+const (
+	salidaOK           = 0 // El comando se completó
+	salidaError        = 1 // Error inesperado (lectura o escritura del archivo, ...)
+	salidaUso          = 2 // Comando, opción o argumento inválido
+	salidaNoEncontrada = 3 // La tarea no existe
+	salidaValidacion   = 4 // Algún campo no supera las validaciones
+	salidaConflicto    = 5 // Ya completada, bloqueada por dependencias o con subtareas
+	salidaPermiso      = 6 // El usuario no es propietario de la tarea ni está asignado
+)
+
+// archivoPorDefecto es el archivo de tareas si no se indica --file.
+const archivoPorDefecto = "tareas.json"
+
+// variableLista es la variable de entorno con la lista activa por defecto,
+// para no repetir --list en cada comando.
+const variableLista = "TODO_LIST"
+
+// variableUsuario es la variable de entorno con el usuario actual, en cuyo
+// nombre se crean y modifican las tareas.
+const variableUsuario = "TODO_USER"
+
+// opcionesGlobales son las opciones que admiten todos los subcomandos, antes
+// o después del nombre del comando.
+type opcionesGlobales struct {
+	archivo string
+	json    bool
+
+	// lista es la lista activa: los comandos solo ven sus tareas y crean
+	// las nuevas en ella ("" = todas las listas)
+	lista string
+
+	// usuario es el usuario actual: propietario de las tareas que se crean,
+	// solo puede modificar las suyas y las asignadas ("" = sin usuario)
+	usuario string
+}
+
+// registrar añade las opciones globales a fs, con los valores actuales como
+// valores por defecto.
+func (o *opcionesGlobales) registrar(fs *flag.FlagSet) {
+	fs.StringVar(&o.archivo, "file", o.archivo, "archivo JSON de tareas (su diario es <archivo>.diario y su auditoría, <archivo>.auditoria)")
+	fs.BoolVar(&o.json, "json", o.json, "salida en JSON, para otros programas")
+	fs.StringVar(&o.lista, "list", o.lista, "lista activa (por defecto, la variable "+variableLista+"; sin ella, todas)")
+	fs.StringVar(&o.usuario, "user", o.usuario, "usuario actual (por defecto, la variable "+variableUsuario+"; sin él, no se comprueban permisos)")
+}
+
+// errorUso es un error en la forma de invocar un comando; se informa con
+// salidaUso.
+type errorUso struct {
+	mensaje string
+}
+
+// Error implementa la interfaz error.
+func (e *errorUso) Error() string {
+	return e.mensaje
+}
+
+// comando es un subcomando de la CLI.
+type comando struct {
+	nombre      string
+	uso         string
+	descripcion string
+
+	// ejecutar interpreta args (sin el nombre del comando) y ejecuta el
+	// comando sobre el gestor que le da abrir
+	ejecutar func(c *contextoComando, args []string) error
+}
+
+// contextoComando reúne lo que necesita un subcomando para ejecutarse.
+type contextoComando struct {
+	opciones opcionesGlobales
+	salida   io.Writer
+	flags    *flag.FlagSet
+	gestor   *tareas.GestorTareas
+}
+
+// comandos son los subcomandos disponibles, en el orden de la ayuda.
+var comandos = []comando{
+	{"add", "add <título> [--description d] [--priority p] [--due dd/mm/aaaa] [--tags a,b] [--parent id] [--assign u1,u2]", "Crea una tarea", comandoAdd},
+	{"list", "list [--pending|--completed|--overdue|--next] [--mine] [--owner u] [--assigned u] [--tag t] [--query consulta] [--sort campos] [--limit n]", "Lista las tareas", comandoList},
+	{"show", "show <id|uid>", "Muestra una tarea", comandoShow},
+	{"done", "done <id|uid>...", "Marca tareas como completadas", comandoDone},
+	{"rm", "rm [--cascade] <id|uid>...", "Mueve tareas a la papelera", comandoRm},
+	{"search", "search [--tolerance n] <texto>...", "Busca por relevancia, sin distinguir tildes y tolerando errores", comandoSearch},
+	{"stats", "stats", "Muestra las estadísticas", comandoStats},
+	{"export", "export --format f [--output archivo]", "Exporta las tareas (csv, markdown, todotxt o ical)", comandoExport},
+	{"import", "import [--format f] [--dry-run] <archivo>", "Importa tareas de un archivo", comandoImport},
+	{"lists", "lists [add <nombre> | rename <actual> <nueva> | rm <nombre>]", "Muestra, crea, renombra o elimina listas", comandoLists},
+	{"move", "move <lista> <id|uid>...", "Mueve tareas, con sus subtareas, a otra lista", comandoMove},
+	{"assign", "assign <id|uid> <usuario>...", "Asigna una tarea a otros usuarios", comandoAssign},
+	{"unassign", "unassign <id|uid> <usuario>...", "Quita usuarios de los asignados de una tarea", comandoUnassign},
+	{"history", "history <id|uid>", "Muestra quién cambió una tarea, cuándo y qué valores tenía", comandoHistory},
+	{"audit", "audit [--by usuario] [--format f] [--output archivo]", "Muestra o exporta todos los cambios (csv o markdown)", comandoAudit},
+	{"report", "report [--days n] [--weeks n]", "Muestra el rendimiento, el tiempo de entrega, la antigüedad y las rachas", comandoReport},
+}
+
+// ejecutarComando ejecuta un subcomando de forma no interactiva y retorna
+// el código de salida del programa.
+//
+// Los resultados se escriben en salida (en JSON con --json) y los errores
+// y mensajes informativos del gestor, en errores, de modo que la salida
+// puede procesarse con otros programas. Los cambios se guardan al terminar.
+//
+// Parámetros:
+//   - opciones: las opciones globales indicadas antes del comando
+//   - args: el nombre del comando y sus argumentos
+//   - salida, errores: normalmente os.Stdout y os.Stderr
+//
+// Retorna:
+//   - int: salidaOK o el código del error (ver salidaUso, salidaNoEncontrada, ...)
+//
+// Ejemplo:
+//
+//	// todo list --pending --json
+//	codigo := ejecutarComando(opcionesGlobales{archivo: "tareas.json"}, []string{"list", "--pending", "--json"}, os.Stdout, os.Stderr)
+//	os.Exit(codigo)
+//
+func ejecutarComando(opciones opcionesGlobales, args []string, salida, errores io.Writer) int {
+	nombre := args[0]
+	if nombre == "help" || nombre == "-h" || nombre == "--help" {
+		mostrarAyuda(salida)
+		return salidaOK
+	}
+
+	var cmd *comando
+	for i := range comandos {
+		if comandos[i].nombre == nombre {
+			cmd = &comandos[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(errores, "❌ Comando desconocido %q\n\n", nombre)
+		mostrarAyuda(errores)
+		return salidaUso
+	}
+
+	c := &contextoComando{opciones: opciones, salida: salida}
+	c.flags = flag.NewFlagSet(nombre, flag.ContinueOnError)
+	c.flags.SetOutput(errores)
+	c.flags.Usage = func() {
+		fmt.Fprintf(errores, "Uso: todo %s\n\n%s.\n\nOpciones:\n", cmd.uso, cmd.descripcion)
+		c.flags.PrintDefaults()
+	}
+	c.opciones.registrar(c.flags)
+
+	err := cmd.ejecutar(c, args[1:])
+	if err == nil && c.gestor != nil && c.gestor.TieneCambiosPendientes() {
+		err = c.gestor.Guardar()
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return salidaOK
+	}
+	if err == nil {
+		return salidaOK
+	}
+
+	codigo := codigoSalida(err)
+	if c.opciones.json {
+		json.NewEncoder(errores).Encode(map[string]any{"error": err.Error(), "codigo": codigo})
+	} else {
+		fmt.Fprintf(errores, "❌ Error: %v\n", err)
+	}
+	if codigo == salidaUso {
+		fmt.Fprintf(errores, "Uso: todo %s\n", cmd.uso)
+	}
+	return codigo
+}
+
+// codigoSalida traduce un error al código de salida del programa.
+func codigoSalida(err error) int {
+	var uso *errorUso
+	var noEncontrada *tareas.ErrorNoEncontrada
+	var validacion *tareas.ErrorValidacion
+	var consulta *tareas.ErrorConsulta
+	var bloqueada *tareas.ErrorBloqueada
+	var permiso *tareas.ErrorPermiso
+
+	switch {
+	case errors.As(err, &uso):
+		return salidaUso
+	case errors.As(err, &noEncontrada):
+		return salidaNoEncontrada // También una lista inexistente
+	case errors.As(err, &validacion), errors.As(err, &consulta):
+		return salidaValidacion
+	case errors.Is(err, tareas.ErrTareaYaCompletada), errors.Is(err, tareas.ErrTieneSubtareas), errors.As(err, &bloqueada):
+		return salidaConflicto
+	case errors.As(err, &permiso):
+		return salidaPermiso
+	}
+	return salidaError
+}
+
+// mostrarAyuda escribe la lista de comandos.
+func mostrarAyuda(w io.Writer) {
+	fmt.Fprintln(w, "Uso: todo [--file archivo] [--list lista] [--user usuario] [--json] <comando> [argumentos]")
+	fmt.Fprintln(w, "Sin comando se abre el menú interactivo.")
+	fmt.Fprintln(w, "\nComandos:")
+	for _, cmd := range comandos {
+		fmt.Fprintf(w, "  %-9s %s\n", cmd.nombre, cmd.descripcion)
+	}
+	fmt.Fprintln(w, "\nUsa 'todo <comando> --help' para ver sus opciones.")
+	fmt.Fprintln(w, "Códigos de salida: 0 correcto, 1 error inesperado, 2 uso incorrecto,")
+	fmt.Fprintln(w, "3 tarea o lista inexistente, 4 datos inválidos, 5 conflicto (completada, bloqueada o con subtareas),")
+	fmt.Fprintln(w, "6 sin permiso (la tarea no es del usuario ni la tiene asignada).")
+}
+
+// parsear interpreta las opciones de args, que pueden ir antes, después o
+// entre los argumentos posicionales, y retorna estos últimos. Tras "--"
+// todo es posicional. Comprueba además que haya entre minimo y maximo
+// posicionales (maximo < 0: sin límite).
+func (c *contextoComando) parsear(args []string, minimo, maximo int) ([]string, error) {
+	var posicionales []string
+	for {
+		if err := c.flags.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, &errorUso{err.Error()}
+		}
+		resto := c.flags.Args()
+		if consumidos := len(args) - len(resto); consumidos > 0 && args[consumidos-1] == "--" {
+			posicionales = append(posicionales, resto...)
+			break
+		}
+		if len(resto) == 0 {
+			break
+		}
+		posicionales = append(posicionales, resto[0])
+		args = resto[1:]
+	}
+
+	switch {
+	case len(posicionales) < minimo:
+		return nil, &errorUso{"faltan argumentos"}
+	case maximo >= 0 && len(posicionales) > maximo:
+		return nil, &errorUso{fmt.Sprintf("sobran argumentos: %s", strings.Join(posicionales[maximo:], " "))}
+	}
+	return posicionales, nil
+}
+
+// abrir carga el gestor del archivo indicado con --file, en nombre del
+// usuario de --user, y comprueba que exista la lista activa. Sus mensajes
+// informativos van a la salida de errores, para no mezclarse con los
+// resultados.
+func (c *contextoComando) abrir() (*tareas.GestorTareas, error) {
+	if c.opciones.usuario != "" {
+		if err := tareas.ValidarUsuario(c.opciones.usuario); err != nil {
+			return nil, err
+		}
+	}
+	gestor, err := tareas.NuevoGestorTareas(c.opciones.archivo,
+		tareas.ConDiario(c.opciones.archivo+".diario"),
+		tareas.ConAuditoria(c.opciones.archivo+".auditoria"),
+		tareas.ConSalida(c.flags.Output()),
+		tareas.ConUsuario(c.opciones.usuario))
+	if err != nil {
+		return nil, err
+	}
+	c.gestor = gestor
+	if c.opciones.lista != "" {
+		if _, _, _, err := gestor.EstadisticasLista(c.opciones.lista); err != nil {
+			return nil, err
+		}
+	}
+	return gestor, nil
+}
+
+// resolverID acepta el ID numérico de una tarea o su UID, igual que la API.
+func resolverID(gestor *tareas.GestorTareas, texto string) (int, error) {
+	if id, err := strconv.Atoi(texto); err == nil {
+		return id, nil
+	}
+	tarea, err := gestor.BuscarPorUID(texto)
+	if err != nil {
+		return 0, err
+	}
+	return tarea.ID, nil
+}
+
+// escribirJSON escribe v en la salida como JSON indentado.
+func (c *contextoComando) escribirJSON(v any) error {
+	codificador := json.NewEncoder(c.salida)
+	codificador.SetIndent("", "  ")
+	return codificador.Encode(v)
+}
+
+// comandoAdd crea una tarea: todo add "Comprar pan" --priority alta --due 20/03/2026
+func comandoAdd(c *contextoComando, args []string) error {
+	var datos tareas.DatosTarea
+	var prioridad, vencimiento, etiquetas, asignados string
+	c.flags.StringVar(&datos.Descripcion, "description", "", "descripción")
+	c.flags.StringVar(&prioridad, "priority", "", "prioridad: baja, media, alta o urgente")
+	c.flags.StringVar(&vencimiento, "due", "", "vencimiento: dd/mm/aaaa o aaaa-mm-dd")
+	c.flags.StringVar(&etiquetas, "tags", "", "etiquetas separadas por comas")
+	c.flags.IntVar(&datos.PadreID, "parent", 0, "ID de la tarea padre, para crear una subtarea")
+	c.flags.StringVar(&asignados, "assign", "", "usuarios asignados, separados por comas")
+	posicionales, err := c.parsear(args, 1, -1)
+	if err != nil {
+		return err
+	}
+
+	datos.Titulo = strings.Join(posicionales, " ")
+	datos.Lista = c.opciones.lista
+	datos.Prioridad = tareas.Prioridad(strings.ToLower(prioridad))
+	if datos.FechaVencimiento, err = parsearVencimiento(vencimiento); err != nil {
+		return &errorUso{err.Error()}
+	}
+	if etiquetas != "" {
+		datos.Etiquetas = strings.Split(etiquetas, ",")
+	}
+	if asignados != "" {
+		datos.Asignados = strings.Split(asignados, ",")
+	}
+
+	gestor, err := c.abrir()
+	if err != nil {
+		return err
+	}
+	tarea, err := gestor.CrearConDatos(datos)
+	if err != nil {
+		return err
+	}
+	if c.opciones.json {
+		return c.escribirJSON(tarea)
+	}
+	fmt.Fprintf(c.salida, "✅ Tarea creada con ID: %d\n", tarea.ID)
+	return nil
+}
+
+// comandoList lista las tareas con filtros, orden y límite:
+// todo list --pending --tag trabajo --sort -prioridad --limit 5
+func comandoList(c *contextoComando, args []string) error {
+	var pendientes, completadas, vencidas, siguientes, mias bool
+	var etiqueta, consulta, orden, propietario, asignada string
+	var limite int
+	c.flags.BoolVar(&pendientes, "pending", false, "solo las pendientes")
+	c.flags.BoolVar(&completadas, "completed", false, "solo las completadas")
+	c.flags.BoolVar(&vencidas, "overdue", false, "solo las vencidas")
+	c.flags.BoolVar(&siguientes, "next", false, "las pendientes en orden de dependencias")
+	c.flags.BoolVar(&mias, "mine", false, "solo las del usuario actual o asignadas a él")
+	c.flags.StringVar(&propietario, "owner", "", "solo las de ese propietario")
+	c.flags.StringVar(&asignada, "assigned", "", "solo las asignadas a ese usuario")
+	c.flags.StringVar(&etiqueta, "tag", "", "solo las que tienen la etiqueta")
+	c.flags.StringVar(&consulta, "query", "", "consulta con el lenguaje de búsqueda (p. ej. 'prioridad:>=alta -tag:casa')")
+	c.flags.StringVar(&orden, "sort", "", "campos de orden separados por comas, '-' para descendente")
+	c.flags.IntVar(&limite, "limit", 0, "cantidad máxima de tareas (0: todas)")
+	if _, err := c.parsear(args, 0, 0); err != nil {
+		return err
+	}
+
+	filtros := 0
+	for _, activo := range []bool{pendientes, completadas, vencidas, siguientes} {
+		if activo {
+			filtros++
+		}
+	}
+	if filtros > 1 {
+		return &errorUso{"usa solo una de --pending, --completed, --overdue y --next"}
+	}
+	if mias && c.opciones.usuario == "" {
+		return &errorUso{"--mine necesita un usuario: usa --user o la variable " + variableUsuario}
+	}
+	criterios, err := tareas.ParsearOrden(orden)
+	if err != nil {
+		return &errorUso{err.Error()}
+	}
+
+	gestor, err := c.abrir()
+	if err != nil {
+		return err
+	}
+	var lista []tareas.Tarea
+	titulo := "📋 TODAS LAS TAREAS"
+	switch {
+	case pendientes:
+		lista, titulo = gestor.ListarPendientes(), "⬜ TAREAS PENDIENTES"
+	case completadas:
+		lista, titulo = gestor.ListarCompletadas(), "✅ TAREAS COMPLETADAS"
+	case vencidas:
+		lista, titulo = gestor.ListarVencidas(), "⏰ TAREAS VENCIDAS"
+	case siguientes:
+		lista, titulo = gestor.ListarSiguientes(), "⏭️  SIGUIENTES TAREAS"
+	default:
+		lista = gestor.Listar()
+	}
+	lista = filtrarPorLista(lista, c.opciones.lista)
+	if mias || propietario != "" || asignada != "" {
+		var filtradas []tareas.Tarea
+		for _, tarea := range lista {
+			if mias && tarea.Propietario != gestor.Usuario() && !tarea.EstaAsignada(gestor.Usuario()) {
+				continue
+			}
+			if propietario != "" && tarea.Propietario != strings.ToLower(propietario) {
+				continue
+			}
+			if asignada != "" && !tarea.EstaAsignada(asignada) {
+				continue
+			}
+			filtradas = append(filtradas, tarea)
+		}
+		lista = filtradas
+	}
+	if consulta != "" {
+		c, err := tareas.ParsearConsulta(consulta)
+		if err != nil {
+			return err
+		}
+		lista = gestor.Filtrar(lista, c)
+	}
+	if etiqueta != "" {
+		var filtradas []tareas.Tarea
+		for _, tarea := range lista {
+			if tarea.TieneEtiqueta(etiqueta) {
+				filtradas = append(filtradas, tarea)
+			}
+		}
+		lista = filtradas
+	}
+
+	// --next ya viene en orden de dependencias: solo se reordena si se pide
+	if len(criterios) > 0 || !siguientes {
+		tareas.Ordenar(lista, criterios)
+	}
+	if limite < 0 {
+		return &errorUso{"--limit no puede ser negativo"}
+	}
+	if limite > 0 && len(lista) > limite {
+		lista = lista[:limite]
+	}
+
+	if c.opciones.json {
+		if lista == nil {
+			lista = []tareas.Tarea{}
+		}
+		return c.escribirJSON(lista)
+	}
+	MostrarTareas(gestor, lista, titulo)
+	return nil
+}
+
+// comandoShow muestra una tarea: todo show 3
+func comandoShow(c *contextoComando, args []string) error {
+	posicionales, err := c.parsear(args, 1, 1)
+	if err != nil {
+		return err
+	}
+	gestor, err := c.abrir()
+	if err != nil {
+		return err
+	}
+	id, err := resolverID(gestor, posicionales[0])
+	if err != nil {
+		return err
+	}
+	tarea, err := gestor.BuscarPorID(id)
+	if err != nil {
+		return err
+	}
+	if c.opciones.json {
+		return c.escribirJSON(tarea)
+	}
+	mostrarTarea(gestor, *tarea, 0, false)
+	return nil
+}
+
+// comandoDone completa una o varias tareas: todo done 3 4. Si alguna
+// falla, las anteriores quedan completadas y se guardan.
+func comandoDone(c *contextoComando, args []string) error {
+	posicionales, err := c.parsear(args, 1, -1)
+	if err != nil {
+		return err
+	}
+	gestor, err := c.abrir()
+	if err != nil {
+		return err
+	}
+
+	var completadas []tareas.Tarea
+	defer func() {
+		if c.opciones.json && len(completadas) > 0 {
+			c.escribirJSON(completadas)
+		}
+	}()
+	for _, texto := range posicionales {
+		id, err := resolverID(gestor, texto)
+		if err != nil {
+			return guardarAntesDeFallar(gestor, err)
+		}
+		siguiente, err := gestor.CompletarConSiguiente(id)
+		if err != nil {
+			return guardarAntesDeFallar(gestor, err)
+		}
+		tarea, _ := gestor.BuscarPorID(id)
+		completadas = append(completadas, *tarea)
+		if !c.opciones.json {
+			fmt.Fprintf(c.salida, "✅ Tarea %d marcada como completada\n", id)
+			if siguiente != nil {
+				fmt.Fprintf(c.salida, "🔁 Próxima ocurrencia: [%d] vence el %s\n", siguiente.ID, siguiente.FechaVencimiento.Format("02/01/2006 15:04"))
+			}
+		}
+	}
+	return nil
+}
+
+// comandoRm mueve una o varias tareas a la papelera: todo rm 3. Las tareas
+// con subtareas solo se eliminan con --cascade, junto con todo su árbol.
+func comandoRm(c *contextoComando, args []string) error {
+	var cascada bool
+	c.flags.BoolVar(&cascada, "cascade", false, "eliminar también las subtareas")
+	posicionales, err := c.parsear(args, 1, -1)
+	if err != nil {
+		return err
+	}
+	gestor, err := c.abrir()
+	if err != nil {
+		return err
+	}
+
+	eliminadas := 0
+	defer func() {
+		if c.opciones.json && eliminadas > 0 {
+			c.escribirJSON(map[string]int{"eliminadas": eliminadas})
+		}
+	}()
+	for _, texto := range posicionales {
+		id, err := resolverID(gestor, texto)
+		if err != nil {
+			return guardarAntesDeFallar(gestor, err)
+		}
+		n := 1
+		if cascada {
+			n, err = gestor.EliminarConSubtareas(id)
+		} else {
+			err = gestor.Eliminar(id)
+		}
+		if err != nil {
+			return guardarAntesDeFallar(gestor, err)
+		}
+		eliminadas += n
+		if !c.opciones.json {
+			fmt.Fprintf(c.salida, "🗑️  %d tarea(s) movida(s) a la papelera\n", n)
+		}
+	}
+	return nil
+}
+
+// comandoSearch busca por relevancia: todo search matematicas
+func comandoSearch(c *contextoComando, args []string) error {
+	var tolerancia int
+	c.flags.IntVar(&tolerancia, "tolerance", tareas.ToleranciaPorDefecto, "errores de escritura admitidos por palabra")
+	posicionales, err := c.parsear(args, 1, -1)
+	if err != nil {
+		return err
+	}
+	if tolerancia < 0 {
+		return &errorUso{"--tolerance no puede ser negativa"}
+	}
+	gestor, err := c.abrir()
+	if err != nil {
+		return err
+	}
+
+	texto := strings.Join(posicionales, " ")
+	resultados := filtrarResultadosPorLista(gestor.BuscarPorRelevancia(texto, tolerancia), c.opciones.lista)
+	if c.opciones.json {
+		if resultados == nil {
+			resultados = []tareas.ResultadoBusqueda{}
+		}
+		return c.escribirJSON(resultados)
+	}
+	MostrarResultados(gestor, resultados, fmt.Sprintf("🔍 RESULTADOS POR RELEVANCIA (%s)", texto))
+	return nil
+}
+
+// comandoStats muestra las estadísticas: todo stats --json
+// Con --list, solo las de esa lista.
+func comandoStats(c *contextoComando, args []string) error {
+	if _, err := c.parsear(args, 0, 0); err != nil {
+		return err
+	}
+	gestor, err := c.abrir()
+	if err != nil {
+		return err
+	}
+
+	total, completadas, pendientes := gestor.Estadisticas()
+	if c.opciones.lista != "" {
+		total, completadas, pendientes, _ = gestor.EstadisticasLista(c.opciones.lista)
+	}
+	vencidas := len(filtrarPorLista(gestor.ListarVencidas(), c.opciones.lista))
+	if c.opciones.json {
+		return c.escribirJSON(map[string]int{
+			"total":       total,
+			"completadas": completadas,
+			"pendientes":  pendientes,
+			"vencidas":    vencidas,
+		})
+	}
+	if c.opciones.lista != "" {
+		fmt.Fprintf(c.salida, "📂 Lista: %s\n", strings.ToLower(c.opciones.lista))
+	}
+	fmt.Fprintln(c.salida, "📊 Estadísticas:")
+	fmt.Fprintf(c.salida, "   Total: %d tarea(s)\n", total)
+	fmt.Fprintf(c.salida, "   ✅ Completadas: %d\n", completadas)
+	fmt.Fprintf(c.salida, "   ⬜ Pendientes: %d\n", pendientes)
+	fmt.Fprintf(c.salida, "   ⏰ Vencidas: %d\n", vencidas)
+	return nil
+}
+
+// comandoExport exporta las tareas (las de la lista activa, con --list):
+// todo export --format ical --output tareas.ics
+// Sin --output el archivo se escribe en la salida estándar.
+func comandoExport(c *contextoComando, args []string) error {
+	var nombreFormato, ruta string
+	c.flags.StringVar(&nombreFormato, "format", "", "csv, markdown, todotxt o ical (por defecto, según la extensión de --output)")
+	c.flags.StringVar(&ruta, "output", "", "archivo de destino (por defecto, la salida estándar)")
+	if _, err := c.parsear(args, 0, 0); err != nil {
+		return err
+	}
+	if nombreFormato == "" {
+		nombreFormato = ruta
+	}
+	formato, err := tareas.ParsearFormato(nombreFormato)
+	if err != nil {
+		return &errorUso{err.Error()}
+	}
+	gestor, err := c.abrir()
+	if err != nil {
+		return err
+	}
+
+	lista := filtrarPorLista(gestor.Listar(), c.opciones.lista)
+	if ruta == "" {
+		return tareas.Exportar(c.salida, lista, formato)
+	}
+	archivo, err := os.Create(ruta)
+	if err != nil {
+		return err
+	}
+	err = tareas.Exportar(archivo, lista, formato)
+	if errCerrar := archivo.Close(); err == nil {
+		err = errCerrar
+	}
+	if err != nil {
+		return err
+	}
+	if !c.opciones.json {
+		fmt.Fprintf(c.salida, "✅ %d tarea(s) exportadas a %s\n", len(lista), ruta)
+	}
+	return nil
+}
+
+// comandoImport importa un archivo: todo import --dry-run tareas.csv
+// Con "-" como archivo lee la entrada estándar (requiere --format).
+func comandoImport(c *contextoComando, args []string) error {
+	var nombreFormato string
+	var simular bool
+	c.flags.StringVar(&nombreFormato, "format", "", "csv, markdown, todotxt o ical (por defecto, según la extensión)")
+	c.flags.BoolVar(&simular, "dry-run", false, "mostrar lo que se importaría sin crear nada")
+	posicionales, err := c.parsear(args, 1, 1)
+	if err != nil {
+		return err
+	}
+	ruta := posicionales[0]
+	if nombreFormato == "" {
+		nombreFormato = ruta
+	}
+	formato, err := tareas.ParsearFormato(nombreFormato)
+	if err != nil {
+		return &errorUso{err.Error()}
+	}
+
+	entrada := io.Reader(os.Stdin)
+	if ruta != "-" {
+		archivo, err := os.Open(ruta)
+		if err != nil {
+			return err
+		}
+		defer archivo.Close()
+		entrada = archivo
+	}
+	gestor, err := c.abrir()
+	if err != nil {
+		return err
+	}
+
+	informe, err := gestor.Importar(entrada, formato, simular)
+	if err != nil {
+		return err
+	}
+	if c.opciones.json {
+		return c.escribirJSON(informe)
+	}
+	MostrarInforme(informe)
+	return nil
+}
+
+// comandoLists muestra las listas con sus recuentos (todo lists) o las
+// crea, renombra y elimina: todo lists add trabajo, todo lists rename
+// trabajo oficina, todo lists rm oficina. Al eliminar una lista sus tareas
+// pasan a la lista por defecto.
+func comandoLists(c *contextoComando, args []string) error {
+	posicionales, err := c.parsear(args, 0, 3)
+	if err != nil {
+		return err
+	}
+
+	// Estos comandos no trabajan dentro de la lista activa
+	c.opciones.lista = ""
+	accion := ""
+	if len(posicionales) > 0 {
+		accion, posicionales = posicionales[0], posicionales[1:]
+	}
+	argumentos := map[string]int{"": 0, "add": 1, "rename": 2, "rm": 1}
+	esperados, ok := argumentos[accion]
+	switch {
+	case !ok:
+		return &errorUso{fmt.Sprintf("acción desconocida %q: usa add, rename o rm", accion)}
+	case len(posicionales) != esperados:
+		return &errorUso{fmt.Sprintf("%q necesita %d argumento(s)", accion, esperados)}
+	}
+
+	gestor, err := c.abrir()
+	if err != nil {
+		return err
+	}
+	switch accion {
+	case "add":
+		err = gestor.CrearLista(posicionales[0])
+	case "rename":
+		err = gestor.RenombrarLista(posicionales[0], posicionales[1])
+	case "rm":
+		var movidas int
+		if movidas, err = gestor.EliminarLista(posicionales[0]); err == nil && !c.opciones.json {
+			fmt.Fprintf(c.salida, "🗑️  Lista eliminada; %d tarea(s) pasaron a '%s'\n", movidas, tareas.ListaPorDefecto)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.opciones.json {
+		return c.escribirJSON(gestor.Listas())
+	}
+	switch accion {
+	case "":
+		for _, lista := range gestor.Listas() {
+			fmt.Fprintf(c.salida, "📂 %-20s %d total | %d completadas | %d pendientes\n", lista.Nombre, lista.Total, lista.Completadas, lista.Pendientes)
+		}
+	case "add":
+		fmt.Fprintf(c.salida, "✅ Lista '%s' creada\n", strings.ToLower(posicionales[0]))
+	case "rename":
+		fmt.Fprintf(c.salida, "✅ Lista '%s' renombrada a '%s'\n", strings.ToLower(posicionales[0]), strings.ToLower(posicionales[1]))
+	}
+	return nil
+}
+
+// comandoMove mueve tareas de primer nivel, con sus subtareas, a otra
+// lista: todo move casa 3 4
+func comandoMove(c *contextoComando, args []string) error {
+	posicionales, err := c.parsear(args, 2, -1)
+	if err != nil {
+		return err
+	}
+	c.opciones.lista = ""
+	gestor, err := c.abrir()
+	if err != nil {
+		return err
+	}
+
+	destino := posicionales[0]
+	var movidas []tareas.Tarea
+	defer func() {
+		if c.opciones.json && len(movidas) > 0 {
+			c.escribirJSON(movidas)
+		}
+	}()
+	for _, texto := range posicionales[1:] {
+		id, err := resolverID(gestor, texto)
+		if err != nil {
+			return guardarAntesDeFallar(gestor, err)
+		}
+		if err := gestor.MoverALista(id, destino); err != nil {
+			return guardarAntesDeFallar(gestor, err)
+		}
+		tarea, _ := gestor.BuscarPorID(id)
+		movidas = append(movidas, *tarea)
+		if !c.opciones.json {
+			fmt.Fprintf(c.salida, "📂 Tarea %d movida a '%s'\n", id, tarea.NombreLista())
+		}
+	}
+	return nil
+}
+
+// comandoAssign asigna una tarea a otros usuarios, que desde entonces
+// pueden modificarla: todo assign 3 luis marta. Solo puede hacerlo el
+// propietario de la tarea.
+func comandoAssign(c *contextoComando, args []string) error {
+	return cambiarAsignados(c, args, (*tareas.GestorTareas).Asignar)
+}
+
+// comandoUnassign quita usuarios de los asignados de una tarea:
+// todo unassign 3 marta
+func comandoUnassign(c *contextoComando, args []string) error {
+	return cambiarAsignados(c, args, (*tareas.GestorTareas).Desasignar)
+}
+
+// cambiarAsignados interpreta los argumentos de assign y unassign, aplica
+// cambiar sobre la tarea e informa de cómo quedaron sus asignados.
+func cambiarAsignados(c *contextoComando, args []string, cambiar func(*tareas.GestorTareas, int, ...string) (*tareas.Tarea, error)) error {
+	posicionales, err := c.parsear(args, 2, -1)
+	if err != nil {
+		return err
+	}
+	gestor, err := c.abrir()
+	if err != nil {
+		return err
+	}
+	id, err := resolverID(gestor, posicionales[0])
+	if err != nil {
+		return err
+	}
+	tarea, err := cambiar(gestor, id, posicionales[1:]...)
+	if err != nil {
+		return err
+	}
+
+	if c.opciones.json {
+		return c.escribirJSON(tarea)
+	}
+	asignados := "nadie"
+	if len(tarea.Asignados) > 0 {
+		asignados = strings.Join(tarea.Asignados, ", ")
+	}
+	fmt.Fprintf(c.salida, "👥 Tarea %d asignada a: %s\n", tarea.ID, asignados)
+	return nil
+}
+
+// comandoHistory muestra los cambios de una tarea, aunque esté en la
+// papelera o ya se haya purgado: todo history 3
+func comandoHistory(c *contextoComando, args []string) error {
+	posicionales, err := c.parsear(args, 1, 1)
+	if err != nil {
+		return err
+	}
+	gestor, err := c.abrir()
+	if err != nil {
+		return err
+	}
+	id, err := resolverID(gestor, posicionales[0])
+	if err != nil {
+		return err
+	}
+	eventos := gestor.AuditoriaTarea(id)
+	if len(eventos) == 0 {
+		return &tareas.ErrorNoEncontrada{ID: id}
+	}
+
+	if c.opciones.json {
+		return c.escribirJSON(eventos)
+	}
+	MostrarEventos(eventos, fmt.Sprintf("🕓 HISTORIAL DE LA TAREA %d", id))
+	return nil
+}
+
+// comandoAudit muestra todos los cambios registrados (todo audit) o los
+// exporta: todo audit --format csv --output auditoria.csv. Con --by solo
+// los hechos por un usuario.
+func comandoAudit(c *contextoComando, args []string) error {
+	var nombreFormato, ruta, autor string
+	c.flags.StringVar(&nombreFormato, "format", "", "csv o markdown (por defecto, según la extensión de --output; sin ninguno, se muestran)")
+	c.flags.StringVar(&ruta, "output", "", "archivo de destino (por defecto, la salida estándar)")
+	c.flags.StringVar(&autor, "by", "", "solo los cambios de este usuario")
+	if _, err := c.parsear(args, 0, 0); err != nil {
+		return err
+	}
+	if nombreFormato == "" {
+		nombreFormato = ruta
+	}
+	var formato tareas.Formato
+	if nombreFormato != "" {
+		var err error
+		if formato, err = tareas.ParsearFormato(nombreFormato); err != nil {
+			return &errorUso{err.Error()}
+		}
+	}
+	gestor, err := c.abrir()
+	if err != nil {
+		return err
+	}
+
+	eventos := gestor.Auditoria()
+	if autor != "" {
+		autor = strings.ToLower(strings.TrimSpace(autor))
+		var filtrados []tareas.Evento
+		for _, evento := range eventos {
+			if evento.Usuario == autor {
+				filtrados = append(filtrados, evento)
+			}
+		}
+		eventos = filtrados
+	}
+
+	switch {
+	case formato == "" && c.opciones.json:
+		if eventos == nil {
+			eventos = []tareas.Evento{}
+		}
+		return c.escribirJSON(eventos)
+	case formato == "":
+		MostrarEventos(eventos, "🕓 AUDITORÍA")
+		return nil
+	case ruta == "":
+		return tareas.ExportarAuditoria(c.salida, eventos, formato)
+	}
+	archivo, err := os.Create(ruta)
+	if err != nil {
+		return err
+	}
+	err = tareas.ExportarAuditoria(archivo, eventos, formato)
+	if errCerrar := archivo.Close(); err == nil {
+		err = errCerrar
+	}
+	if err != nil {
+		return err
+	}
+	if !c.opciones.json {
+		fmt.Fprintf(c.salida, "✅ %d evento(s) exportados a %s\n", len(eventos), ruta)
+	}
+	return nil
+}
+
+// comandoReport muestra el informe de productividad (el de la lista activa,
+// con --list): todo report --days 7 --weeks 4 --json
+func comandoReport(c *contextoComando, args []string) error {
+	p := tareas.ParametrosInforme{Lista: c.opciones.lista}
+	c.flags.IntVar(&p.Dias, "days", tareas.DiasInformePorDefecto, "días del rendimiento diario, hasta hoy")
+	c.flags.IntVar(&p.Semanas, "weeks", tareas.SemanasInformePorDefecto, "semanas del rendimiento semanal, hasta la actual")
+	if _, err := c.parsear(args, 0, 0); err != nil {
+		return err
+	}
+	if p.Dias <= 0 || p.Semanas <= 0 {
+		return &errorUso{"--days y --weeks deben ser mayores que 0"}
+	}
+	gestor, err := c.abrir()
+	if err != nil {
+		return err
+	}
+
+	informe, err := gestor.InformeProductividad(p)
+	if err != nil {
+		return err
+	}
+	if c.opciones.json {
+		return c.escribirJSON(informe)
+	}
+	if c.opciones.lista != "" {
+		fmt.Fprintf(c.salida, "📂 Lista: %s\n", strings.ToLower(c.opciones.lista))
+	}
+	MostrarProductividad(c.salida, informe)
+	return nil
+}
+
+// guardarAntesDeFallar guarda lo hecho por un comando con varios
+// argumentos antes de informar del error en uno de ellos, y retorna ese
+// error.
+func guardarAntesDeFallar(gestor *tareas.GestorTareas, err error) error {
+	if errGuardar := gestor.Guardar(); errGuardar != nil {
+		return errGuardar
+	}
+	return err
+}
+
+// parsearVencimiento interpreta una fecha de vencimiento dd/mm/aaaa o
+// aaaa-mm-dd ("" si no hay). La tarea vence al final del día indicado,
+// también si ese día dura 23 o 25 horas por un cambio de horario.
+func parsearVencimiento(texto string) (time.Time, error) {
+	if texto == "" {
+		return time.Time{}, nil
+	}
+	dia, err := time.ParseInLocation("02/01/2006", texto, time.Local)
+	if err != nil {
+		if dia, err = time.ParseInLocation(time.DateOnly, texto, time.Local); err != nil {
+			return time.Time{}, fmt.Errorf("fecha de vencimiento inválida %q, usa dd/mm/aaaa", texto)
+		}
+	}
+	return tareas.FinDelDia(dia), nil
+}
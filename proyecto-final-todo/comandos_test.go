@@ -0,0 +1,223 @@
+// Tests de los subcomandos de la CLI
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cristianjonhson/GO-API/proyecto-final-todo/tareas"
+)
+
+// ejecutar corre un subcomando sobre el archivo de tareas con las opciones
+// globales dadas y retorna su código de salida, su salida y sus errores
+func ejecutar(t *testing.T, opciones opcionesGlobales, args ...string) (int, string, string) {
+	t.Helper()
+	var salida, errores bytes.Buffer
+	codigo := ejecutarComando(opciones, args, &salida, &errores)
+	return codigo, salida.String(), errores.String()
+}
+
+// archivoConTareas retorna un archivo de tareas nuevo con:
+//   - 1 "Preparar informe" de ana, con la subtarea 2
+//   - 3 "Comprar pan", sin propietario y completada
+func archivoConTareas(t *testing.T) string {
+	t.Helper()
+	archivo := filepath.Join(t.TempDir(), "tareas.json")
+	ana := opcionesGlobales{archivo: archivo, usuario: "ana"}
+	for _, args := range [][]string{
+		{"add", "Preparar informe"},
+		{"add", "--parent", "1", "Revisar cifras"},
+		{"add", "--user", "", "Comprar pan"},
+		{"done", "3"},
+	} {
+		if codigo, _, errores := ejecutar(t, ana, args...); codigo != salidaOK {
+			t.Fatalf("Error al preparar %v: %d %s", args, codigo, errores)
+		}
+	}
+	return archivo
+}
+
+// TestCodigosDeSalida verifica el código de salida de cada clase de error
+func TestCodigosDeSalida(t *testing.T) {
+	tests := []struct {
+		nombre  string
+		usuario string
+		args    []string
+		codigo  int
+	}{
+		{"mostrar", "", []string{"show", "1", "--json"}, salidaOK},
+		{"ayuda del comando", "", []string{"show", "--help"}, salidaOK},
+		{"archivo a importar inexistente", "", []string{"import", "no-existe.csv"}, salidaError},
+		{"comando desconocido", "", []string{"volar"}, salidaUso},
+		{"faltan argumentos", "", []string{"show"}, salidaUso},
+		{"opción desconocida", "", []string{"list", "--todas"}, salidaUso},
+		{"filtros incompatibles", "", []string{"list", "--pending", "--completed"}, salidaUso},
+		{"tarea inexistente", "", []string{"show", "99"}, salidaNoEncontrada},
+		{"UID inexistente", "", []string{"done", "uid-desconocido"}, salidaNoEncontrada},
+		{"lista inexistente", "", []string{"stats", "--list", "ocio"}, salidaNoEncontrada},
+		{"prioridad inválida", "", []string{"add", "Otra", "--priority", "altísima"}, salidaValidacion},
+		{"consulta inválida", "", []string{"list", "--query", "prioridad:>>", "--json"}, salidaValidacion},
+		{"ya completada", "", []string{"done", "3"}, salidaConflicto},
+		{"con subtareas", "", []string{"rm", "1"}, salidaConflicto},
+		{"tarea ajena", "luis", []string{"done", "1"}, salidaPermiso},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			opciones := opcionesGlobales{archivo: archivoConTareas(t), usuario: tt.usuario}
+			if codigo, _, errores := ejecutar(t, opciones, tt.args...); codigo != tt.codigo {
+				t.Errorf("Código = %d, se esperaba %d (errores: %s)", codigo, tt.codigo, errores)
+			}
+		})
+	}
+}
+
+// TestSalidaJSON verifica la forma de la salida con --json, incluido el
+// error de un comando que falla a mitad de sus argumentos
+func TestSalidaJSON(t *testing.T) {
+	tests := []struct {
+		nombre string
+		args   []string
+		codigo int
+		claves []string // claves del objeto, o del primer elemento si es un array
+		array  bool
+	}{
+		{"add", []string{"add", "Llamar a Luis"}, salidaOK, []string{"id", "titulo", "completada", "fecha_creacion", "propietario"}, false},
+		{"show", []string{"show", "2"}, salidaOK, []string{"id", "titulo", "completada", "fecha_creacion", "padre_id", "propietario"}, false},
+		{"list", []string{"list", "--completed"}, salidaOK, []string{"id", "titulo", "completada", "fecha_creacion", "fecha_completada", "fecha_actualizacion"}, true},
+		{"stats", []string{"stats"}, salidaOK, []string{"total", "completadas", "pendientes", "vencidas"}, false},
+		{"lists", []string{"lists"}, salidaOK, []string{"nombre", "total", "completadas", "pendientes"}, true},
+		{"rm", []string{"rm", "--cascade", "1"}, salidaOK, []string{"eliminadas"}, false},
+		{"done parcial", []string{"done", "2", "99"}, salidaNoEncontrada, []string{"id", "titulo", "completada", "fecha_creacion", "fecha_completada", "fecha_actualizacion", "padre_id", "propietario"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			archivo := archivoConTareas(t)
+			opciones := opcionesGlobales{archivo: archivo, json: true, usuario: "ana"}
+			codigo, salida, errores := ejecutar(t, opciones, tt.args...)
+			if codigo != tt.codigo {
+				t.Fatalf("Código = %d, se esperaba %d (errores: %s)", codigo, tt.codigo, errores)
+			}
+
+			var objeto map[string]any
+			if tt.array {
+				var elementos []map[string]any
+				if err := json.Unmarshal([]byte(salida), &elementos); err != nil || len(elementos) == 0 {
+					t.Fatalf("Se esperaba un array no vacío: %v\n%s", err, salida)
+				}
+				objeto = elementos[0]
+			} else if err := json.Unmarshal([]byte(salida), &objeto); err != nil {
+				t.Fatalf("Se esperaba un objeto: %v\n%s", err, salida)
+			}
+			var claves []string
+			for clave := range objeto {
+				claves = append(claves, clave)
+			}
+			slices.Sort(claves)
+			esperadas := slices.Sorted(slices.Values(tt.claves))
+			if !reflect.DeepEqual(claves, esperadas) {
+				t.Errorf("Claves = %v, se esperaban %v", claves, esperadas)
+			}
+
+			if tt.codigo == salidaOK {
+				return
+			}
+
+			// El error es la última línea de errores, tras los mensajes del gestor
+			lineas := strings.Split(strings.TrimSpace(errores), "\n")
+			var respuesta struct {
+				Error  string `json:"error"`
+				Codigo int    `json:"codigo"`
+			}
+			if err := json.Unmarshal([]byte(lineas[len(lineas)-1]), &respuesta); err != nil || respuesta.Codigo != tt.codigo || respuesta.Error == "" {
+				t.Errorf("Error JSON inesperado: %v %q", err, errores)
+			}
+
+			// Lo hecho antes del error queda guardado
+			_, salida, _ = ejecutar(t, opciones, "show", "2")
+			var tarea tareas.Tarea
+			if err := json.Unmarshal([]byte(salida), &tarea); err != nil || !tarea.Completada {
+				t.Errorf("La tarea 2 debería seguir completada: %v %s", err, salida)
+			}
+		})
+	}
+
+	// Un listado vacío es [] y no null
+	archivo := filepath.Join(t.TempDir(), "vacio.json")
+	if _, salida, _ := ejecutar(t, opcionesGlobales{archivo: archivo, json: true}, "list"); strings.TrimSpace(salida) != "[]" {
+		t.Errorf("Listado vacío = %q, se esperaba []", salida)
+	}
+}
+
+// TestOpcionesEnCualquierPosicion verifica que las opciones, incluidas las
+// globales, puedan ir antes, después o entre los argumentos, y que tras
+// "--" todo sea posicional
+func TestOpcionesEnCualquierPosicion(t *testing.T) {
+	tests := []struct {
+		nombre    string
+		opciones  opcionesGlobales
+		args      []string
+		titulo    string
+		prioridad tareas.Prioridad
+	}{
+		{"opciones antes", opcionesGlobales{}, []string{"add", "--json", "--priority", "alta", "Comprar", "pan"}, "Comprar pan", tareas.PrioridadAlta},
+		{"opciones entre argumentos", opcionesGlobales{}, []string{"add", "Comprar", "--priority=alta", "pan", "--json"}, "Comprar pan", tareas.PrioridadAlta},
+		{"opciones después", opcionesGlobales{}, []string{"add", "Comprar", "pan", "--priority", "alta", "--json"}, "Comprar pan", tareas.PrioridadAlta},
+		{"global antes del comando", opcionesGlobales{json: true}, []string{"add", "Comprar", "pan"}, "Comprar pan", ""},
+		{"tras --", opcionesGlobales{}, []string{"add", "--json", "--", "--priority", "alta"}, "--priority alta", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			tt.opciones.archivo = filepath.Join(t.TempDir(), "tareas.json")
+			codigo, salida, errores := ejecutar(t, tt.opciones, tt.args...)
+			if codigo != salidaOK {
+				t.Fatalf("Código = %d: %s", codigo, errores)
+			}
+			var tarea tareas.Tarea
+			if err := json.Unmarshal([]byte(salida), &tarea); err != nil {
+				t.Fatalf("Salida JSON inválida: %v\n%s", err, salida)
+			}
+			if tarea.Titulo != tt.titulo || tarea.Prioridad != tt.prioridad {
+				t.Errorf("Tarea = %q (%q), se esperaba %q (%q)", tarea.Titulo, tarea.Prioridad, tt.titulo, tt.prioridad)
+			}
+		})
+	}
+}
+
+// TestParsearVencimiento verifica los formatos admitidos y que la tarea
+// venza a las 23:59:59, también los días de cambio de horario
+func TestParsearVencimiento(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skipf("Sin datos de zonas horarias: %v", err)
+	}
+	local := time.Local
+	time.Local = madrid
+	t.Cleanup(func() { time.Local = local })
+
+	tests := []struct {
+		texto    string
+		esperado time.Time
+	}{
+		{"", time.Time{}},
+		{"15/06/2026", time.Date(2026, 6, 15, 23, 59, 59, 0, madrid)},
+		{"2026-06-15", time.Date(2026, 6, 15, 23, 59, 59, 0, madrid)},
+		{"29/03/2026", time.Date(2026, 3, 29, 23, 59, 59, 0, madrid)},  // día de 23 horas
+		{"25/10/2026", time.Date(2026, 10, 25, 23, 59, 59, 0, madrid)}, // día de 25 horas
+	}
+	for _, tt := range tests {
+		vencimiento, err := parsearVencimiento(tt.texto)
+		if err != nil || !vencimiento.Equal(tt.esperado) {
+			t.Errorf("parsearVencimiento(%q) = %v, %v; se esperaba %v", tt.texto, vencimiento, err, tt.esperado)
+		}
+	}
+	if _, err := parsearVencimiento("31/02/2026"); err == nil {
+		t.Error("Se esperaba error con una fecha inexistente")
+	}
+}
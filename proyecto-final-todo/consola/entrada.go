@@ -0,0 +1,286 @@
+// Package consola implementa la entrada y la interfaz de terminal de la CLI
+// de tareas.
+//
+// Entrada lee líneas completas, con sus espacios, de cualquier io.Reader.
+// Si la entrada es un terminal, las líneas se editan en modo crudo: flechas
+// para mover el cursor y recorrer el historial, Inicio/Fin, Supr y los
+// atajos habituales (Ctrl-A, Ctrl-E, Ctrl-U, Ctrl-K, Ctrl-W). Con una
+// tubería o un archivo se leen tal cual, lo que permite probar la CLI con
+// una entrada preparada.
+//
+// Interfaz muestra las tareas a pantalla completa y se maneja con el
+// teclado: moverse por la lista, completar con la barra espaciadora,
+// editar el título en línea, crear, eliminar y deshacer.
+package consola
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// maxHistorial es la cantidad de líneas que recuerda el historial.
+const maxHistorial = 100
+
+// Entrada lee líneas de la entrada y escribe las indicaciones en la salida.
+//
+// Todas las lecturas de un programa deben pasar por la misma Entrada: el
+// lector guarda en su búfer lo que ya se leyó de r pero aún no se consumió.
+type Entrada struct {
+	lector    *bufio.Reader
+	salida    io.Writer
+	terminal  *terminal // nil si r no es un terminal
+	editar    bool      // Editar las líneas en modo crudo
+	historial []string
+	cerrada   bool
+}
+
+// NuevaEntrada crea una entrada que lee de r y escribe en w.
+//
+// Si r es un terminal, las líneas se editan en modo crudo; si no, se leen
+// hasta el salto de línea sin eco, como las escribe el programa que envía
+// los datos.
+//
+// Parámetros:
+//   - r: de dónde leer, normalmente os.Stdin
+//   - w: dónde escribir las indicaciones y la edición, normalmente os.Stdout
+//
+// Retorna:
+//   - *Entrada: la entrada lista para usarse
+//
+// Ejemplo:
+//
+//	entrada := consola.NuevaEntrada(os.Stdin, os.Stdout)
+//	titulo := entrada.LeerLinea("📝 Título de la tarea: ")
+//
+func NuevaEntrada(r io.Reader, w io.Writer) *Entrada {
+	e := &Entrada{lector: bufio.NewReader(r), salida: w}
+	if f, ok := r.(*os.File); ok {
+		e.terminal = abrirTerminal(f)
+		e.editar = e.terminal != nil
+	}
+	return e
+}
+
+// Salida retorna dónde escribe la entrada.
+func (e *Entrada) Salida() io.Writer {
+	return e.salida
+}
+
+// Cerrada indica si la entrada terminó (fin de archivo o Ctrl-D en una
+// línea vacía). A partir de entonces todas las lecturas retornan "".
+func (e *Entrada) Cerrada() bool {
+	return e.cerrada
+}
+
+// LeerLinea muestra la indicación y lee una línea completa, sin los
+// espacios de los extremos.
+//
+// Las líneas no vacías se añaden al historial, que se recorre con las
+// flechas arriba y abajo. Ctrl-C o Esc descartan la línea y retornan "".
+//
+// Parámetros:
+//   - indicacion: el texto que se muestra antes de leer; puede empezar con
+//     saltos de línea, que se escriben una sola vez
+//
+// Retorna:
+//   - string: la línea leída ("" si se canceló o la entrada terminó)
+//
+// Ejemplo:
+//
+//	consulta := entrada.LeerLinea("Consulta: ")
+//
+func (e *Entrada) LeerLinea(indicacion string) string {
+	if e.cerrada {
+		return ""
+	}
+
+	var linea string
+	if e.editar {
+		if e.terminal != nil {
+			restaurar, err := e.terminal.modoCrudo()
+			if err != nil {
+				e.editar = false
+				return e.LeerLinea(indicacion)
+			}
+			defer restaurar()
+		}
+		linea, _ = e.editarLinea(indicacion, "")
+	} else {
+		fmt.Fprint(e.salida, indicacion)
+		var err error
+		linea, err = e.lector.ReadString('\n')
+		if err != nil {
+			e.cerrada = true
+		}
+	}
+
+	linea = strings.TrimSpace(linea)
+	e.agregarHistorial(linea)
+	return linea
+}
+
+// LeerEntero muestra la indicación y lee un número entero. Retorna
+// porDefecto si la línea está vacía o no es un número.
+//
+// Ejemplo:
+//
+//	// -1 para que una entrada vacía o inválida no se confunda con "Salir"
+//	opcion := entrada.LeerEntero("➤ Selecciona una opción: ", -1)
+//
+func (e *Entrada) LeerEntero(indicacion string, porDefecto int) int {
+	n, err := strconv.Atoi(e.LeerLinea(indicacion))
+	if err != nil {
+		return porDefecto
+	}
+	return n
+}
+
+// agregarHistorial añade linea al historial, salvo si está vacía o repite
+// la anterior, descartando las más antiguas por encima de maxHistorial.
+func (e *Entrada) agregarHistorial(linea string) {
+	if linea == "" || (len(e.historial) > 0 && e.historial[len(e.historial)-1] == linea) {
+		return
+	}
+	e.historial = append(e.historial, linea)
+	if len(e.historial) > maxHistorial {
+		e.historial = e.historial[len(e.historial)-maxHistorial:]
+	}
+}
+
+// editarLinea edita una línea tecla a tecla, con el terminal ya en modo
+// crudo, empezando con el texto inicial. Retorna la línea y false si se
+// canceló con Esc o Ctrl-C (o la entrada terminó).
+func (e *Entrada) editarLinea(indicacion, inicial string) (string, bool) {
+	// Los saltos de línea de la indicación se escriben una vez; lo que
+	// sigue al último se redibuja con la línea
+	if i := strings.LastIndex(indicacion, "\n"); i >= 0 {
+		fmt.Fprint(e.salida, strings.ReplaceAll(indicacion[:i+1], "\n", "\r\n"))
+		indicacion = indicacion[i+1:]
+	}
+
+	ed := &editor{
+		indicacion: indicacion,
+		linea:      []rune(inicial),
+		cursor:     len([]rune(inicial)),
+		historial:  e.historial,
+		posicion:   len(e.historial),
+	}
+	ed.dibujar(e.salida)
+	for {
+		t, err := leerTecla(e.lector)
+		if err != nil {
+			e.cerrada = true
+			fmt.Fprint(e.salida, "\r\n")
+			return string(ed.linea), false
+		}
+
+		switch t.codigo {
+		case teclaEnter:
+			fmt.Fprint(e.salida, "\r\n")
+			return string(ed.linea), true
+		case teclaCancelar, teclaEscape:
+			fmt.Fprint(e.salida, "^C\r\n")
+			return "", false
+		case teclaFinEntrada:
+			if len(ed.linea) == 0 {
+				e.cerrada = true
+				fmt.Fprint(e.salida, "\r\n")
+				return "", false
+			}
+			ed.aplicar(tecla{codigo: teclaSuprimir})
+		default:
+			ed.aplicar(t)
+		}
+		ed.dibujar(e.salida)
+	}
+}
+
+// editor es el estado de una línea en edición.
+type editor struct {
+	indicacion string
+	linea      []rune
+	cursor     int // Posición del cursor en linea, en runas
+
+	historial []string
+	posicion  int    // Línea del historial que se muestra; len(historial) es la nueva
+	borrador  []rune // La línea nueva mientras se recorre el historial
+}
+
+// aplicar modifica la línea según la tecla pulsada.
+func (ed *editor) aplicar(t tecla) {
+	switch t.codigo {
+	case teclaRuna:
+		ed.linea = append(ed.linea[:ed.cursor], append([]rune{t.runa}, ed.linea[ed.cursor:]...)...)
+		ed.cursor++
+	case teclaTab:
+		ed.aplicar(tecla{codigo: teclaRuna, runa: ' '})
+	case teclaRetroceso:
+		if ed.cursor > 0 {
+			ed.linea = append(ed.linea[:ed.cursor-1], ed.linea[ed.cursor:]...)
+			ed.cursor--
+		}
+	case teclaSuprimir:
+		if ed.cursor < len(ed.linea) {
+			ed.linea = append(ed.linea[:ed.cursor], ed.linea[ed.cursor+1:]...)
+		}
+	case teclaIzquierda:
+		ed.cursor = max(0, ed.cursor-1)
+	case teclaDerecha:
+		ed.cursor = min(len(ed.linea), ed.cursor+1)
+	case teclaInicio:
+		ed.cursor = 0
+	case teclaFin:
+		ed.cursor = len(ed.linea)
+	case teclaBorrarLinea:
+		ed.linea = ed.linea[ed.cursor:]
+		ed.cursor = 0
+	case teclaBorrarHastaFin:
+		ed.linea = ed.linea[:ed.cursor]
+	case teclaBorrarPalabra:
+		// Los espacios anteriores al cursor y la palabra que les precede
+		inicio := ed.cursor
+		for inicio > 0 && ed.linea[inicio-1] == ' ' {
+			inicio--
+		}
+		for inicio > 0 && ed.linea[inicio-1] != ' ' {
+			inicio--
+		}
+		ed.linea = append(ed.linea[:inicio], ed.linea[ed.cursor:]...)
+		ed.cursor = inicio
+	case teclaArriba:
+		ed.mostrarHistorial(ed.posicion - 1)
+	case teclaAbajo:
+		ed.mostrarHistorial(ed.posicion + 1)
+	}
+}
+
+// mostrarHistorial sustituye la línea por la del historial en la posición
+// indicada, guardando la línea nueva para cuando se vuelva a ella.
+func (ed *editor) mostrarHistorial(posicion int) {
+	if posicion < 0 || posicion > len(ed.historial) || posicion == ed.posicion {
+		return
+	}
+	if ed.posicion == len(ed.historial) {
+		ed.borrador = ed.linea
+	}
+	ed.posicion = posicion
+	if posicion == len(ed.historial) {
+		ed.linea = ed.borrador
+	} else {
+		ed.linea = []rune(ed.historial[posicion])
+	}
+	ed.cursor = len(ed.linea)
+}
+
+// dibujar reescribe la línea actual del terminal con la indicación y el
+// texto, y deja el cursor en su posición.
+func (ed *editor) dibujar(w io.Writer) {
+	fmt.Fprintf(w, "\r%s%s\x1b[K", ed.indicacion, string(ed.linea))
+	if atras := anchoTexto(ed.linea[ed.cursor:]); atras > 0 {
+		fmt.Fprintf(w, "\x1b[%dD", atras)
+	}
+}
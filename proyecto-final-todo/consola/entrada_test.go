@@ -0,0 +1,148 @@
+// Tests de lectura y edición de líneas
+
+package consola
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// nuevaEntradaEditable crea una entrada que edita las líneas como si r
+// fuera un terminal en modo crudo
+func nuevaEntradaEditable(r io.Reader) (*Entrada, *bytes.Buffer) {
+	var salida bytes.Buffer
+	entrada := NuevaEntrada(r, &salida)
+	entrada.editar = true
+	return entrada, &salida
+}
+
+// TestLeerLineaSinTerminal verifica que se lean líneas completas, con sus
+// espacios, de una entrada que no es un terminal
+func TestLeerLineaSinTerminal(t *testing.T) {
+	var salida bytes.Buffer
+	entrada := NuevaEntrada(strings.NewReader("Comprar leche\r\n  con espacios  \n7\nsiete\núltima sin salto"), &salida)
+
+	if linea := entrada.LeerLinea("\n📝 Título: "); linea != "Comprar leche" {
+		t.Errorf("Se esperaba \"Comprar leche\", se obtuvo %q", linea)
+	}
+	if salida.String() != "\n📝 Título: " {
+		t.Errorf("Indicación inesperada: %q", salida.String())
+	}
+	if linea := entrada.LeerLinea(""); linea != "con espacios" {
+		t.Errorf("Se esperaba \"con espacios\", se obtuvo %q", linea)
+	}
+	if n := entrada.LeerEntero("", -1); n != 7 {
+		t.Errorf("Se esperaba 7, se obtuvo %d", n)
+	}
+	if n := entrada.LeerEntero("", -1); n != -1 {
+		t.Errorf("Un texto no numérico debería dar el valor por defecto, se obtuvo %d", n)
+	}
+	if entrada.Cerrada() {
+		t.Fatal("La entrada no debería estar cerrada todavía")
+	}
+	if linea := entrada.LeerLinea(""); linea != "última sin salto" || !entrada.Cerrada() {
+		t.Errorf("Se esperaba la última línea y la entrada cerrada, se obtuvo %q (cerrada: %v)", linea, entrada.Cerrada())
+	}
+	if linea := entrada.LeerLinea(""); linea != "" {
+		t.Errorf("Tras cerrarse, la entrada debería retornar \"\", se obtuvo %q", linea)
+	}
+}
+
+// TestLeerLineaEditando verifica la edición con las teclas del terminal
+func TestLeerLineaEditando(t *testing.T) {
+	const (
+		izquierda = "\x1b[D"
+		derecha   = "\x1b[C"
+		suprimir  = "\x1b[3~"
+		inicio    = "\x1b[H"
+		fin       = "\x1bOF"
+	)
+	tests := []struct {
+		nombre   string
+		teclas   string
+		esperado string
+	}{
+		{"texto con espacios", "Comprar leche\r", "Comprar leche"},
+		{"retroceso", "Comprar lecha\x7fe\r", "Comprar leche"},
+		{"insertar en medio", "leche" + strings.Repeat(izquierda, 5) + "Comprar " + fin + "!\r", "Comprar leche!"},
+		{"inicio y fin", "mundo\x01Hola \x05!\r", "Hola mundo!"},
+		{"inicio y fin con escapes", "b" + inicio + "a" + fin + "c\r", "abc"},
+		{"suprimir", "abc" + izquierda + izquierda + suprimir + "\r", "ac"},
+		{"ctrl-d borra como suprimir", "abc\x01\x04\r", "bc"},
+		{"ctrl-u", "abc\x15xyz\r", "xyz"},
+		{"ctrl-k", "abcdef" + strings.Repeat(izquierda, 3) + "\x0b\r", "abc"},
+		{"ctrl-w", "ir al mercado  \x17\r", "ir al"},
+		{"flechas en los extremos", izquierda + "ab" + derecha + derecha + "c\r", "abc"},
+		{"tildes y eñes", "ñandú" + izquierda + "\x7f\r", "ñanú"},
+		{"enter como salto de línea", "pan\n", "pan"},
+		{"secuencia desconocida", "a\x1b[99Xb\r", "ab"},
+		{"ctrl-c cancela", "abc\x03", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			entrada, _ := nuevaEntradaEditable(strings.NewReader(tt.teclas))
+			if linea := entrada.LeerLinea("> "); linea != tt.esperado {
+				t.Errorf("Se esperaba %q, se obtuvo %q", tt.esperado, linea)
+			}
+		})
+	}
+
+	// Ctrl-D en una línea vacía termina la entrada
+	entrada, _ := nuevaEntradaEditable(strings.NewReader("\x04más"))
+	if linea := entrada.LeerLinea("> "); linea != "" || !entrada.Cerrada() {
+		t.Errorf("Ctrl-D en una línea vacía debería cerrar la entrada, se obtuvo %q", linea)
+	}
+}
+
+// TestLeerLineaHistorial verifica que las flechas recorran las líneas
+// anteriores y devuelvan la línea que se estaba escribiendo
+func TestLeerLineaHistorial(t *testing.T) {
+	const arriba, abajo = "\x1b[A", "\x1b[B"
+	entrada, salida := nuevaEntradaEditable(strings.NewReader(
+		"uno\r" +
+			"dos\r" +
+			"dos\r" + // Repetida: no se añade de nuevo
+			"\r" + // Vacía: no se añade
+			arriba + arriba + "\r" +
+			"tr" + arriba + abajo + "es\r" +
+			arriba + arriba + arriba + arriba + arriba + abajo + " y medio\r"))
+
+	esperadas := []string{"uno", "dos", "dos", "", "uno", "tres", "dos y medio"}
+	for _, esperada := range esperadas {
+		if linea := entrada.LeerLinea("> "); linea != esperada {
+			t.Errorf("Se esperaba %q, se obtuvo %q", esperada, linea)
+		}
+	}
+	if !strings.Contains(salida.String(), "\r> uno\x1b[K") {
+		t.Errorf("La línea recuperada del historial debería redibujarse:\n%q", salida.String())
+	}
+}
+
+// TestDibujarEditor verifica que el cursor vuelva a su posición teniendo en
+// cuenta el ancho de cada carácter
+func TestDibujarEditor(t *testing.T) {
+	tests := []struct {
+		linea    string
+		cursor   int
+		esperado string
+	}{
+		{"abc", 3, "\r> abc\x1b[K"},
+		{"abc", 1, "\r> abc\x1b[K\x1b[2D"},
+		{"ñú✅x", 2, "\r> ñú✅x\x1b[K\x1b[3D"},
+		{"éx", 0, "\r> éx\x1b[K\x1b[2D"},
+	}
+	for _, tt := range tests {
+		var salida bytes.Buffer
+		ed := &editor{indicacion: "> ", linea: []rune(tt.linea), cursor: tt.cursor}
+		ed.dibujar(&salida)
+		if salida.String() != tt.esperado {
+			t.Errorf("dibujar(%q, %d) = %q; se esperaba %q", tt.linea, tt.cursor, salida.String(), tt.esperado)
+		}
+	}
+
+	if recortado := recortar("⬜ [1] Comprar pan", 10); recortado != "⬜ [1] Co…" {
+		t.Errorf("recortar: se obtuvo %q", recortado)
+	}
+}
@@ -0,0 +1,482 @@
+package consola
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cristianjonhson/GO-API/proyecto-final-todo/tareas"
+)
+
+// Dimensiones de la pantalla cuando la entrada no es un terminal.
+const (
+	filasPorDefecto    = 24
+	columnasPorDefecto = 80
+)
+
+// filasFijas son las filas de la pantalla que no ocupa la lista: título,
+// dos separadores, mensaje y ayuda.
+const filasFijas = 5
+
+// Secuencias de escape ANSI de la interfaz.
+const (
+	ansiPantallaAlternativa = "\x1b[?1049h"
+	ansiPantallaNormal      = "\x1b[?1049l"
+	ansiOcultarCursor       = "\x1b[?25l"
+	ansiMostrarCursor       = "\x1b[?25h"
+	ansiInicio              = "\x1b[H"
+	ansiBorrarHastaFin      = "\x1b[J"
+	ansiInvertido           = "\x1b[7m"
+	ansiNormal              = "\x1b[0m"
+)
+
+// ayudaInterfaz resume las teclas principales en la última fila de la
+// pantalla; cabe en las 80 columnas habituales.
+const ayudaInterfaz = "↑↓ mover  ␣ marcar  e editar  n nueva  d borrar  u deshacer  f filtro  q salir"
+
+// filtroInterfaz son las tareas que muestra la interfaz.
+type filtroInterfaz int
+
+const (
+	filtroTodas filtroInterfaz = iota
+	filtroPendientes
+	filtroCompletadas
+)
+
+// String retorna el nombre del filtro para el título.
+func (f filtroInterfaz) String() string {
+	return [...]string{"todas", "pendientes", "completadas"}[f]
+}
+
+// Interfaz muestra las tareas de un gestor a pantalla completa y las
+// modifica con el teclado.
+//
+// La pantalla se redibuja entera tras cada tecla, con la lista en el orden
+// de MostrarTareas (cada subtarea bajo su tarea padre, con sangría) y la
+// tarea seleccionada resaltada.
+type Interfaz struct {
+	gestor  *tareas.GestorTareas
+	entrada *Entrada
+	salida  io.Writer
+
+	filtro         filtroInterfaz
+	lista          []tareas.Tarea
+	niveles        []int // Nivel de anidamiento de cada tarea de lista
+	seleccion      int
+	desplazamiento int    // Primera tarea visible de lista
+	mensaje        string // Resultado de la última acción
+}
+
+// NuevaInterfaz crea la interfaz a pantalla completa sobre el gestor,
+// leyendo las teclas de entrada.
+//
+// Parámetros:
+//   - gestor: el gestor cuyas tareas se muestran y modifican
+//   - entrada: la entrada del programa; la interfaz escribe en su Salida()
+//
+// Retorna:
+//   - *Interfaz: la interfaz, que se muestra con Ejecutar
+//
+// Ejemplo:
+//
+//	entrada := consola.NuevaEntrada(os.Stdin, os.Stdout)
+//	if err := consola.NuevaInterfaz(gestor, entrada).Ejecutar(); err != nil {
+//	    fmt.Printf("❌ Error: %v\n", err)
+//	}
+//
+func NuevaInterfaz(gestor *tareas.GestorTareas, entrada *Entrada) *Interfaz {
+	return &Interfaz{gestor: gestor, entrada: entrada, salida: entrada.Salida()}
+}
+
+// Ejecutar muestra la interfaz hasta que se pulsa q, Esc o Ctrl-C, o la
+// entrada termina, y restaura después la pantalla anterior.
+//
+// Si la entrada es un terminal se usa en modo crudo, de modo que cada
+// tecla actúa al pulsarla. Retorna error si no puede cambiarse el modo del
+// terminal.
+func (i *Interfaz) Ejecutar() error {
+	if i.entrada.terminal != nil {
+		restaurar, err := i.entrada.terminal.modoCrudo()
+		if err != nil {
+			return fmt.Errorf("no se pudo preparar el terminal: %v", err)
+		}
+		defer restaurar()
+	}
+	fmt.Fprint(i.salida, ansiPantallaAlternativa+ansiOcultarCursor)
+	defer fmt.Fprint(i.salida, ansiMostrarCursor+ansiPantallaNormal)
+
+	i.recargar(0)
+	for {
+		i.dibujar()
+		t, err := leerTecla(i.entrada.lector)
+		if err != nil {
+			i.entrada.cerrada = true
+			return nil
+		}
+		if !i.procesar(t) {
+			return nil
+		}
+	}
+}
+
+// procesar ejecuta la acción de la tecla pulsada. Retorna false si la
+// tecla cierra la interfaz.
+func (i *Interfaz) procesar(t tecla) bool {
+	i.mensaje = ""
+	filasLista := i.filasLista()
+
+	switch t.codigo {
+	case teclaEscape, teclaCancelar, teclaFinEntrada:
+		return false
+	case teclaArriba:
+		i.mover(-1)
+	case teclaAbajo:
+		i.mover(1)
+	case teclaRePag:
+		i.mover(-filasLista)
+	case teclaAvPag:
+		i.mover(filasLista)
+	case teclaInicio:
+		i.mover(-len(i.lista))
+	case teclaFin:
+		i.mover(len(i.lista))
+	case teclaTab:
+		i.cambiarFiltro()
+	case teclaEnter:
+		i.editarTitulo()
+	case teclaSuprimir:
+		i.eliminar()
+	case teclaRuna:
+		switch t.runa {
+		case 'q':
+			return false
+		case 'k':
+			i.mover(-1)
+		case 'j':
+			i.mover(1)
+		case 'g':
+			i.mover(-len(i.lista))
+		case 'G':
+			i.mover(len(i.lista))
+		case ' ', 'x':
+			i.alternarCompletada()
+		case 'e':
+			i.editarTitulo()
+		case 'n':
+			i.crear()
+		case 'd':
+			i.eliminar()
+		case 'u':
+			i.deshacer(i.gestor.Deshacer, "↩️  Deshecho")
+		case 'r':
+			i.deshacer(i.gestor.Rehacer, "↪️  Rehecho")
+		case 'f':
+			i.cambiarFiltro()
+		}
+	}
+	return true
+}
+
+// recargar vuelve a leer las tareas del gestor según el filtro y
+// selecciona la tarea con el ID indicado, o la más cercana a la selección
+// anterior si ya no está en la lista.
+func (i *Interfaz) recargar(id int) {
+	var lista []tareas.Tarea
+	switch i.filtro {
+	case filtroPendientes:
+		lista = i.gestor.ListarPendientes()
+	case filtroCompletadas:
+		lista = i.gestor.ListarCompletadas()
+	default:
+		lista = i.gestor.Listar()
+	}
+	i.lista, i.niveles = ordenarJerarquia(lista)
+
+	for j, tarea := range i.lista {
+		if tarea.ID == id {
+			i.seleccion = j
+		}
+	}
+	i.mover(0)
+}
+
+// ordenarJerarquia ordena la lista con cada subtarea bajo su tarea padre,
+// como MostrarTareas, y retorna el nivel de anidamiento de cada una. Las
+// subtareas cuyo padre no está en la lista se muestran en el primer nivel.
+func ordenarJerarquia(lista []tareas.Tarea) ([]tareas.Tarea, []int) {
+	enLista := make(map[int]bool, len(lista))
+	for _, tarea := range lista {
+		enLista[tarea.ID] = true
+	}
+	hijas := make(map[int][]tareas.Tarea)
+	var raices []tareas.Tarea
+	for _, tarea := range lista {
+		if tarea.PadreID != 0 && enLista[tarea.PadreID] {
+			hijas[tarea.PadreID] = append(hijas[tarea.PadreID], tarea)
+		} else {
+			raices = append(raices, tarea)
+		}
+	}
+
+	ordenada := make([]tareas.Tarea, 0, len(lista))
+	niveles := make([]int, 0, len(lista))
+	var agregar func(tarea tareas.Tarea, nivel int)
+	agregar = func(tarea tareas.Tarea, nivel int) {
+		ordenada = append(ordenada, tarea)
+		niveles = append(niveles, nivel)
+		for _, hija := range hijas[tarea.ID] {
+			agregar(hija, nivel+1)
+		}
+	}
+	for _, tarea := range raices {
+		agregar(tarea, 0)
+	}
+	return ordenada, niveles
+}
+
+// mover desplaza la selección delta tareas, dentro de los límites de la
+// lista, y ajusta el desplazamiento para que siga visible.
+func (i *Interfaz) mover(delta int) {
+	i.seleccion = max(0, min(len(i.lista)-1, i.seleccion+delta))
+	filasLista := i.filasLista()
+	if i.seleccion < i.desplazamiento {
+		i.desplazamiento = i.seleccion
+	}
+	if i.seleccion >= i.desplazamiento+filasLista {
+		i.desplazamiento = i.seleccion - filasLista + 1
+	}
+	i.desplazamiento = max(0, min(i.desplazamiento, len(i.lista)-filasLista))
+}
+
+// seleccionada retorna la tarea seleccionada, o nil si la lista está vacía.
+func (i *Interfaz) seleccionada() *tareas.Tarea {
+	if len(i.lista) == 0 {
+		return nil
+	}
+	return &i.lista[i.seleccion]
+}
+
+// cambiarFiltro pasa al siguiente filtro: todas, pendientes, completadas.
+func (i *Interfaz) cambiarFiltro() {
+	id := 0
+	if tarea := i.seleccionada(); tarea != nil {
+		id = tarea.ID
+	}
+	i.filtro = (i.filtro + 1) % 3
+	i.seleccion = 0
+	i.recargar(id)
+}
+
+// alternarCompletada completa la tarea seleccionada, o la vuelve a dejar
+// pendiente si ya estaba completada.
+func (i *Interfaz) alternarCompletada() {
+	tarea := i.seleccionada()
+	if tarea == nil {
+		return
+	}
+
+	if tarea.Completada {
+		pendiente := false
+		if _, err := i.gestor.Actualizar(tarea.ID, tareas.CambiosTarea{Completada: &pendiente}); err != nil {
+			i.mensaje = fmt.Sprintf("❌ Error: %v", err)
+		} else {
+			i.mensaje = fmt.Sprintf("⬜ Tarea %d pendiente", tarea.ID)
+		}
+	} else {
+		siguiente, err := i.gestor.CompletarConSiguiente(tarea.ID)
+		switch {
+		case err != nil:
+			i.mensaje = fmt.Sprintf("❌ Error: %v", err)
+		case siguiente != nil:
+			i.mensaje = fmt.Sprintf("✅ Tarea %d completada · 🔁 próxima ocurrencia: [%d]", tarea.ID, siguiente.ID)
+		default:
+			i.mensaje = fmt.Sprintf("✅ Tarea %d completada", tarea.ID)
+		}
+	}
+	i.recargar(tarea.ID)
+}
+
+// editarTitulo edita en línea el título de la tarea seleccionada, en su
+// propia fila. Enter guarda el cambio; Esc lo descarta.
+func (i *Interfaz) editarTitulo() {
+	tarea := i.seleccionada()
+	if tarea == nil {
+		return
+	}
+
+	fila := 3 + i.seleccion - i.desplazamiento
+	titulo, ok := i.leerEnFila(fila, "✏️  "+strings.Repeat("  ", i.niveles[i.seleccion]), tarea.Titulo)
+	if !ok || titulo == tarea.Titulo {
+		return
+	}
+	if _, err := i.gestor.Actualizar(tarea.ID, tareas.CambiosTarea{Titulo: &titulo}); err != nil {
+		i.mensaje = fmt.Sprintf("❌ Error: %v", err)
+	} else {
+		i.mensaje = fmt.Sprintf("✅ Tarea %d actualizada", tarea.ID)
+	}
+	i.recargar(tarea.ID)
+}
+
+// crear pide en la fila de mensajes el título de una tarea nueva y la
+// selecciona al crearla.
+func (i *Interfaz) crear() {
+	titulo, ok := i.leerEnFila(i.filas()-1, "➕ Nueva tarea: ", "")
+	if !ok || titulo == "" {
+		return
+	}
+	tarea, err := i.gestor.Crear(titulo)
+	if err != nil {
+		i.mensaje = fmt.Sprintf("❌ Error: %v", err)
+		return
+	}
+	i.mensaje = fmt.Sprintf("✅ Tarea creada con ID: %d", tarea.ID)
+	if i.filtro == filtroCompletadas {
+		i.filtro = filtroTodas
+	}
+	i.recargar(tarea.ID)
+}
+
+// eliminar mueve la tarea seleccionada a la papelera, con sus subtareas,
+// tras pedir confirmación.
+func (i *Interfaz) eliminar() {
+	tarea := i.seleccionada()
+	if tarea == nil {
+		return
+	}
+
+	pregunta := fmt.Sprintf("¿Mover '%s' a la papelera? (s/n)", tarea.Titulo)
+	subtareas := i.gestor.ListarDescendientes(tarea.ID)
+	if len(subtareas) > 0 {
+		pregunta = fmt.Sprintf("¿Mover '%s' y sus %d subtarea(s) a la papelera? (s/n)", tarea.Titulo, len(subtareas))
+	}
+	i.mensaje = pregunta
+	i.dibujar()
+	if t, err := leerTecla(i.entrada.lector); err != nil || t.codigo != teclaRuna || (t.runa != 's' && t.runa != 'S') {
+		i.mensaje = "❌ Cancelado"
+		return
+	}
+
+	eliminadas := 1
+	var err error
+	if len(subtareas) > 0 {
+		eliminadas, err = i.gestor.EliminarConSubtareas(tarea.ID)
+	} else {
+		err = i.gestor.Eliminar(tarea.ID)
+	}
+	if err != nil {
+		i.mensaje = fmt.Sprintf("❌ Error: %v", err)
+	} else {
+		i.mensaje = fmt.Sprintf("🗑️  %d tarea(s) movida(s) a la papelera (u para deshacer)", eliminadas)
+	}
+	i.recargar(0)
+}
+
+// deshacer ejecuta Deshacer o Rehacer del gestor e informa de la operación.
+func (i *Interfaz) deshacer(operacion func() (tareas.Operacion, error), etiqueta string) {
+	op, err := operacion()
+	if err != nil {
+		i.mensaje = fmt.Sprintf("❌ Error: %v", err)
+	} else {
+		i.mensaje = fmt.Sprintf("%s: %s", etiqueta, op)
+	}
+	id := 0
+	if tarea := i.seleccionada(); tarea != nil {
+		id = tarea.ID
+	}
+	i.recargar(id)
+}
+
+// leerEnFila edita un texto en la fila indicada de la pantalla (1 es la
+// primera), con el cursor visible mientras dura la edición.
+func (i *Interfaz) leerEnFila(fila int, indicacion, inicial string) (string, bool) {
+	fmt.Fprintf(i.salida, "\x1b[%d;1H%s", fila, ansiMostrarCursor)
+	defer fmt.Fprint(i.salida, ansiOcultarCursor)
+	texto, ok := i.entrada.editarLinea(indicacion, inicial)
+	return strings.TrimSpace(texto), ok
+}
+
+// dimensiones retorna las filas y columnas actuales de la pantalla. Se
+// consultan en cada dibujo para adaptarse si cambia el tamaño de la ventana.
+func (i *Interfaz) dimensiones() (filas, columnas int) {
+	if i.entrada.terminal != nil {
+		if filas, columnas, err := i.entrada.terminal.dimensiones(); err == nil && filas > filasFijas && columnas > 0 {
+			return filas, columnas
+		}
+	}
+	return filasPorDefecto, columnasPorDefecto
+}
+
+// filas retorna las filas actuales de la pantalla.
+func (i *Interfaz) filas() int {
+	filas, _ := i.dimensiones()
+	return filas
+}
+
+// filasLista es la cantidad de tareas que caben en la pantalla.
+func (i *Interfaz) filasLista() int {
+	return i.filas() - filasFijas
+}
+
+// dibujar escribe la pantalla completa: título, lista, mensaje y ayuda.
+func (i *Interfaz) dibujar() {
+	filas, columnas := i.dimensiones()
+	filasLista := filas - filasFijas
+	var b strings.Builder
+	b.WriteString(ansiInicio)
+
+	total, completadas, pendientes := i.gestor.Estadisticas()
+	titulo := fmt.Sprintf("📋 TAREAS (%s) · %d total | %d completadas | %d pendientes", i.filtro, total, completadas, pendientes)
+	escribirFila(&b, recortar(titulo, columnas))
+	escribirFila(&b, strings.Repeat("─", columnas))
+
+	for fila := 0; fila < filasLista; fila++ {
+		j := i.desplazamiento + fila
+		switch {
+		case j < len(i.lista):
+			linea := recortar(formatearFila(i.lista[j], i.niveles[j]), columnas)
+			if j == i.seleccion {
+				// El resaltado ocupa toda la fila
+				relleno := max(0, columnas-anchoTexto([]rune(linea)))
+				linea = ansiInvertido + linea + strings.Repeat(" ", relleno) + ansiNormal
+			}
+			escribirFila(&b, linea)
+		case j == 0:
+			escribirFila(&b, "   No hay tareas (n para crear una)")
+		default:
+			escribirFila(&b, "")
+		}
+	}
+
+	escribirFila(&b, strings.Repeat("─", columnas))
+	escribirFila(&b, recortar(i.mensaje, columnas))
+	b.WriteString(recortar(ayudaInterfaz, columnas))
+	b.WriteString(ansiBorrarHastaFin)
+	fmt.Fprint(i.salida, b.String())
+}
+
+// escribirFila añade una fila a la pantalla, borrando lo que quedara de la
+// anterior.
+func escribirFila(b *strings.Builder, linea string) {
+	b.WriteString(linea)
+	b.WriteString("\x1b[K\r\n")
+}
+
+// formatearFila resume una tarea en una fila: estado, ID, título,
+// prioridad, vencimiento y etiquetas.
+func formatearFila(tarea tareas.Tarea, nivel int) string {
+	estado := "⬜"
+	if tarea.Completada {
+		estado = "✅"
+	}
+	partes := []string{fmt.Sprintf("%s%s [%d] %s", strings.Repeat("  ", nivel), estado, tarea.ID, tarea.Titulo)}
+	if tarea.Prioridad != "" {
+		partes = append(partes, "🔥 "+string(tarea.Prioridad))
+	}
+	if !tarea.FechaVencimiento.IsZero() {
+		partes = append(partes, "⏰ "+tarea.FechaVencimiento.Format("02/01/2006"))
+	}
+	for _, etiqueta := range tarea.Etiquetas {
+		partes = append(partes, "#"+etiqueta)
+	}
+	return strings.Join(partes, "  ")
+}
@@ -0,0 +1,228 @@
+// Tests de la interfaz a pantalla completa
+
+package consola
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cristianjonhson/GO-API/proyecto-final-todo/tareas"
+)
+
+// nuevoGestorInterfaz crea un gestor en memoria con una tarea, otra con una
+// subtarea y una tarea completada
+func nuevoGestorInterfaz(t *testing.T) *tareas.GestorTareas {
+	t.Helper()
+	gestor, err := tareas.NuevoGestorTareas("",
+		tareas.ConAlmacenamiento(tareas.NuevoAlmacenamientoMemoria()),
+		tareas.ConSalida(io.Discard))
+	if err != nil {
+		t.Fatalf("Error al crear gestor: %v", err)
+	}
+	datos := []tareas.DatosTarea{
+		{Titulo: "Comprar pan", Prioridad: tareas.PrioridadAlta},
+		{Titulo: "Preparar viaje", Etiquetas: []string{"ocio"}},
+		{Titulo: "Reservar hotel", PadreID: 2},
+		{Titulo: "Pagar luz"},
+	}
+	for _, d := range datos {
+		if _, err := gestor.CrearConDatos(d); err != nil {
+			t.Fatalf("Error al crear %q: %v", d.Titulo, err)
+		}
+	}
+	gestor.Completar(4)
+	return gestor
+}
+
+// ejecutarInterfaz ejecuta la interfaz con las teclas indicadas y retorna
+// lo que escribió
+func ejecutarInterfaz(t *testing.T, gestor *tareas.GestorTareas, teclas string) string {
+	t.Helper()
+	var salida bytes.Buffer
+	entrada := NuevaEntrada(strings.NewReader(teclas), &salida)
+	if err := NuevaInterfaz(gestor, entrada).Ejecutar(); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	return salida.String()
+}
+
+// TestInterfazAcciones verifica cada acción de teclado sobre el gestor
+func TestInterfazAcciones(t *testing.T) {
+	const abajo, arriba = "\x1b[B", "\x1b[A"
+	tests := []struct {
+		nombre    string
+		preparar  func(gestor *tareas.GestorTareas)
+		teclas    string
+		comprobar func(t *testing.T, gestor *tareas.GestorTareas, salida string)
+	}{
+		{
+			nombre:   "completar con espacio",
+			preparar: func(gestor *tareas.GestorTareas) { gestor.AgregarDependencia(1, 3) },
+			teclas:   " j ",
+			comprobar: func(t *testing.T, gestor *tareas.GestorTareas, salida string) {
+				// Comprar pan depende de Reservar hotel: no se completa
+				if tarea, _ := gestor.BuscarPorID(1); tarea.Completada {
+					t.Error("La tarea 1 no debería completarse con dependencias pendientes")
+				}
+				if tarea, _ := gestor.BuscarPorID(2); !tarea.Completada {
+					t.Error("La tarea 2 debería estar completada")
+				}
+				if !strings.Contains(salida, "❌ Error") || !strings.Contains(salida, "✅ Tarea 2 completada") {
+					t.Error("Faltan los mensajes de error y de tarea completada")
+				}
+			},
+		},
+		{
+			nombre: "completar subtarea y volver a pendiente",
+			teclas: abajo + abajo + " " + "x" + abajo + " ",
+			comprobar: func(t *testing.T, gestor *tareas.GestorTareas, salida string) {
+				if tarea, _ := gestor.BuscarPorID(3); tarea.Completada {
+					t.Error("La tarea 3 debería volver a estar pendiente")
+				}
+				if tarea, _ := gestor.BuscarPorID(4); tarea.Completada {
+					t.Error("La tarea 4 debería estar pendiente")
+				}
+				if !strings.Contains(salida, "✅ Tarea 3 completada") || !strings.Contains(salida, "⬜ Tarea 4 pendiente") {
+					t.Error("Faltan los mensajes de completar y dejar pendiente")
+				}
+			},
+		},
+		{
+			nombre: "editar en línea",
+			teclas: "e\x17y mantequilla\r" + abajo + "\r\x15Cancelado\x03",
+			comprobar: func(t *testing.T, gestor *tareas.GestorTareas, salida string) {
+				if tarea, _ := gestor.BuscarPorID(1); tarea.Titulo != "Comprar y mantequilla" {
+					t.Errorf("Título inesperado: %q", tarea.Titulo)
+				}
+				if tarea, _ := gestor.BuscarPorID(2); tarea.Titulo != "Preparar viaje" {
+					t.Errorf("Ctrl-C debería descartar la edición, título: %q", tarea.Titulo)
+				}
+			},
+		},
+		{
+			nombre: "crear y seleccionar",
+			teclas: "nLlamar a Ana\r ",
+			comprobar: func(t *testing.T, gestor *tareas.GestorTareas, salida string) {
+				tarea, err := gestor.BuscarPorID(5)
+				if err != nil || tarea.Titulo != "Llamar a Ana" || !tarea.Completada {
+					t.Errorf("Se esperaba la tarea 5 creada y completada: %+v, %v", tarea, err)
+				}
+			},
+		},
+		{
+			nombre: "título inválido",
+			teclas: "nab\r",
+			comprobar: func(t *testing.T, gestor *tareas.GestorTareas, salida string) {
+				if total, _, _ := gestor.Estadisticas(); total != 4 || !strings.Contains(salida, "al menos 3 caracteres") {
+					t.Errorf("No debería crearse la tarea, hay %d", total)
+				}
+			},
+		},
+		{
+			nombre: "eliminar con confirmación y deshacer",
+			teclas: "dn" + "j" + "ds" + "G" + "\x1b[3~s" + "u",
+			comprobar: func(t *testing.T, gestor *tareas.GestorTareas, salida string) {
+				if total, _, _ := gestor.Estadisticas(); total != 2 {
+					t.Errorf("Deberían quedar 2 tareas, hay %d", total)
+				}
+				if _, err := gestor.BuscarPorID(2); err == nil {
+					t.Error("La tarea 2 debería estar en la papelera")
+				}
+				if !strings.Contains(salida, "y sus 1 subtarea(s)") || !strings.Contains(salida, "❌ Cancelado") ||
+					!strings.Contains(salida, "↩️  Deshecho") {
+					t.Error("Faltan la confirmación, la cancelación o el aviso de deshacer")
+				}
+			},
+		},
+		{
+			nombre: "eliminar cuenta las subtareas de cualquier nivel",
+			preparar: func(gestor *tareas.GestorTareas) {
+				gestor.CrearConDatos(tareas.DatosTarea{Titulo: "Pagar reserva", PadreID: 3})
+			},
+			teclas: "jds",
+			comprobar: func(t *testing.T, gestor *tareas.GestorTareas, salida string) {
+				if !strings.Contains(salida, "y sus 2 subtarea(s) a la papelera") || !strings.Contains(salida, "🗑️  3 tarea(s) movida(s)") {
+					t.Error("La confirmación debería contar las 2 subtareas que se eliminan")
+				}
+			},
+		},
+		{
+			nombre: "filtros",
+			teclas: "f" + abajo + abajo + abajo + arriba + " \tff",
+			comprobar: func(t *testing.T, gestor *tareas.GestorTareas, salida string) {
+				if tarea, _ := gestor.BuscarPorID(2); !tarea.Completada {
+					t.Error("La tarea 2 debería estar completada")
+				}
+				pantallas := strings.Split(salida, ansiInicio)
+				final := pantallas[len(pantallas)-1]
+				if !strings.Contains(final, "TAREAS (pendientes)") || !strings.Contains(final, "[1] Comprar pan") || strings.Contains(final, "[2]") || strings.Contains(final, "[4]") {
+					t.Errorf("Se esperaban solo las pendientes:\n%s", final)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			gestor := nuevoGestorInterfaz(t)
+			if tt.preparar != nil {
+				tt.preparar(gestor)
+			}
+			salida := ejecutarInterfaz(t, gestor, tt.teclas+"q")
+			tt.comprobar(t, gestor, salida)
+		})
+	}
+}
+
+// TestInterfazPantalla verifica el dibujo de la lista: sangría de las
+// subtareas, selección resaltada, desplazamiento y restauración final
+func TestInterfazPantalla(t *testing.T) {
+	gestor := nuevoGestorInterfaz(t)
+	salida := ejecutarInterfaz(t, gestor, "jq")
+
+	pantallas := strings.Split(salida, ansiInicio)
+	if len(pantallas) != 3 || !strings.HasPrefix(salida, ansiPantallaAlternativa) || !strings.HasSuffix(salida, ansiPantallaNormal) {
+		t.Fatalf("Se esperaban 2 pantallas entre los cambios de pantalla:\n%q", salida)
+	}
+	esperados := []string{
+		"📋 TAREAS (todas) · 4 total | 1 completadas | 3 pendientes",
+		"⬜ [1] Comprar pan  🔥 alta\x1b[K",
+		ansiInvertido + "⬜ [2] Preparar viaje  #ocio",
+		"\r\n  ⬜ [3] Reservar hotel\x1b[K",
+		"✅ [4] Pagar luz",
+		ayudaInterfaz,
+	}
+	for _, esperado := range esperados {
+		if !strings.Contains(pantallas[2], esperado) {
+			t.Errorf("Se esperaba %q en:\n%s", esperado, pantallas[2])
+		}
+	}
+	if filas := strings.Count(pantallas[2], "\r\n"); filas != filasPorDefecto-1 {
+		t.Errorf("La pantalla debería ocupar %d filas, ocupa %d", filasPorDefecto, filas+1)
+	}
+
+	// Con más tareas de las que caben, la lista se desplaza con la selección
+	for n := 5; n <= 30; n++ {
+		gestor.Crear("Tarea de relleno")
+	}
+	salida = ejecutarInterfaz(t, gestor, "G\x1b[5~q")
+	pantallas = strings.Split(salida, ansiInicio)
+	if final := pantallas[2]; !strings.Contains(final, "[30] Tarea") || strings.Contains(final, "[1] Comprar") {
+		t.Errorf("Al ir al final deberían verse las últimas tareas:\n%s", final)
+	}
+	if repag := pantallas[3]; !strings.Contains(repag, ansiInvertido+"⬜ [11] Tarea") {
+		t.Errorf("Re Pág debería subir %d tareas:\n%s", filasPorDefecto-filasFijas, repag)
+	}
+
+	// Sin tareas, y al terminar la entrada sin pulsar q
+	vacio, _ := tareas.NuevoGestorTareas("", tareas.ConAlmacenamiento(tareas.NuevoAlmacenamientoMemoria()), tareas.ConSalida(io.Discard))
+	var b bytes.Buffer
+	entrada := NuevaEntrada(strings.NewReader(" ed"), &b)
+	if err := NuevaInterfaz(vacio, entrada).Ejecutar(); err != nil || !entrada.Cerrada() {
+		t.Fatalf("La interfaz debería terminar con la entrada: %v", err)
+	}
+	if !strings.Contains(b.String(), "No hay tareas") {
+		t.Errorf("Se esperaba el aviso de lista vacía:\n%s", b.String())
+	}
+}
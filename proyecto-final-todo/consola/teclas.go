@@ -0,0 +1,172 @@
+package consola
+
+import (
+	"bufio"
+	"unicode"
+)
+
+// codigoTecla identifica una tecla ya decodificada de la entrada.
+type codigoTecla int
+
+const (
+	teclaRuna           codigoTecla = iota // Carácter imprimible, en tecla.runa
+	teclaEnter                             // Enter (\r o \n)
+	teclaRetroceso                         // Retroceso o Ctrl-H
+	teclaSuprimir                          // Supr
+	teclaIzquierda                         // ← o Ctrl-B
+	teclaDerecha                           // → o Ctrl-F
+	teclaArriba                            // ↑ o Ctrl-P
+	teclaAbajo                             // ↓ o Ctrl-N
+	teclaInicio                            // Inicio o Ctrl-A
+	teclaFin                               // Fin o Ctrl-E
+	teclaRePag                             // Re Pág
+	teclaAvPag                             // Av Pág
+	teclaTab                               // Tabulador
+	teclaEscape                            // Esc, sola
+	teclaCancelar                          // Ctrl-C
+	teclaFinEntrada                        // Ctrl-D
+	teclaBorrarLinea                       // Ctrl-U: borra hasta el principio
+	teclaBorrarHastaFin                    // Ctrl-K: borra hasta el final
+	teclaBorrarPalabra                     // Ctrl-W: borra la palabra anterior
+	teclaDesconocida                       // Otra tecla de control o secuencia de escape
+)
+
+// tecla es una pulsación decodificada.
+type tecla struct {
+	codigo codigoTecla
+	runa   rune
+}
+
+// teclasControl traduce los caracteres de control que entiende el editor.
+var teclasControl = map[rune]codigoTecla{
+	0x01: teclaInicio,         // Ctrl-A
+	0x02: teclaIzquierda,      // Ctrl-B
+	0x03: teclaCancelar,       // Ctrl-C
+	0x04: teclaFinEntrada,     // Ctrl-D
+	0x05: teclaFin,            // Ctrl-E
+	0x06: teclaDerecha,        // Ctrl-F
+	0x08: teclaRetroceso,      // Ctrl-H
+	0x09: teclaTab,            // Tab
+	0x0b: teclaBorrarHastaFin, // Ctrl-K
+	0x0e: teclaAbajo,          // Ctrl-N
+	0x10: teclaArriba,         // Ctrl-P
+	0x15: teclaBorrarLinea,    // Ctrl-U
+	0x17: teclaBorrarPalabra,  // Ctrl-W
+	0x7f: teclaRetroceso,      // Retroceso
+}
+
+// secuenciasEscape traduce las secuencias CSI (ESC [) y SS3 (ESC O) de las
+// teclas especiales, sin el ESC inicial. Los terminales usan unas u otras
+// según su modo: ESC [ A y ESC O A son ambas la flecha arriba.
+var secuenciasEscape = map[string]codigoTecla{
+	"[A": teclaArriba, "OA": teclaArriba,
+	"[B": teclaAbajo, "OB": teclaAbajo,
+	"[C": teclaDerecha, "OC": teclaDerecha,
+	"[D": teclaIzquierda, "OD": teclaIzquierda,
+	"[H": teclaInicio, "OH": teclaInicio, "[1~": teclaInicio, "[7~": teclaInicio,
+	"[F": teclaFin, "OF": teclaFin, "[4~": teclaFin, "[8~": teclaFin,
+	"[3~": teclaSuprimir,
+	"[5~": teclaRePag,
+	"[6~": teclaAvPag,
+}
+
+// leerTecla lee y decodifica la siguiente pulsación.
+//
+// Una secuencia de escape llega de una vez, así que un ESC sin más bytes
+// ya leídos es la tecla Esc sola. Retorna el error de lectura (io.EOF al
+// terminar la entrada).
+func leerTecla(r *bufio.Reader) (tecla, error) {
+	runa, _, err := r.ReadRune()
+	if err != nil {
+		return tecla{}, err
+	}
+
+	if codigo, ok := teclasControl[runa]; ok {
+		return tecla{codigo: codigo}, nil
+	}
+	switch {
+	case runa == '\r' || runa == '\n':
+		// "\r\n" cuenta como un solo Enter; sin más bytes ya leídos no se
+		// espera al siguiente, que podría no llegar nunca
+		if runa == '\r' && r.Buffered() > 0 {
+			if siguiente, _ := r.Peek(1); siguiente[0] == '\n' {
+				r.ReadByte()
+			}
+		}
+		return tecla{codigo: teclaEnter}, nil
+	case runa == 0x1b:
+		return leerSecuenciaEscape(r), nil
+	case unicode.IsPrint(runa):
+		return tecla{codigo: teclaRuna, runa: runa}, nil
+	}
+	return tecla{codigo: teclaDesconocida}, nil
+}
+
+// leerSecuenciaEscape decodifica lo que sigue a un ESC.
+func leerSecuenciaEscape(r *bufio.Reader) tecla {
+	if r.Buffered() == 0 {
+		return tecla{codigo: teclaEscape}
+	}
+	inicio, _ := r.Peek(1)
+	if inicio[0] != '[' && inicio[0] != 'O' {
+		// Alt+tecla: se ignora el ESC y la tecla se lee después
+		return tecla{codigo: teclaDesconocida}
+	}
+
+	// Parámetros y byte final (entre 0x40 y 0x7e) de la secuencia
+	secuencia := []byte{inicio[0]}
+	r.ReadByte()
+	for r.Buffered() > 0 {
+		b, _ := r.ReadByte()
+		secuencia = append(secuencia, b)
+		if b >= 0x40 && b <= 0x7e {
+			break
+		}
+	}
+	if codigo, ok := secuenciasEscape[string(secuencia)]; ok {
+		return tecla{codigo: codigo}
+	}
+	return tecla{codigo: teclaDesconocida}
+}
+
+// anchoRuna es la cantidad de columnas que ocupa r en un terminal: 0 para
+// las marcas combinantes y los selectores de variante, 2 para los
+// caracteres anchos (CJK y la mayoría de emojis) y 1 para el resto.
+func anchoRuna(r rune) int {
+	switch {
+	case unicode.Is(unicode.Mn, r), r == 0x200d, r >= 0xfe00 && r <= 0xfe0f:
+		return 0
+	case r >= 0x1100 && r <= 0x115f, r >= 0x2e80 && r <= 0xa4cf, r >= 0xac00 && r <= 0xd7a3,
+		r >= 0xf900 && r <= 0xfaff, r >= 0xff00 && r <= 0xff60, r >= 0xffe0 && r <= 0xffe6,
+		r >= 0x1f300 && r <= 0x1f64f, r >= 0x1f900 && r <= 0x1f9ff, r >= 0x20000 && r <= 0x3fffd,
+		r == 0x231a, r == 0x231b, r == 0x23f0, r == 0x23f3, r == 0x2705, r == 0x26d4, r == 0x2b1c, r == 0x2b50:
+		return 2
+	}
+	return 1
+}
+
+// anchoTexto es la cantidad de columnas que ocupa s en un terminal.
+func anchoTexto(s []rune) int {
+	ancho := 0
+	for _, r := range s {
+		ancho += anchoRuna(r)
+	}
+	return ancho
+}
+
+// recortar acorta s para que ocupe como mucho ancho columnas, terminando
+// en "…" si no cabe entero.
+func recortar(s string, ancho int) string {
+	runas := []rune(s)
+	if anchoTexto(runas) <= ancho {
+		return s
+	}
+	usado := 0
+	for i, r := range runas {
+		if usado+anchoRuna(r) > ancho-1 {
+			return string(runas[:i]) + "…"
+		}
+		usado += anchoRuna(r)
+	}
+	return s
+}
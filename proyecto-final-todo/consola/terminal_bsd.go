@@ -0,0 +1,11 @@
+//go:build darwin || freebsd || netbsd || openbsd
+
+package consola
+
+import "syscall"
+
+// Peticiones ioctl para leer y cambiar la configuración del terminal.
+const (
+	ioctlLeerAtributos     = syscall.TIOCGETA
+	ioctlEscribirAtributos = syscall.TIOCSETA
+)
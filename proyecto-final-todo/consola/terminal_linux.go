@@ -0,0 +1,9 @@
+package consola
+
+import "syscall"
+
+// Peticiones ioctl para leer y cambiar la configuración del terminal.
+const (
+	ioctlLeerAtributos     = syscall.TCGETS
+	ioctlEscribirAtributos = syscall.TCSETS
+)
@@ -0,0 +1,28 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package consola
+
+import (
+	"errors"
+	"os"
+)
+
+// terminal no se implementa en este sistema: la entrada se lee siempre por
+// líneas, con la edición que ofrezca el propio terminal.
+type terminal struct{}
+
+// abrirTerminal retorna siempre nil: sin modo crudo, f se trata como una
+// entrada cualquiera.
+func abrirTerminal(f *os.File) *terminal {
+	return nil
+}
+
+// modoCrudo no está disponible en este sistema.
+func (t *terminal) modoCrudo() (restaurar func(), err error) {
+	return nil, errors.New("modo crudo no disponible en este sistema")
+}
+
+// dimensiones no está disponible en este sistema.
+func (t *terminal) dimensiones() (filas, columnas int, err error) {
+	return 0, 0, errors.New("dimensiones del terminal no disponibles en este sistema")
+}
@@ -0,0 +1,75 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package consola
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// terminal es una entrada conectada a un terminal, que puede pasar a modo
+// crudo para leer tecla a tecla.
+type terminal struct {
+	fd uintptr
+}
+
+// dimensionesVentana es la estructura winsize de TIOCGWINSZ.
+type dimensionesVentana struct {
+	filas, columnas, ancho, alto uint16
+}
+
+// abrirTerminal retorna el terminal de f, o nil si f no es un terminal
+// (un archivo, una tubería, ...).
+func abrirTerminal(f *os.File) *terminal {
+	t := &terminal{fd: f.Fd()}
+	if _, err := t.leerAtributos(); err != nil {
+		return nil
+	}
+	return t
+}
+
+// modoCrudo desactiva el eco, la edición por líneas y las señales del
+// teclado (Ctrl-C llega como una tecla más), y retorna la función que
+// restaura el modo anterior.
+func (t *terminal) modoCrudo() (restaurar func(), err error) {
+	original, err := t.leerAtributos()
+	if err != nil {
+		return nil, err
+	}
+	crudo := *original
+	crudo.Iflag &^= syscall.ICRNL | syscall.IXON
+	crudo.Lflag &^= syscall.ICANON | syscall.ECHO | syscall.ISIG | syscall.IEXTEN
+	crudo.Cc[syscall.VMIN] = 1
+	crudo.Cc[syscall.VTIME] = 0
+	if err := t.ioctl(ioctlEscribirAtributos, unsafe.Pointer(&crudo)); err != nil {
+		return nil, err
+	}
+	return func() { t.ioctl(ioctlEscribirAtributos, unsafe.Pointer(original)) }, nil
+}
+
+// dimensiones retorna las filas y columnas de la ventana del terminal.
+func (t *terminal) dimensiones() (filas, columnas int, err error) {
+	var d dimensionesVentana
+	if err := t.ioctl(syscall.TIOCGWINSZ, unsafe.Pointer(&d)); err != nil {
+		return 0, 0, err
+	}
+	return int(d.filas), int(d.columnas), nil
+}
+
+// leerAtributos retorna la configuración actual del terminal.
+func (t *terminal) leerAtributos() (*syscall.Termios, error) {
+	var atributos syscall.Termios
+	if err := t.ioctl(ioctlLeerAtributos, unsafe.Pointer(&atributos)); err != nil {
+		return nil, err
+	}
+	return &atributos, nil
+}
+
+// ioctl ejecuta la llamada al sistema ioctl sobre el terminal.
+func (t *terminal) ioctl(peticion uintptr, arg unsafe.Pointer) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, t.fd, peticion, uintptr(arg)); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cristianjonhson/GO-API/proyecto-final-todo/consola"
+	"github.com/cristianjonhson/GO-API/proyecto-final-todo/tareas"
+)
+
+// filtrarPorLista retorna las tareas de lista que pertenecen a la lista
+// indicada, o todas si nombre está vacío.
+func filtrarPorLista(lista []tareas.Tarea, nombre string) []tareas.Tarea {
+	if nombre == "" {
+		return lista
+	}
+	nombre = strings.ToLower(strings.TrimSpace(nombre))
+	var filtradas []tareas.Tarea
+	for _, tarea := range lista {
+		if tarea.NombreLista() == nombre {
+			filtradas = append(filtradas, tarea)
+		}
+	}
+	return filtradas
+}
+
+// filtrarResultadosPorLista es filtrarPorLista para los resultados de una
+// búsqueda por relevancia, que conservan su orden.
+func filtrarResultadosPorLista(resultados []tareas.ResultadoBusqueda, nombre string) []tareas.ResultadoBusqueda {
+	if nombre == "" {
+		return resultados
+	}
+	nombre = strings.ToLower(strings.TrimSpace(nombre))
+	var filtrados []tareas.ResultadoBusqueda
+	for _, resultado := range resultados {
+		if resultado.Tarea.NombreLista() == nombre {
+			filtrados = append(filtrados, resultado)
+		}
+	}
+	return filtrados
+}
+
+// MostrarListas imprime las listas con el recuento de sus tareas, marcando
+// la lista activa ("" si se trabaja con todas).
+func MostrarListas(listas []tareas.ResumenLista, activa string) {
+	fmt.Println("\n" + strings.Repeat("=", 70))
+	fmt.Println("📂 LISTAS")
+	fmt.Println(strings.Repeat("=", 70))
+	for _, lista := range listas {
+		marca := "  "
+		if lista.Nombre == strings.ToLower(activa) {
+			marca = "➤ "
+		}
+		fmt.Printf("%s%-20s %d total | %d completadas | %d pendientes\n", marca, lista.Nombre, lista.Total, lista.Completadas, lista.Pendientes)
+	}
+	fmt.Println(strings.Repeat("=", 70))
+}
+
+// gestionarListas muestra las listas y pregunta qué hacer con ellas:
+// cambiar la lista activa, crear, renombrar o eliminar una lista, o mover
+// una tarea a otra lista. Retorna la lista activa resultante ("" = todas).
+func gestionarListas(entrada *consola.Entrada, gestor *tareas.GestorTareas, activa string) string {
+	MostrarListas(gestor.Listas(), activa)
+	accion := entrada.LeerEntero("\n📂 Acción (1=Cambiar de lista, 2=Crear, 3=Renombrar, 4=Eliminar, 5=Mover tarea, Enter=volver): ", 0)
+
+	switch accion {
+	case 1:
+		nombre := entrada.LeerLinea("📂 Lista activa (Enter para ver todas): ")
+		if nombre == "" {
+			fmt.Println("✅ Se muestran las tareas de todas las listas")
+			return ""
+		}
+		if _, _, _, err := gestor.EstadisticasLista(nombre); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			return activa
+		}
+		fmt.Printf("✅ Lista activa: %s\n", strings.ToLower(nombre))
+		return strings.ToLower(nombre)
+
+	case 2:
+		nombre := entrada.LeerLinea("📂 Nombre de la nueva lista: ")
+		if err := gestor.CrearLista(nombre); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+		} else {
+			fmt.Printf("✅ Lista '%s' creada\n", strings.ToLower(nombre))
+		}
+
+	case 3:
+		actual := entrada.LeerLinea("📂 Lista a renombrar: ")
+		nueva := entrada.LeerLinea("📂 Nuevo nombre: ")
+		if err := gestor.RenombrarLista(actual, nueva); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			break
+		}
+		fmt.Printf("✅ Lista '%s' renombrada a '%s'\n", strings.ToLower(actual), strings.ToLower(nueva))
+		if strings.EqualFold(actual, activa) {
+			return strings.ToLower(nueva)
+		}
+
+	case 4:
+		nombre := entrada.LeerLinea("📂 Lista a eliminar: ")
+		confirmar := entrada.LeerLinea(fmt.Sprintf("¿Eliminar la lista '%s'? Sus tareas pasarán a '%s' (s/n): ", nombre, tareas.ListaPorDefecto))
+		if strings.ToLower(confirmar) != "s" {
+			fmt.Println("❌ Cancelado")
+			break
+		}
+		movidas, err := gestor.EliminarLista(nombre)
+		if err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			break
+		}
+		fmt.Printf("✅ Lista eliminada; %d tarea(s) pasaron a '%s'\n", movidas, tareas.ListaPorDefecto)
+		if strings.EqualFold(nombre, activa) {
+			return ""
+		}
+
+	case 5:
+		id := entrada.LeerEntero("📂 ID de la tarea a mover: ", 0)
+		nombre := entrada.LeerLinea("📂 Lista de destino: ")
+		if err := gestor.MoverALista(id, nombre); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+		} else {
+			fmt.Printf("✅ Tarea %d movida a '%s', con sus subtareas\n", id, strings.ToLower(nombre))
+		}
+	}
+	return activa
+}
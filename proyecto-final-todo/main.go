@@ -1,643 +1,526 @@
-// Package main implementa un sistema completo de gestión de tareas desde línea de comandos.
-//
-// El sistema proporciona operaciones CRUD (Crear, Leer, Actualizar, Eliminar) para tareas,
-// con persistencia en archivos JSON, validaciones de entrada, manejo robusto de errores,
-// y guardado automático mediante concurrencia con goroutines.
-//
-// # Características principales
-//
-//   - CRUD completo de tareas con validaciones
-//   - Persistencia automática en formato JSON
-//   - Búsqueda por ID o texto (case-insensitive)
-//   - Filtrado por estado (completadas/pendientes)
-//   - Estadísticas en tiempo real
-//   - Autoguardado periódico con goroutines
-//   - Interfaz CLI interactiva con menú
-//
-// # Uso básico
-//
-// Crear un gestor y realizar operaciones:
-//
-//	gestor, err := NuevoGestorTareas("tareas.json")
-//	if err != nil {
-//		log.Fatal(err)
-//	}
-//
-//	// Crear una tarea
-//	tarea, err := gestor.Crear("Estudiar concurrencia en Go")
-//	if err != nil {
-//		log.Fatal(err)
-//	}
-//
-//	// Listar tareas pendientes
-//	pendientes := gestor.ListarPendientes()
-//	for _, t := range pendientes {
-//		fmt.Printf("[%d] %s\n", t.ID, t.Titulo)
-//	}
-//
-//	// Completar una tarea
-//	err = gestor.Completar(tarea.ID)
-//
-//	// Guardar cambios
-//	err = gestor.Guardar()
-//
-// # Autoguardado
-//
-// El sistema soporta guardado automático periódico:
-//
-//	detener := make(chan bool)
-//	gestor.IniciarAutoguardado(30*time.Second, detener)
-//	// ... operaciones ...
-//	detener <- true  // Detener cuando termine
+// Package main implementa la interfaz de línea de comandos del sistema de gestión de tareas.
 //
+// Toda la lógica de negocio (CRUD, persistencia y autoguardado) vive en el
+// paquete tareas; este programa solo se encarga del menú interactivo, de los
+// subcomandos no interactivos (ver comandos.go) y de presentar los
+// resultados al usuario.
 package main
 
 import (
-	"encoding/json"
+	"bytes"
+	"errors"
+	"flag"
 	"fmt"
-	"io/ioutil"
 	"os"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/cristianjonhson/GO-API/proyecto-final-todo/consola"
+	"github.com/cristianjonhson/GO-API/proyecto-final-todo/tareas"
 )
 
-// Tarea representa una tarea individual en el sistema de gestión.
-//
-// Cada tarea contiene un identificador único auto-incremental, un título
-// descriptivo que debe cumplir validaciones de longitud (3-100 caracteres),
-// un estado de completitud booleano, y un timestamp de creación.
-//
-// Las tareas se serializan a JSON para persistencia usando los tags json.
-type Tarea struct {
-	// ID es el identificador único auto-incremental de la tarea.
-	// Los IDs se asignan secuencialmente y nunca se reutilizan.
-	ID            int       `json:"id"`
-	
-	// Titulo es la descripción de la tarea.
-	// Debe tener entre 3 y 100 caracteres y no puede estar vacío.
-	Titulo        string    `json:"titulo"`
-	
-	// Completada indica si la tarea ha sido marcada como finalizada.
-	// Las tareas nuevas siempre comienzan con false.
-	Completada    bool      `json:"completada"`
-	
-	// FechaCreacion es el timestamp UTC de cuando se creó la tarea.
-	// Se asigna automáticamente al crear la tarea.
-	FechaCreacion time.Time `json:"fecha_creacion"`
-}
+// tareasPorPagina es la cantidad de tareas que muestra cada página de los
+// listados de MostrarTareasPaginadas.
+const tareasPorPagina = 10
 
-// GestorTareas maneja la colección de tareas y su persistencia en disco.
-//
-// Este tipo es el núcleo del sistema, encapsulando todas las operaciones
-// sobre tareas (CRUD), la persistencia en JSON, y el control del autoguardado.
+// MostrarTareas imprime una lista de tareas con formato visual atractivo.
 //
-// Los campos no exportados garantizan la integridad de los datos y evitan
-// modificaciones directas desde código externo.
+// Genera una salida formateada con emojis para el estado (✅ completada, ⬜ pendiente),
+// el ID, título y fecha de creación de cada tarea, más los campos opcionales
+// que tenga (descripción, prioridad, vencimiento, etiquetas y fechas de
+// completado y de eliminación, y regla de recurrencia). Incluye un
+// encabezado con el título especificado y un resumen con el total de
+// tareas mostradas.
 //
-// Todas las operaciones que modifican tareas son thread-safe cuando se usan
-// correctamente con el autoguardado.
-type GestorTareas struct {
-	// tareas almacena la colección completa de tareas en memoria
-	tareas         []Tarea
-	
-	// archivoRuta es la ruta del archivo JSON donde se persisten las tareas
-	archivoRuta    string
-	
-	// proximoID es el siguiente ID disponible para asignar a nuevas tareas
-	proximoID      int
-	
-	// cambiosPendientes indica si hay modificaciones sin guardar en disco
-	cambiosPendientes bool
-	
-	// autoguardadoActivo indica si el goroutine de autoguardado está ejecutándose
-	autoguardadoActivo bool
-}
-
-// NuevoGestorTareas crea un nuevo gestor de tareas con persistencia en archivo.
+// Las subtareas se muestran como un árbol, sangradas bajo su tarea padre
+// cuando ambas están en la lista; las tareas con subtareas indican además
+// su porcentaje de avance, que se consulta al gestor.
 //
-// Si el archivo especificado existe, carga automáticamente las tareas desde él
-// y actualiza el próximo ID para evitar colisiones. Si el archivo no existe,
-// crea un gestor vacío que creará el archivo en el primer guardado.
+// Si la lista está vacía, muestra un mensaje indicándolo.
 //
 // Parámetros:
-//   - archivoRuta: ruta del archivo JSON para persistencia (ej: "tareas.json")
-//
-// Retorna:
-//   - *GestorTareas: puntero al gestor creado y listo para usar
-//   - error: error si hay problemas leyendo/parseando el archivo existente
+//   - gestor: gestor del que provienen las tareas, para calcular el avance
+//   - lista: slice de tareas a mostrar (puede estar vacío)
+//   - titulo: encabezado descriptivo para la lista (ej: "TAREAS PENDIENTES")
 //
 // Ejemplo:
 //
-//	gestor, err := NuevoGestorTareas("mis_tareas.json")
-//	if err != nil {
-//		return err
-//	}
+//	pendientes := gestor.ListarPendientes()
+//	MostrarTareas(gestor, pendientes, "⬜ TAREAS POR HACER")
 //
-func NuevoGestorTareas(archivoRuta string) (*GestorTareas, error) {
-	gestor := &GestorTareas{
-		tareas:         []Tarea{},
-		archivoRuta:    archivoRuta,
-		proximoID:      1,
-		cambiosPendientes: false,
-		autoguardadoActivo: false,
-	}
-
-	// Intentamos cargar tareas existentes
-	if err := gestor.Cargar(); err != nil {
-		// Si no existe el archivo, no es un error crítico
-		if !os.IsNotExist(err) {
-			return nil, err
-		}
+func MostrarTareas(gestor *tareas.GestorTareas, lista []tareas.Tarea, titulo string) {
+	if len(lista) == 0 {
+		fmt.Printf("\n%s: No hay tareas\n", titulo)
+		return
 	}
 
-	return gestor, nil
-}
+	fmt.Printf("\n%s\n", titulo)
+	fmt.Println(strings.Repeat("=", 70))
 
-// Guardar persiste todas las tareas en el archivo JSON configurado.
-//
-// Serializa la colección completa de tareas a JSON con indentación para
-// legibilidad y sobrescribe el archivo completo. Si el guardado es exitoso,
-// resetea la bandera de cambios pendientes.
-//
-// El archivo se crea con permisos 0644 (lectura para todos, escritura para dueño).
-//
-// Retorna:
-//   - error: error si falla la serialización o escritura del archivo
-//
-// Ejemplo:
-//
-//	if err := gestor.Guardar(); err != nil {
-//		log.Printf("Error al guardar: %v", err)
-//	}
-//
-func (g *GestorTareas) Guardar() error {
-	datos, err := json.MarshalIndent(g.tareas, "", "  ")
-	if err != nil {
-		return fmt.Errorf("error al serializar tareas: %v", err)
+	// Agrupamos cada subtarea bajo su padre si este también se muestra
+	enLista := make(map[int]bool)
+	for _, tarea := range lista {
+		enLista[tarea.ID] = true
+	}
+	subtareas := make(map[int][]tareas.Tarea)
+	var raices []tareas.Tarea
+	for _, tarea := range lista {
+		if tarea.PadreID != 0 && enLista[tarea.PadreID] {
+			subtareas[tarea.PadreID] = append(subtareas[tarea.PadreID], tarea)
+		} else {
+			raices = append(raices, tarea)
+		}
 	}
 
-	err = ioutil.WriteFile(g.archivoRuta, datos, 0644)
-	if err != nil {
-		return fmt.Errorf("error al escribir archivo: %v", err)
+	var mostrarRama func(tarea tareas.Tarea, nivel int)
+	mostrarRama = func(tarea tareas.Tarea, nivel int) {
+		mostrarTarea(gestor, tarea, nivel, enLista[tarea.PadreID])
+		for _, sub := range subtareas[tarea.ID] {
+			mostrarRama(sub, nivel+1)
+		}
+	}
+	for _, tarea := range raices {
+		mostrarRama(tarea, 0)
 	}
 
-	g.cambiosPendientes = false
-	return nil
+	fmt.Println(strings.Repeat("=", 70))
+	fmt.Printf("Total: %d tarea(s)\n", len(lista))
 }
 
-// Cargar lee y deserializa las tareas desde el archivo JSON.
-//
-// Lee el archivo completo, parsea el JSON a la estructura de tareas,
-// y actualiza el próximo ID basándose en el ID más alto encontrado.
-// Imprime un mensaje confirmando cuántas tareas se cargaron.
+// MostrarTareasPaginadas muestra un listado ordenado y por páginas.
 //
-// Esta función se llama automáticamente por NuevoGestorTareas, pero puede
-// invocarse manualmente para recargar tareas desde disco.
+// Pregunta primero por qué campos ordenar, con la sintaxis de
+// tareas.ParsearOrden (Enter mantiene el orden de creación), y después
+// muestra las tareas de tareasPorPagina en tareasPorPagina con MostrarTareas,
+// preguntando tras cada página si pasar a la siguiente, volver a la
+// anterior o regresar al menú.
 //
-// Retorna:
-//   - error: error si el archivo no existe, no se puede leer, o el JSON es inválido
+// Ejemplo:
 //
-// Nota: Si el archivo no existe, retorna os.IsNotExist error que puede
-// manejarse con os.IsNotExist(err).
+//	MostrarTareasPaginadas(entrada, gestor, gestor.ListarPendientes(), "⬜ TAREAS PENDIENTES")
 //
-func (g *GestorTareas) Cargar() error {
-	datos, err := ioutil.ReadFile(g.archivoRuta)
-	if err != nil {
-		return err
+func MostrarTareasPaginadas(entrada *consola.Entrada, gestor *tareas.GestorTareas, lista []tareas.Tarea, titulo string) {
+	var orden tareas.Orden
+	if len(lista) > 1 {
+		var err error
+		if orden, err = tareas.ParsearOrden(entrada.LeerLinea("↕️  Ordenar por (Enter = creación; ej.: -prioridad,vencimiento): ")); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
 	}
 
-	err = json.Unmarshal(datos, &g.tareas)
-	if err != nil {
-		return fmt.Errorf("error al parsear JSON: %v", err)
-	}
+	p := tareas.Paginacion{Orden: orden, Limite: tareasPorPagina}
+	for {
+		pagina, err := tareas.Paginar(lista, p)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		if pagina.Total <= tareasPorPagina {
+			MostrarTareas(gestor, pagina.Tareas, titulo)
+			return
+		}
+
+		numero := pagina.Desplazamiento/tareasPorPagina + 1
+		paginas := (pagina.Total + tareasPorPagina - 1) / tareasPorPagina
+		MostrarTareas(gestor, pagina.Tareas, fmt.Sprintf("%s (página %d de %d)", titulo, numero, paginas))
+		fmt.Printf("Mostrando %d-%d de %d\n", pagina.Desplazamiento+1, pagina.Desplazamiento+len(pagina.Tareas), pagina.Total)
 
-	// Actualizamos el próximo ID
-	for _, tarea := range g.tareas {
-		if tarea.ID >= g.proximoID {
-			g.proximoID = tarea.ID + 1
+		indicacion := "➡️  [Enter] página siguiente, [a] anterior, [q] volver al menú: "
+		if numero == paginas {
+			indicacion = "⏹️  Última página: [a] anterior, [Enter] volver al menú: "
+		}
+		switch strings.ToLower(entrada.LeerLinea(indicacion)) {
+		case "a":
+			p.Desplazamiento = max(0, p.Desplazamiento-tareasPorPagina)
+		case "":
+			if numero == paginas {
+				return
+			}
+			p.Desplazamiento += tareasPorPagina
+		default:
+			return
 		}
 	}
-
-	fmt.Printf("✓ Cargadas %d tarea(s) desde %s\n", len(g.tareas), g.archivoRuta)
-	return nil
 }
 
-// ValidarTitulo valida que el título de una tarea cumpla todos los requisitos.
-//
-// El título se considera válido si:
-//   - No está vacío después de eliminar espacios en blanco
-//   - Tiene al menos 3 caracteres
-//   - No excede los 100 caracteres
-//
-// Esta función se usa internamente por Crear antes de añadir una nueva tarea.
-//
-// Parámetros:
-//   - titulo: el título a validar (puede contener espacios al inicio/fin)
+// MostrarResultados imprime los resultados de una búsqueda por relevancia
+// en su orden, de más a menos relevante, con la puntuación de cada uno.
 //
-// Retorna:
-//   - error: error descriptivo si la validación falla, nil si es válido
-//
-// Ejemplo:
-//
-//	if err := ValidarTitulo("Comprar leche"); err != nil {
-//		fmt.Println("Título inválido:", err)
-//	}
-//
-func ValidarTitulo(titulo string) error {
-	titulo = strings.TrimSpace(titulo)
-	
-	if titulo == "" {
-		return fmt.Errorf("el título no puede estar vacío")
-	}
-	
-	if len(titulo) < 3 {
-		return fmt.Errorf("el título debe tener al menos 3 caracteres")
-	}
-	
-	if len(titulo) > 100 {
-		return fmt.Errorf("el título no puede exceder 100 caracteres")
-	}
-	
-	return nil
+// A diferencia de MostrarTareas no agrupa las subtareas bajo su padre, para
+// no alterar el orden de los resultados.
+func MostrarResultados(gestor *tareas.GestorTareas, resultados []tareas.ResultadoBusqueda, titulo string) {
+	if len(resultados) == 0 {
+		fmt.Printf("\n%s: No hay tareas\n", titulo)
+		return
+	}
+
+	fmt.Printf("\n%s\n", titulo)
+	fmt.Println(strings.Repeat("=", 70))
+	for i, r := range resultados {
+		fmt.Printf("#%d  ⭐ Relevancia: %.2f\n", i+1, r.Puntuacion)
+		mostrarTarea(gestor, r.Tarea, 0, false)
+	}
+	fmt.Println(strings.Repeat("=", 70))
+	fmt.Printf("Total: %d tarea(s)\n", len(resultados))
 }
 
-// Crear añade una nueva tarea a la colección con el título especificado.
-//
-// Valida el título usando ValidarTitulo, asigna un ID único auto-incremental,
-// establece el estado como no completada, y registra el timestamp de creación.
-// Los espacios en blanco al inicio/fin del título se eliminan automáticamente.
-//
-// Marca el gestor como teniendo cambios pendientes para el autoguardado.
-//
-// Parámetros:
-//   - titulo: descripción de la tarea (se validará longitud)
-//
-// Retorna:
-//   - *Tarea: puntero a la tarea recién creada
-//   - error: error si la validación del título falla
-//
-// Ejemplo:
-//
-//	tarea, err := gestor.Crear("Estudiar goroutines")
-//	if err != nil {
-//		return err
-//	}
-//	fmt.Printf("Tarea creada con ID: %d\n", tarea.ID)
-//
-func (g *GestorTareas) Crear(titulo string) (*Tarea, error) {
-	// Validamos el título
-	if err := ValidarTitulo(titulo); err != nil {
-		return nil, err
+// MostrarInforme imprime el informe de una importación (o de su
+// simulación): las tareas creadas, las duplicadas omitidas y los errores
+// de cada línea.
+func MostrarInforme(informe *tareas.InformeImportacion) {
+	titulo := "📥 TAREAS IMPORTADAS"
+	if informe.Simulacion {
+		titulo = "📥 TAREAS QUE SE IMPORTARÍAN"
 	}
+	fmt.Printf("\n%s\n", titulo)
+	fmt.Println(strings.Repeat("=", 70))
+	for _, t := range informe.Creadas {
+		estado := "⬜"
+		if t.Completada {
+			estado = "✅"
+		}
+		fmt.Printf("%s [%d] %s", estado, t.ID, t.Titulo)
+		if t.PadreID != 0 {
+			fmt.Printf(" (subtarea de %d)", t.PadreID)
+		}
+		fmt.Println()
+	}
+	for _, d := range informe.Duplicadas {
+		fmt.Printf("♊ Línea %d: '%s' ya existe (tarea %d)\n", d.Linea, d.Titulo, d.ExistenteID)
+	}
+	for _, e := range informe.Errores {
+		fmt.Printf("❌ Línea %d: %s\n", e.Linea, e.Mensaje)
+	}
+	fmt.Println(strings.Repeat("=", 70))
+	fmt.Printf("Total: %s\n", informe)
+}
 
-	tarea := Tarea{
-		ID:            g.proximoID,
-		Titulo:        strings.TrimSpace(titulo),
-		Completada:    false,
-		FechaCreacion: time.Now(),
+// mostrarTarea imprime una tarea de MostrarTareas sangrada según su nivel
+// en el árbol. padreVisible indica si su tarea padre aparece encima; si no,
+// se indica de qué tarea es subtarea.
+func mostrarTarea(gestor *tareas.GestorTareas, tarea tareas.Tarea, nivel int, padreVisible bool) {
+	sangria := strings.Repeat("    ", nivel)
+	rama := ""
+	if nivel > 0 {
+		rama = "↳ "
 	}
 
-	g.tareas = append(g.tareas, tarea)
-	g.proximoID++
-	g.cambiosPendientes = true
+	estado := "⬜"
+	if tarea.Completada {
+		estado = "✅"
+	}
 
-	return &tarea, nil
+	fecha := tarea.FechaCreacion.Format("02/01/2006 15:04")
+	fmt.Printf("%s%s%s [%d] %s\n", sangria, rama, estado, tarea.ID, tarea.Titulo)
+	if tarea.UID != "" {
+		fmt.Printf("%s    🆔 UID: %s\n", sangria, tarea.UID)
+	}
+	if tarea.PadreID != 0 && !padreVisible {
+		fmt.Printf("%s    ⤴️  Subtarea de: %d\n", sangria, tarea.PadreID)
+	}
+	if tarea.Lista != "" && nivel == 0 {
+		fmt.Printf("%s    📂 Lista: %s\n", sangria, tarea.Lista)
+	}
+	if tarea.Propietario != "" {
+		fmt.Printf("%s    👤 Propietario: %s\n", sangria, tarea.Propietario)
+	}
+	if len(tarea.Asignados) > 0 {
+		fmt.Printf("%s    👥 Asignada a: %s\n", sangria, strings.Join(tarea.Asignados, ", "))
+	}
+	if len(tarea.Dependencias) > 0 {
+		fmt.Printf("%s    🔗 Depende de: %s\n", sangria, unirIDs(tarea.Dependencias))
+		if pendientes, err := gestor.RequisitosPendientes(tarea.ID); err == nil && len(pendientes) > 0 && !tarea.Completada {
+			fmt.Printf("%s    🔒 Bloqueada por: %s\n", sangria, unirIDs(pendientes))
+		}
+	}
+	if len(gestor.ListarSubtareas(tarea.ID)) > 0 {
+		if porcentaje, err := gestor.PorcentajeCompletado(tarea.ID); err == nil {
+			fmt.Printf("%s    📊 Progreso: %d%%\n", sangria, porcentaje)
+		}
+	}
+	if tarea.Descripcion != "" {
+		fmt.Printf("%s    📝 %s\n", sangria, tarea.Descripcion)
+	}
+	if tarea.Prioridad != "" {
+		fmt.Printf("%s    🔥 Prioridad: %s\n", sangria, tarea.Prioridad)
+	}
+	fmt.Printf("%s    📅 Creada: %s\n", sangria, fecha)
+	if !tarea.FechaVencimiento.IsZero() {
+		aviso := ""
+		if tarea.Vencida(time.Now()) {
+			aviso = " (¡vencida!)"
+		}
+		fmt.Printf("%s    ⏰ Vence: %s%s\n", sangria, tarea.FechaVencimiento.Format("02/01/2006 15:04"), aviso)
+	}
+	if !tarea.FechaCompletada.IsZero() {
+		fmt.Printf("%s    🏁 Completada: %s\n", sangria, tarea.FechaCompletada.Format("02/01/2006 15:04"))
+	}
+	if !tarea.FechaActualizacion.IsZero() {
+		fmt.Printf("%s    ✏️  Actualizada: %s\n", sangria, tarea.FechaActualizacion.Format("02/01/2006 15:04"))
+	}
+	if tarea.EnPapelera() {
+		fmt.Printf("%s    🗑️  Eliminada: %s\n", sangria, tarea.FechaEliminada.Format("02/01/2006 15:04"))
+	}
+	if len(tarea.Etiquetas) > 0 {
+		fmt.Printf("%s    🏷️  #%s\n", sangria, strings.Join(tarea.Etiquetas, " #"))
+	}
+	if tarea.Recurrencia != nil {
+		fmt.Printf("%s    🔁 Se repite: %s\n", sangria, tarea.Recurrencia)
+	}
 }
 
-// Listar retorna todas las tareas sin filtrar.
-//
-// Devuelve una copia del slice de tareas, incluyendo tanto completadas
-// como pendientes, en el orden en que fueron creadas.
-//
-// Retorna:
-//   - []Tarea: slice con todas las tareas (puede estar vacío)
-//
-// Ver también: ListarPendientes, ListarCompletadas
-//
-func (g *GestorTareas) Listar() []Tarea {
-	return g.tareas
+// unirIDs formatea una lista de IDs como "#1, #3".
+func unirIDs(ids []int) string {
+	partes := make([]string, len(ids))
+	for i, id := range ids {
+		partes[i] = fmt.Sprintf("#%d", id)
+	}
+	return strings.Join(partes, ", ")
 }
 
-// ListarPendientes retorna solo las tareas que no han sido completadas.
+// pedirDatosTarea solicita por consola el título y los campos opcionales de
+// una nueva tarea. Los campos opcionales se omiten pulsando Enter.
 //
-// Filtra la colección completa y retorna únicamente las tareas con
-// Completada == false.
-//
-// Retorna:
-//   - []Tarea: slice con tareas pendientes (vacío si no hay pendientes)
-//
-// Ejemplo:
-//
-//	pendientes := gestor.ListarPendientes()
-//	fmt.Printf("Tienes %d tareas pendientes\n", len(pendientes))
-//
-func (g *GestorTareas) ListarPendientes() []Tarea {
-	var pendientes []Tarea
-	for _, tarea := range g.tareas {
-		if !tarea.Completada {
-			pendientes = append(pendientes, tarea)
-		}
+// Retorna error si la fecha de vencimiento no tiene el formato dd/mm/aaaa;
+// el resto de validaciones las realiza el gestor al crear la tarea.
+func pedirDatosTarea(entrada *consola.Entrada) (tareas.DatosTarea, error) {
+	var datos tareas.DatosTarea
+
+	datos.Titulo = entrada.LeerLinea("\n📝 Título de la tarea: ")
+
+	prioridad := entrada.LeerLinea("🔥 Prioridad (baja/media/alta/urgente, Enter para omitir): ")
+	datos.Prioridad = tareas.Prioridad(strings.ToLower(prioridad))
+
+	vencimiento := entrada.LeerLinea("⏰ Vencimiento (dd/mm/aaaa, Enter para omitir): ")
+	fechaVencimiento, err := parsearVencimiento(vencimiento)
+	if err != nil {
+		return datos, err
 	}
-	return pendientes
-}
+	datos.FechaVencimiento = fechaVencimiento
 
-// ListarCompletadas retorna solo las tareas que han sido marcadas como completadas.
-//
-// Filtra la colección completa y retorna únicamente las tareas con
-// Completada == true.
-//
-// Retorna:
-//   - []Tarea: slice con tareas completadas (vacío si no hay completadas)
-//
-// Ejemplo:
-//
-//	completadas := gestor.ListarCompletadas()
-//	fmt.Printf("Has completado %d tareas\n", len(completadas))
-//
-func (g *GestorTareas) ListarCompletadas() []Tarea {
-	var completadas []Tarea
-	for _, tarea := range g.tareas {
-		if tarea.Completada {
-			completadas = append(completadas, tarea)
-		}
+	etiquetas := entrada.LeerLinea("🏷️  Etiquetas separadas por comas (Enter para omitir): ")
+	if etiquetas != "" {
+		datos.Etiquetas = strings.Split(etiquetas, ",")
 	}
-	return completadas
-}
 
-// BuscarPorID encuentra y retorna una tarea específica por su identificador único.
-//
-// Realiza una búsqueda lineal en la colección de tareas. Si encuentra
-// una tarea con el ID especificado, retorna un puntero a ella.
-//
-// Parámetros:
-//   - id: el identificador único de la tarea a buscar
-//
-// Retorna:
-//   - *Tarea: puntero a la tarea encontrada
-//   - error: error si no existe ninguna tarea con ese ID
-//
-// Ejemplo:
-//
-//	tarea, err := gestor.BuscarPorID(5)
-//	if err != nil {
-//		fmt.Println("Tarea no encontrada")
-//	} else {
-//		fmt.Println("Tarea:", tarea.Titulo)
-//	}
-//
-func (g *GestorTareas) BuscarPorID(id int) (*Tarea, error) {
-	for i := range g.tareas {
-		if g.tareas[i].ID == id {
-			return &g.tareas[i], nil
+	padre := entrada.LeerLinea("🌳 ID de la tarea padre (Enter si no es subtarea): ")
+	if padre != "" {
+		id, err := strconv.Atoi(padre)
+		if err != nil {
+			return datos, fmt.Errorf("ID de tarea padre inválido %q", padre)
 		}
+		datos.PadreID = id
 	}
-	return nil, fmt.Errorf("tarea con ID %d no encontrada", id)
-}
 
-// BuscarPorTexto encuentra todas las tareas cuyos títulos contengan el texto especificado.
-//
-// Realiza una búsqueda case-insensitive (no distingue mayúsculas/minúsculas)
-// en todos los títulos de tareas. Retorna todas las coincidencias encontradas.
-//
-// Si el texto está vacío, retorna todas las tareas.
-//
-// Parámetros:
-//   - texto: el texto a buscar en los títulos (case-insensitive)
-//
-// Retorna:
-//   - []Tarea: slice con todas las tareas que contienen el texto (puede estar vacío)
-//
-// Ejemplo:
-//
-//	resultados := gestor.BuscarPorTexto("comprar")
-//	fmt.Printf("Se encontraron %d tareas\n", len(resultados))
-//	for _, t := range resultados {
-//		fmt.Printf("  - %s\n", t.Titulo)
-//	}
-//
-func (g *GestorTareas) BuscarPorTexto(texto string) []Tarea {
-	var encontradas []Tarea
-	textoBusqueda := strings.ToLower(texto)
-
-	for _, tarea := range g.tareas {
-		if strings.Contains(strings.ToLower(tarea.Titulo), textoBusqueda) {
-			encontradas = append(encontradas, tarea)
+	frecuencia := entrada.LeerLinea("🔁 Repetir (diaria/semanal/mensual, Enter para omitir): ")
+	if frecuencia != "" {
+		recurrencia, err := pedirRecurrencia(entrada, frecuencia)
+		if err != nil {
+			return datos, err
 		}
+		datos.Recurrencia = recurrencia
 	}
 
-	return encontradas
+	return datos, nil
 }
 
-// Completar marca una tarea específica como completada.
-//
-// Busca la tarea por su ID y establece su campo Completada en true.
-// Verifica que la tarea no esté ya completada para evitar operaciones redundantes.
-//
-// Marca el gestor como teniendo cambios pendientes para el autoguardado.
-//
-// Parámetros:
-//   - id: el identificador único de la tarea a completar
-//
-// Retorna:
-//   - error: error si el ID no existe o la tarea ya está completada
-//
-// Ejemplo:
-//
-//	if err := gestor.Completar(3); err != nil {
-//		fmt.Println("Error:", err)
-//	} else {
-//		fmt.Println("Tarea completada exitosamente")
-//	}
-//
-func (g *GestorTareas) Completar(id int) error {
-	for i := range g.tareas {
-		if g.tareas[i].ID == id {
-			if g.tareas[i].Completada {
-				return fmt.Errorf("la tarea ya está completada")
-			}
-			g.tareas[i].Completada = true
-			g.cambiosPendientes = true
-			return nil
+// pedirRecurrencia completa por consola una regla de recurrencia con la
+// frecuencia indicada: el intervalo, los días de la semana (solo si es
+// semanal) y la fecha de fin. Retorna error si algún dato no tiene un
+// formato válido; el resto de validaciones las realiza el gestor.
+func pedirRecurrencia(entrada *consola.Entrada, frecuencia string) (*tareas.Recurrencia, error) {
+	recurrencia := &tareas.Recurrencia{Frecuencia: tareas.Frecuencia(strings.ToLower(frecuencia))}
+
+	intervalo := entrada.LeerLinea("   Cada cuántas unidades (Enter = 1): ")
+	if intervalo != "" {
+		n, err := strconv.Atoi(intervalo)
+		if err != nil {
+			return nil, fmt.Errorf("intervalo inválido %q", intervalo)
 		}
+		recurrencia.Intervalo = n
 	}
-	return fmt.Errorf("tarea con ID %d no encontrada", id)
-}
 
-// Eliminar remueve permanentemente una tarea de la colección.
-//
-// Busca la tarea por su ID y la elimina del slice. Esta operación
-// es irreversible y el ID eliminado no se reutiliza.
-//
-// Marca el gestor como teniendo cambios pendientes para el autoguardado.
-//
-// Parámetros:
-//   - id: el identificador único de la tarea a eliminar
-//
-// Retorna:
-//   - error: error si no existe ninguna tarea con ese ID
-//
-// Ejemplo:
-//
-//	if err := gestor.Eliminar(7); err != nil {
-//		fmt.Println("No se pudo eliminar:", err)
-//	} else {
-//		fmt.Println("Tarea eliminada")
-//	}
-//
-func (g *GestorTareas) Eliminar(id int) error {
-	for i := range g.tareas {
-		if g.tareas[i].ID == id {
-			g.tareas = append(g.tareas[:i], g.tareas[i+1:]...)
-			g.cambiosPendientes = true
-			return nil
+	if recurrencia.Frecuencia == tareas.FrecuenciaSemanal {
+		dias := entrada.LeerLinea("   Días separados por comas, ej. lun,jue (Enter = el mismo día): ")
+		lista, err := tareas.ParsearDiasSemana(dias)
+		if err != nil {
+			return nil, err
 		}
+		recurrencia.DiasSemana = lista
 	}
-	return fmt.Errorf("tarea con ID %d no encontrada", id)
-}
 
-// Estadisticas calcula y retorna estadísticas sobre las tareas.
-//
-// Recorre todas las tareas y cuenta el total, cuántas están completadas,
-// y cuántas están pendientes. Es útil para mostrar resúmenes al usuario.
-//
-// Retorna (retornos con nombre):
-//   - total: número total de tareas en la colección
-//   - completadas: número de tareas con Completada == true
-//   - pendientes: número de tareas con Completada == false
-//
-// Ejemplo:
-//
-//	total, completadas, pendientes := gestor.Estadisticas()
-//	fmt.Printf("Total: %d | Completadas: %d | Pendientes: %d\n",
-//		total, completadas, pendientes)
-//
-func (g *GestorTareas) Estadisticas() (total, completadas, pendientes int) {
-	total = len(g.tareas)
-	for _, tarea := range g.tareas {
-		if tarea.Completada {
-			completadas++
-		} else {
-			pendientes++
+	hasta := entrada.LeerLinea("   Hasta (dd/mm/aaaa, Enter = sin fin): ")
+	if hasta != "" {
+		dia, err := time.ParseInLocation("02/01/2006", hasta, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("fecha de fin inválida %q, usa dd/mm/aaaa", hasta)
 		}
+		recurrencia.Hasta = dia
 	}
-	return
+
+	return recurrencia, nil
 }
 
-// IniciarAutoguardado inicia una goroutine que guarda automáticamente las tareas periódicamente.
-//
-// Crea un ticker que dispara cada intervalo especificado. En cada tick,
-// verifica si hay cambios pendientes y solo guarda si es necesario para
-// optimizar I/O. La goroutine se ejecuta en segundo plano hasta recibir
-// una señal por el canal detener.
-//
-// Solo puede haber un autoguardado activo a la vez. Llamadas adicionales
-// son ignoradas mientras hay uno en ejecución.
-//
-// Parámetros:
-//   - intervalo: frecuencia de guardado (ej: 30*time.Second para cada 30s)
-//   - detener: canal de solo lectura para señalizar terminación del autoguardado
-//
-// Ejemplo:
-//
-//	detener := make(chan bool)
-//	gestor.IniciarAutoguardado(30*time.Second, detener)
-//	defer func() { detener <- true }()
-//	// ... realizar operaciones ...
-//
-// Nota: Es responsabilidad del llamador cerrar o enviar señal por el canal
-// cuando desee detener el autoguardado.
+// pedirCambiosTarea solicita por consola los campos a modificar de una tarea.
 //
-func (g *GestorTareas) IniciarAutoguardado(intervalo time.Duration, detener <-chan bool) {
-	if g.autoguardadoActivo {
-		return
+// Enter mantiene el valor actual; "-" quita la fecha de vencimiento, las
+// etiquetas, la tarea padre o la recurrencia. Retorna error si la fecha, el
+// estado, el ID de la tarea padre o la recurrencia no tienen un formato válido.
+func pedirCambiosTarea(entrada *consola.Entrada) (tareas.CambiosTarea, error) {
+	var cambios tareas.CambiosTarea
+
+	titulo := entrada.LeerLinea("📝 Nuevo título (Enter para mantener): ")
+	if titulo != "" {
+		cambios.Titulo = &titulo
 	}
-	
-	g.autoguardadoActivo = true
-	
-	go func() {
-		ticker := time.NewTicker(intervalo)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ticker.C:
-				if g.cambiosPendientes {
-					if err := g.Guardar(); err != nil {
-						fmt.Printf("\n⚠️  Error en autoguardado: %v\n", err)
-					} else {
-						fmt.Printf("\n💾 Autoguardado realizado (%s)\n", time.Now().Format("15:04:05"))
-					}
-				}
-			case <-detener:
-				fmt.Println("\n🛑 Autoguardado detenido")
-				return
-			}
+
+	prioridad := entrada.LeerLinea("🔥 Nueva prioridad (baja/media/alta/urgente, Enter para mantener): ")
+	if prioridad != "" {
+		p := tareas.Prioridad(strings.ToLower(prioridad))
+		cambios.Prioridad = &p
+	}
+
+	vencimiento := entrada.LeerLinea("⏰ Nuevo vencimiento (dd/mm/aaaa, - para quitar, Enter para mantener): ")
+	switch vencimiento {
+	case "":
+	case "-":
+		cambios.FechaVencimiento = &time.Time{}
+	default:
+		fin, err := parsearVencimiento(vencimiento)
+		if err != nil {
+			return cambios, err
 		}
-	}()
-}
+		cambios.FechaVencimiento = &fin
+	}
 
-// MostrarTareas imprime una lista de tareas con formato visual atractivo.
-//
-// Genera una salida formateada con emojis para el estado (✅ completada, ⬜ pendiente),
-// el ID, título y fecha de creación de cada tarea. Incluye un encabezado con el
-// título especificado y un resumen con el total de tareas mostradas.
-//
-// Si la lista está vacía, muestra un mensaje indicándolo.
-//
-// Parámetros:
-//   - tareas: slice de tareas a mostrar (puede estar vacío)
-//   - titulo: encabezado descriptivo para la lista (ej: "TAREAS PENDIENTES")
-//
-// Ejemplo:
-//
-//	pendientes := gestor.ListarPendientes()
-//	MostrarTareas(pendientes, "⬜ TAREAS POR HACER")
-//
-func MostrarTareas(tareas []Tarea, titulo string) {
-	if len(tareas) == 0 {
-		fmt.Printf("\n%s: No hay tareas\n", titulo)
-		return
+	etiquetas := entrada.LeerLinea("🏷️  Nuevas etiquetas separadas por comas (- para quitar, Enter para mantener): ")
+	switch etiquetas {
+	case "":
+	case "-":
+		cambios.Etiquetas = &[]string{}
+	default:
+		lista := strings.Split(etiquetas, ",")
+		cambios.Etiquetas = &lista
 	}
 
-	fmt.Printf("\n%s\n", titulo)
-	fmt.Println(strings.Repeat("=", 70))
+	padre := entrada.LeerLinea("🌳 Nueva tarea padre (ID, - para quitar, Enter para mantener): ")
+	switch padre {
+	case "":
+	case "-":
+		cambios.PadreID = new(int)
+	default:
+		id, err := strconv.Atoi(padre)
+		if err != nil {
+			return cambios, fmt.Errorf("ID de tarea padre inválido %q", padre)
+		}
+		cambios.PadreID = &id
+	}
 
-	for _, tarea := range tareas {
-		estado := "⬜"
-		if tarea.Completada {
-			estado = "✅"
+	frecuencia := entrada.LeerLinea("🔁 Repetir (diaria/semanal/mensual, - para no repetir, Enter para mantener): ")
+	switch frecuencia {
+	case "":
+	case "-":
+		cambios.Recurrencia = &tareas.Recurrencia{}
+	default:
+		recurrencia, err := pedirRecurrencia(entrada, frecuencia)
+		if err != nil {
+			return cambios, err
 		}
+		cambios.Recurrencia = recurrencia
+	}
 
-		fecha := tarea.FechaCreacion.Format("02/01/2006 15:04")
-		fmt.Printf("%s [%d] %s\n", estado, tarea.ID, tarea.Titulo)
-		fmt.Printf("    📅 Creada: %s\n", fecha)
+	completada := entrada.LeerLinea("✔️  ¿Completada? (s/n, Enter para mantener): ")
+	switch strings.ToLower(completada) {
+	case "":
+	case "s":
+		si := true
+		cambios.Completada = &si
+	case "n":
+		no := false
+		cambios.Completada = &no
+	default:
+		return cambios, fmt.Errorf("respuesta inválida %q, usa s o n", completada)
 	}
 
-	fmt.Println(strings.Repeat("=", 70))
-	fmt.Printf("Total: %d tarea(s)\n", len(tareas))
+	return cambios, nil
 }
 
 func main() {
+	// Las opciones globales pueden ir antes del comando: todo --file otro.json list
+	opciones := opcionesGlobales{archivo: archivoPorDefecto, lista: os.Getenv(variableLista), usuario: os.Getenv(variableUsuario)}
+	flags := flag.NewFlagSet("todo", flag.ContinueOnError)
+	flags.Usage = func() { mostrarAyuda(os.Stderr) }
+	opciones.registrar(flags)
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(salidaOK)
+		}
+		os.Exit(salidaUso)
+	}
+
+	// Sin comando se abre el menú interactivo
+	if flags.NArg() == 0 {
+		ejecutarMenu(opciones.archivo, opciones.lista, opciones.usuario)
+		return
+	}
+	os.Exit(ejecutarComando(opciones, flags.Args(), os.Stdout, os.Stderr))
+}
+
+// ejecutarMenu muestra el menú interactivo sobre el archivo de tareas
+// indicado, hasta que se elige salir. Con listaActiva, los listados y las
+// tareas nuevas se limitan a esa lista hasta que se cambie (opción 23).
+// Con usuario, las tareas se crean y modifican en su nombre.
+func ejecutarMenu(archivo, listaActiva, usuario string) {
 	fmt.Println("╔═══════════════════════════════════════════════════════╗")
 	fmt.Println("║     SISTEMA DE GESTIÓN DE TAREAS - TODO CLI         ║")
 	fmt.Println("╚═══════════════════════════════════════════════════════╝")
 
 	// Creamos el gestor de tareas
-	gestor, err := NuevoGestorTareas("tareas.json")
+	// El diario registra cada cambio al instante, así un cierre abrupto
+	// entre autoguardados no pierde trabajo
+	if usuario != "" {
+		if err := tareas.ValidarUsuario(usuario); err != nil {
+			fmt.Printf("Error fatal al iniciar: %v\n", err)
+			return
+		}
+	}
+	gestor, err := tareas.NuevoGestorTareas(archivo, tareas.ConDiario(archivo+".diario"),
+		tareas.ConAuditoria(archivo+".auditoria"), tareas.ConUsuario(usuario))
 	if err != nil {
 		fmt.Printf("Error fatal al iniciar: %v\n", err)
 		return
 	}
 
+	// Todas las preguntas leen líneas completas de la misma entrada, con
+	// edición e historial si es un terminal
+	entrada := consola.NuevaEntrada(os.Stdin, os.Stdout)
+
 	// Iniciamos el autoguardado cada 30 segundos
 	detenerAutoguardado := make(chan bool)
 	gestor.IniciarAutoguardado(30*time.Second, detenerAutoguardado)
 
+	// Una lista activa inexistente se informa y se trabaja con todas
+	if _, _, _, err := gestor.EstadisticasLista(listaActiva); listaActiva != "" && err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		listaActiva = ""
+	}
+
 	// Menú principal
 	for {
 		total, completadas, pendientes := gestor.Estadisticas()
+		if listaActiva != "" {
+			total, completadas, pendientes, _ = gestor.EstadisticasLista(listaActiva)
+		}
 
 		fmt.Println("\n┌─────────────────────────────────────────────────────┐")
+		if gestor.Usuario() != "" {
+			fmt.Printf("│ 👤 Usuario: %s\n", gestor.Usuario())
+		}
+		if listaActiva != "" {
+			fmt.Printf("│ 📂 Lista: %s\n", listaActiva)
+		}
 		fmt.Printf("│ Tareas: %d total | %d completadas | %d pendientes    \n", total, completadas, pendientes)
 		fmt.Println("└─────────────────────────────────────────────────────┘")
 		fmt.Println("\n📋 MENÚ PRINCIPAL")
@@ -649,20 +532,50 @@ func main() {
 		fmt.Println("6. ✔️  Completar tarea")
 		fmt.Println("7. 🗑️  Eliminar tarea")
 		fmt.Println("8. 💾 Guardar ahora")
-		fmt.Println("9. 🚪 Salir")
-
-		var opcion int
-		fmt.Print("\n➤ Selecciona una opción: ")
-		fmt.Scanln(&opcion)
+		fmt.Println("9. ⏰ Listar vencidas")
+		fmt.Println("10. 🏷️  Listar por etiqueta")
+		fmt.Println("11. ✏️  Editar tarea")
+		fmt.Println("12. ↩️  Deshacer")
+		fmt.Println("13. ↪️  Rehacer")
+		fmt.Println("14. 🗑️  Ver papelera")
+		fmt.Println("15. ♻️  Restaurar tarea")
+		fmt.Println("16. 🔥 Vaciar papelera")
+		fmt.Println("17. 🔗 Agregar dependencia")
+		fmt.Println("18. ✂️  Quitar dependencia")
+		fmt.Println("19. ⏭️  ¿Qué hago ahora?")
+		fmt.Println("20. 📤 Exportar tareas")
+		fmt.Println("21. 📥 Importar tareas")
+		fmt.Println("22. 🖥️  Pantalla completa")
+		fmt.Println("23. 📂 Listas")
+		fmt.Println("24. 👤 Mis tareas")
+		fmt.Println("25. 👥 Asignar tarea")
+		fmt.Println("26. 🕓 Historial de una tarea")
+		fmt.Println("27. 📈 Informe de productividad")
+		fmt.Println("0. 🚪 Salir")
+
+		// -1 para que una entrada vacía o inválida no se confunda con "Salir"
+		opcion := entrada.LeerEntero("\n➤ Selecciona una opción: ", -1)
+		if opcion == -1 && entrada.Cerrada() {
+			// Sin más entrada (Ctrl-D o fin de la tubería) se sale guardando
+			opcion = 0
+		}
 
 		switch opcion {
 		case 1:
 			// Crear tarea
-			fmt.Print("\n📝 Título de la tarea: ")
-			var titulo string
-			fmt.Scanln(&titulo)
+			datos, err := pedirDatosTarea(entrada)
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				continue
+			}
 
-			tarea, err := gestor.Crear(titulo)
+			datos.Lista = listaActiva
+			if gestor.Usuario() != "" {
+				if asignados := entrada.LeerLinea("👥 Asignar a (usuarios separados por comas, Enter para omitir): "); asignados != "" {
+					datos.Asignados = strings.Split(asignados, ",")
+				}
+			}
+			tarea, err := gestor.CrearConDatos(datos)
 			if err != nil {
 				fmt.Printf("❌ Error: %v\n", err)
 			} else {
@@ -671,65 +584,75 @@ func main() {
 
 		case 2:
 			// Listar todas
-			MostrarTareas(gestor.Listar(), "📋 TODAS LAS TAREAS")
+			MostrarTareasPaginadas(entrada, gestor, filtrarPorLista(gestor.Listar(), listaActiva), "📋 TODAS LAS TAREAS")
 
 		case 3:
 			// Listar pendientes
-			MostrarTareas(gestor.ListarPendientes(), "⬜ TAREAS PENDIENTES")
+			MostrarTareasPaginadas(entrada, gestor, filtrarPorLista(gestor.ListarPendientes(), listaActiva), "⬜ TAREAS PENDIENTES")
 
 		case 4:
 			// Listar completadas
-			MostrarTareas(gestor.ListarCompletadas(), "✅ TAREAS COMPLETADAS")
+			MostrarTareasPaginadas(entrada, gestor, filtrarPorLista(gestor.ListarCompletadas(), listaActiva), "✅ TAREAS COMPLETADAS")
 
 		case 5:
 			// Buscar tarea
-			fmt.Print("\n🔍 Buscar por (1=ID, 2=Texto): ")
-			var tipoBusqueda int
-			fmt.Scanln(&tipoBusqueda)
+			tipoBusqueda := entrada.LeerEntero("\n🔍 Buscar por (1=ID, 2=Consulta, 3=Texto aproximado): ", 0)
 
-			if tipoBusqueda == 1 {
+			switch tipoBusqueda {
+			case 1:
 				// Buscar por ID
-				var id int
-				fmt.Print("ID de la tarea: ")
-				fmt.Scanln(&id)
+				id := entrada.LeerEntero("ID de la tarea: ", 0)
 
 				tarea, err := gestor.BuscarPorID(id)
 				if err != nil {
 					fmt.Printf("❌ %v\n", err)
 				} else {
-					MostrarTareas([]Tarea{*tarea}, "🔍 RESULTADO DE BÚSQUEDA")
+					MostrarTareas(gestor, []tareas.Tarea{*tarea}, "🔍 RESULTADO DE BÚSQUEDA")
 				}
-			} else {
-				// Buscar por texto
-				var texto string
-				fmt.Print("Texto a buscar: ")
-				fmt.Scanln(&texto)
+			case 3:
+				// Buscar tolerando tildes y errores, por relevancia
+				texto := entrada.LeerLinea("Texto: ")
 
-				encontradas := gestor.BuscarPorTexto(texto)
-				if len(encontradas) == 0 {
+				resultados := filtrarResultadosPorLista(gestor.BuscarPorRelevancia(texto, tareas.ToleranciaPorDefecto), listaActiva)
+				if len(resultados) == 0 {
 					fmt.Println("❌ No se encontraron tareas")
 				} else {
-					MostrarTareas(encontradas, fmt.Sprintf("🔍 RESULTADOS (contienen '%s')", texto))
+					MostrarResultados(gestor, resultados, fmt.Sprintf("🔍 RESULTADOS POR RELEVANCIA (%s)", texto))
+				}
+			default:
+				// Buscar con el lenguaje de consultas
+				fmt.Println("   Ej.: informe \"texto exacto\" -excluir estado:pendiente prioridad:>=alta tag:trabajo creada:>2026-01-01")
+				consulta := entrada.LeerLinea("Consulta: ")
+
+				encontradas, err := gestor.Buscar(consulta)
+				encontradas = filtrarPorLista(encontradas, listaActiva)
+				switch {
+				case err != nil:
+					fmt.Printf("❌ %v\n", err)
+				case len(encontradas) == 0:
+					fmt.Println("❌ No se encontraron tareas")
+				default:
+					MostrarTareasPaginadas(entrada, gestor, encontradas, fmt.Sprintf("🔍 RESULTADOS (%s)", consulta))
 				}
 			}
 
 		case 6:
 			// Completar tarea
-			var id int
-			fmt.Print("\n✔️  ID de la tarea a completar: ")
-			fmt.Scanln(&id)
+			id := entrada.LeerEntero("\n✔️  ID de la tarea a completar: ", 0)
 
-			if err := gestor.Completar(id); err != nil {
+			siguiente, err := gestor.CompletarConSiguiente(id)
+			if err != nil {
 				fmt.Printf("❌ Error: %v\n", err)
 			} else {
 				fmt.Printf("✅ Tarea %d marcada como completada\n", id)
+				if siguiente != nil {
+					fmt.Printf("🔁 Próxima ocurrencia: [%d] vence el %s\n", siguiente.ID, siguiente.FechaVencimiento.Format("02/01/2006 15:04"))
+				}
 			}
 
 		case 7:
 			// Eliminar tarea
-			var id int
-			fmt.Print("\n🗑️  ID de la tarea a eliminar: ")
-			fmt.Scanln(&id)
+			id := entrada.LeerEntero("\n🗑️  ID de la tarea a eliminar: ", 0)
 
 			// Mostramos la tarea antes de eliminar
 			tarea, err := gestor.BuscarPorID(id)
@@ -738,15 +661,26 @@ func main() {
 				continue
 			}
 
-			fmt.Printf("¿Eliminar '%s'? (s/n): ", tarea.Titulo)
-			var confirmar string
-			fmt.Scanln(&confirmar)
+			// Con subtareas, se eliminan junto con la tarea o no se elimina nada
+			pregunta := fmt.Sprintf("¿Mover '%s' a la papelera? (s/n): ", tarea.Titulo)
+			subtareas := gestor.ListarSubtareas(id)
+			if len(subtareas) > 0 {
+				pregunta = fmt.Sprintf("'%s' tiene %d subtarea(s) directa(s). ¿Mover a la papelera la tarea y todas sus subtareas? (s/n): ", tarea.Titulo, len(subtareas))
+			}
+
+			confirmar := entrada.LeerLinea(pregunta)
 
 			if strings.ToLower(confirmar) == "s" {
-				if err := gestor.Eliminar(id); err != nil {
+				eliminadas := 1
+				if len(subtareas) > 0 {
+					eliminadas, err = gestor.EliminarConSubtareas(id)
+				} else {
+					err = gestor.Eliminar(id)
+				}
+				if err != nil {
 					fmt.Printf("❌ Error: %v\n", err)
 				} else {
-					fmt.Printf("✅ Tarea eliminada\n")
+					fmt.Printf("✅ %d tarea(s) movida(s) a la papelera (opción 15 para restaurar)\n", eliminadas)
 				}
 			} else {
 				fmt.Println("❌ Cancelado")
@@ -761,16 +695,219 @@ func main() {
 			}
 
 		case 9:
+			// Listar vencidas
+			MostrarTareasPaginadas(entrada, gestor, filtrarPorLista(gestor.ListarVencidas(), listaActiva), "⏰ TAREAS VENCIDAS")
+
+		case 10:
+			// Listar por etiqueta
+			etiqueta := entrada.LeerLinea("\n🏷️  Etiqueta: ")
+
+			MostrarTareasPaginadas(entrada, gestor, filtrarPorLista(gestor.ListarPorEtiqueta(etiqueta), listaActiva), fmt.Sprintf("🏷️  TAREAS CON #%s", strings.ToLower(etiqueta)))
+
+		case 11:
+			// Editar tarea
+			id := entrada.LeerEntero("\n✏️  ID de la tarea a editar: ", 0)
+
+			// Mostramos la tarea antes de editar
+			tarea, err := gestor.BuscarPorID(id)
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				continue
+			}
+			MostrarTareas(gestor, []tareas.Tarea{*tarea}, "✏️  TAREA A EDITAR")
+
+			cambios, err := pedirCambiosTarea(entrada)
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				continue
+			}
+
+			if _, err := gestor.Actualizar(id, cambios); err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+			} else {
+				fmt.Printf("✅ Tarea %d actualizada\n", id)
+			}
+
+		case 12:
+			// Deshacer la última operación
+			if op, err := gestor.Deshacer(); err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+			} else {
+				fmt.Printf("↩️  Deshecho: %s\n", op)
+			}
+
+		case 13:
+			// Rehacer la última operación deshecha
+			if op, err := gestor.Rehacer(); err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+			} else {
+				fmt.Printf("↪️  Rehecho: %s\n", op)
+			}
+
+		case 14:
+			// Ver papelera
+			MostrarTareas(gestor, gestor.ListarPapelera(), "🗑️  PAPELERA")
+
+		case 15:
+			// Restaurar tarea de la papelera
+			id := entrada.LeerEntero("\n♻️  ID de la tarea a restaurar: ", 0)
+
+			if err := gestor.Restaurar(id); err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+			} else {
+				fmt.Printf("✅ Tarea %d restaurada\n", id)
+			}
+
+		case 16:
+			// Vaciar papelera
+			papelera := gestor.ListarPapelera()
+			if len(papelera) == 0 {
+				fmt.Println("🗑️  La papelera está vacía")
+				continue
+			}
+
+			confirmar := entrada.LeerLinea(fmt.Sprintf("¿Eliminar definitivamente %d tarea(s)? No podrá deshacerse (s/n): ", len(papelera)))
+
+			if strings.ToLower(confirmar) == "s" {
+				if purgadas, err := gestor.VaciarPapelera(); err != nil {
+					fmt.Printf("❌ Error: %v\n", err)
+				} else {
+					fmt.Printf("✅ %d tarea(s) eliminada(s) definitivamente\n", purgadas)
+					if restantes := len(papelera) - purgadas; restantes > 0 {
+						fmt.Printf("ℹ️  %d tarea(s) de otros usuarios siguen en la papelera\n", restantes)
+					}
+				}
+			} else {
+				fmt.Println("❌ Cancelado")
+			}
+
+		case 17, 18:
+			// Agregar o quitar una dependencia
+			id := entrada.LeerEntero("\n🔗 ID de la tarea que depende: ", 0)
+			requisito := entrada.LeerEntero("🔗 ID de la tarea requisito: ", 0)
+
+			if opcion == 17 {
+				err = gestor.AgregarDependencia(id, requisito)
+			} else {
+				err = gestor.QuitarDependencia(id, requisito)
+			}
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+			} else if opcion == 17 {
+				fmt.Printf("✅ La tarea %d ahora depende de la tarea %d\n", id, requisito)
+			} else {
+				fmt.Printf("✅ La tarea %d ya no depende de la tarea %d\n", id, requisito)
+			}
+
+		case 19:
+			// Tareas pendientes en orden de dependencias
+			MostrarTareas(gestor, filtrarPorLista(gestor.ListarSiguientes(), listaActiva), "⏭️  SIGUIENTES TAREAS (las primeras pueden empezarse ya)")
+
+		case 20:
+			// Exportar todas las tareas a un archivo
+			formato, err := tareas.ParsearFormato(entrada.LeerLinea("\n📤 Formato (csv, markdown, todotxt, ical): "))
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				continue
+			}
+			ruta := "tareas" + formato.Extension()
+			if texto := entrada.LeerLinea(fmt.Sprintf("📤 Archivo (Enter para %s): ", ruta)); texto != "" {
+				ruta = texto
+			}
+
+			archivo, err := os.Create(ruta)
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				continue
+			}
+			lista := filtrarPorLista(gestor.Listar(), listaActiva)
+			err = tareas.Exportar(archivo, lista, formato)
+			if errCerrar := archivo.Close(); err == nil {
+				err = errCerrar
+			}
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+			} else {
+				fmt.Printf("✅ %d tarea(s) exportadas a %s\n", len(lista), ruta)
+			}
+
+		case 21:
+			// Importar tareas: primero se simula y se pide confirmación
+			ruta := entrada.LeerLinea("\n📥 Archivo a importar: ")
+			formato, err := tareas.ParsearFormato(ruta)
+			if err != nil {
+				if formato, err = tareas.ParsearFormato(entrada.LeerLinea("📥 Formato (csv, markdown, todotxt, ical): ")); err != nil {
+					fmt.Printf("❌ Error: %v\n", err)
+					continue
+				}
+			}
+			contenido, err := os.ReadFile(ruta)
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				continue
+			}
+
+			informe, err := gestor.Importar(bytes.NewReader(contenido), formato, true)
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				continue
+			}
+			MostrarInforme(informe)
+			if len(informe.Creadas) == 0 {
+				fmt.Println("ℹ️  No hay tareas nuevas que importar")
+				continue
+			}
+
+			if strings.ToLower(entrada.LeerLinea("\n📥 ¿Importar estas tareas? (s/n): ")) != "s" {
+				fmt.Println("❌ Importación cancelada")
+				continue
+			}
+			informe, err = gestor.Importar(bytes.NewReader(contenido), formato, false)
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+			} else {
+				fmt.Printf("✅ Importación completada: %s\n", informe)
+			}
+
+		case 22:
+			// Interfaz a pantalla completa sobre las mismas tareas
+			if err := consola.NuevaInterfaz(gestor, entrada).Ejecutar(); err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+			}
+
+		case 23:
+			// Cambiar de lista, crearlas, renombrarlas, eliminarlas y mover tareas
+			listaActiva = gestionarListas(entrada, gestor, listaActiva)
+
+		case 24:
+			// Tareas propias y asignadas al usuario actual
+			if gestor.Usuario() == "" {
+				fmt.Printf("❌ No hay usuario actual: inicia con --user o la variable %s\n", variableUsuario)
+				continue
+			}
+			MostrarTareasPaginadas(entrada, gestor, filtrarPorLista(gestor.ListarMias(), listaActiva), "👤 MIS TAREAS")
+
+		case 25:
+			// Asignar o desasignar usuarios
+			gestionarAsignados(entrada, gestor)
+
+		case 26:
+			// Quién cambió la tarea, cuándo y qué valores tenía
+			mostrarHistorial(entrada, gestor)
+
+		case 27:
+			// Rendimiento, tiempo de entrega, antigüedad y rachas
+			mostrarInformeProductividad(entrada, gestor, listaActiva)
+
+		case 0:
 			// Salir
 			detenerAutoguardado <- true
 			
 			// Guardamos antes de salir si hay cambios
-			if gestor.cambiosPendientes {
-				fmt.Print("\n💾 Hay cambios sin guardar. ¿Guardar antes de salir? (s/n): ")
-				var guardar string
-				fmt.Scanln(&guardar)
+			if gestor.TieneCambiosPendientes() {
+				guardar := entrada.LeerLinea("\n💾 Hay cambios sin guardar. ¿Guardar antes de salir? (s/n): ")
 				
-				if strings.ToLower(guardar) == "s" {
+				if strings.ToLower(guardar) == "s" || entrada.Cerrada() {
 					if err := gestor.Guardar(); err != nil {
 						fmt.Printf("❌ Error al guardar: %v\n", err)
 					} else {
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cristianjonhson/GO-API/proyecto-final-todo/consola"
+	"github.com/cristianjonhson/GO-API/proyecto-final-todo/tareas"
+)
+
+// anchoBarra es la cantidad de caracteres de la barra más larga de los
+// gráficos del informe de productividad.
+const anchoBarra = 40
+
+// MostrarProductividad escribe el informe de productividad como tablas de
+// texto, con gráficos de barras ASCII para el rendimiento y la antigüedad.
+//
+// Parámetros:
+//   - w: dónde escribir (os.Stdout en el menú, la salida del comando en la CLI)
+//   - informe: el informe a mostrar
+//
+// Ejemplo:
+//
+//	informe, _ := gestor.InformeProductividad(tareas.ParametrosInforme{})
+//	MostrarProductividad(os.Stdout, informe)
+//
+func MostrarProductividad(w io.Writer, informe tareas.InformeProductividad) {
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 70))
+	fmt.Fprintf(w, "📈 INFORME DE PRODUCTIVIDAD (%s)\n", informe.Fecha.Format("02/01/2006 15:04"))
+	fmt.Fprintln(w, strings.Repeat("=", 70))
+	fmt.Fprintf(w, "Total: %d tarea(s) · ✅ Completadas: %d · ⬜ Pendientes: %d\n", informe.Total, informe.Completadas, informe.Pendientes)
+
+	fmt.Fprintln(w, "\n✅ Completadas por día")
+	for _, dia := range informe.PorDia {
+		mostrarFilaBarra(w, dia.Etiqueta, dia.Completadas, maximoRecuentos(informe.PorDia))
+	}
+
+	fmt.Fprintln(w, "\n✅ Completadas por semana")
+	for _, semana := range informe.PorSemana {
+		mostrarFilaBarra(w, semana.Etiqueta, semana.Completadas, maximoRecuentos(informe.PorSemana))
+	}
+
+	fmt.Fprintln(w, "\n⏱️  Tiempo de entrega (de creada a completada)")
+	if informe.TiempoEntrega.Tareas == 0 {
+		fmt.Fprintln(w, "   Sin tareas completadas con fecha")
+	} else {
+		fmt.Fprintf(w, "   Media: %.1f día(s) · Mediana: %.1f día(s) · %d tarea(s)\n",
+			informe.TiempoEntrega.MediaDias, informe.TiempoEntrega.MedianaDias, informe.TiempoEntrega.Tareas)
+	}
+
+	fmt.Fprintln(w, "\n⏳ Antigüedad de las pendientes")
+	maximo := 0
+	for _, tramo := range informe.Antiguedad {
+		maximo = max(maximo, tramo.Pendientes)
+	}
+	for _, tramo := range informe.Antiguedad {
+		mostrarFilaBarra(w, tramo.Nombre, tramo.Pendientes, maximo)
+	}
+
+	fmt.Fprintln(w, "\n🔥 Rachas (días seguidos completando alguna tarea)")
+	fmt.Fprintf(w, "   Actual: %d día(s)\n", informe.RachaActual)
+	if informe.RachaMaxima > 0 {
+		fin := informe.InicioRachaMaxima.AddDate(0, 0, informe.RachaMaxima-1)
+		fmt.Fprintf(w, "   Máxima: %d día(s), del %s al %s\n", informe.RachaMaxima,
+			informe.InicioRachaMaxima.Format("02/01/2006"), fin.Format("02/01/2006"))
+	} else {
+		fmt.Fprintln(w, "   Máxima: 0 días")
+	}
+	fmt.Fprintln(w, strings.Repeat("=", 70))
+}
+
+// mostrarFilaBarra escribe una fila de tabla con su etiqueta, su valor y
+// una barra proporcional al máximo de la tabla. Los valores distintos de 0
+// tienen siempre al menos un carácter de barra.
+func mostrarFilaBarra(w io.Writer, etiqueta string, valor, maximo int) {
+	barra := ""
+	if valor > 0 && maximo > 0 {
+		barra = strings.Repeat("#", max(valor*anchoBarra/maximo, 1))
+	}
+	relleno := max(12-len([]rune(etiqueta)), 0)
+	fmt.Fprintf(w, "   %s%s %4d │%s\n", etiqueta, strings.Repeat(" ", relleno), valor, barra)
+}
+
+// maximoRecuentos retorna la mayor cantidad de completadas de los periodos.
+func maximoRecuentos(periodos []tareas.Recuento) int {
+	maximo := 0
+	for _, periodo := range periodos {
+		maximo = max(maximo, periodo.Completadas)
+	}
+	return maximo
+}
+
+// mostrarInformeProductividad pregunta los días y semanas de los gráficos y
+// muestra el informe de las tareas de la lista activa (todas si no hay).
+func mostrarInformeProductividad(entrada *consola.Entrada, gestor *tareas.GestorTareas, listaActiva string) {
+	p := tareas.ParametrosInforme{
+		Dias:    entrada.LeerEntero(fmt.Sprintf("\n📈 Días a mostrar [%d]: ", tareas.DiasInformePorDefecto), 0),
+		Semanas: entrada.LeerEntero(fmt.Sprintf("📈 Semanas a mostrar [%d]: ", tareas.SemanasInformePorDefecto), 0),
+		Lista:   listaActiva,
+	}
+	informe, err := gestor.InformeProductividad(p)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		return
+	}
+	MostrarProductividad(os.Stdout, informe)
+}
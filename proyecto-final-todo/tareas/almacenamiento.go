@@ -0,0 +1,366 @@
+package tareas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// Almacenamiento define dónde y cómo se persisten las tareas de un GestorTareas.
+//
+// El gestor mantiene siempre la colección completa en memoria y delega en el
+// almacenamiento únicamente la lectura inicial y la escritura de cambios. Esto
+// permite cambiar de backend (archivo JSON, memoria, registro de operaciones)
+// sin tocar la lógica CRUD.
+//
+// Las implementaciones no necesitan ser seguras para uso concurrente: el
+// gestor serializa todas las llamadas con su propio bloqueo.
+type Almacenamiento interface {
+	// CargarTodas retorna todas las tareas persistidas, en orden de creación.
+	// Si el almacenamiento aún no existe debe retornar un error que cumpla
+	// os.IsNotExist, para que el gestor arranque vacío.
+	CargarTodas() ([]Tarea, error)
+
+	// GuardarTodas reemplaza el contenido persistido por las tareas dadas.
+	GuardarTodas(tareas []Tarea) error
+
+	// String describe el almacenamiento (ej: la ruta del archivo) para
+	// mensajes al usuario.
+	String() string
+}
+
+// AlmacenamientoIncremental es un Almacenamiento capaz de persistir cada
+// cambio de forma individual, sin reescribir la colección completa.
+//
+// Cuando el almacenamiento del gestor implementa esta interfaz, Crear,
+// Completar y Eliminar persisten el cambio en el momento y no lo dejan
+// pendiente para el siguiente Guardar.
+type AlmacenamientoIncremental interface {
+	Almacenamiento
+
+	// GuardarTarea inserta la tarea o reemplaza la existente con el mismo ID.
+	GuardarTarea(tarea Tarea) error
+
+	// BorrarTarea elimina la tarea con el ID indicado.
+	BorrarTarea(id int) error
+}
+
+// AlmacenamientoRecuperable es un Almacenamiento capaz de recuperarse de un
+// archivo dañado usando una copia de respaldo.
+//
+// El gestor lo consulta tras cargar para avisar al usuario de que las tareas
+// no provienen del archivo principal.
+type AlmacenamientoRecuperable interface {
+	Almacenamiento
+
+	// Recuperacion retorna el archivo desde el que se recuperaron las tareas
+	// en la última carga y el error del archivo principal. Si la última carga
+	// usó el archivo principal, archivo es "".
+	Recuperacion() (archivo string, causa error)
+}
+
+// AlmacenamientoListas es un Almacenamiento capaz de persistir también los
+// nombres de las listas creadas con GestorTareas.CrearLista.
+//
+// Las listas que contienen tareas se deducen de las propias tareas; esta
+// interfaz permite conservar además las listas vacías. Con un almacenamiento
+// que no la implementa, una lista vacía desaparece al recargar.
+type AlmacenamientoListas interface {
+	Almacenamiento
+
+	// CargarListas retorna las listas leídas en la última llamada a
+	// CargarTodas (nil si no había ninguna).
+	CargarListas() []string
+
+	// GuardarListas persiste los nombres de las listas. Un almacenamiento
+	// que reescribe la colección completa puede limitarse a recordarlos y
+	// escribirlos en el siguiente GuardarTodas; uno incremental debe
+	// escribirlos en el momento.
+	GuardarListas(listas []string) error
+}
+
+// AlmacenamientoProximoID es un Almacenamiento capaz de persistir el
+// siguiente ID a asignar.
+//
+// Sin él, el gestor deduce el siguiente ID del mayor de las tareas cargadas,
+// y tras purgar las últimas de la papelera volvería a asignar sus IDs a
+// tareas nuevas, mezclando en la auditoría el historial de tareas distintas.
+type AlmacenamientoProximoID interface {
+	Almacenamiento
+
+	// CargarProximoID retorna el siguiente ID leído en la última llamada a
+	// CargarTodas (0 si no se guardó ninguno).
+	CargarProximoID() int
+
+	// GuardarProximoID persiste el siguiente ID a asignar. Puede limitarse
+	// a recordarlo y escribirlo en el siguiente GuardarTodas.
+	GuardarProximoID(id int) error
+}
+
+// RespaldosPorDefecto es la cantidad de copias de respaldo que conserva
+// NuevoAlmacenamientoJSON (tareas.json.1 es la más reciente).
+const RespaldosPorDefecto = 3
+
+// AlmacenamientoJSON persiste todas las tareas como un array JSON en un archivo.
+//
+// Es el almacenamiento por defecto de NuevoGestorTareas y el formato
+// histórico de tareas.json. Si se crearon listas, o si se purgaron las
+// tareas con los IDs más altos, el archivo pasa a ser un objeto
+// {"proximo_id": n, "listas": [...], "tareas": [...]}; ambos formatos se leen.
+//
+// Las escrituras son atómicas (archivo temporal, fsync y renombrado) y antes
+// de cada una se rota un conjunto de respaldos (tareas.json.1,
+// tareas.json.2, ...). Si el archivo principal no puede parsearse, la carga
+// recurre al respaldo válido más reciente.
+type AlmacenamientoJSON struct {
+	// ruta es la ruta del archivo JSON donde se persisten las tareas
+	ruta string
+
+	// respaldos es la cantidad de copias de respaldo a conservar
+	respaldos int
+
+	// listas son los nombres de las listas leídos o por escribir
+	listas []string
+
+	// proximoID es el siguiente ID leído o por escribir (0 si no hay)
+	proximoID int
+
+	// recuperadoDe y causaRecuperacion describen la última carga desde un
+	// respaldo; recuperadoDe está vacío si se usó el archivo principal
+	recuperadoDe      string
+	causaRecuperacion error
+}
+
+// NuevoAlmacenamientoJSON crea un almacenamiento sobre el archivo JSON indicado.
+//
+// El archivo no se crea hasta el primer guardado.
+//
+// Ejemplo:
+//
+//	gestor, err := NuevoGestorTareas("", ConAlmacenamiento(NuevoAlmacenamientoJSON("tareas.json")))
+//
+func NuevoAlmacenamientoJSON(ruta string) *AlmacenamientoJSON {
+	return &AlmacenamientoJSON{ruta: ruta, respaldos: RespaldosPorDefecto}
+}
+
+// ConRespaldos cambia la cantidad de respaldos a conservar (0 los desactiva).
+//
+// Retorna el mismo almacenamiento para poder encadenarlo con el constructor:
+//
+//	almacen := NuevoAlmacenamientoJSON("tareas.json").ConRespaldos(5)
+//
+func (a *AlmacenamientoJSON) ConRespaldos(cantidad int) *AlmacenamientoJSON {
+	a.respaldos = cantidad
+	return a
+}
+
+// CargarTodas lee y parsea el archivo JSON completo.
+//
+// Si el archivo no existe, retorna el error de os sin envolver para que
+// pueda detectarse con os.IsNotExist. Si existe pero no puede leerse o
+// parsearse, prueba los respaldos del más reciente al más antiguo y retorna
+// el primero válido; Recuperacion indica entonces cuál se usó. Si ningún
+// respaldo sirve, retorna el error del archivo principal.
+func (a *AlmacenamientoJSON) CargarTodas() ([]Tarea, error) {
+	a.recuperadoDe, a.causaRecuperacion = "", nil
+
+	contenido, err := leerArchivoJSON(a.ruta)
+	if err == nil || os.IsNotExist(err) {
+		a.listas, a.proximoID = contenido.Listas, contenido.ProximoID
+		return contenido.Tareas, err
+	}
+
+	for n := 1; n <= a.respaldos; n++ {
+		respaldo := rutaRespaldo(a.ruta, n)
+		if recuperado, errRespaldo := leerArchivoJSON(respaldo); errRespaldo == nil {
+			a.recuperadoDe, a.causaRecuperacion = respaldo, err
+			a.listas, a.proximoID = recuperado.Listas, recuperado.ProximoID
+			return recuperado.Tareas, nil
+		}
+	}
+	return nil, err
+}
+
+// GuardarTodas serializa las tareas con indentación y reemplaza el archivo
+// de forma atómica, rotando antes los respaldos.
+//
+// Sin listas, y si el siguiente ID es el que sigue al mayor de las tareas,
+// se escribe el array de siempre, de modo que versiones anteriores del
+// programa siguen pudiendo leer el archivo.
+//
+// El archivo se crea con permisos 0644 (lectura para todos, escritura para dueño).
+func (a *AlmacenamientoJSON) GuardarTodas(tareas []Tarea) error {
+	var contenido any = tareas
+	proximoID := 0
+	if a.proximoID > maximoID(tareas)+1 {
+		proximoID = a.proximoID
+	}
+	if len(a.listas) > 0 || proximoID > 0 {
+		contenido = archivoJSON{ProximoID: proximoID, Listas: a.listas, Tareas: tareas}
+	}
+	datos, err := json.MarshalIndent(contenido, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error al serializar tareas: %v", err)
+	}
+
+	if err := rotarRespaldos(a.ruta, a.respaldos); err != nil {
+		return err
+	}
+	return escribirArchivoAtomico(a.ruta, datos, 0644)
+}
+
+// Recuperacion implementa AlmacenamientoRecuperable.
+func (a *AlmacenamientoJSON) Recuperacion() (archivo string, causa error) {
+	return a.recuperadoDe, a.causaRecuperacion
+}
+
+// CargarListas implementa AlmacenamientoListas.
+func (a *AlmacenamientoJSON) CargarListas() []string {
+	return slices.Clone(a.listas)
+}
+
+// GuardarListas implementa AlmacenamientoListas: las listas se escriben en
+// el siguiente GuardarTodas.
+func (a *AlmacenamientoJSON) GuardarListas(listas []string) error {
+	a.listas = slices.Clone(listas)
+	return nil
+}
+
+// CargarProximoID implementa AlmacenamientoProximoID.
+func (a *AlmacenamientoJSON) CargarProximoID() int {
+	return a.proximoID
+}
+
+// GuardarProximoID implementa AlmacenamientoProximoID: el siguiente ID se
+// escribe en el siguiente GuardarTodas.
+func (a *AlmacenamientoJSON) GuardarProximoID(id int) error {
+	a.proximoID = id
+	return nil
+}
+
+// archivoJSON es el contenido de un archivo de tareas con listas o con el
+// siguiente ID.
+type archivoJSON struct {
+	ProximoID int      `json:"proximo_id,omitempty"`
+	Listas    []string `json:"listas,omitempty"`
+	Tareas    []Tarea  `json:"tareas"`
+}
+
+// leerArchivoJSON lee y parsea desde ruta un array de tareas o un objeto
+// con listas y tareas.
+func leerArchivoJSON(ruta string) (archivoJSON, error) {
+	datos, err := os.ReadFile(ruta)
+	if err != nil {
+		return archivoJSON{}, err
+	}
+
+	var contenido archivoJSON
+	destino := any(&contenido.Tareas)
+	if inicio := bytes.TrimSpace(datos); len(inicio) > 0 && inicio[0] == '{' {
+		destino = &contenido
+	}
+	if err := json.Unmarshal(datos, destino); err != nil {
+		return archivoJSON{}, fmt.Errorf("error al parsear JSON: %v", err)
+	}
+	return contenido, nil
+}
+
+// String retorna la ruta del archivo.
+func (a *AlmacenamientoJSON) String() string {
+	return a.ruta
+}
+
+// AlmacenamientoMemoria guarda las tareas únicamente en memoria.
+//
+// Es útil en tests o para sesiones efímeras: nada llega a disco y el
+// contenido se pierde al terminar el programa.
+type AlmacenamientoMemoria struct {
+	// tareas es la última colección guardada; nil si nunca se guardó
+	tareas []Tarea
+
+	// listas son los nombres de las listas guardados
+	listas []string
+
+	// proximoID es el siguiente ID guardado
+	proximoID int
+}
+
+// NuevoAlmacenamientoMemoria crea un almacenamiento en memoria vacío.
+//
+// Opcionalmente recibe tareas iniciales, que se retornarán en la primera carga.
+//
+// Ejemplo:
+//
+//	gestor, _ := NuevoGestorTareas("", ConAlmacenamiento(NuevoAlmacenamientoMemoria()))
+//
+func NuevoAlmacenamientoMemoria(iniciales ...Tarea) *AlmacenamientoMemoria {
+	a := &AlmacenamientoMemoria{}
+	if len(iniciales) > 0 {
+		a.tareas = copiarTareas(iniciales)
+	}
+	return a
+}
+
+// CargarTodas retorna una copia de las tareas guardadas.
+//
+// Si nunca se guardó nada, retorna os.ErrNotExist para que el gestor
+// arranque vacío igual que con un archivo inexistente.
+func (a *AlmacenamientoMemoria) CargarTodas() ([]Tarea, error) {
+	if a.tareas == nil {
+		return nil, os.ErrNotExist
+	}
+	return copiarTareas(a.tareas), nil
+}
+
+// GuardarTodas reemplaza las tareas guardadas por una copia de las dadas.
+func (a *AlmacenamientoMemoria) GuardarTodas(tareas []Tarea) error {
+	a.tareas = copiarTareas(tareas)
+	return nil
+}
+
+// CargarListas implementa AlmacenamientoListas.
+func (a *AlmacenamientoMemoria) CargarListas() []string {
+	return slices.Clone(a.listas)
+}
+
+// GuardarListas implementa AlmacenamientoListas.
+func (a *AlmacenamientoMemoria) GuardarListas(listas []string) error {
+	a.listas = slices.Clone(listas)
+	return nil
+}
+
+// CargarProximoID implementa AlmacenamientoProximoID.
+func (a *AlmacenamientoMemoria) CargarProximoID() int {
+	return a.proximoID
+}
+
+// GuardarProximoID implementa AlmacenamientoProximoID.
+func (a *AlmacenamientoMemoria) GuardarProximoID(id int) error {
+	a.proximoID = id
+	return nil
+}
+
+// String identifica el almacenamiento en mensajes.
+func (a *AlmacenamientoMemoria) String() string {
+	return "memoria"
+}
+
+// copiarTareas retorna una copia independiente del slice (nunca nil).
+func copiarTareas(tareas []Tarea) []Tarea {
+	copia := make([]Tarea, len(tareas))
+	for i, tarea := range tareas {
+		copia[i] = tarea.clonar()
+	}
+	return copia
+}
+
+// maximoID retorna el mayor ID de las tareas (0 si no hay ninguna).
+func maximoID(tareas []Tarea) int {
+	maximo := 0
+	for _, tarea := range tareas {
+		maximo = max(maximo, tarea.ID)
+	}
+	return maximo
+}
@@ -0,0 +1,385 @@
+// Tests de los distintos backends de almacenamiento
+
+package tareas
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// eliminarConRespaldos borra un archivo de tareas junto con sus respaldos
+func eliminarConRespaldos(ruta string) {
+	os.Remove(ruta)
+	for n := 1; n <= RespaldosPorDefecto; n++ {
+		os.Remove(rutaRespaldo(ruta, n))
+	}
+}
+
+// TestAlmacenamientos verifica que todos los backends conserven el estado
+// entre un gestor y otro creado sobre el mismo almacenamiento
+func TestAlmacenamientos(t *testing.T) {
+	defer eliminarConRespaldos("test_alm.json")
+	defer os.Remove("test_alm.log")
+
+	tests := []struct {
+		nombre  string
+		almacen Almacenamiento
+	}{
+		{"json", NuevoAlmacenamientoJSON("test_alm.json")},
+		{"memoria", NuevoAlmacenamientoMemoria()},
+		{"registro", NuevoAlmacenamientoRegistro("test_alm.log")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			gestor, err := NuevoGestorTareas("", ConAlmacenamiento(tt.almacen))
+			if err != nil {
+				t.Fatalf("Error al crear gestor: %v", err)
+			}
+
+			gestor.Crear("Tarea 1")
+			gestor.Crear("Tarea 2")
+			gestor.Crear("Tarea 3")
+			gestor.Completar(1)
+			gestor.Eliminar(2)
+
+			if err := gestor.Guardar(); err != nil {
+				t.Fatalf("Error al guardar: %v", err)
+			}
+
+			recargado, err := NuevoGestorTareas("", ConAlmacenamiento(tt.almacen))
+			if err != nil {
+				t.Fatalf("Error al recargar: %v", err)
+			}
+
+			lista := recargado.Listar()
+			if len(lista) != 2 || lista[0].ID != 1 || lista[1].ID != 3 {
+				t.Fatalf("Se esperaban las tareas 1 y 3, se obtuvo: %+v", lista)
+			}
+			if !lista[0].Completada {
+				t.Error("La tarea 1 debería seguir completada")
+			}
+
+			nueva, _ := recargado.Crear("Tarea 4")
+			if nueva.ID != 4 {
+				t.Errorf("El próximo ID debería ser 4, es: %d", nueva.ID)
+			}
+		})
+	}
+}
+
+// TestProximoIDTrasPurgar verifica que ningún backend reutilice los IDs de
+// las tareas purgadas de la papelera al recargar, ni siquiera tras compactar
+func TestProximoIDTrasPurgar(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		nombre  string
+		almacen Almacenamiento
+	}{
+		{"json", NuevoAlmacenamientoJSON(filepath.Join(dir, "tareas.json"))},
+		{"memoria", NuevoAlmacenamientoMemoria()},
+		{"registro", NuevoAlmacenamientoRegistro(filepath.Join(dir, "tareas.log"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			gestor, _ := NuevoGestorTareas("", ConAlmacenamiento(tt.almacen), ConSalida(io.Discard))
+			gestor.Crear("Tarea 1")
+			gestor.Crear("Tarea 2")
+			gestor.Eliminar(2)
+			gestor.VaciarPapelera()
+			if err := gestor.Guardar(); err != nil {
+				t.Fatalf("Error al guardar: %v", err)
+			}
+
+			// Dos recargas: la primera vuelve a guardar sin la tarea purgada
+			for i := range 2 {
+				recargado, err := NuevoGestorTareas("", ConAlmacenamiento(tt.almacen), ConSalida(io.Discard))
+				if err != nil {
+					t.Fatalf("Error al recargar: %v", err)
+				}
+				if err := recargado.Guardar(); err != nil {
+					t.Fatalf("Error al guardar: %v", err)
+				}
+				if i == 1 {
+					if nueva, _ := recargado.Crear("Tarea nueva"); nueva.ID != 3 {
+						t.Errorf("La tarea nueva no debería reutilizar el ID purgado: %d", nueva.ID)
+					}
+				}
+			}
+		})
+	}
+
+	// Sin purgas ni listas, tareas.json sigue siendo un array
+	ruta := filepath.Join(dir, "array.json")
+	gestor, _ := NuevoGestorTareas(ruta, ConSalida(io.Discard))
+	gestor.Crear("Tarea 1")
+	gestor.Guardar()
+	if datos, _ := os.ReadFile(ruta); !strings.HasPrefix(string(datos), "[") {
+		t.Errorf("Se esperaba el formato de array:\n%s", datos)
+	}
+}
+
+// TestAlmacenamientoRegistroIncremental verifica que el registro persista
+// cada cambio sin necesidad de llamar a Guardar
+func TestAlmacenamientoRegistroIncremental(t *testing.T) {
+	archivoTemp := "test_registro.log"
+	defer os.Remove(archivoTemp)
+
+	gestor, _ := NuevoGestorTareas("", ConAlmacenamiento(NuevoAlmacenamientoRegistro(archivoTemp)))
+	gestor.Crear("Tarea 1")
+	gestor.Crear("Tarea 2")
+	gestor.Completar(2)
+	gestor.Eliminar(1)
+
+	if gestor.TieneCambiosPendientes() {
+		t.Error("Con un almacenamiento incremental no deberían quedar cambios pendientes")
+	}
+
+	// Sin Guardar: el estado debe reconstruirse reproduciendo el registro
+	recargado, err := NuevoGestorTareas("", ConAlmacenamiento(NuevoAlmacenamientoRegistro(archivoTemp)))
+	if err != nil {
+		t.Fatalf("Error al recargar: %v", err)
+	}
+	lista := recargado.Listar()
+	if len(lista) != 1 || lista[0].ID != 2 || !lista[0].Completada {
+		t.Fatalf("Se esperaba solo la tarea 2 completada, se obtuvo: %+v", lista)
+	}
+
+	// Guardar compacta el registro a una línea por tarea, incluida la de la papelera
+	if err := recargado.Guardar(); err != nil {
+		t.Fatalf("Error al compactar: %v", err)
+	}
+	datos, _ := os.ReadFile(archivoTemp)
+	if lineas := strings.Count(string(datos), "\n"); lineas != 2 {
+		t.Errorf("Tras compactar se esperaban 2 líneas, hay: %d", lineas)
+	}
+}
+
+// TestAlmacenamientoRegistroCorrupto verifica la tolerancia a escrituras interrumpidas
+func TestAlmacenamientoRegistroCorrupto(t *testing.T) {
+	archivoTemp := "test_registro_corrupto.log"
+	defer os.Remove(archivoTemp)
+
+	valida := `{"op":"guardar","tarea":{"id":1,"titulo":"Tarea 1","completada":false,"fecha_creacion":"2026-01-01T00:00:00Z"}}`
+
+	// Una última línea truncada se ignora
+	os.WriteFile(archivoTemp, []byte(valida+"\n"+`{"op":"guar`), 0644)
+	tareas, err := NuevoAlmacenamientoRegistro(archivoTemp).CargarTodas()
+	if err != nil {
+		t.Fatalf("Una última línea truncada no debería ser un error: %v", err)
+	}
+	if len(tareas) != 1 {
+		t.Errorf("Se esperaba 1 tarea, se obtuvieron: %d", len(tareas))
+	}
+
+	// La línea truncada se recorta al cargar y al añadir, así que lo añadido
+	// después no la deja en medio del registro
+	if datos, _ := os.ReadFile(archivoTemp); string(datos) != valida+"\n" {
+		t.Errorf("La carga debería recortar la línea truncada:\n%s", datos)
+	}
+	gestor, _ := NuevoGestorTareas("", ConAlmacenamiento(NuevoAlmacenamientoRegistro(archivoTemp)), ConSalida(io.Discard))
+	interrumpida := `{"op":"guardar","tarea":{"id":3,"tit`
+	archivo, _ := os.OpenFile(archivoTemp, os.O_APPEND|os.O_WRONLY, 0644)
+	archivo.WriteString(interrumpida)
+	archivo.Close()
+	gestor.Crear("Tarea 2")
+	if tareas, err := NuevoAlmacenamientoRegistro(archivoTemp).CargarTodas(); err != nil || len(tareas) != 2 {
+		t.Fatalf("Se esperaban 2 tareas tras añadir, se obtuvo: %+v, %v", tareas, err)
+	}
+	datos, _ := os.ReadFile(archivoTemp)
+	if strings.Contains(string(datos), interrumpida) {
+		t.Errorf("La línea truncada debería haberse descartado:\n%s", datos)
+	}
+
+	// Una línea inválida en medio del registro sí es un error
+	os.WriteFile(archivoTemp, []byte("basura\n"+valida+"\n"), 0644)
+	_, err = NuevoAlmacenamientoRegistro(archivoTemp).CargarTodas()
+	if err == nil || !strings.Contains(err.Error(), "línea 1") {
+		t.Errorf("Se esperaba error indicando la línea 1, se obtuvo: %v", err)
+	}
+}
+
+// TestGuardarRotaRespaldos verifica que cada guardado conserve copias anteriores
+func TestGuardarRotaRespaldos(t *testing.T) {
+	archivoTemp := "test_respaldos.json"
+	defer eliminarConRespaldos(archivoTemp)
+
+	gestor, _ := NuevoGestorTareas(archivoTemp)
+
+	// Cinco guardados, cada uno con una tarea más
+	for i := 1; i <= 5; i++ {
+		gestor.Crear("Tarea de respaldo")
+		if err := gestor.Guardar(); err != nil {
+			t.Fatalf("Error al guardar: %v", err)
+		}
+	}
+
+	// El principal tiene 5 tareas; .1 tiene 4, .2 tiene 3, .3 tiene 2
+	for n, esperadas := range map[int]int{0: 5, 1: 4, 2: 3, 3: 2} {
+		ruta := archivoTemp
+		if n > 0 {
+			ruta = rutaRespaldo(archivoTemp, n)
+		}
+		contenido, err := leerArchivoJSON(ruta)
+		if err != nil {
+			t.Fatalf("Error al leer %s: %v", ruta, err)
+		}
+		if len(contenido.Tareas) != esperadas {
+			t.Errorf("%s: se esperaban %d tareas, hay: %d", ruta, esperadas, len(contenido.Tareas))
+		}
+	}
+
+	// No se conservan más respaldos de los configurados
+	if _, err := os.Stat(rutaRespaldo(archivoTemp, RespaldosPorDefecto+1)); !os.IsNotExist(err) {
+		t.Errorf("No debería existir el respaldo %d", RespaldosPorDefecto+1)
+	}
+
+	// Ni quedan archivos temporales de la escritura atómica
+	temporales, _ := filepath.Glob(archivoTemp + ".tmp-*")
+	if len(temporales) != 0 {
+		t.Errorf("Quedaron archivos temporales: %v", temporales)
+	}
+}
+
+// TestCargarRecuperaDesdeRespaldo verifica la recuperación ante un archivo dañado
+func TestCargarRecuperaDesdeRespaldo(t *testing.T) {
+	archivoTemp := "test_recuperar.json"
+	defer eliminarConRespaldos(archivoTemp)
+
+	gestor, _ := NuevoGestorTareas(archivoTemp)
+	gestor.Crear("Tarea 1")
+	gestor.Guardar()
+	gestor.Crear("Tarea 2")
+	gestor.Guardar()
+
+	// Simulamos un archivo principal truncado y un primer respaldo también dañado
+	os.WriteFile(archivoTemp, []byte(`[{"id": 1, "tit`), 0644)
+	os.WriteFile(rutaRespaldo(archivoTemp, 1), []byte(`{`), 0644)
+	os.WriteFile(rutaRespaldo(archivoTemp, 2), []byte(`[{"id": 7, "titulo": "Del respaldo 2"}]`), 0644)
+
+	almacen := NuevoAlmacenamientoJSON(archivoTemp)
+	recuperado, err := NuevoGestorTareas("", ConAlmacenamiento(almacen))
+	if err != nil {
+		t.Fatalf("Se esperaba recuperar desde un respaldo, error: %v", err)
+	}
+
+	archivo, causa := almacen.Recuperacion()
+	if archivo != rutaRespaldo(archivoTemp, 2) {
+		t.Errorf("Se esperaba recuperar desde %s, se usó: '%s'", rutaRespaldo(archivoTemp, 2), archivo)
+	}
+	if causa == nil {
+		t.Error("Debería reportarse el error del archivo principal")
+	}
+	if _, err := recuperado.BuscarPorID(7); err != nil {
+		t.Error("Deberían cargarse las tareas del respaldo")
+	}
+
+	// Sin respaldos válidos, el error del principal se propaga
+	os.WriteFile(rutaRespaldo(archivoTemp, 2), []byte(`basura`), 0644)
+	if _, err := NuevoGestorTareas(archivoTemp); err == nil {
+		t.Error("Se esperaba error si ni el principal ni los respaldos son válidos")
+	}
+}
+
+// TestDiarioSobreviveCierreAbrupto simula un kill -9 entre guardados
+func TestDiarioSobreviveCierreAbrupto(t *testing.T) {
+	archivoTemp := "test_diario.json"
+	diarioTemp := "test_diario.json.diario"
+	defer eliminarConRespaldos(archivoTemp)
+	defer os.Remove(diarioTemp)
+
+	gestor, _ := NuevoGestorTareas(archivoTemp, ConDiario(diarioTemp))
+	gestor.Crear("Tarea guardada")
+	if err := gestor.Guardar(); err != nil {
+		t.Fatalf("Error al guardar: %v", err)
+	}
+
+	// Tras Guardar el diario queda vacío
+	if info, err := os.Stat(diarioTemp); err != nil || info.Size() != 0 {
+		t.Errorf("El diario debería estar vacío tras guardar")
+	}
+
+	// Cambios posteriores a la instantánea, sin Guardar
+	gestor.Crear("Tarea solo en diario")
+	gestor.Completar(1)
+	gestor.Crear("Tarea eliminada")
+	gestor.Eliminar(3)
+
+	// "Reiniciamos" sin guardar: el diario debe reproducirse sobre tareas.json
+	recuperado, err := NuevoGestorTareas(archivoTemp, ConDiario(diarioTemp))
+	if err != nil {
+		t.Fatalf("Error al recuperar: %v", err)
+	}
+
+	lista := recuperado.Listar()
+	if len(lista) != 2 {
+		t.Fatalf("Se esperaban 2 tareas, se obtuvieron: %+v", lista)
+	}
+	if !lista[0].Completada {
+		t.Error("La tarea 1 debería estar completada según el diario")
+	}
+	if lista[1].Titulo != "Tarea solo en diario" {
+		t.Errorf("Título esperado: 'Tarea solo en diario', obtenido: '%s'", lista[1].Titulo)
+	}
+	if !recuperado.TieneCambiosPendientes() {
+		t.Error("Lo reproducido del diario debería quedar pendiente de guardar")
+	}
+
+	// El ID 3 ya se usó antes del cierre y no debe reasignarse
+	nueva, _ := recuperado.Crear("Tarea nueva")
+	if nueva.ID != 4 {
+		t.Errorf("El próximo ID debería ser 4, es: %d", nueva.ID)
+	}
+
+	// Guardar compacta el diario en tareas.json
+	recuperado.Guardar()
+	final, _ := NuevoGestorTareas(archivoTemp)
+	if total, _, _ := final.Estadisticas(); total != 3 {
+		t.Errorf("tareas.json debería contener 3 tareas, contiene: %d", total)
+	}
+}
+
+// TestDiarioTrasEscrituraInterrumpida verifica que una entrada a medio
+// escribir no impida añadir otras ni reabrir el gestor
+func TestDiarioTrasEscrituraInterrumpida(t *testing.T) {
+	dir := t.TempDir()
+	archivoTemp := filepath.Join(dir, "tareas.json")
+	diarioTemp := archivoTemp + ".diario"
+	abrir := func() *GestorTareas {
+		t.Helper()
+		gestor, err := NuevoGestorTareas(archivoTemp, ConDiario(diarioTemp), ConSalida(io.Discard))
+		if err != nil {
+			t.Fatalf("Error al abrir el gestor: %v", err)
+		}
+		return gestor
+	}
+	interrumpir := func(parcial string) {
+		archivo, _ := os.OpenFile(diarioTemp, os.O_APPEND|os.O_WRONLY, 0644)
+		archivo.WriteString(parcial)
+		archivo.Close()
+	}
+
+	// Escritura interrumpida y, en la misma sesión, otra entrada
+	gestor := abrir()
+	gestor.Crear("uno")
+	interrumpir(`{"op":"guardar","tarea":{"id":2,"tit`)
+	gestor.Crear("dos")
+	if lista := abrir().Listar(); len(lista) != 2 || lista[1].Titulo != "dos" {
+		t.Fatalf("Se esperaban las tareas uno y dos, se obtuvo: %+v", lista)
+	}
+
+	// Tras un cierre abrupto la línea incompleta se recorta al reabrir
+	interrumpir(`{"op":"bor`)
+	abrir().Crear("tres")
+	if lista := abrir().Listar(); len(lista) != 3 {
+		t.Fatalf("Se esperaban 3 tareas, se obtuvo: %+v", lista)
+	}
+	datos, _ := os.ReadFile(diarioTemp)
+	if lineas := strings.Count(string(datos), "\n"); lineas != 3 || !strings.HasSuffix(string(datos), "}\n") {
+		t.Errorf("El diario debería tener 3 entradas completas:\n%s", datos)
+	}
+}
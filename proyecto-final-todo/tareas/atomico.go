@@ -0,0 +1,113 @@
+package tareas
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// escribirArchivoAtomico reemplaza el contenido de ruta sin dejar nunca un
+// archivo a medio escribir.
+//
+// Los datos se escriben primero en un archivo temporal del mismo directorio,
+// se sincronizan a disco (fsync) y solo entonces se renombra el temporal sobre
+// el original. El renombrado es atómico en el mismo sistema de archivos, por
+// lo que ante un corte de luz o un disco lleno el archivo queda con el
+// contenido anterior o con el nuevo, nunca truncado.
+func escribirArchivoAtomico(ruta string, datos []byte, permisos os.FileMode) error {
+	dir := filepath.Dir(ruta)
+
+	temp, err := os.CreateTemp(dir, filepath.Base(ruta)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("error al crear archivo temporal: %v", err)
+	}
+	// Si algo falla antes del renombrado, no dejamos basura en el directorio
+	rutaTemp := temp.Name()
+	defer os.Remove(rutaTemp)
+
+	if _, err := temp.Write(datos); err != nil {
+		temp.Close()
+		return fmt.Errorf("error al escribir archivo: %v", err)
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return fmt.Errorf("error al sincronizar archivo: %v", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("error al cerrar archivo: %v", err)
+	}
+	if err := os.Chmod(rutaTemp, permisos); err != nil {
+		return fmt.Errorf("error al asignar permisos: %v", err)
+	}
+
+	if err := os.Rename(rutaTemp, ruta); err != nil {
+		return fmt.Errorf("error al reemplazar archivo: %v", err)
+	}
+
+	// Sincronizamos el directorio para que el renombrado también sea durable.
+	// No todos los sistemas lo permiten, así que es un esfuerzo opcional.
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// rutaRespaldo retorna la ruta del respaldo número n de ruta (ej: tareas.json.2).
+func rutaRespaldo(ruta string, n int) string {
+	return fmt.Sprintf("%s.%d", ruta, n)
+}
+
+// rotarRespaldos conserva una copia del archivo actual antes de reemplazarlo.
+//
+// Desplaza los respaldos existentes (ruta.1 -> ruta.2, ...), descartando el
+// más antiguo, y copia el archivo actual a ruta.1. El original no se mueve,
+// de modo que siempre existe un archivo principal válido. Si el archivo
+// actual no existe o cantidad es 0, no hace nada.
+func rotarRespaldos(ruta string, cantidad int) error {
+	if cantidad <= 0 {
+		return nil
+	}
+	if _, err := os.Stat(ruta); os.IsNotExist(err) {
+		return nil
+	}
+
+	for n := cantidad - 1; n >= 1; n-- {
+		origen := rutaRespaldo(ruta, n)
+		if _, err := os.Stat(origen); err == nil {
+			if err := os.Rename(origen, rutaRespaldo(ruta, n+1)); err != nil {
+				return fmt.Errorf("error al rotar respaldo: %v", err)
+			}
+		}
+	}
+
+	if err := copiarArchivo(ruta, rutaRespaldo(ruta, 1)); err != nil {
+		return fmt.Errorf("error al crear respaldo: %v", err)
+	}
+	return nil
+}
+
+// copiarArchivo copia origen en destino, sobrescribiéndolo si existe.
+func copiarArchivo(origen, destino string) error {
+	entrada, err := os.Open(origen)
+	if err != nil {
+		return err
+	}
+	defer entrada.Close()
+
+	info, err := entrada.Stat()
+	if err != nil {
+		return err
+	}
+
+	salida, err := os.OpenFile(destino, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(salida, entrada); err != nil {
+		salida.Close()
+		return err
+	}
+	return salida.Close()
+}
@@ -0,0 +1,414 @@
+package tareas
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tipos de evento de la auditoría que no son operaciones del historial.
+const (
+	// OperacionDeshacer y OperacionRehacer registran los cambios que
+	// aplicaron Deshacer y Rehacer.
+	OperacionDeshacer TipoOperacion = "deshacer"
+	OperacionRehacer  TipoOperacion = "rehacer"
+
+	// OperacionPurgar registra una tarea eliminada definitivamente de la
+	// papelera, a mano o al vencer su retención.
+	OperacionPurgar TipoOperacion = "purgar"
+)
+
+// Evento es un cambio registrado en la auditoría del gestor.
+//
+// A diferencia del historial de deshacer, que es limitado y vive en
+// memoria, la auditoría conserva todos los cambios: quién los hizo, cuándo
+// y cómo era la tarea antes y después. Una operación que afecta a varias
+// tareas (eliminar con subtareas, completar una tarea recurrente, importar)
+// genera un evento por tarea con la misma fecha, y las operaciones sobre
+// listas un evento más con los nombres de las listas.
+type Evento struct {
+	// Tipo es la operación que produjo el cambio (crear, completar, editar,
+	// deshacer, purgar, ...)
+	Tipo TipoOperacion `json:"tipo"`
+
+	// TareaID es la tarea que cambió; 0 en el evento de los nombres de las
+	// listas
+	TareaID int `json:"tarea_id,omitempty"`
+
+	// Antes y Despues son la tarea antes y después del cambio; Antes es nil
+	// al crearla y Despues al purgarla o al deshacer su creación
+	Antes   *Tarea `json:"antes,omitempty"`
+	Despues *Tarea `json:"despues,omitempty"`
+
+	// Lista es la lista sobre la que se hizo una operación de listas, y
+	// ListasAntes y ListasDespues son los nombres de las listas creadas
+	// antes y después de ella (solo en el evento de las listas)
+	Lista         string   `json:"lista,omitempty"`
+	ListasAntes   []string `json:"listas_antes,omitempty"`
+	ListasDespues []string `json:"listas_despues,omitempty"`
+
+	// Fecha es el momento del cambio, según el reloj del gestor
+	Fecha time.Time `json:"fecha"`
+
+	// Usuario es el usuario del gestor que hizo el cambio; vacío si el
+	// gestor no tenía usuario
+	Usuario string `json:"usuario,omitempty"`
+}
+
+// CampoCambiado es un campo que un Evento modificó, con sus valores antes y
+// después como texto ("" si estaba vacío).
+type CampoCambiado struct {
+	Campo   string `json:"campo"`
+	Antes   string `json:"antes"`
+	Despues string `json:"despues"`
+}
+
+// camposAuditados son los campos que compara Evento.CamposCambiados, en el
+// orden en que se muestran. No incluye los que cambian con cualquier
+// modificación (fecha_actualizacion) ni los que nunca cambian (id, uid).
+var camposAuditados = []struct {
+	nombre string
+	valor  func(Tarea) string
+}{
+	{"titulo", func(t Tarea) string { return t.Titulo }},
+	{"descripcion", func(t Tarea) string { return t.Descripcion }},
+	{"completada", func(t Tarea) string { return strconv.FormatBool(t.Completada) }},
+	{"prioridad", func(t Tarea) string { return string(t.Prioridad) }},
+	{"fecha_vencimiento", func(t Tarea) string { return formatearFechaCSV(t.FechaVencimiento) }},
+	{"etiquetas", func(t Tarea) string { return strings.Join(t.Etiquetas, ",") }},
+	{"fecha_completada", func(t Tarea) string { return formatearFechaCSV(t.FechaCompletada) }},
+	{"fecha_eliminada", func(t Tarea) string { return formatearFechaCSV(t.FechaEliminada) }},
+	{"padre_id", func(t Tarea) string { return formatearID(t.PadreID) }},
+	{"dependencias", func(t Tarea) string { return formatearIDs(t.Dependencias) }},
+	{"recurrencia", func(t Tarea) string {
+		if t.Recurrencia == nil {
+			return ""
+		}
+		return t.Recurrencia.String()
+	}},
+	{"lista", func(t Tarea) string { return t.Lista }},
+	{"propietario", func(t Tarea) string { return t.Propietario }},
+	{"asignados", func(t Tarea) string { return strings.Join(t.Asignados, ",") }},
+}
+
+// CamposCambiados retorna los campos que el evento modificó, con sus valores
+// antes y después.
+//
+// Al crear una tarea aparecen todos sus campos con valor (Antes vacío), y al
+// purgarla todos con Despues vacío; completada se compara con false. En el
+// evento de las listas el único campo es "listas", con los nombres
+// separados por comas.
+//
+// Ejemplo:
+//
+//	for _, campo := range evento.CamposCambiados() {
+//		fmt.Printf("%s: %q → %q\n", campo.Campo, campo.Antes, campo.Despues)
+//	}
+//
+func (e Evento) CamposCambiados() []CampoCambiado {
+	if e.Antes == nil && e.Despues == nil {
+		if slices.Equal(e.ListasAntes, e.ListasDespues) {
+			return nil
+		}
+		return []CampoCambiado{{"listas", strings.Join(e.ListasAntes, ","), strings.Join(e.ListasDespues, ",")}}
+	}
+
+	var antes, despues Tarea
+	if e.Antes != nil {
+		antes = *e.Antes
+	}
+	if e.Despues != nil {
+		despues = *e.Despues
+	}
+	var cambiados []CampoCambiado
+	for _, campo := range camposAuditados {
+		valorAntes, valorDespues := campo.valor(antes), campo.valor(despues)
+		if valorAntes != valorDespues {
+			cambiados = append(cambiados, CampoCambiado{campo.nombre, valorAntes, valorDespues})
+		}
+	}
+	return cambiados
+}
+
+// String describe el evento en una línea para mostrarlo al usuario (ej:
+// "17/10/2026 09:30 ana: completar tarea 3 'Comprar pan'").
+func (e Evento) String() string {
+	usuario := e.Usuario
+	if usuario == "" {
+		usuario = "(sin usuario)"
+	}
+	descripcion := fmt.Sprintf("%s %s: %s", e.Fecha.Format("02/01/2006 15:04"), usuario, e.Tipo)
+	switch {
+	case e.TareaID == 0:
+		descripcion += fmt.Sprintf(" lista '%s'", e.Lista)
+	case e.Despues != nil:
+		descripcion += fmt.Sprintf(" tarea %d '%s'", e.TareaID, e.Despues.Titulo)
+	case e.Antes != nil:
+		descripcion += fmt.Sprintf(" tarea %d '%s'", e.TareaID, e.Antes.Titulo)
+	}
+	return descripcion
+}
+
+// ConAuditoria guarda la auditoría del gestor en rutaAuditoria.
+//
+// El gestor registra siempre cada cambio en su auditoría (ver Auditoria),
+// pero sin esta opción solo la conserva en memoria. Con ella, cada evento se
+// añade al archivo (JSON Lines, un Evento por línea) y se sincroniza a disco
+// en el momento del cambio, y NuevoGestorTareas carga los eventos
+// anteriores. El archivo solo crece: a diferencia del diario, Guardar no lo
+// vacía.
+//
+// Ejemplo:
+//
+//	gestor, err := NuevoGestorTareas("tareas.json", ConAuditoria("tareas.json.auditoria"))
+//
+func ConAuditoria(rutaAuditoria string) Opcion {
+	return func(g *GestorTareas) {
+		g.rutaAuditoria = rutaAuditoria
+	}
+}
+
+// Auditoria retorna todos los eventos registrados, del más antiguo al más
+// reciente, incluidos los de tareas ya purgadas.
+//
+// Ejemplo:
+//
+//	for _, evento := range gestor.Auditoria() {
+//		fmt.Println(evento)
+//	}
+//
+func (g *GestorTareas) Auditoria() []Evento {
+	return g.eventosSi(func(Evento) bool { return true })
+}
+
+// AuditoriaTarea retorna los eventos de una tarea, del más antiguo al más
+// reciente. Permite saber, por ejemplo, cuándo y quién la completó o qué
+// título tenía antes de editarla, aunque ya esté en la papelera o purgada.
+//
+// Parámetros:
+//   - id: la tarea cuyos eventos se buscan
+//
+// Retorna:
+//   - []Evento: los eventos de la tarea (vacío si no hay ninguno)
+//
+// Ejemplo:
+//
+//	for _, evento := range gestor.AuditoriaTarea(3) {
+//		if evento.Tipo == OperacionCompletar {
+//			fmt.Println("Completada el", evento.Fecha)
+//		}
+//	}
+//
+func (g *GestorTareas) AuditoriaTarea(id int) []Evento {
+	return g.eventosSi(func(e Evento) bool { return e.TareaID == id })
+}
+
+// ExportarAuditoria escribe los eventos en w con el formato indicado.
+//
+// En FormatoCSV escribe una fila por campo cambiado (ver
+// Evento.CamposCambiados) con las columnas fecha, usuario, tipo, tarea_id,
+// lista, campo, antes y despues; en FormatoMarkdown, un elemento por evento
+// con sus campos cambiados anidados. Las fechas de los eventos se escriben
+// en RFC 3339.
+//
+// Parámetros:
+//   - w: destino (un archivo, la respuesta HTTP, ...)
+//   - eventos: los eventos a exportar (ej: gestor.Auditoria())
+//   - formato: FormatoCSV o FormatoMarkdown
+//
+// Retorna:
+//   - error: error de escritura, o ErrorValidacion si el formato no es
+//     CSV ni Markdown
+//
+// Ejemplo:
+//
+//	err := ExportarAuditoria(os.Stdout, gestor.AuditoriaTarea(3), FormatoCSV)
+//
+func ExportarAuditoria(w io.Writer, eventos []Evento, formato Formato) error {
+	switch formato {
+	case FormatoCSV:
+		return exportarAuditoriaCSV(w, eventos)
+	case FormatoMarkdown:
+		return exportarAuditoriaMarkdown(w, eventos)
+	}
+	return &ErrorValidacion{fmt.Sprintf("la auditoría no puede exportarse como %q: usa csv o markdown", formato)}
+}
+
+// exportarAuditoriaCSV escribe la cabecera y una fila por campo cambiado; un
+// evento sin campos cambiados ocupa una fila con el campo vacío.
+func exportarAuditoriaCSV(w io.Writer, eventos []Evento) error {
+	escritor := csv.NewWriter(w)
+	escritor.Write([]string{"fecha", "usuario", "tipo", "tarea_id", "lista", "campo", "antes", "despues"})
+	for _, e := range eventos {
+		fila := []string{formatearFechaCSV(e.Fecha), e.Usuario, string(e.Tipo), formatearID(e.TareaID), e.Lista}
+		campos := e.CamposCambiados()
+		if len(campos) == 0 {
+			escritor.Write(append(fila, "", "", ""))
+		}
+		for _, c := range campos {
+			escritor.Write(append(slices.Clone(fila), c.Campo, c.Antes, c.Despues))
+		}
+	}
+	escritor.Flush()
+	if err := escritor.Error(); err != nil {
+		return fmt.Errorf("error al exportar auditoría CSV: %v", err)
+	}
+	return nil
+}
+
+// exportarAuditoriaMarkdown escribe un encabezado y un elemento por evento
+// con sus campos cambiados.
+func exportarAuditoriaMarkdown(w io.Writer, eventos []Evento) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, "# Auditoría")
+	fmt.Fprintln(b)
+	for _, e := range eventos {
+		fmt.Fprintf(b, "- %s\n", e)
+		for _, c := range e.CamposCambiados() {
+			fmt.Fprintf(b, "  - %s: `%s` → `%s`\n", c.Campo, c.Antes, c.Despues)
+		}
+	}
+	if err := b.Flush(); err != nil {
+		return fmt.Errorf("error al exportar auditoría Markdown: %v", err)
+	}
+	return nil
+}
+
+// cargarAuditoria lee los eventos del archivo de auditoría, si existe.
+//
+// Como en el registro, una última línea incompleta (escritura interrumpida)
+// se recorta del archivo, para que los eventos añadidos después no la dejen
+// en medio, y cualquier otra línea inválida se reporta con su número.
+func (g *GestorTareas) cargarAuditoria() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	datos, err := os.ReadFile(g.rutaAuditoria)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error al leer auditoría: %v", err)
+	}
+	if datos, err = descartarLineaIncompleta(g.rutaAuditoria, datos); err != nil {
+		return err
+	}
+
+	lineas := bytes.Split(datos, []byte("\n"))
+	for i, linea := range lineas {
+		if len(bytes.TrimSpace(linea)) == 0 {
+			continue
+		}
+		var evento Evento
+		if err := json.Unmarshal(linea, &evento); err != nil {
+			if i == len(lineas)-1 {
+				break
+			}
+			return fmt.Errorf("error al parsear auditoría %s (línea %d): %v", g.rutaAuditoria, i+1, err)
+		}
+		g.auditoria = append(g.auditoria, evento)
+	}
+	return nil
+}
+
+// auditar registra en la auditoría los cambios de una operación. Con
+// deshecha, los cambios se registran invertidos (de despues a antes), como
+// los aplica Deshacer. Debe llamarse con g.mu tomado en modo escritura, tras
+// aplicar los cambios.
+func (g *GestorTareas) auditar(tipo TipoOperacion, op Operacion, deshecha bool) {
+	ahora := g.reloj()
+	var eventos []Evento
+	for i, c := range op.cambios {
+		antes, despues := c.antes, c.despues
+		if deshecha {
+			antes, despues = despues, antes
+		}
+		evento := Evento{Tipo: tipo, TareaID: c.id(), Antes: copiarTarea(antes), Despues: copiarTarea(despues), Fecha: ahora, Usuario: g.usuario}
+		// La siguiente ocurrencia de una tarea recurrente se crea al completarla
+		if tipo == OperacionCompletar && i > 0 && antes == nil {
+			evento.Tipo = OperacionCrear
+		}
+		eventos = append(eventos, evento)
+	}
+	if op.listas != nil {
+		antes, despues := op.listas.antes, op.listas.despues
+		if deshecha {
+			antes, despues = despues, antes
+		}
+		eventos = append(eventos, Evento{Tipo: tipo, Lista: op.Lista, ListasAntes: slices.Clone(antes),
+			ListasDespues: slices.Clone(despues), Fecha: ahora, Usuario: g.usuario})
+	}
+	g.registrarEventos(eventos)
+}
+
+// registrarEventos añade eventos a la auditoría y, si se configuró
+// ConAuditoria, al archivo. El cambio ya está aplicado, así que un error de
+// escritura no lo revierte: se avisa al usuario y el evento queda en memoria.
+// Debe llamarse con g.mu tomado en modo escritura.
+func (g *GestorTareas) registrarEventos(eventos []Evento) {
+	if len(eventos) == 0 {
+		return
+	}
+	g.auditoria = append(g.auditoria, eventos...)
+	if g.rutaAuditoria == "" {
+		return
+	}
+	entradas := make([]any, len(eventos))
+	for i, evento := range eventos {
+		entradas[i] = evento
+	}
+	if err := anadirEntrada(g.rutaAuditoria, entradas...); err != nil {
+		fmt.Fprintf(g.salida, "⚠️  Error al escribir la auditoría: %v\n", err)
+	}
+}
+
+// eventosSi retorna copias de los eventos de la auditoría que cumplen la
+// condición, en orden.
+func (g *GestorTareas) eventosSi(condicion func(Evento) bool) []Evento {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var encontrados []Evento
+	for _, evento := range g.auditoria {
+		if condicion(evento) {
+			evento.Antes, evento.Despues = copiarTarea(evento.Antes), copiarTarea(evento.Despues)
+			evento.ListasAntes, evento.ListasDespues = slices.Clone(evento.ListasAntes), slices.Clone(evento.ListasDespues)
+			encontrados = append(encontrados, evento)
+		}
+	}
+	return encontrados
+}
+
+// copiarTarea retorna un puntero a una copia de la tarea, o nil.
+func copiarTarea(tarea *Tarea) *Tarea {
+	if tarea == nil {
+		return nil
+	}
+	copia := tarea.clonar()
+	return &copia
+}
+
+// formatearID escribe un ID, o "" si es 0.
+func formatearID(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.Itoa(id)
+}
+
+// formatearIDs escribe una lista de IDs separados por comas.
+func formatearIDs(ids []int) string {
+	textos := make([]string, len(ids))
+	for i, id := range ids {
+		textos[i] = strconv.Itoa(id)
+	}
+	return strings.Join(textos, ",")
+}
@@ -0,0 +1,228 @@
+// Tests de la auditoría de cambios
+
+package tareas
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tiposDe retorna los tipos de los eventos, en orden
+func tiposDe(eventos []Evento) []TipoOperacion {
+	var tipos []TipoOperacion
+	for _, evento := range eventos {
+		tipos = append(tipos, evento.Tipo)
+	}
+	return tipos
+}
+
+// TestAuditoriaRegistraMutaciones verifica que cada mutación quede en la
+// auditoría con su tipo, fecha, usuario y valores antes y después
+func TestAuditoriaRegistraMutaciones(t *testing.T) {
+	ahora := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	// Sin historial de deshacer, para comprobar que la auditoría no depende de él
+	ana := nuevoGestorEnMemoria(t, ConUsuario("ana"), ConHistorial(0), ConReloj(func() time.Time { return ahora }))
+	luis := ana.ComoUsuario("luis")
+
+	ana.CrearConDatos(DatosTarea{Titulo: "Comprar pan", Asignados: []string{"luis"}})
+	ahora = ahora.Add(time.Hour)
+	titulo := "Comprar pan integral"
+	luis.Actualizar(1, CambiosTarea{Titulo: &titulo})
+	ahora = ahora.Add(time.Hour)
+	luis.Completar(1)
+	ana.Eliminar(1)
+	ana.Restaurar(1)
+
+	eventos := ana.AuditoriaTarea(1)
+	esperados := []TipoOperacion{OperacionCrear, OperacionEditar, OperacionCompletar, OperacionEliminar, OperacionRestaurar}
+	if tipos := tiposDe(eventos); !reflect.DeepEqual(tipos, esperados) {
+		t.Fatalf("Tipos = %v, se esperaba %v", tipos, esperados)
+	}
+
+	// Cuándo se completó, quién lo hizo y qué título tenía antes
+	completada := eventos[2]
+	if !completada.Fecha.Equal(ahora) || completada.Usuario != "luis" {
+		t.Errorf("Completada el %v por %q, se esperaba el %v por luis", completada.Fecha, completada.Usuario, ahora)
+	}
+	edicion := eventos[1]
+	if edicion.Antes.Titulo != "Comprar pan" || edicion.Despues.Titulo != titulo {
+		t.Errorf("Títulos de la edición: %q → %q", edicion.Antes.Titulo, edicion.Despues.Titulo)
+	}
+	if campos := edicion.CamposCambiados(); !reflect.DeepEqual(campos, []CampoCambiado{{"titulo", "Comprar pan", titulo}}) {
+		t.Errorf("Campos de la edición: %+v", campos)
+	}
+	if eventos[0].Antes != nil || eventos[0].Usuario != "ana" {
+		t.Errorf("La creación no debería tener estado previo: %+v", eventos[0])
+	}
+
+	// Las operaciones rechazadas no se auditan
+	ana.Crear("Tarea de ana")
+	if err := ana.ComoUsuario("marta").Completar(2); err == nil {
+		t.Fatal("Se esperaba un error de permiso")
+	}
+	if tipos := tiposDe(ana.AuditoriaTarea(2)); !reflect.DeepEqual(tipos, []TipoOperacion{OperacionCrear}) {
+		t.Errorf("Tipos de la tarea 2 = %v", tipos)
+	}
+
+	// Las copias no alteran la auditoría
+	eventos[1].Antes.Titulo = "Otro"
+	if original := ana.AuditoriaTarea(1)[1]; original.Antes.Titulo != "Comprar pan" {
+		t.Error("Modificar la copia no debería alterar la auditoría")
+	}
+}
+
+// TestAuditoriaOperacionesCompuestas verifica los eventos de deshacer,
+// rehacer, las subtareas, la recurrencia, las listas y la purga
+func TestAuditoriaOperacionesCompuestas(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t)
+
+	gestor.Crear("Preparar viaje")
+	gestor.CrearConDatos(DatosTarea{Titulo: "Reservar hotel", PadreID: 1})
+	gestor.CrearConDatos(DatosTarea{Titulo: "Regar plantas", Recurrencia: &Recurrencia{Frecuencia: FrecuenciaDiaria}})
+	gestor.CompletarConSiguiente(3)
+	gestor.EliminarConSubtareas(1)
+	gestor.Deshacer()
+	gestor.Rehacer()
+	gestor.CrearLista("casa")
+	gestor.VaciarPapelera()
+
+	tests := []struct {
+		nombre   string
+		id       int
+		esperado []TipoOperacion
+	}{
+		{"padre", 1, []TipoOperacion{OperacionCrear, OperacionEliminar, OperacionDeshacer, OperacionRehacer, OperacionPurgar}},
+		{"subtarea", 2, []TipoOperacion{OperacionCrear, OperacionEliminar, OperacionDeshacer, OperacionRehacer, OperacionPurgar}},
+		{"recurrente", 3, []TipoOperacion{OperacionCrear, OperacionCompletar}},
+		{"siguiente ocurrencia", 4, []TipoOperacion{OperacionCrear}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			if tipos := tiposDe(gestor.AuditoriaTarea(tt.id)); !reflect.DeepEqual(tipos, tt.esperado) {
+				t.Errorf("Tipos = %v, se esperaba %v", tipos, tt.esperado)
+			}
+		})
+	}
+
+	// Deshacer la eliminación registra la tarea saliendo de la papelera
+	deshecho := gestor.AuditoriaTarea(1)[2]
+	if !deshecho.Antes.EnPapelera() || deshecho.Despues.EnPapelera() {
+		t.Errorf("Deshacer debería sacar la tarea de la papelera: %+v", deshecho)
+	}
+	// La purga conserva la última versión de la tarea
+	purgada := gestor.AuditoriaTarea(1)[4]
+	if purgada.Despues != nil || purgada.Antes.Titulo != "Preparar viaje" {
+		t.Errorf("Evento de purga inesperado: %+v", purgada)
+	}
+
+	// Las listas se registran con sus nombres antes y después
+	var lista *Evento
+	for _, evento := range gestor.Auditoria() {
+		if evento.Tipo == OperacionCrearLista {
+			lista = &evento
+		}
+	}
+	if lista == nil || lista.TareaID != 0 || lista.Lista != "casa" || !reflect.DeepEqual(lista.ListasDespues, []string{"casa"}) {
+		t.Fatalf("Evento de lista inesperado: %+v", lista)
+	}
+	if campos := lista.CamposCambiados(); !reflect.DeepEqual(campos, []CampoCambiado{{"listas", "", "casa"}}) {
+		t.Errorf("Campos de la lista: %+v", campos)
+	}
+}
+
+// TestAuditoriaPersistente verifica que la auditoría se conserve en su
+// archivo entre ejecuciones y que Guardar no la vacíe
+func TestAuditoriaPersistente(t *testing.T) {
+	dir := t.TempDir()
+	ruta := filepath.Join(dir, "tareas.json")
+	rutaAuditoria := ruta + ".auditoria"
+
+	gestor, err := NuevoGestorTareas(ruta, ConAuditoria(rutaAuditoria), ConUsuario("ana"), ConSalida(&bytes.Buffer{}))
+	if err != nil {
+		t.Fatalf("Error al crear gestor: %v", err)
+	}
+	gestor.Crear("Comprar pan")
+	gestor.Completar(1)
+	if err := gestor.Guardar(); err != nil {
+		t.Fatalf("Error al guardar: %v", err)
+	}
+
+	// Una última línea a medio escribir se ignora
+	archivo, _ := os.OpenFile(rutaAuditoria, os.O_APPEND|os.O_WRONLY, 0644)
+	archivo.WriteString(`{"tipo":"editar","tarea_id":1,"fe`)
+	archivo.Close()
+
+	recargado, err := NuevoGestorTareas(ruta, ConAuditoria(rutaAuditoria), ConSalida(&bytes.Buffer{}))
+	if err != nil {
+		t.Fatalf("Error al recargar: %v", err)
+	}
+	eventos := recargado.AuditoriaTarea(1)
+	if tipos := tiposDe(eventos); !reflect.DeepEqual(tipos, []TipoOperacion{OperacionCrear, OperacionCompletar}) {
+		t.Fatalf("Tipos tras recargar = %v", tipos)
+	}
+	if eventos[1].Usuario != "ana" || !eventos[1].Despues.Completada {
+		t.Errorf("Evento recargado inesperado: %+v", eventos[1])
+	}
+
+	// La carga la recorta del archivo; ni lo añadido después ni lo añadido tras otra escritura
+	// interrumpida en la misma sesión la dejan en medio del archivo
+	if datos, _ := os.ReadFile(rutaAuditoria); !strings.HasSuffix(string(datos), "}\n") {
+		t.Errorf("La carga debería recortar la línea a medio escribir:\n%s", datos)
+	}
+	recargado.Crear("Llamar a Luis")
+	archivo, _ = os.OpenFile(rutaAuditoria, os.O_APPEND|os.O_WRONLY, 0644)
+	archivo.WriteString(`{"tipo":"crear","ta`)
+	archivo.Close()
+	recargado.Completar(2)
+	recargado, err = NuevoGestorTareas(ruta, ConAuditoria(rutaAuditoria), ConSalida(&bytes.Buffer{}))
+	if err != nil {
+		t.Fatalf("Error al recargar tras añadir: %v", err)
+	}
+	if tipos := tiposDe(recargado.AuditoriaTarea(2)); !reflect.DeepEqual(tipos, []TipoOperacion{OperacionCrear, OperacionCompletar}) {
+		t.Errorf("Tipos de la tarea 2 tras recargar = %v", tipos)
+	}
+
+	// Una línea dañada en medio del archivo sí es un error
+	os.WriteFile(rutaAuditoria, []byte("{roto\n{}\n"), 0644)
+	if _, err := NuevoGestorTareas(ruta, ConAuditoria(rutaAuditoria), ConSalida(&bytes.Buffer{})); err == nil {
+		t.Error("Se esperaba error al cargar una auditoría dañada")
+	}
+}
+
+// TestExportarAuditoria verifica la exportación a CSV y Markdown
+func TestExportarAuditoria(t *testing.T) {
+	ahora := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	gestor := nuevoGestorEnMemoria(t, ConUsuario("ana"), ConReloj(func() time.Time { return ahora }))
+	gestor.Crear("Comprar pan")
+	gestor.Completar(1)
+
+	var csv bytes.Buffer
+	if err := ExportarAuditoria(&csv, gestor.Auditoria(), FormatoCSV); err != nil {
+		t.Fatalf("Error al exportar CSV: %v", err)
+	}
+	esperado := "fecha,usuario,tipo,tarea_id,lista,campo,antes,despues\n" +
+		"2026-03-02T09:00:00Z,ana,crear,1,,titulo,,Comprar pan\n" +
+		"2026-03-02T09:00:00Z,ana,crear,1,,propietario,,ana\n" +
+		"2026-03-02T09:00:00Z,ana,completar,1,,completada,false,true\n" +
+		"2026-03-02T09:00:00Z,ana,completar,1,,fecha_completada,,2026-03-02T09:00:00Z\n"
+	if csv.String() != esperado {
+		t.Errorf("CSV inesperado:\n%s", csv.String())
+	}
+
+	var markdown bytes.Buffer
+	if err := ExportarAuditoria(&markdown, gestor.Auditoria(), FormatoMarkdown); err != nil {
+		t.Fatalf("Error al exportar Markdown: %v", err)
+	}
+	if !strings.Contains(markdown.String(), "- 02/03/2026 09:00 ana: completar tarea 1 'Comprar pan'\n  - completada: `false` → `true`") {
+		t.Errorf("Markdown inesperado:\n%s", markdown.String())
+	}
+
+	if err := ExportarAuditoria(&bytes.Buffer{}, nil, FormatoICalendar); err == nil {
+		t.Error("Se esperaba error con un formato no admitido")
+	}
+}
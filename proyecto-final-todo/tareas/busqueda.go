@@ -0,0 +1,235 @@
+package tareas
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ToleranciaPorDefecto es la cantidad de errores de escritura por palabra
+// que admite la búsqueda por relevancia en la CLI y la API.
+const ToleranciaPorDefecto = 1
+
+// ResultadoBusqueda es una tarea encontrada por BuscarPorRelevancia junto
+// con su puntuación.
+type ResultadoBusqueda struct {
+	// Tarea es una copia de la tarea encontrada
+	Tarea Tarea `json:"tarea"`
+
+	// Puntuacion mide la relevancia, redondeada a dos decimales: cuanto
+	// mayor, mejor coincide
+	Puntuacion float64 `json:"puntuacion"`
+}
+
+// Pesos de cada campo de la tarea en la puntuación.
+const (
+	pesoTitulo      = 2.0
+	pesoDescripcion = 1.0
+	pesoEtiqueta    = 1.0
+)
+
+// BuscarPorRelevancia busca tareas tolerando tildes, mayúsculas y errores de
+// escritura, y las retorna ordenadas de más a menos relevante.
+//
+// El texto se divide en palabras y cada una debe aparecer en el título, la
+// descripción o las etiquetas de la tarea, ya sea como palabra exacta, como
+// comienzo o parte de otra palabra, o con hasta tolerancia errores (letras
+// de más, de menos, cambiadas o intercambiadas). Así "tarea" encuentra
+// "Tárea" y "tareas", y "comprr" encuentra "Comprar". Las palabras cortas
+// admiten menos errores (ninguno hasta 3 letras, uno desde 4 y dos desde 7)
+// para que no coincidan con cualquier cosa.
+//
+// La puntuación premia las coincidencias exactas sobre las aproximadas, las
+// del título sobre las de la descripción y que el texto completo aparezca
+// tal cual en el título. A igual puntuación se conserva el orden de creación.
+// Las tareas de la papelera no se incluyen.
+//
+// Parámetros:
+//   - texto: las palabras a buscar
+//   - tolerancia: errores de escritura admitidos por palabra (0 = ninguno)
+//
+// Retorna:
+//   - []ResultadoBusqueda: tareas encontradas con su puntuación (vacío si
+//     el texto no tiene palabras)
+//
+// Ejemplo:
+//
+//	for _, r := range gestor.BuscarPorRelevancia("matematicas", ToleranciaPorDefecto) {
+//		fmt.Printf("%.2f [%d] %s\n", r.Puntuacion, r.Tarea.ID, r.Tarea.Titulo)
+//	}
+//
+func (g *GestorTareas) BuscarPorRelevancia(texto string, tolerancia int) []ResultadoBusqueda {
+	consulta := palabras(texto)
+	if len(consulta) == 0 {
+		return nil
+	}
+	frase := strings.Join(consulta, " ")
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	candidatas := g.tareasCandidatas(consulta, func(buscada, termino string) bool {
+		return similitud(buscada, termino, tolerancia) > 0
+	})
+
+	var resultados []ResultadoBusqueda
+	for _, tarea := range candidatas {
+		puntuacion, ok := puntuar(tarea, consulta, tolerancia)
+		if !ok {
+			continue
+		}
+		if strings.Contains(strings.Join(palabras(tarea.Titulo), " "), frase) {
+			puntuacion += 1
+		}
+		resultados = append(resultados, ResultadoBusqueda{
+			Tarea:      tarea.clonar(),
+			Puntuacion: math.Round(puntuacion*100) / 100,
+		})
+	}
+
+	slices.SortStableFunc(resultados, func(a, b ResultadoBusqueda) int {
+		switch {
+		case a.Puntuacion > b.Puntuacion:
+			return -1
+		case a.Puntuacion < b.Puntuacion:
+			return 1
+		}
+		return 0
+	})
+	return resultados
+}
+
+// puntuar suma, para cada palabra de la consulta, su mejor coincidencia en
+// los campos de la tarea. Retorna false si alguna palabra no coincide.
+func puntuar(tarea Tarea, consulta []string, tolerancia int) (float64, bool) {
+	campos := []struct {
+		palabras []string
+		peso     float64
+	}{
+		{palabras(tarea.Titulo), pesoTitulo},
+		{palabras(tarea.Descripcion), pesoDescripcion},
+		{palabras(strings.Join(tarea.Etiquetas, " ")), pesoEtiqueta},
+	}
+
+	total := 0.0
+	for _, buscada := range consulta {
+		mejor := 0.0
+		for _, campo := range campos {
+			for _, palabra := range campo.palabras {
+				mejor = max(mejor, similitud(buscada, palabra, tolerancia)*campo.peso)
+			}
+		}
+		if mejor == 0 {
+			return 0, false
+		}
+		total += mejor
+	}
+	return total, true
+}
+
+// similitud puntúa entre 0 y 1 cuánto se parece la palabra buscada a una
+// palabra de la tarea, ambas ya normalizadas.
+func similitud(buscada, palabra string, tolerancia int) float64 {
+	switch {
+	case buscada == palabra:
+		return 1
+	case strings.HasPrefix(palabra, buscada):
+		return 0.8
+	case strings.Contains(palabra, buscada):
+		return 0.6
+	}
+
+	a, b := []rune(buscada), []rune(palabra)
+	tolerancia = min(tolerancia, (len(a)-1)/3)
+	if tolerancia <= 0 || abs(len(a)-len(b)) > tolerancia {
+		return 0
+	}
+	distancia := distanciaEdicion(a, b)
+	if distancia > tolerancia {
+		return 0
+	}
+	return 0.5 * (1 - float64(distancia)/float64(len(a)+1))
+}
+
+// distanciaEdicion calcula la distancia de Damerau-Levenshtein (en su
+// variante de alineamiento óptimo) entre a y b: el mínimo de inserciones,
+// borrados, sustituciones e intercambios de letras contiguas para pasar de
+// una a otra.
+func distanciaEdicion(a, b []rune) int {
+	// d[i][j] es la distancia entre a[:i] y b[:j]
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			coste := 1
+			if a[i-1] == b[j-1] {
+				coste = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+coste)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+// sinDiacriticos reemplaza las letras acentuadas más comunes por su letra
+// base. Cubre el español y los idiomas de Europa occidental.
+var sinDiacriticos = map[rune]rune{
+	'á': 'a', 'à': 'a', 'ä': 'a', 'â': 'a', 'ã': 'a', 'å': 'a',
+	'é': 'e', 'è': 'e', 'ë': 'e', 'ê': 'e',
+	'í': 'i', 'ì': 'i', 'ï': 'i', 'î': 'i',
+	'ó': 'o', 'ò': 'o', 'ö': 'o', 'ô': 'o', 'õ': 'o',
+	'ú': 'u', 'ù': 'u', 'ü': 'u', 'û': 'u',
+	'ñ': 'n', 'ç': 'c', 'ý': 'y', 'ÿ': 'y',
+}
+
+// normalizarTexto pasa el texto a minúsculas y le quita las tildes y demás
+// diacríticos, tanto si vienen compuestos ("á") como descompuestos ("a"
+// seguida de un acento combinable), para comparar textos en español.
+func normalizarTexto(texto string) string {
+	var b strings.Builder
+	b.Grow(len(texto))
+	for _, r := range texto {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		r = unicode.ToLower(r)
+		if base, ok := sinDiacriticos[r]; ok {
+			r = base
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// palabras normaliza el texto y lo divide en palabras, descartando los
+// signos de puntuación.
+func palabras(texto string) []string {
+	return strings.FieldsFunc(normalizarTexto(texto), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// contieneNormalizado indica si texto contiene buscado sin distinguir
+// mayúsculas ni tildes.
+func contieneNormalizado(texto, buscado string) bool {
+	return strings.Contains(normalizarTexto(texto), normalizarTexto(buscado))
+}
+
+// abs retorna el valor absoluto de n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,97 @@
+// Tests de la búsqueda tolerante a tildes y errores
+
+package tareas
+
+import (
+	"testing"
+)
+
+// TestNormalizarTexto verifica que se ignoren mayúsculas y diacríticos
+func TestNormalizarTexto(t *testing.T) {
+	tests := []struct {
+		texto    string
+		esperado string
+	}{
+		{"Tárea", "tarea"},
+		{"CAMIÓN Ñandú", "camion nandu"},
+		{"pingüino", "pinguino"},
+		{"árbol", "arbol"}, // tilde combinable (forma descompuesta)
+		{"sin cambios 123", "sin cambios 123"},
+	}
+	for _, tt := range tests {
+		if obtenido := normalizarTexto(tt.texto); obtenido != tt.esperado {
+			t.Errorf("normalizarTexto(%q) = %q, se esperaba %q", tt.texto, obtenido, tt.esperado)
+		}
+	}
+}
+
+// TestDistanciaEdicion verifica la distancia entre palabras
+func TestDistanciaEdicion(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		esperada int
+	}{
+		{"tarea", "tarea", 0},
+		{"tarea", "taera", 1},  // letras intercambiadas
+		{"tarea", "tareas", 1}, // letra de más
+		{"tarea", "tara", 1},   // letra de menos
+		{"tarea", "torea", 1},  // letra cambiada
+		{"comprar", "vender", 6},
+		{"", "go", 2},
+	}
+	for _, tt := range tests {
+		if obtenida := distanciaEdicion([]rune(tt.a), []rune(tt.b)); obtenida != tt.esperada {
+			t.Errorf("distanciaEdicion(%q, %q) = %d, se esperaba %d", tt.a, tt.b, obtenida, tt.esperada)
+		}
+	}
+}
+
+// TestBuscarPorRelevancia verifica qué tareas se encuentran y en qué orden
+func TestBuscarPorRelevancia(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t)
+	gestor.Crear("Revisar tareas pendientes")                                             // 1
+	gestor.CrearConDatos(DatosTarea{Titulo: "Comprar pan", Descripcion: "Para la tárea"}) // 2
+	gestor.Crear("Tárea de matemáticas")                                                  // 3
+	gestor.CrearConDatos(DatosTarea{Titulo: "Llamar", Etiquetas: []string{"trabajo"}})    // 4
+	gestor.Crear("Tarea borrada")                                                         // 5
+	gestor.Eliminar(5)
+
+	tests := []struct {
+		nombre     string
+		texto      string
+		tolerancia int
+		esperados  []int
+	}{
+		{"exacta en el título primero", "tarea", 0, []int{3, 1, 2}},
+		{"sin tildes", "matematicas", 0, []int{3}},
+		{"con un error", "comprr", 1, []int{2}},
+		{"letras intercambiadas", "matemátcias", 1, []int{3}},
+		{"sin tolerancia no hay errores", "comprr", 0, nil},
+		{"todas las palabras deben coincidir", "tarea pan", 1, []int{2}},
+		{"en las etiquetas", "trabjo", 1, []int{4}},
+		{"palabra corta sin errores", "pon", 1, nil},
+		{"texto vacío", "  ", 1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			resultados := gestor.BuscarPorRelevancia(tt.texto, tt.tolerancia)
+			var ids []int
+			for _, r := range resultados {
+				ids = append(ids, r.Tarea.ID)
+			}
+			if len(ids) != len(tt.esperados) {
+				t.Fatalf("Se esperaban las tareas %v, se obtuvieron: %v", tt.esperados, ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.esperados[i] {
+					t.Fatalf("Se esperaban las tareas %v, se obtuvieron: %v", tt.esperados, ids)
+				}
+			}
+			for i := 1; i < len(resultados); i++ {
+				if resultados[i].Puntuacion > resultados[i-1].Puntuacion {
+					t.Errorf("Los resultados deberían ir de mayor a menor puntuación: %+v", resultados)
+				}
+			}
+		})
+	}
+}
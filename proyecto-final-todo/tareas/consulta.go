@@ -0,0 +1,326 @@
+package tareas
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrorConsulta indica que el texto de una consulta no respeta la sintaxis.
+//
+// Los llamadores pueden detectarlo con errors.As para, por ejemplo,
+// traducirlo a un 400 en la API HTTP.
+type ErrorConsulta struct {
+	// Posicion es el carácter (contando desde 1) donde se detectó el error
+	Posicion int
+
+	// Mensaje describe el problema y, si procede, los valores admitidos
+	Mensaje string
+}
+
+// Error implementa la interfaz error.
+func (e *ErrorConsulta) Error() string {
+	return fmt.Sprintf("consulta inválida en la posición %d: %s", e.Posicion, e.Mensaje)
+}
+
+// Consulta es un filtro de tareas obtenido con ParsearConsulta.
+//
+// Una tarea cumple la consulta si cumple todos sus términos. Una consulta
+// vacía acepta todas las tareas.
+type Consulta struct {
+	// texto es la consulta original, para mostrarla
+	texto string
+
+	// terminos son las condiciones que deben cumplirse a la vez
+	terminos []termino
+}
+
+// termino es una condición de la consulta, posiblemente negada.
+type termino struct {
+	negado   bool
+	coincide func(tarea Tarea, ahora time.Time) bool
+}
+
+// camposConsulta son los campos admitidos en los términos campo:valor.
+const camposConsulta = "estado, prioridad, tag, lista, propietario, asignada, creada, vence o completada"
+
+// ParsearConsulta convierte el texto de una búsqueda en una Consulta.
+//
+// La sintaxis admite, separados por espacios:
+//   - palabra: el título o la descripción contienen la palabra
+//   - "texto exacto": el título o la descripción contienen la frase
+//   - estado:pendiente, estado:completada o estado:vencida
+//   - prioridad:alta, o comparada: prioridad:>=media (ninguna = sin prioridad)
+//   - tag:trabajo (o etiqueta:trabajo)
+//   - lista:casa (lista:general para la lista por defecto)
+//   - propietario:ana, o asignada:luis (asignada a ese usuario)
+//   - creada:, vence: o completada: con una fecha aaaa-mm-dd, opcionalmente
+//     precedida de >, >=, < o <= (sin operador, ese mismo día)
+//   - -término: niega cualquiera de los anteriores
+//
+// Las comparaciones de texto no distinguen mayúsculas ni tildes. Todos los términos
+// deben cumplirse a la vez. Para buscar un texto que contenga ':' debe
+// escribirse entre comillas.
+//
+// Parámetros:
+//   - texto: la consulta escrita por el usuario
+//
+// Retorna:
+//   - *Consulta: el filtro listo para evaluar con GestorTareas.Filtrar
+//   - error: *ErrorConsulta con la posición y la causa si la sintaxis no es válida
+//
+// Ejemplo:
+//
+//	consulta, err := ParsearConsulta(`estado:pendiente prioridad:alta tag:trabajo "informe anual" -borrador creada:>2026-01-01`)
+//	if err != nil {
+//		fmt.Println(err) // p. ej. "consulta inválida en la posición 8: comillas sin cerrar"
+//	}
+//
+func ParsearConsulta(texto string) (*Consulta, error) {
+	consulta := &Consulta{texto: strings.TrimSpace(texto)}
+	runas := []rune(texto)
+
+	for i := 0; i < len(runas); {
+		if unicode.IsSpace(runas[i]) {
+			i++
+			continue
+		}
+		inicio := i
+
+		negado := runas[i] == '-'
+		if negado {
+			i++
+			if i == len(runas) || unicode.IsSpace(runas[i]) {
+				return nil, &ErrorConsulta{inicio + 1, "falta el término que sigue a '-'"}
+			}
+		}
+
+		// Frase entre comillas
+		if runas[i] == '"' {
+			fin := i + 1
+			for fin < len(runas) && runas[fin] != '"' {
+				fin++
+			}
+			if fin == len(runas) {
+				return nil, &ErrorConsulta{i + 1, "comillas sin cerrar"}
+			}
+			frase := string(runas[i+1 : fin])
+			if strings.TrimSpace(frase) == "" {
+				return nil, &ErrorConsulta{i + 1, "la frase entre comillas está vacía"}
+			}
+			consulta.terminos = append(consulta.terminos, termino{negado, contieneTexto(frase)})
+			i = fin + 1
+			continue
+		}
+
+		// Palabra o campo:valor hasta el siguiente espacio
+		fin := i
+		for fin < len(runas) && !unicode.IsSpace(runas[fin]) {
+			fin++
+		}
+		palabra := string(runas[i:fin])
+
+		coincide, err := parsearTermino(palabra, i+1)
+		if err != nil {
+			return nil, err
+		}
+		consulta.terminos = append(consulta.terminos, termino{negado, coincide})
+		i = fin
+	}
+
+	return consulta, nil
+}
+
+// String retorna el texto original de la consulta.
+func (c *Consulta) String() string {
+	return c.texto
+}
+
+// Coincide indica si la tarea cumple todos los términos de la consulta.
+// ahora es el momento de referencia para estado:vencida.
+func (c *Consulta) Coincide(tarea Tarea, ahora time.Time) bool {
+	for _, t := range c.terminos {
+		if t.coincide(tarea, ahora) == t.negado {
+			return false
+		}
+	}
+	return true
+}
+
+// Buscar retorna las tareas que cumplen una consulta escrita con la sintaxis
+// de ParsearConsulta, en el orden en que fueron creadas. Las tareas de la
+// papelera no se incluyen.
+//
+// Retorna:
+//   - []Tarea: tareas que cumplen la consulta (puede estar vacío)
+//   - error: *ErrorConsulta si la consulta no es válida
+//
+// Ejemplo:
+//
+//	resultados, err := gestor.Buscar(`tag:casa -estado:completada "lista de la compra"`)
+//	if err != nil {
+//		fmt.Println("❌", err)
+//	}
+//
+func (g *GestorTareas) Buscar(texto string) ([]Tarea, error) {
+	consulta, err := ParsearConsulta(texto)
+	if err != nil {
+		return nil, err
+	}
+	return g.Filtrar(g.Listar(), consulta), nil
+}
+
+// Filtrar retorna las tareas de lista que cumplen la consulta, evaluada con
+// el reloj del gestor. Permite combinar una consulta con cualquier listado
+// (ListarPendientes, ListarSiguientes, ...).
+func (g *GestorTareas) Filtrar(lista []Tarea, consulta *Consulta) []Tarea {
+	ahora := g.reloj()
+	var filtradas []Tarea
+	for _, tarea := range lista {
+		if consulta.Coincide(tarea, ahora) {
+			filtradas = append(filtradas, tarea)
+		}
+	}
+	return filtradas
+}
+
+// parsearTermino interpreta una palabra sin comillas: un campo:valor si
+// empieza por un nombre de campo seguido de ':', o un texto a buscar.
+// posicion es la de la palabra en la consulta, para los errores.
+func parsearTermino(palabra string, posicion int) (func(Tarea, time.Time) bool, error) {
+	campo, valor, esCampo := strings.Cut(palabra, ":")
+	if !esCampo || campo == "" || strings.IndexFunc(campo, func(r rune) bool { return !unicode.IsLetter(r) }) >= 0 {
+		return contieneTexto(palabra), nil
+	}
+
+	campo = strings.ToLower(campo)
+	posicionValor := posicion + len([]rune(campo)) + 1
+	if valor == "" {
+		return nil, &ErrorConsulta{posicionValor, fmt.Sprintf("falta el valor de %q", campo)}
+	}
+
+	switch campo {
+	case "estado":
+		return parsearEstado(strings.ToLower(valor), posicionValor)
+	case "prioridad":
+		return parsearPrioridad(strings.ToLower(valor), posicionValor)
+	case "tag", "etiqueta":
+		return func(t Tarea, _ time.Time) bool { return t.TieneEtiqueta(valor) }, nil
+	case "lista":
+		lista := normalizarLista(valor)
+		return func(t Tarea, _ time.Time) bool { return t.Lista == lista }, nil
+	case "propietario":
+		usuario := normalizarUsuario(valor)
+		return func(t Tarea, _ time.Time) bool { return t.Propietario == usuario }, nil
+	case "asignada", "asignado":
+		usuario := normalizarUsuario(valor)
+		return func(t Tarea, _ time.Time) bool { return t.EstaAsignada(usuario) }, nil
+	case "creada":
+		return parsearFecha(valor, posicionValor, func(t Tarea) time.Time { return t.FechaCreacion })
+	case "vence":
+		return parsearFecha(valor, posicionValor, func(t Tarea) time.Time { return t.FechaVencimiento })
+	case "completada":
+		return parsearFecha(valor, posicionValor, func(t Tarea) time.Time { return t.FechaCompletada })
+	}
+	return nil, &ErrorConsulta{posicion, fmt.Sprintf("campo desconocido %q: usa %s (o pon el texto entre comillas)", campo, camposConsulta)}
+}
+
+// contieneTexto busca texto en el título o la descripción, sin distinguir
+// mayúsculas ni tildes.
+func contieneTexto(texto string) func(Tarea, time.Time) bool {
+	return func(t Tarea, _ time.Time) bool {
+		return contieneNormalizado(t.Titulo, texto) || contieneNormalizado(t.Descripcion, texto)
+	}
+}
+
+// parsearEstado interpreta el valor de estado:.
+func parsearEstado(valor string, posicion int) (func(Tarea, time.Time) bool, error) {
+	switch strings.TrimSuffix(valor, "s") {
+	case "pendiente":
+		return func(t Tarea, _ time.Time) bool { return !t.Completada }, nil
+	case "completada":
+		return func(t Tarea, _ time.Time) bool { return t.Completada }, nil
+	case "vencida":
+		return func(t Tarea, ahora time.Time) bool { return t.Vencida(ahora) }, nil
+	}
+	return nil, &ErrorConsulta{posicion, fmt.Sprintf("estado desconocido %q: usa pendiente, completada o vencida", valor)}
+}
+
+// parsearPrioridad interpreta el valor de prioridad:, con un operador de
+// comparación opcional.
+func parsearPrioridad(valor string, posicion int) (func(Tarea, time.Time) bool, error) {
+	operador, valor := separarOperador(valor)
+	posicion += len(operador)
+
+	prioridad := Prioridad(valor)
+	if valor == "ninguna" {
+		prioridad = ""
+	}
+	nivel := prioridad.Nivel()
+	if nivel < 0 || valor == "" {
+		return nil, &ErrorConsulta{posicion, fmt.Sprintf("prioridad desconocida %q: usa baja, media, alta, urgente o ninguna", valor)}
+	}
+
+	return func(t Tarea, _ time.Time) bool {
+		return comparar(t.Prioridad.Nivel(), nivel, operador)
+	}, nil
+}
+
+// parsearFecha interpreta una fecha aaaa-mm-dd con un operador opcional y
+// retorna una condición sobre la fecha que extrae campo. Las tareas sin esa
+// fecha (valor cero) nunca la cumplen.
+func parsearFecha(valor string, posicion int, campo func(Tarea) time.Time) (func(Tarea, time.Time) bool, error) {
+	operador, valor := separarOperador(valor)
+	posicion += len(operador)
+
+	dia, err := time.ParseInLocation("2006-01-02", valor, time.Local)
+	if err != nil {
+		return nil, &ErrorConsulta{posicion, fmt.Sprintf("fecha inválida %q: usa aaaa-mm-dd, por ejemplo 2026-01-31", valor)}
+	}
+	siguiente := dia.AddDate(0, 0, 1)
+
+	return func(t Tarea, _ time.Time) bool {
+		fecha := campo(t)
+		if fecha.IsZero() {
+			return false
+		}
+		switch operador {
+		case ">":
+			return !fecha.Before(siguiente)
+		case ">=":
+			return !fecha.Before(dia)
+		case "<":
+			return fecha.Before(dia)
+		case "<=":
+			return fecha.Before(siguiente)
+		}
+		return !fecha.Before(dia) && fecha.Before(siguiente)
+	}, nil
+}
+
+// separarOperador separa el operador de comparación inicial (>, >=, <, <=
+// o =) del resto del valor. Sin operador retorna "".
+func separarOperador(valor string) (string, string) {
+	for _, operador := range []string{">=", "<=", ">", "<", "="} {
+		if resto, ok := strings.CutPrefix(valor, operador); ok {
+			return operador, resto
+		}
+	}
+	return "", valor
+}
+
+// comparar aplica el operador a dos niveles; sin operador, igualdad.
+func comparar(a, b int, operador string) bool {
+	switch operador {
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	}
+	return a == b
+}
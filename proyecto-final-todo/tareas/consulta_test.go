@@ -0,0 +1,112 @@
+// Tests del lenguaje de consultas
+
+package tareas
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// crearTareasConsulta crea, con el reloj del gestor apuntando a cada fecha
+// de creación, las tareas usadas por los tests de consultas:
+//
+//	1 Informe anual de ventas  alta     #trabajo  creada 15/12/2025, vence 10/01/2026
+//	2 Borrador del informe     media    #trabajo  creada 05/01/2026
+//	3 Comprar pan              -        #casa     creada 01/02/2026, completada 02/02/2026
+//	4 Llamar al fontanero      urgente  #casa     creada 01/03/2026, vence 10/03/2026
+func crearTareasConsulta(t *testing.T) *GestorTareas {
+	t.Helper()
+	fecha := func(anio int, mes time.Month, dia int) time.Time {
+		return time.Date(anio, mes, dia, 10, 0, 0, 0, time.Local)
+	}
+	gestor, ahora := nuevoGestorConReloj(t, time.Time{})
+
+	creadas := []time.Time{fecha(2025, 12, 15), fecha(2026, 1, 5), fecha(2026, 2, 1), fecha(2026, 3, 1)}
+	crearTareas(t, gestor, []DatosTarea{
+		{Titulo: "Informe anual de ventas", Prioridad: PrioridadAlta, Etiquetas: []string{"trabajo"}, FechaVencimiento: fecha(2026, 1, 10)},
+		{Titulo: "Borrador del informe", Descripcion: "Versión para revisar", Prioridad: PrioridadMedia, Etiquetas: []string{"trabajo"}},
+		{Titulo: "Comprar pan", Etiquetas: []string{"casa"}},
+		{Titulo: "Llamar al fontanero", Prioridad: PrioridadUrgente, Etiquetas: []string{"casa"}, FechaVencimiento: fecha(2026, 3, 10)},
+	}, func(i int) { *ahora = creadas[i] })
+
+	*ahora = fecha(2026, 2, 2)
+	gestor.Completar(3)
+	*ahora = fecha(2026, 3, 2)
+	return gestor
+}
+
+// TestBuscarConConsulta verifica cada tipo de término y su combinación
+func TestBuscarConConsulta(t *testing.T) {
+	gestor := crearTareasConsulta(t)
+
+	tests := []struct {
+		consulta string
+		ids      []int
+	}{
+		{"", []int{1, 2, 3, 4}},
+		{"informe", []int{1, 2}},
+		{`"informe anual"`, []int{1}},
+		{"informe -borrador", []int{1}},
+		{"VERSIÓN", []int{2}},
+		{"estado:pendiente", []int{1, 2, 4}},
+		{"estado:completadas", []int{3}},
+		{"estado:vencida", []int{1}},
+		{"prioridad:alta", []int{1}},
+		{"prioridad:>=alta", []int{1, 4}},
+		{"prioridad:ninguna", []int{3}},
+		{"tag:CASA", []int{3, 4}},
+		{"-tag:trabajo", []int{3, 4}},
+		{"creada:>2026-01-01", []int{2, 3, 4}},
+		{"creada:2026-01-05", []int{2}},
+		{"vence:<2026-03-01", []int{1}},
+		{"completada:2026-02-02", []int{3}},
+		{`estado:pendiente prioridad:alta tag:trabajo "informe" -excluir creada:<2026-01-01`, []int{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.consulta, func(t *testing.T) {
+			resultados, err := gestor.Buscar(tt.consulta)
+			if err != nil {
+				t.Fatalf("Error inesperado: %v", err)
+			}
+			var ids []int
+			for _, tarea := range resultados {
+				ids = append(ids, tarea.ID)
+			}
+			if !slices.Equal(ids, tt.ids) {
+				t.Errorf("IDs esperados: %v, obtenidos: %v", tt.ids, ids)
+			}
+		})
+	}
+}
+
+// TestErroresConsulta verifica la posición y el mensaje de los errores de sintaxis
+func TestErroresConsulta(t *testing.T) {
+	tests := []struct {
+		consulta string
+		posicion int
+		mensaje  string
+	}{
+		{`"sin cerrar`, 1, "comillas sin cerrar"},
+		{"informe estado:hecha", 16, "estado desconocido"},
+		{"color:rojo", 1, "campo desconocido"},
+		{"creada:>2026-13-01", 9, "fecha inválida"},
+		{"prioridad:", 11, "falta el valor"},
+		{"prioridad:<=máxima", 13, "prioridad desconocida"},
+		{"tarea -", 7, "falta el término"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.consulta, func(t *testing.T) {
+			_, err := ParsearConsulta(tt.consulta)
+			var errConsulta *ErrorConsulta
+			if !errors.As(err, &errConsulta) {
+				t.Fatalf("Se esperaba ErrorConsulta, se obtuvo: %v", err)
+			}
+			if errConsulta.Posicion != tt.posicion || !strings.Contains(errConsulta.Mensaje, tt.mensaje) {
+				t.Errorf("Se esperaba '%s' en la posición %d, se obtuvo: %v", tt.mensaje, tt.posicion, err)
+			}
+		})
+	}
+}
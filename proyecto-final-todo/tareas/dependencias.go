@@ -0,0 +1,236 @@
+package tareas
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrorBloqueada indica que una tarea no puede completarse porque alguno de
+// sus requisitos sigue pendiente.
+//
+// Los llamadores pueden detectarlo con errors.As para, por ejemplo,
+// traducirlo a un 409 en la API HTTP.
+type ErrorBloqueada struct {
+	// ID es la tarea que se intentó completar
+	ID int
+
+	// Pendientes son los IDs de los requisitos aún sin completar
+	Pendientes []int
+}
+
+// Error implementa la interfaz error.
+func (e *ErrorBloqueada) Error() string {
+	ids := make([]string, len(e.Pendientes))
+	for i, id := range e.Pendientes {
+		ids[i] = strconv.Itoa(id)
+	}
+	return fmt.Sprintf("la tarea %d está bloqueada por tareas pendientes: %s", e.ID, strings.Join(ids, ", "))
+}
+
+// AgregarDependencia declara que la tarea id no puede completarse hasta que
+// se complete la tarea requisitoID.
+//
+// Ambas tareas deben existir fuera de la papelera. Se rechaza cualquier
+// dependencia que forme un ciclo (A depende de B y B, directa o
+// indirectamente, de A), ya que ninguna de las tareas podría completarse.
+// Declarar una dependencia existente no tiene efecto. La operación puede
+// deshacerse como cualquier edición.
+//
+// Parámetros:
+//   - id: la tarea que queda bloqueada
+//   - requisitoID: la tarea que debe completarse antes
+//
+// Retorna:
+//   - error: ErrorNoEncontrada si alguna tarea no existe, *ErrorPermiso si
+//     el usuario no puede modificar la tarea id, ErrorValidacion si la
+//     dependencia formaría un ciclo, o un error de escritura del diario
+//
+// Ejemplo:
+//
+//	// "Desplegar" (3) no puede completarse antes que "Pasar tests" (2)
+//	if err := gestor.AgregarDependencia(3, 2); err != nil {
+//		fmt.Println("Error:", err)
+//	}
+//
+func (g *GestorTareas) AgregarDependencia(id, requisitoID int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	i := g.indiceVisible(id)
+	if i < 0 {
+		return &ErrorNoEncontrada{ID: id}
+	}
+	if g.indiceVisible(requisitoID) < 0 {
+		return &ErrorNoEncontrada{ID: requisitoID}
+	}
+	if err := g.comprobarPermiso(i); err != nil {
+		return err
+	}
+	if slices.Contains(g.tareas[i].Dependencias, requisitoID) {
+		return nil
+	}
+	if requisitoID == id {
+		return &ErrorValidacion{"una tarea no puede depender de sí misma"}
+	}
+	if g.dependeDe(requisitoID, id) {
+		return &ErrorValidacion{fmt.Sprintf("la tarea %d ya depende de %d: la dependencia crearía un ciclo", requisitoID, id)}
+	}
+
+	actualizada := g.tareas[i].clonar()
+	actualizada.Dependencias = append(actualizada.Dependencias, requisitoID)
+	return g.reemplazarTarea(i, actualizada)
+}
+
+// QuitarDependencia elimina la dependencia de la tarea id sobre requisitoID.
+//
+// Retorna ErrorNoEncontrada si la tarea no existe, *ErrorPermiso si el
+// usuario no puede modificarla o ErrorValidacion si no tenía esa dependencia.
+//
+func (g *GestorTareas) QuitarDependencia(id, requisitoID int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	i := g.indiceVisible(id)
+	if i < 0 {
+		return &ErrorNoEncontrada{ID: id}
+	}
+	if err := g.comprobarPermiso(i); err != nil {
+		return err
+	}
+	posicion := slices.Index(g.tareas[i].Dependencias, requisitoID)
+	if posicion < 0 {
+		return &ErrorValidacion{fmt.Sprintf("la tarea %d no depende de %d", id, requisitoID)}
+	}
+
+	actualizada := g.tareas[i].clonar()
+	actualizada.Dependencias = slices.Delete(actualizada.Dependencias, posicion, posicion+1)
+	if len(actualizada.Dependencias) == 0 {
+		actualizada.Dependencias = nil
+	}
+	return g.reemplazarTarea(i, actualizada)
+}
+
+// RequisitosPendientes retorna los IDs de los requisitos de la tarea que aún
+// no se han completado, es decir, lo que impide completarla ahora.
+//
+// Los requisitos eliminados (en la papelera o purgados) no bloquean.
+//
+// Retorna:
+//   - []int: IDs pendientes, vacío si la tarea puede completarse
+//   - error: ErrorNoEncontrada si la tarea no existe
+//
+func (g *GestorTareas) RequisitosPendientes(id int) ([]int, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	i := g.indiceVisible(id)
+	if i < 0 {
+		return nil, &ErrorNoEncontrada{ID: id}
+	}
+	return g.requisitosPendientes(g.tareas[i]), nil
+}
+
+// ListarSiguientes responde a "¿en qué puedo trabajar ahora?".
+//
+// Retorna las tareas pendientes (las de ListarPendientes) en orden
+// topológico: primero todas las que pueden empezarse ya, luego las que
+// quedan desbloqueadas al completar esas, y así sucesivamente, de modo que
+// cada tarea aparece después de todos sus requisitos pendientes. Dentro de
+// cada tanda se conserva el orden de creación.
+//
+// Ejemplo:
+//
+//	siguientes := gestor.ListarSiguientes()
+//	if len(siguientes) > 0 {
+//		fmt.Println("Siguiente:", siguientes[0].Titulo)
+//	}
+//
+func (g *GestorTareas) ListarSiguientes() []Tarea {
+	pendientes := g.ListarPendientes()
+
+	// Solo bloquean los requisitos que también están pendientes
+	porOrdenar := make(map[int]bool, len(pendientes))
+	for _, tarea := range pendientes {
+		porOrdenar[tarea.ID] = true
+	}
+
+	ordenadas := make([]Tarea, 0, len(pendientes))
+	for len(ordenadas) < len(pendientes) {
+		// Tanda de tareas sin requisitos pendientes por ordenar
+		var tanda []Tarea
+		for _, tarea := range pendientes {
+			if porOrdenar[tarea.ID] && !slices.ContainsFunc(tarea.Dependencias, func(r int) bool { return porOrdenar[r] }) {
+				tanda = append(tanda, tarea)
+			}
+		}
+		for _, tarea := range tanda {
+			ordenadas = append(ordenadas, tarea)
+			delete(porOrdenar, tarea.ID)
+		}
+
+		// Un ciclo en datos antiguos: el resto se lista en orden de creación
+		if len(tanda) == 0 {
+			for _, tarea := range pendientes {
+				if porOrdenar[tarea.ID] {
+					ordenadas = append(ordenadas, tarea)
+				}
+			}
+			break
+		}
+	}
+	return ordenadas
+}
+
+// requisitosPendientes retorna los requisitos de la tarea que existen fuera
+// de la papelera y no están completados. Debe llamarse con g.mu tomado.
+func (g *GestorTareas) requisitosPendientes(tarea Tarea) []int {
+	var pendientes []int
+	for _, requisito := range tarea.Dependencias {
+		if i := g.indiceVisible(requisito); i >= 0 && !g.tareas[i].Completada {
+			pendientes = append(pendientes, requisito)
+		}
+	}
+	return pendientes
+}
+
+// dependeDe indica si la tarea id depende, directa o indirectamente, de
+// requisitoID. Debe llamarse con g.mu tomado.
+func (g *GestorTareas) dependeDe(id, requisitoID int) bool {
+	visitadas := make(map[int]bool)
+	porVisitar := []int{id}
+
+	for len(porVisitar) > 0 {
+		actual := porVisitar[len(porVisitar)-1]
+		porVisitar = porVisitar[:len(porVisitar)-1]
+		if actual == requisitoID {
+			return true
+		}
+		if visitadas[actual] {
+			continue
+		}
+		visitadas[actual] = true
+
+		if i := g.indicePorID(actual); i >= 0 {
+			porVisitar = append(porVisitar, g.tareas[i].Dependencias...)
+		}
+	}
+	return false
+}
+
+// reemplazarTarea sustituye la tarea en la posición i por actualizada como
+// una edición: registra FechaActualizacion, escribe el diario, guarda la
+// operación en el historial y persiste. Debe llamarse con g.mu tomado en
+// modo escritura.
+func (g *GestorTareas) reemplazarTarea(i int, actualizada Tarea) error {
+	actualizada.FechaActualizacion = g.reloj()
+	if err := g.escribirDiario(entradaRegistro{Op: opGuardar, Tarea: &actualizada}); err != nil {
+		return err
+	}
+
+	g.registrarOperacion(OperacionEditar, &g.tareas[i], &actualizada, i)
+	g.sustituirTarea(i, actualizada)
+	g.registrarGuardado(actualizada)
+	return nil
+}
@@ -0,0 +1,129 @@
+// Tests de dependencias entre tareas
+
+package tareas
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// TestCompletarRespetaDependencias verifica que los requisitos pendientes bloqueen
+func TestCompletarRespetaDependencias(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t)
+	gestor.Crear("Escribir código")
+	gestor.Crear("Pasar tests")
+	gestor.Crear("Desplegar")
+
+	gestor.AgregarDependencia(3, 1)
+	gestor.AgregarDependencia(3, 2)
+
+	var bloqueada *ErrorBloqueada
+	if err := gestor.Completar(3); !errors.As(err, &bloqueada) {
+		t.Fatalf("Se esperaba ErrorBloqueada, se obtuvo: %v", err)
+	}
+	if !slices.Equal(bloqueada.Pendientes, []int{1, 2}) {
+		t.Errorf("Requisitos pendientes esperados [1 2], obtenidos: %v", bloqueada.Pendientes)
+	}
+
+	// Actualizar tampoco puede saltarse las dependencias
+	si := true
+	if _, err := gestor.Actualizar(3, CambiosTarea{Completada: &si}); !errors.As(err, &bloqueada) {
+		t.Errorf("Actualizar debería respetar las dependencias, se obtuvo: %v", err)
+	}
+
+	gestor.Completar(1)
+	if pendientes, _ := gestor.RequisitosPendientes(3); !slices.Equal(pendientes, []int{2}) {
+		t.Errorf("Solo debería quedar pendiente la tarea 2, quedan: %v", pendientes)
+	}
+
+	// Un requisito eliminado deja de bloquear
+	gestor.Eliminar(2)
+	if err := gestor.Completar(3); err != nil {
+		t.Errorf("Con los requisitos completados o eliminados debería completarse: %v", err)
+	}
+}
+
+// TestAgregarDependenciaValidaciones verifica existencia y detección de ciclos
+func TestAgregarDependenciaValidaciones(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t)
+	for _, titulo := range []string{"Tarea A", "Tarea B", "Tarea C"} {
+		gestor.Crear(titulo)
+	}
+	// A depende de B y B de C
+	gestor.AgregarDependencia(1, 2)
+	gestor.AgregarDependencia(2, 3)
+
+	tests := []struct {
+		nombre      string
+		id          int
+		requisitoID int
+		errEsperado any
+	}{
+		{"ciclo directo", 2, 1, &ErrorValidacion{}},
+		{"ciclo indirecto", 3, 1, &ErrorValidacion{}},
+		{"a sí misma", 1, 1, &ErrorValidacion{}},
+		{"tarea inexistente", 999, 1, &ErrorNoEncontrada{}},
+		{"requisito inexistente", 1, 999, &ErrorNoEncontrada{}},
+		{"repetida", 1, 2, nil},
+		{"válida", 1, 3, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			err := gestor.AgregarDependencia(tt.id, tt.requisitoID)
+			switch esperado := tt.errEsperado.(type) {
+			case nil:
+				if err != nil {
+					t.Errorf("Error inesperado: %v", err)
+				}
+			case *ErrorValidacion:
+				if !errors.As(err, &esperado) {
+					t.Errorf("Se esperaba ErrorValidacion, se obtuvo: %v", err)
+				}
+			case *ErrorNoEncontrada:
+				if !errors.As(err, &esperado) {
+					t.Errorf("Se esperaba ErrorNoEncontrada, se obtuvo: %v", err)
+				}
+			}
+		})
+	}
+
+	tarea, _ := gestor.BuscarPorID(1)
+	if !slices.Equal(tarea.Dependencias, []int{2, 3}) {
+		t.Errorf("Dependencias esperadas [2 3], obtenidas: %v", tarea.Dependencias)
+	}
+
+	// Quitar una dependencia y deshacerlo
+	if err := gestor.QuitarDependencia(1, 2); err != nil {
+		t.Fatalf("Error al quitar dependencia: %v", err)
+	}
+	if err := gestor.QuitarDependencia(1, 2); err == nil {
+		t.Error("Quitar una dependencia inexistente debería fallar")
+	}
+	gestor.Deshacer()
+	if tarea, _ := gestor.BuscarPorID(1); !slices.Equal(tarea.Dependencias, []int{2, 3}) {
+		t.Errorf("Deshacer debería restaurar la dependencia, obtenidas: %v", tarea.Dependencias)
+	}
+}
+
+// TestListarSiguientes verifica el orden topológico de las tareas pendientes
+func TestListarSiguientes(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t)
+	for _, titulo := range []string{"Desplegar", "Escribir código", "Pasar tests", "Documentar", "Ya hecha"} {
+		gestor.Crear(titulo)
+	}
+	gestor.AgregarDependencia(1, 3) // Desplegar tras pasar tests
+	gestor.AgregarDependencia(3, 2) // Pasar tests tras escribir código
+	gestor.AgregarDependencia(4, 5) // Documentar tras una tarea ya completada
+	gestor.Completar(5)
+
+	var ids []int
+	for _, tarea := range gestor.ListarSiguientes() {
+		ids = append(ids, tarea.ID)
+	}
+
+	// 2 y 4 están disponibles (en orden de creación), luego 3 y por último 1
+	if esperados := []int{2, 4, 3, 1}; !slices.Equal(ids, esperados) {
+		t.Errorf("Orden esperado: %v, obtenido: %v", esperados, ids)
+	}
+}
@@ -0,0 +1,98 @@
+package tareas
+
+import (
+	"fmt"
+	"os"
+)
+
+// ConDiario activa un diario de escritura anticipada (write-ahead log) en rutaDiario.
+//
+// Con el diario activo, cada Crear, Completar y Eliminar (y cada cambio de
+// las listas) se añade al diario y se sincroniza a disco antes de aplicarse
+// en memoria, de modo que un cierre abrupto (kill -9, corte de luz) entre
+// dos guardados no pierde trabajo.
+// NuevoGestorTareas reproduce el diario sobre la última instantánea cargada,
+// y cada Guardar exitoso lo vacía porque su contenido ya quedó en ella.
+//
+// Ejemplo:
+//
+//	gestor, err := NuevoGestorTareas("tareas.json", ConDiario("tareas.json.diario"))
+//
+func ConDiario(rutaDiario string) Opcion {
+	return func(g *GestorTareas) {
+		g.diario = rutaDiario
+	}
+}
+
+// reproducirDiario aplica sobre las tareas cargadas las operaciones del diario.
+//
+// Si el diario no existe o está vacío no hace nada. Una última línea
+// incompleta se recorta del diario antes de reproducirlo. Las operaciones
+// aplicadas quedan como cambios pendientes hasta el siguiente Guardar.
+// Reproducir el mismo diario dos veces es inofensivo: cada entrada contiene
+// el estado completo de la tarea, no un incremento.
+func (g *GestorTareas) reproducirDiario() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	datos, err := os.ReadFile(g.diario)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error al leer diario: %v", err)
+	}
+	datos, err = descartarLineaIncompleta(g.diario, datos)
+	if err != nil {
+		return err
+	}
+
+	r, err := aplicarEntradas(g.tareas, datos)
+	if err != nil {
+		return fmt.Errorf("error al reproducir diario %s: %v", g.diario, err)
+	}
+	if r.aplicadas == 0 {
+		return nil
+	}
+
+	g.tareas = r.tareas
+	g.reconstruirIndice()
+	if r.hayListas {
+		g.listas = r.listas
+	}
+	if r.maxID >= g.proximoID {
+		g.proximoID = r.maxID + 1
+	}
+	g.cambiosPendientes = true
+
+	fmt.Fprintf(g.salida, "✓ Reproducidas %d operación(es) del diario %s\n", r.aplicadas, g.diario)
+	return nil
+}
+
+// escribirDiario añade una entrada al diario, si está activo.
+//
+// Debe llamarse antes de aplicar el cambio en memoria: si falla, la
+// operación debe abortarse para que memoria y diario no diverjan.
+func (g *GestorTareas) escribirDiario(entrada entradaRegistro) error {
+	if g.diario == "" {
+		return nil
+	}
+	if err := anadirEntrada(g.diario, entrada); err != nil {
+		return fmt.Errorf("error al escribir diario: %v", err)
+	}
+	return nil
+}
+
+// vaciarDiario descarta las entradas del diario tras un guardado exitoso.
+//
+// Si falla no es grave: las entradas se reproducirían de nuevo sobre una
+// instantánea que ya las contiene, con el mismo resultado.
+func (g *GestorTareas) vaciarDiario() error {
+	if g.diario == "" {
+		return nil
+	}
+	if err := os.Truncate(g.diario, 0); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error al vaciar diario: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package tareas
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formato es un formato de texto al que pueden exportarse las tareas y desde
+// el que pueden importarse con GestorTareas.Importar.
+type Formato string
+
+// Formatos de importación y exportación disponibles.
+const (
+	// FormatoCSV es una tabla con una columna por campo y cabecera
+	// (ver columnasCSV). Conserva todos los campos salvo la recurrencia y
+	// las dependencias.
+	FormatoCSV Formato = "csv"
+
+	// FormatoMarkdown es una lista de casillas ("- [x] título #etiqueta")
+	// con las subtareas sangradas bajo su tarea padre. Solo conserva el
+	// título, el estado, las etiquetas y la jerarquía.
+	FormatoMarkdown Formato = "markdown"
+
+	// FormatoTodoTxt es el formato de todo.txt (http://todotxt.org): una
+	// tarea por línea con prioridad (A)-(D), fechas, +etiquetas y due:. No
+	// conserva la descripción ni las subtareas, y las fechas solo con el día.
+	FormatoTodoTxt Formato = "todotxt"
+
+	// FormatoICalendar es un calendario iCalendar (RFC 5545, .ics) con un
+	// VTODO por tarea, que pueden leer las aplicaciones de calendario.
+	// Conserva todos los campos salvo la recurrencia y las dependencias,
+	// incluido el UID, de modo que una tarea exportada e importada de nuevo
+	// se reconoce como la misma.
+	FormatoICalendar Formato = "ical"
+)
+
+// columnasCSV son las columnas que escribe Exportar en FormatoCSV, en orden.
+// Importar solo exige titulo y admite las columnas en cualquier orden.
+var columnasCSV = []string{
+	"id", "uid", "titulo", "descripcion", "completada", "prioridad", "etiquetas",
+	"fecha_creacion", "fecha_vencimiento", "fecha_completada", "padre_id",
+}
+
+// letrasPrioridad asocia cada prioridad con su letra en todo.txt.
+var letrasPrioridad = map[Prioridad]string{
+	PrioridadUrgente: "A",
+	PrioridadAlta:    "B",
+	PrioridadMedia:   "C",
+	PrioridadBaja:    "D",
+}
+
+// ParsearFormato interpreta el nombre de un formato ("csv", "markdown" o
+// "md", "todotxt" o "todo.txt", "ical", "icalendar" o "ics") o el nombre de
+// un archivo con extensión .csv, .md, .txt o .ics.
+//
+// Retorna:
+//   - Formato: el formato reconocido
+//   - error: ErrorValidacion si no se reconoce
+//
+// Ejemplo:
+//
+//	formato, err := ParsearFormato("tareas.md") // FormatoMarkdown
+//
+func ParsearFormato(texto string) (Formato, error) {
+	nombre := strings.ToLower(strings.TrimSpace(texto))
+	switch nombre {
+	case "csv":
+		return FormatoCSV, nil
+	case "markdown", "md":
+		return FormatoMarkdown, nil
+	case "todotxt", "todo.txt":
+		return FormatoTodoTxt, nil
+	case "ical", "icalendar", "ics":
+		return FormatoICalendar, nil
+	}
+	switch filepath.Ext(nombre) {
+	case ".csv":
+		return FormatoCSV, nil
+	case ".md", ".markdown":
+		return FormatoMarkdown, nil
+	case ".txt":
+		return FormatoTodoTxt, nil
+	case ".ics", ".ical":
+		return FormatoICalendar, nil
+	}
+	return "", errorFormato(texto)
+}
+
+// Extension retorna la extensión de archivo habitual del formato.
+func (f Formato) Extension() string {
+	switch f {
+	case FormatoMarkdown:
+		return ".md"
+	case FormatoTodoTxt:
+		return ".txt"
+	case FormatoICalendar:
+		return ".ics"
+	}
+	return "." + string(f)
+}
+
+// Exportar escribe las tareas de lista en w con el formato indicado.
+//
+// Las fechas se escriben en RFC 3339 en CSV, como aaaa-mm-dd (hora local)
+// en todo.txt y en UTC en iCalendar. En Markdown las subtareas se anidan
+// bajo su tarea padre si esta también está en la lista. Todo lo exportado
+// puede volver a leerse con GestorTareas.Importar.
+//
+// Parámetros:
+//   - w: destino (un archivo, la respuesta HTTP, ...)
+//   - lista: las tareas a exportar, en el orden en que se escribirán
+//   - formato: FormatoCSV, FormatoMarkdown, FormatoTodoTxt o FormatoICalendar
+//
+// Retorna:
+//   - error: error de escritura, o ErrorValidacion si el formato no existe
+//
+// Ejemplo:
+//
+//	archivo, _ := os.Create("tareas.csv")
+//	defer archivo.Close()
+//	err := Exportar(archivo, gestor.Listar(), FormatoCSV)
+//
+func Exportar(w io.Writer, lista []Tarea, formato Formato) error {
+	switch formato {
+	case FormatoCSV:
+		return exportarCSV(w, lista)
+	case FormatoMarkdown:
+		return exportarMarkdown(w, lista)
+	case FormatoTodoTxt:
+		return exportarTodoTxt(w, lista)
+	case FormatoICalendar:
+		return exportarICalendar(w, lista)
+	}
+	return errorFormato(string(formato))
+}
+
+// errorFormato es el error de un formato desconocido.
+func errorFormato(formato string) error {
+	return &ErrorValidacion{fmt.Sprintf("formato desconocido %q: usa csv, markdown, todotxt o ical", formato)}
+}
+
+// exportarCSV escribe la cabecera columnasCSV y una fila por tarea.
+func exportarCSV(w io.Writer, lista []Tarea) error {
+	escritor := csv.NewWriter(w)
+	escritor.Write(columnasCSV)
+	for _, t := range lista {
+		padre := ""
+		if t.PadreID != 0 {
+			padre = strconv.Itoa(t.PadreID)
+		}
+		escritor.Write([]string{
+			strconv.Itoa(t.ID),
+			t.UID,
+			t.Titulo,
+			t.Descripcion,
+			strconv.FormatBool(t.Completada),
+			string(t.Prioridad),
+			strings.Join(t.Etiquetas, ","),
+			formatearFechaCSV(t.FechaCreacion),
+			formatearFechaCSV(t.FechaVencimiento),
+			formatearFechaCSV(t.FechaCompletada),
+			padre,
+		})
+	}
+	escritor.Flush()
+	if err := escritor.Error(); err != nil {
+		return fmt.Errorf("error al exportar CSV: %v", err)
+	}
+	return nil
+}
+
+// exportarMarkdown escribe un encabezado y la lista de casillas, con las
+// subtareas sangradas dos espacios por nivel.
+func exportarMarkdown(w io.Writer, lista []Tarea) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintln(b, "# Tareas")
+	fmt.Fprintln(b)
+
+	enLista := make(map[int]bool)
+	for _, t := range lista {
+		enLista[t.ID] = true
+	}
+	subtareas := make(map[int][]Tarea)
+	var raices []Tarea
+	for _, t := range lista {
+		if t.PadreID != 0 && enLista[t.PadreID] {
+			subtareas[t.PadreID] = append(subtareas[t.PadreID], t)
+		} else {
+			raices = append(raices, t)
+		}
+	}
+
+	var escribir func(t Tarea, nivel int)
+	escribir = func(t Tarea, nivel int) {
+		casilla := " "
+		if t.Completada {
+			casilla = "x"
+		}
+		linea := fmt.Sprintf("%s- [%s] %s", strings.Repeat("  ", nivel), casilla, t.Titulo)
+		for _, etiqueta := range t.Etiquetas {
+			linea += " #" + etiqueta
+		}
+		fmt.Fprintln(b, linea)
+		for _, sub := range subtareas[t.ID] {
+			escribir(sub, nivel+1)
+		}
+	}
+	for _, t := range raices {
+		escribir(t, 0)
+	}
+
+	if err := b.Flush(); err != nil {
+		return fmt.Errorf("error al exportar Markdown: %v", err)
+	}
+	return nil
+}
+
+// exportarTodoTxt escribe una línea de todo.txt por tarea. Las tareas
+// completadas llevan la prioridad como pri:, como recomienda el formato.
+func exportarTodoTxt(w io.Writer, lista []Tarea) error {
+	b := bufio.NewWriter(w)
+	for _, t := range lista {
+		var partes []string
+		letra, conPrioridad := letrasPrioridad[t.Prioridad]
+		if t.Completada {
+			partes = append(partes, "x")
+			if !t.FechaCompletada.IsZero() {
+				partes = append(partes, formatearFechaTodoTxt(t.FechaCompletada))
+			}
+		} else if conPrioridad {
+			partes = append(partes, "("+letra+")")
+		}
+		if !t.FechaCreacion.IsZero() {
+			partes = append(partes, formatearFechaTodoTxt(t.FechaCreacion))
+		}
+		partes = append(partes, strings.Fields(t.Titulo)...)
+		for _, etiqueta := range t.Etiquetas {
+			partes = append(partes, "+"+etiqueta)
+		}
+		if !t.FechaVencimiento.IsZero() {
+			partes = append(partes, "due:"+formatearFechaTodoTxt(t.FechaVencimiento))
+		}
+		if t.Completada && conPrioridad {
+			partes = append(partes, "pri:"+letra)
+		}
+		fmt.Fprintln(b, strings.Join(partes, " "))
+	}
+
+	if err := b.Flush(); err != nil {
+		return fmt.Errorf("error al exportar todo.txt: %v", err)
+	}
+	return nil
+}
+
+// formatearFechaCSV escribe una fecha en RFC 3339, o "" si no hay fecha.
+func formatearFechaCSV(fecha time.Time) string {
+	if fecha.IsZero() {
+		return ""
+	}
+	return fecha.Format(time.RFC3339)
+}
+
+// formatearFechaTodoTxt escribe el día de una fecha en la hora local.
+func formatearFechaTodoTxt(fecha time.Time) string {
+	return fecha.In(time.Local).Format(time.DateOnly)
+}
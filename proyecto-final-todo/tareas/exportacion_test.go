@@ -0,0 +1,109 @@
+// Tests de exportación de tareas
+
+package tareas
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// crearTareasExportacion crea un gestor con una tarea completada con
+// prioridad, una con vencimiento y etiquetas, y una subtarea
+func crearTareasExportacion(t *testing.T) *GestorTareas {
+	t.Helper()
+	gestor, ahora := nuevoGestorConReloj(t, time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local))
+
+	datos := []DatosTarea{
+		{Titulo: "Declarar impuestos", Prioridad: PrioridadUrgente},
+		{Titulo: "Preparar viaje", Descripcion: "Vuelos, hotel", Etiquetas: []string{"ocio", "familia"}, FechaVencimiento: ahora.AddDate(0, 0, 10)},
+		{Titulo: "Reservar hotel", PadreID: 2},
+	}
+	crearTareas(t, gestor, datos, nil)
+	*ahora = ahora.AddDate(0, 0, 1)
+	gestor.Completar(1)
+	return gestor
+}
+
+// TestExportar verifica el contenido de cada formato
+func TestExportar(t *testing.T) {
+	gestor := crearTareasExportacion(t)
+
+	tests := []struct {
+		formato   Formato
+		esperados []string
+	}{
+		{FormatoCSV, []string{
+			"id,uid,titulo,descripcion,completada,prioridad,etiquetas,fecha_creacion,fecha_vencimiento,fecha_completada,padre_id",
+			"1,,Declarar impuestos,,true,urgente,,",
+			`2,,Preparar viaje,"Vuelos, hotel",false,,"ocio,familia",`,
+			"3,,Reservar hotel,,false,,,",
+		}},
+		{FormatoMarkdown, []string{
+			"# Tareas",
+			"- [x] Declarar impuestos\n",
+			"- [ ] Preparar viaje #ocio #familia\n  - [ ] Reservar hotel\n",
+		}},
+		{FormatoTodoTxt, []string{
+			"x 2026-03-03 2026-03-02 Declarar impuestos pri:A\n",
+			"2026-03-02 Preparar viaje +ocio +familia due:2026-03-12\n",
+			"2026-03-02 Reservar hotel\n",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.formato), func(t *testing.T) {
+			var salida bytes.Buffer
+			if err := Exportar(&salida, gestor.Listar(), tt.formato); err != nil {
+				t.Fatalf("Error inesperado: %v", err)
+			}
+			for _, esperado := range tt.esperados {
+				if !strings.Contains(salida.String(), esperado) {
+					t.Errorf("Se esperaba %q en:\n%s", esperado, salida.String())
+				}
+			}
+		})
+	}
+
+	// Una tarea pendiente con prioridad la lleva al principio
+	gestor.Deshacer()
+	var salida bytes.Buffer
+	Exportar(&salida, gestor.Listar(), FormatoTodoTxt)
+	if !strings.HasPrefix(salida.String(), "(A) 2026-03-02 Declarar impuestos\n") {
+		t.Errorf("Se esperaba la prioridad (A) al principio, se obtuvo:\n%s", salida.String())
+	}
+
+	var errValidacion *ErrorValidacion
+	if err := Exportar(&salida, nil, "pdf"); !errors.As(err, &errValidacion) {
+		t.Errorf("Un formato desconocido debería dar ErrorValidacion, se obtuvo: %v", err)
+	}
+}
+
+// TestParsearFormato verifica los nombres y extensiones admitidos
+func TestParsearFormato(t *testing.T) {
+	tests := []struct {
+		texto    string
+		esperado Formato
+		valido   bool
+	}{
+		{"csv", FormatoCSV, true},
+		{" Markdown ", FormatoMarkdown, true},
+		{"md", FormatoMarkdown, true},
+		{"todo.txt", FormatoTodoTxt, true},
+		{"copia/tareas.CSV", FormatoCSV, true},
+		{"notas.md", FormatoMarkdown, true},
+		{"lista.txt", FormatoTodoTxt, true},
+		{"tareas.json", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		formato, err := ParsearFormato(tt.texto)
+		if (err == nil) != tt.valido || formato != tt.esperado {
+			t.Errorf("ParsearFormato(%q) = %q, %v; se esperaba %q (válido: %v)", tt.texto, formato, err, tt.esperado, tt.valido)
+		}
+	}
+	if FormatoMarkdown.Extension() != ".md" || FormatoCSV.Extension() != ".csv" || FormatoTodoTxt.Extension() != ".txt" {
+		t.Error("Extension() no retorna las extensiones habituales")
+	}
+}
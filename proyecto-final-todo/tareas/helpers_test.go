@@ -0,0 +1,53 @@
+// Utilidades compartidas por los tests del paquete
+
+package tareas
+
+import (
+	"testing"
+	"time"
+)
+
+// nuevoGestorEnMemoria crea un gestor sin archivo para tests que no prueban persistencia
+func nuevoGestorEnMemoria(t testing.TB, opciones ...Opcion) *GestorTareas {
+	t.Helper()
+	opciones = append([]Opcion{ConAlmacenamiento(NuevoAlmacenamientoMemoria())}, opciones...)
+	gestor, err := NuevoGestorTareas("", opciones...)
+	if err != nil {
+		t.Fatalf("Error al crear gestor: %v", err)
+	}
+	return gestor
+}
+
+// nuevoGestorConReloj crea un gestor en memoria cuyo reloj marca la hora
+// del puntero retornado, que empieza en inicio; el test mueve el reloj
+// asignándole otra hora.
+func nuevoGestorConReloj(t testing.TB, inicio time.Time, opciones ...Opcion) (*GestorTareas, *time.Time) {
+	t.Helper()
+	ahora := &inicio
+	opciones = append([]Opcion{ConReloj(func() time.Time { return *ahora })}, opciones...)
+	return nuevoGestorEnMemoria(t, opciones...), ahora
+}
+
+// crearTareas crea en el gestor las tareas de datos, en orden, y detiene el
+// test si alguna falla. antes, si no es nil, se llama antes de crear cada
+// una con su posición en datos; sirve para mover el reloj del gestor.
+func crearTareas(t testing.TB, gestor *GestorTareas, datos []DatosTarea, antes func(i int)) {
+	t.Helper()
+	for i, d := range datos {
+		if antes != nil {
+			antes(i)
+		}
+		if _, err := gestor.CrearConDatos(d); err != nil {
+			t.Fatalf("Error al crear %q: %v", d.Titulo, err)
+		}
+	}
+}
+
+// idsDe retorna los IDs de las tareas, en orden
+func idsDe(lista []Tarea) []int {
+	var ids []int
+	for _, tarea := range lista {
+		ids = append(ids, tarea.ID)
+	}
+	return ids
+}
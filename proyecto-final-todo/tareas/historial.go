@@ -0,0 +1,499 @@
+package tareas
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// HistorialPorDefecto es la cantidad de operaciones que pueden deshacerse
+// si no se indica otra con ConHistorial.
+const HistorialPorDefecto = 20
+
+// ErrNadaQueDeshacer se retorna al llamar a Deshacer con el historial vacío.
+var ErrNadaQueDeshacer = errors.New("no hay operaciones para deshacer")
+
+// ErrNadaQueRehacer se retorna al llamar a Rehacer sin operaciones deshechas.
+var ErrNadaQueRehacer = errors.New("no hay operaciones para rehacer")
+
+// ErrorConflictoHistorial indica que una operación no puede deshacerse ni
+// rehacerse porque una de sus tareas cambió después (por ejemplo, otro
+// usuario la editó): aplicarla descartaría ese cambio posterior.
+type ErrorConflictoHistorial struct {
+	// ID es la tarea que cambió.
+	ID int
+}
+
+// Error implementa la interfaz error.
+func (e *ErrorConflictoHistorial) Error() string {
+	return fmt.Sprintf("la tarea %d cambió después de la operación; deshaz antes los cambios posteriores", e.ID)
+}
+
+// TipoOperacion identifica la clase de mutación registrada en el historial.
+type TipoOperacion string
+
+// Tipos de operación que pueden deshacerse.
+const (
+	OperacionCrear     TipoOperacion = "crear"
+	OperacionCompletar TipoOperacion = "completar"
+	OperacionEditar    TipoOperacion = "editar"
+	OperacionEliminar  TipoOperacion = "eliminar"
+	OperacionRestaurar TipoOperacion = "restaurar"
+	OperacionImportar  TipoOperacion = "importar"
+	OperacionMover     TipoOperacion = "mover"
+
+	OperacionCrearLista     TipoOperacion = "crear lista"
+	OperacionRenombrarLista TipoOperacion = "renombrar lista"
+	OperacionEliminarLista  TipoOperacion = "eliminar lista"
+)
+
+// Operacion es una mutación registrada en el historial de deshacer/rehacer.
+//
+// Guarda una copia completa de cada tarea afectada antes y después del
+// cambio, de modo que deshacer restaura exactamente el estado anterior
+// (incluidas fechas y etiquetas) en lugar de aplicar una operación inversa
+// aproximada. Una misma operación puede afectar a varias tareas, por
+// ejemplo al eliminar una tarea junto con sus subtareas o al completar una
+// tarea recurrente, que crea su siguiente ocurrencia.
+type Operacion struct {
+	// Tipo es la clase de mutación (crear, completar, editar, eliminar)
+	Tipo TipoOperacion
+
+	// TareaID es el ID de la tarea sobre la que se pidió la operación (0 en
+	// las operaciones sobre listas)
+	TareaID int
+
+	// Lista es la lista sobre la que se pidió una operación de listas, o la
+	// lista de destino al mover una tarea
+	Lista string
+
+	// cambios son los cambios de cada tarea afectada, en el orden en que se
+	// aplicaron; el primero corresponde a TareaID
+	cambios []cambioTarea
+
+	// listas es el cambio de los nombres de las listas; nil si la operación
+	// no los modificó
+	listas *cambioListas
+
+	// usuario es el usuario del gestor que hizo la operación; solo él puede
+	// deshacerla o rehacerla
+	usuario string
+}
+
+// cambioListas son los nombres de las listas antes y después de una
+// Operacion.
+type cambioListas struct {
+	antes, despues []string
+}
+
+// cambioTarea es el cambio de una tarea dentro de una Operacion.
+type cambioTarea struct {
+	// antes es la tarea previa al cambio (nil al crear)
+	antes *Tarea
+
+	// despues es la tarea tras el cambio; al eliminar, la tarea ya en la papelera
+	despues *Tarea
+
+	// posicion es el índice que ocupaba (o pasó a ocupar) la tarea en la
+	// colección, para restaurarla en el mismo lugar
+	posicion int
+}
+
+// nuevoCambio crea un cambioTarea con copias de antes y despues, que pueden
+// ser nil.
+func nuevoCambio(antes, despues *Tarea, posicion int) cambioTarea {
+	cambio := cambioTarea{posicion: posicion}
+	if antes != nil {
+		copia := antes.clonar()
+		cambio.antes = &copia
+	}
+	if despues != nil {
+		copia := despues.clonar()
+		cambio.despues = &copia
+	}
+	return cambio
+}
+
+// id retorna el ID de la tarea afectada por el cambio.
+func (c cambioTarea) id() int {
+	if c.despues != nil {
+		return c.despues.ID
+	}
+	return c.antes.ID
+}
+
+// String describe la operación para mostrarla al usuario.
+func (o Operacion) String() string {
+	titulo := ""
+	if len(o.cambios) > 0 {
+		if c := o.cambios[0]; c.despues != nil {
+			titulo = c.despues.Titulo
+		} else {
+			titulo = c.antes.Titulo
+		}
+	}
+	switch o.Tipo {
+	case OperacionImportar:
+		return fmt.Sprintf("importar %d tarea(s)", len(o.cambios))
+	case OperacionCrearLista, OperacionRenombrarLista, OperacionEliminarLista:
+		descripcion := fmt.Sprintf("%s '%s'", o.Tipo, o.Lista)
+		if len(o.cambios) > 0 {
+			descripcion += fmt.Sprintf(" y %d tarea(s)", len(o.cambios))
+		}
+		return descripcion
+	}
+	descripcion := fmt.Sprintf("%s tarea %d '%s'", o.Tipo, o.TareaID, titulo)
+	switch {
+	case len(o.cambios) > 1 && o.Tipo == OperacionCompletar:
+		descripcion += " y su siguiente ocurrencia"
+	case len(o.cambios) > 1:
+		descripcion += fmt.Sprintf(" y %d subtarea(s)", len(o.cambios)-1)
+	}
+	return descripcion
+}
+
+// ConHistorial cambia la cantidad máxima de operaciones que pueden deshacerse.
+//
+// Con 0 el historial queda desactivado.
+//
+// Ejemplo:
+//
+//	gestor, err := NuevoGestorTareas("tareas.json", ConHistorial(100))
+//
+func ConHistorial(limite int) Opcion {
+	return func(g *GestorTareas) {
+		g.limiteHistorial = limite
+	}
+}
+
+// Deshacer revierte la última operación registrada (crear, completar,
+// editar, eliminar o restaurar) y la deja disponible para Rehacer.
+//
+// Cada usuario tiene su propio historial (ver ComoUsuario): Deshacer solo
+// revierte operaciones hechas por el usuario del gestor, y no las revierte
+// si otro cambio posterior modificó alguna de sus tareas.
+//
+// La tarea vuelve exactamente al estado previo: deshacer una eliminación la
+// saca de la papelera con sus fechas originales, y deshacer una creación
+// la elimina. Los IDs nunca se reutilizan: tras deshacer una creación, la
+// siguiente tarea creada recibe un ID nuevo, no el de la tarea deshecha.
+// Las operaciones sobre listas (crear, renombrar, eliminar y mover tareas
+// entre listas) también se deshacen, junto con las tareas que modificaron.
+//
+// El cambio se persiste igual que cualquier otra mutación, y queda en la
+// auditoría como eventos "deshacer". Se aplica entero o no se aplica: si
+// alguna tarea no puede revertirse, no se revierte ninguna y la operación
+// sigue en el historial.
+//
+// Retorna:
+//   - Operacion: la operación deshecha
+//   - error: ErrNadaQueDeshacer si el historial del usuario está vacío,
+//     ErrTieneSubtareas si deshacer una creación dejaría subtareas
+//     huérfanas, ErrorNoEncontrada si una de las tareas ya no existe,
+//     *ErrorConflictoHistorial si una cambió después de la operación, o un
+//     error de escritura del diario
+//
+// Ejemplo:
+//
+//	if op, err := gestor.Deshacer(); err == nil {
+//		fmt.Println("Deshecho:", op)
+//	}
+//
+func (g *GestorTareas) Deshacer() (Operacion, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	i := g.ultimaOperacion(g.deshacer)
+	if i < 0 {
+		return Operacion{}, ErrNadaQueDeshacer
+	}
+
+	op := g.deshacer[i]
+	if aplicados, err := g.aplicarOperacion(op, true); err != nil {
+		// Los cambios que no pudieron revertirse quedan en la auditoría
+		g.auditar(OperacionDeshacer, Operacion{cambios: op.cambios[len(op.cambios)-aplicados:]}, true)
+		return Operacion{}, err
+	}
+	g.auditar(OperacionDeshacer, op, true)
+
+	g.deshacer = slices.Delete(g.deshacer, i, i+1)
+	g.rehacer = append(g.rehacer, op)
+	return op, nil
+}
+
+// Rehacer vuelve a aplicar la última operación deshecha por el usuario del
+// gestor.
+//
+// Cualquier mutación nueva (Crear, Completar, Actualizar, Eliminar,
+// Restaurar) descarta las operaciones del mismo usuario pendientes de
+// rehacer.
+//
+// Igual que Deshacer, se aplica entero o no se aplica.
+//
+// Retorna:
+//   - Operacion: la operación rehecha
+//   - error: ErrNadaQueRehacer si el usuario no tiene operaciones deshechas,
+//     ErrorNoEncontrada si una de las tareas ya no existe,
+//     *ErrorConflictoHistorial si una cambió después de deshacerla, o un
+//     error de escritura del diario
+//
+func (g *GestorTareas) Rehacer() (Operacion, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	i := g.ultimaOperacion(g.rehacer)
+	if i < 0 {
+		return Operacion{}, ErrNadaQueRehacer
+	}
+
+	op := g.rehacer[i]
+	if aplicados, err := g.aplicarOperacion(op, false); err != nil {
+		g.auditar(OperacionRehacer, Operacion{cambios: op.cambios[:aplicados]}, false)
+		return Operacion{}, err
+	}
+	g.auditar(OperacionRehacer, op, false)
+
+	g.rehacer = slices.Delete(g.rehacer, i, i+1)
+	g.deshacer = append(g.deshacer, op)
+	return op, nil
+}
+
+// ultimaOperacion retorna la posición en ops de la última operación del
+// usuario del gestor, o -1 si no tiene ninguna.
+func (g *GestorTareas) ultimaOperacion(ops []Operacion) int {
+	for i := len(ops) - 1; i >= 0; i-- {
+		if ops[i].usuario == g.usuario {
+			return i
+		}
+	}
+	return -1
+}
+
+// registrarOperacion añade al historial una mutación sobre una sola tarea.
+// Debe llamarse con g.mu tomado en modo escritura, tras aplicar el cambio.
+func (g *GestorTareas) registrarOperacion(tipo TipoOperacion, antes, despues *Tarea, posicion int) {
+	g.registrarOperacionCompuesta(tipo, []cambioTarea{nuevoCambio(antes, despues, posicion)})
+}
+
+// registrarOperacionCompuesta añade al historial una mutación sobre varias
+// tareas (ver apilarOperacion). Los cambios se deshacen juntos, en orden
+// inverso.
+// Debe llamarse con g.mu tomado en modo escritura, tras aplicar el cambio.
+func (g *GestorTareas) registrarOperacionCompuesta(tipo TipoOperacion, cambios []cambioTarea) {
+	if len(cambios) == 0 {
+		return
+	}
+	g.apilarOperacion(Operacion{Tipo: tipo, TareaID: cambios[0].id(), cambios: cambios})
+}
+
+// apilarOperacion registra op en la auditoría y la añade al historial de
+// deshacer del usuario del gestor, descarta sus operaciones pendientes de
+// rehacer y respeta el límite configurado, que se aplica a cada usuario
+// por separado. Todas las mutaciones pasan por aquí, así que la
+// auditoría las recoge aunque el historial esté desactivado.
+// Debe llamarse con g.mu tomado en modo escritura, tras aplicar el cambio.
+func (g *GestorTareas) apilarOperacion(op Operacion) {
+	g.auditar(op.Tipo, op, false)
+	if g.limiteHistorial <= 0 {
+		return
+	}
+	op.usuario = g.usuario
+	g.deshacer = append(g.deshacer, op)
+
+	// Del usuario conservamos solo las últimas limiteHistorial operaciones
+	propias := 0
+	for i := len(g.deshacer) - 1; i >= 0; i-- {
+		if g.deshacer[i].usuario != g.usuario {
+			continue
+		}
+		if propias++; propias > g.limiteHistorial {
+			g.deshacer = slices.Delete(g.deshacer, i, i+1)
+		}
+	}
+	g.rehacer = slices.DeleteFunc(g.rehacer, func(o Operacion) bool {
+		return o.usuario == g.usuario
+	})
+}
+
+// olvidarOperaciones descarta del historial las operaciones sobre las tareas
+// indicadas, que ya no pueden deshacerse ni rehacerse (por ejemplo, porque
+// se purgaron de la papelera). Debe llamarse con g.mu tomado en modo escritura.
+func (g *GestorTareas) olvidarOperaciones(ids map[int]bool) {
+	if len(ids) == 0 {
+		return
+	}
+	filtrar := func(ops []Operacion) []Operacion {
+		conservadas := ops[:0]
+		for _, op := range ops {
+			afectada := false
+			for _, c := range op.cambios {
+				afectada = afectada || ids[c.id()]
+			}
+			if !afectada {
+				conservadas = append(conservadas, op)
+			}
+		}
+		return conservadas
+	}
+	g.deshacer = filtrar(g.deshacer)
+	g.rehacer = filtrar(g.rehacer)
+}
+
+// pasoEstado es el cambio de una tarea en el sentido en que se aplica: del
+// estado desde al estado hasta (ver aplicarEstado).
+type pasoEstado struct {
+	desde, hasta *Tarea
+	posicion     int
+}
+
+// aplicarOperacion aplica los cambios de op, revirtiéndolos (deshacer) o
+// volviendo a aplicarlos, y después sus listas.
+//
+// Primero comprueba todos los cambios, de modo que si alguna tarea no
+// existe o quedarían subtareas huérfanas no aplica ninguno. Si después
+// falla una escritura, revierte los cambios ya aplicados. Retorna la
+// cantidad de cambios que quedaron aplicados pese al error (0 salvo que
+// también falle la reversión). Debe llamarse con g.mu tomado en modo escritura.
+func (g *GestorTareas) aplicarOperacion(op Operacion, deshacer bool) (int, error) {
+	pasos := make([]pasoEstado, len(op.cambios))
+	for i, c := range op.cambios {
+		if deshacer {
+			pasos[len(pasos)-1-i] = pasoEstado{c.despues, c.antes, c.posicion}
+		} else {
+			pasos[i] = pasoEstado{c.antes, c.despues, c.posicion}
+		}
+	}
+	if err := g.comprobarPasos(pasos); err != nil {
+		return 0, err
+	}
+
+	var listas []string
+	if op.listas != nil {
+		listas = op.listas.despues
+		if deshacer {
+			listas = op.listas.antes
+		}
+	}
+
+	var err error
+	aplicados := 0
+	for _, paso := range pasos {
+		if err = g.aplicarEstado(paso.desde, paso.hasta, paso.posicion); err != nil {
+			break
+		}
+		aplicados++
+	}
+	if err == nil && op.listas != nil {
+		err = g.establecerListas(listas)
+	}
+	if err == nil {
+		return aplicados, nil
+	}
+
+	// Revertimos en orden inverso lo ya aplicado
+	for ; aplicados > 0; aplicados-- {
+		paso := pasos[aplicados-1]
+		if errReversion := g.aplicarEstado(paso.hasta, paso.desde, paso.posicion); errReversion != nil {
+			return aplicados, fmt.Errorf("%v (y no pudo revertirse: %v)", err, errReversion)
+		}
+	}
+	return 0, err
+}
+
+// comprobarPasos verifica, sin modificar nada, que los pasos puedan
+// aplicarse en orden: que existan las tareas a reemplazar o eliminar, que
+// sigan exactamente en el estado desde (nadie las cambió después) y que
+// eliminar una no deje subtareas huérfanas, teniendo en cuenta los pasos
+// anteriores. Debe llamarse con g.mu tomado.
+func (g *GestorTareas) comprobarPasos(pasos []pasoEstado) error {
+	actuales := make(map[int]*Tarea, len(g.tareas)) // ID -> estado de cada tarea
+	for i := range g.tareas {
+		actuales[g.tareas[i].ID] = &g.tareas[i]
+	}
+	for _, paso := range pasos {
+		if paso.desde == nil {
+			if _, ok := actuales[paso.hasta.ID]; ok {
+				return &ErrorConflictoHistorial{ID: paso.hasta.ID}
+			}
+			actuales[paso.hasta.ID] = paso.hasta
+			continue
+		}
+		id := paso.desde.ID
+		actual, ok := actuales[id]
+		if !ok {
+			return &ErrorNoEncontrada{ID: id}
+		}
+		if !reflect.DeepEqual(*actual, *paso.desde) {
+			return &ErrorConflictoHistorial{ID: id}
+		}
+		if paso.hasta != nil {
+			actuales[id] = paso.hasta
+			continue
+		}
+		for _, tarea := range actuales {
+			if tarea.PadreID == id {
+				return ErrTieneSubtareas
+			}
+		}
+		delete(actuales, id)
+	}
+	return nil
+}
+
+// aplicarEstado lleva una tarea del estado desde al estado hasta:
+//   - desde nil: inserta hasta en posicion (rehacer crear)
+//   - hasta nil: elimina desde (deshacer crear)
+//   - ambos: reemplaza desde por hasta (completar, editar, eliminar, restaurar)
+//
+// Escribe primero en el diario y luego persiste igual que el resto de
+// mutaciones. Debe llamarse con g.mu tomado en modo escritura.
+func (g *GestorTareas) aplicarEstado(desde, hasta *Tarea, posicion int) error {
+	switch {
+	case hasta == nil:
+		indice := g.indicePorID(desde.ID)
+		if indice < 0 {
+			return &ErrorNoEncontrada{ID: desde.ID}
+		}
+		// No dejamos subtareas huérfanas (sus creaciones pudieron salir del historial)
+		if g.tieneSubtareas(desde.ID, true) {
+			return ErrTieneSubtareas
+		}
+		if err := g.escribirDiario(entradaRegistro{Op: opBorrar, ID: desde.ID}); err != nil {
+			return err
+		}
+		g.quitarTarea(indice)
+		g.registrarBorrado(desde.ID)
+
+	case desde == nil:
+		tarea := hasta.clonar()
+		if err := g.escribirDiario(entradaRegistro{Op: opGuardar, Tarea: &tarea}); err != nil {
+			return err
+		}
+		posicion = min(posicion, len(g.tareas))
+		g.insertarTarea(posicion, tarea)
+		g.registrarGuardado(tarea)
+
+	default:
+		indice := g.indicePorID(desde.ID)
+		if indice < 0 {
+			return &ErrorNoEncontrada{ID: desde.ID}
+		}
+		tarea := hasta.clonar()
+		if err := g.escribirDiario(entradaRegistro{Op: opGuardar, Tarea: &tarea}); err != nil {
+			return err
+		}
+		g.sustituirTarea(indice, tarea)
+		g.registrarGuardado(tarea)
+	}
+	return nil
+}
+
+// indicePorID retorna la posición de la tarea con el ID dado, o -1.
+// Debe llamarse con g.mu tomado.
+func (g *GestorTareas) indicePorID(id int) int {
+	if i, ok := g.indice.posiciones[id]; ok {
+		return i
+	}
+	return -1
+}
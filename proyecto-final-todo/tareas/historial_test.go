@@ -0,0 +1,189 @@
+// Tests del historial de deshacer/rehacer
+
+package tareas
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// TestDeshacerRestauraEstadoExacto deshace cada tipo de operación y compara
+// el estado completo con el previo
+func TestDeshacerRestauraEstadoExacto(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t)
+	gestor.CrearConDatos(DatosTarea{Titulo: "Tarea 1", Etiquetas: []string{"casa"}})
+	gestor.Crear("Tarea 2")
+	gestor.Crear("Tarea 3")
+
+	nuevo := "Tarea 2 editada"
+	operaciones := []struct {
+		nombre string
+		tipo   TipoOperacion
+		hacer  func() error
+	}{
+		{"completar", OperacionCompletar, func() error { return gestor.Completar(1) }},
+		{"editar", OperacionEditar, func() error {
+			_, err := gestor.Actualizar(2, CambiosTarea{Titulo: &nuevo})
+			return err
+		}},
+		{"eliminar del medio", OperacionEliminar, func() error { return gestor.Eliminar(2) }},
+		{"crear", OperacionCrear, func() error {
+			_, err := gestor.Crear("Tarea nueva")
+			return err
+		}},
+	}
+
+	for _, tt := range operaciones {
+		t.Run(tt.nombre, func(t *testing.T) {
+			antes := gestor.Listar()
+			if err := tt.hacer(); err != nil {
+				t.Fatalf("Error al ejecutar la operación: %v", err)
+			}
+			despues := gestor.Listar()
+
+			op, err := gestor.Deshacer()
+			if err != nil {
+				t.Fatalf("Error al deshacer: %v", err)
+			}
+			if op.Tipo != tt.tipo {
+				t.Errorf("Tipo esperado: %s, obtenido: %s", tt.tipo, op.Tipo)
+			}
+			if !reflect.DeepEqual(gestor.Listar(), antes) {
+				t.Errorf("Deshacer no restauró el estado previo\nesperado: %+v\nobtenido: %+v", antes, gestor.Listar())
+			}
+
+			if _, err := gestor.Rehacer(); err != nil {
+				t.Fatalf("Error al rehacer: %v", err)
+			}
+			if !reflect.DeepEqual(gestor.Listar(), despues) {
+				t.Errorf("Rehacer no restauró el estado posterior\nesperado: %+v\nobtenido: %+v", despues, gestor.Listar())
+			}
+
+			// Dejamos el estado como antes de la operación para el siguiente caso
+			gestor.Deshacer()
+		})
+	}
+}
+
+// TestDeshacerCrearNoReutilizaID verifica la semántica de proximoID
+func TestDeshacerCrearNoReutilizaID(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t)
+	gestor.Crear("Tarea 1")
+	gestor.Crear("Tarea 2")
+
+	gestor.Deshacer()
+	if _, err := gestor.BuscarPorID(2); err == nil {
+		t.Fatal("Deshacer crear debería eliminar la tarea 2")
+	}
+
+	// El ID 2 ya se asignó una vez y no se reutiliza
+	nueva, _ := gestor.Crear("Tarea nueva")
+	if nueva.ID != 3 {
+		t.Errorf("El próximo ID debería ser 3, es: %d", nueva.ID)
+	}
+
+	// Una operación nueva descarta lo pendiente de rehacer
+	if _, err := gestor.Rehacer(); !errors.Is(err, ErrNadaQueRehacer) {
+		t.Errorf("Se esperaba ErrNadaQueRehacer, se obtuvo: %v", err)
+	}
+}
+
+// TestDeshacerEliminarRestauraPosicion verifica que la tarea vuelva a su lugar
+func TestDeshacerEliminarRestauraPosicion(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t)
+	gestor.Crear("Tarea 1")
+	gestor.Crear("Tarea 2")
+	gestor.Crear("Tarea 3")
+
+	gestor.Eliminar(1)
+	gestor.Deshacer()
+
+	lista := gestor.Listar()
+	if len(lista) != 3 || lista[0].ID != 1 || lista[1].ID != 2 || lista[2].ID != 3 {
+		t.Errorf("Se esperaba el orden 1, 2, 3, se obtuvo: %+v", lista)
+	}
+}
+
+// TestDeshacerTodoONada verifica que si una tarea de la operación no puede
+// revertirse no se revierta ninguna y la operación siga en el historial
+func TestDeshacerTodoONada(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t)
+	gestor.Crear("Tarea 1")
+	titulo := "Tarea editada"
+	gestor.Actualizar(1, CambiosTarea{Titulo: &titulo})
+
+	// La primera tarea de la operación ya no existe; al deshacer se
+	// revertiría en último lugar, después de la tarea 1
+	fantasma := Tarea{ID: 99, Titulo: "Fantasma"}
+	op := &gestor.deshacer[len(gestor.deshacer)-1]
+	op.cambios = append([]cambioTarea{nuevoCambio(&fantasma, &fantasma, 0)}, op.cambios...)
+	operaciones, eventos := len(gestor.deshacer), len(gestor.Auditoria())
+
+	for range 2 {
+		var noEncontrada *ErrorNoEncontrada
+		if _, err := gestor.Deshacer(); !errors.As(err, &noEncontrada) || noEncontrada.ID != 99 {
+			t.Fatalf("Se esperaba ErrorNoEncontrada de la tarea 99, se obtuvo: %v", err)
+		}
+		if tarea, _ := gestor.BuscarPorID(1); tarea.Titulo != titulo {
+			t.Errorf("La tarea 1 no debería haberse revertido: %q", tarea.Titulo)
+		}
+	}
+	if len(gestor.deshacer) != operaciones || len(gestor.Auditoria()) != eventos {
+		t.Errorf("La operación debería seguir en el historial sin auditarse: %d operación(es), %d evento(s) nuevos", len(gestor.deshacer), len(gestor.Auditoria())-eventos)
+	}
+
+	// Al quitar el cambio imposible se deshace con normalidad
+	op.cambios = op.cambios[1:]
+	if _, err := gestor.Deshacer(); err != nil {
+		t.Fatalf("Error al deshacer: %v", err)
+	}
+	if tarea, _ := gestor.BuscarPorID(1); tarea.Titulo != "Tarea 1" {
+		t.Errorf("Título tras deshacer: %q", tarea.Titulo)
+	}
+}
+
+// TestHistorialAcotado verifica el límite de operaciones deshacibles
+func TestHistorialAcotado(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t, ConHistorial(2))
+	gestor.Crear("Tarea 1")
+	gestor.Crear("Tarea 2")
+	gestor.Crear("Tarea 3")
+
+	gestor.Deshacer()
+	gestor.Deshacer()
+	if _, err := gestor.Deshacer(); !errors.Is(err, ErrNadaQueDeshacer) {
+		t.Errorf("Se esperaba ErrNadaQueDeshacer tras agotar el historial, se obtuvo: %v", err)
+	}
+
+	if total, _, _ := gestor.Estadisticas(); total != 1 {
+		t.Errorf("Debería quedar solo la primera tarea, hay: %d", total)
+	}
+
+	// Con límite 0 el historial está desactivado
+	sinHistorial := nuevoGestorEnMemoria(t, ConHistorial(0))
+	sinHistorial.Crear("Tarea 1")
+	if _, err := sinHistorial.Deshacer(); !errors.Is(err, ErrNadaQueDeshacer) {
+		t.Errorf("Sin historial no debería poder deshacerse, se obtuvo: %v", err)
+	}
+}
+
+// TestDeshacerSePersiste verifica que deshacer pase por el diario
+func TestDeshacerSePersiste(t *testing.T) {
+	archivoTemp := "test_deshacer.json"
+	diarioTemp := "test_deshacer.json.diario"
+	defer eliminarConRespaldos(archivoTemp)
+	defer os.Remove(diarioTemp)
+
+	gestor, _ := NuevoGestorTareas(archivoTemp, ConDiario(diarioTemp))
+	gestor.Crear("Tarea 1")
+	gestor.Eliminar(1)
+	gestor.Deshacer()
+
+	// Sin Guardar: al reiniciar, el diario refleja la tarea restaurada
+	recuperado, _ := NuevoGestorTareas(archivoTemp, ConDiario(diarioTemp))
+	if _, err := recuperado.BuscarPorID(1); err != nil {
+		t.Error("La tarea restaurada con Deshacer debería sobrevivir al reinicio")
+	}
+}
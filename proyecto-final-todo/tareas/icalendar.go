@@ -0,0 +1,403 @@
+package tareas
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formatos de fecha de iCalendar (RFC 5545, sección 3.3): fecha sola,
+// fecha y hora en UTC, y fecha y hora local o con TZID.
+const (
+	fechaICal          = "20060102"
+	fechaHoraICalUTC   = "20060102T150405Z"
+	fechaHoraICalLocal = "20060102T150405"
+)
+
+// maxOctetosICal es la longitud máxima de una línea de iCalendar sin contar
+// el salto de línea; las más largas se pliegan.
+const maxOctetosICal = 75
+
+// prioridadesICal asocia cada prioridad con su valor de PRIORITY, donde 1
+// es la más alta y 9 la más baja.
+var prioridadesICal = map[Prioridad]int{
+	PrioridadUrgente: 1,
+	PrioridadAlta:    3,
+	PrioridadMedia:   5,
+	PrioridadBaja:    9,
+}
+
+// uidExportado retorna el UID con el que se exporta una tarea: el suyo o,
+// si no tiene (generador secuencial), uno derivado del ID, porque en
+// iCalendar el UID es obligatorio.
+func uidExportado(t Tarea) string {
+	if t.UID != "" {
+		return t.UID
+	}
+	return fmt.Sprintf("tarea-%d@todo-cli", t.ID)
+}
+
+// exportarICalendar escribe un VCALENDAR con un VTODO por tarea. Las
+// subtareas se relacionan con su padre mediante RELATED-TO si el padre
+// también está en la lista.
+func exportarICalendar(w io.Writer, lista []Tarea) error {
+	b := bufio.NewWriter(w)
+	escribir := func(nombre, valor string) {
+		escribirLineaICal(b, nombre+":"+valor)
+	}
+
+	uids := make(map[int]string)
+	for _, t := range lista {
+		uids[t.ID] = uidExportado(t)
+	}
+
+	escribir("BEGIN", "VCALENDAR")
+	escribir("VERSION", "2.0")
+	escribir("PRODID", "-//GO-API//Gestor de tareas//ES")
+	for _, t := range lista {
+		escribir("BEGIN", "VTODO")
+		escribir("UID", escaparTextoICal(uids[t.ID]))
+		// Sin METHOD, DTSTAMP es la fecha de la última revisión
+		escribir("DTSTAMP", formatearFechaICal(ultimaModificacion(t)))
+		escribir("CREATED", formatearFechaICal(t.FechaCreacion))
+		if !t.FechaActualizacion.IsZero() {
+			escribir("LAST-MODIFIED", formatearFechaICal(t.FechaActualizacion))
+		}
+		escribir("SUMMARY", escaparTextoICal(t.Titulo))
+		if t.Descripcion != "" {
+			escribir("DESCRIPTION", escaparTextoICal(t.Descripcion))
+		}
+		if t.Completada {
+			escribir("STATUS", "COMPLETED")
+			if !t.FechaCompletada.IsZero() {
+				escribir("COMPLETED", formatearFechaICal(t.FechaCompletada))
+			}
+		} else {
+			escribir("STATUS", "NEEDS-ACTION")
+		}
+		if !t.FechaVencimiento.IsZero() {
+			escribir("DUE", formatearFechaICal(t.FechaVencimiento))
+		}
+		if prioridad, ok := prioridadesICal[t.Prioridad]; ok {
+			escribir("PRIORITY", strconv.Itoa(prioridad))
+		}
+		if len(t.Etiquetas) > 0 {
+			categorias := make([]string, len(t.Etiquetas))
+			for i, etiqueta := range t.Etiquetas {
+				categorias[i] = escaparTextoICal(etiqueta)
+			}
+			escribir("CATEGORIES", strings.Join(categorias, ","))
+		}
+		if padre, ok := uids[t.PadreID]; ok && t.PadreID != 0 {
+			escribir("RELATED-TO;RELTYPE=PARENT", escaparTextoICal(padre))
+		}
+		escribir("END", "VTODO")
+	}
+	escribir("END", "VCALENDAR")
+
+	if err := b.Flush(); err != nil {
+		return fmt.Errorf("error al exportar iCalendar: %v", err)
+	}
+	return nil
+}
+
+// escribirLineaICal escribe una línea terminada en CRLF, plegándola en
+// líneas de hasta maxOctetosICal octetos que continúan con un espacio. No
+// corta nunca un carácter UTF-8 por la mitad.
+func escribirLineaICal(b *bufio.Writer, linea string) {
+	limite := maxOctetosICal
+	for len(linea) > limite {
+		corte := limite
+		for corte > 0 && !esInicioRuna(linea[corte]) {
+			corte--
+		}
+		b.WriteString(linea[:corte])
+		b.WriteString("\r\n ")
+		linea = linea[corte:]
+		limite = maxOctetosICal - 1 // el espacio inicial cuenta
+	}
+	b.WriteString(linea)
+	b.WriteString("\r\n")
+}
+
+// esInicioRuna indica si el byte b empieza un carácter UTF-8 (no es un
+// byte de continuación).
+func esInicioRuna(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// escaparTextoICal escapa un valor de tipo TEXT. Todos los saltos de línea
+// (CRLF, LF o CR sueltos) se escriben como \n, para no cortar la línea.
+func escaparTextoICal(texto string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(texto)
+}
+
+// desescaparTextoICal deshace escaparTextoICal.
+func desescaparTextoICal(texto string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n").Replace(texto)
+}
+
+// formatearFechaICal escribe una fecha y hora en UTC.
+func formatearFechaICal(fecha time.Time) string {
+	return fecha.UTC().Format(fechaHoraICalUTC)
+}
+
+// lineaICal es una línea de contenido ya desplegada: NOMBRE;PARAM=x:VALOR.
+type lineaICal struct {
+	numero     int
+	nombre     string
+	parametros map[string]string
+	valor      string
+
+	// err indica que la línea no tiene la forma NOMBRE:VALOR
+	err error
+}
+
+// leerICalendar lee los VTODO de un archivo iCalendar. Los demás
+// componentes (VEVENT, VTIMEZONE, ...) y las propiedades desconocidas se
+// ignoran. Un error en una propiedad descarta su VTODO y se informa con la
+// línea de la propiedad.
+func leerICalendar(r io.Reader) ([]tareaLeida, []ErrorLinea, error) {
+	lineas, err := desplegarICal(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var leidas []tareaLeida
+	var errores []ErrorLinea
+	porUID := make(map[string]int)    // UID → índice en leidas
+	padres := make(map[int]lineaICal) // índice en leidas → RELATED-TO
+
+	var actual *tareaLeida
+	var padre *lineaICal
+	var errActual *ErrorLinea
+	anidados := 0 // componentes abiertos dentro del VTODO actual (VALARM)
+	for _, l := range lineas {
+		switch {
+		case l.err != nil && actual == nil:
+			errores = append(errores, ErrorLinea{l.numero, l.err.Error()})
+		case l.err != nil:
+			if errActual == nil {
+				errActual = &ErrorLinea{l.numero, l.err.Error()}
+			}
+		case l.nombre == "BEGIN" && strings.EqualFold(l.valor, "VTODO") && actual == nil:
+			actual = &tareaLeida{linea: l.numero, padre: -1}
+			padre, errActual, anidados = nil, nil, 0
+		case actual == nil:
+			// Fuera de un VTODO no hay nada que leer
+		case l.nombre == "BEGIN":
+			anidados++
+		case l.nombre == "END" && anidados > 0:
+			anidados--
+		case l.nombre == "END" && !strings.EqualFold(l.valor, "VTODO"):
+			errores = append(errores, ErrorLinea{actual.linea, "falta END:VTODO"})
+			actual = nil
+		case l.nombre == "END":
+			if errActual != nil {
+				errores = append(errores, *errActual)
+			} else {
+				if actual.uid != "" {
+					porUID[actual.uid] = len(leidas)
+				}
+				if padre != nil {
+					padres[len(leidas)] = *padre
+				}
+				leidas = append(leidas, *actual)
+			}
+			actual = nil
+		case anidados > 0 || errActual != nil:
+			// Propiedades de un VALARM, o de un VTODO ya descartado
+		case l.nombre == "RELATED-TO":
+			if tipo := l.parametros["RELTYPE"]; tipo == "" || strings.EqualFold(tipo, "PARENT") {
+				padre = &l
+			}
+		default:
+			if err := leerPropiedadICal(actual, l); err != nil {
+				errActual = &ErrorLinea{l.numero, err.Error()}
+			}
+		}
+	}
+	if actual != nil {
+		errores = append(errores, ErrorLinea{actual.linea, "falta END:VTODO"})
+	}
+
+	// RELATED-TO se resuelve al final: el padre puede aparecer después. Si
+	// no está en el archivo, se busca entre las tareas del gestor al importar
+	for i, relacion := range padres {
+		uid := desescaparTextoICal(relacion.valor)
+		if p, ok := porUID[uid]; ok {
+			leidas[i].padre = p
+		} else {
+			leidas[i].padreUID = uid
+		}
+	}
+	return leidas, errores, nil
+}
+
+// leerPropiedadICal aplica una propiedad de un VTODO a la tarea leída.
+func leerPropiedadICal(leida *tareaLeida, l lineaICal) error {
+	var err error
+	switch l.nombre {
+	case "UID":
+		leida.uid = strings.TrimSpace(desescaparTextoICal(l.valor))
+	case "SUMMARY":
+		leida.datos.Titulo = desescaparTextoICal(l.valor)
+	case "DESCRIPTION":
+		leida.datos.Descripcion = desescaparTextoICal(l.valor)
+	case "STATUS":
+		// COMPLETED tiene prioridad, aparezca antes o después de STATUS
+		leida.completada = strings.EqualFold(l.valor, "COMPLETED") || !leida.fechaCompletada.IsZero()
+	case "COMPLETED":
+		leida.completada = true
+		leida.fechaCompletada, err = parsearFechaICal(l)
+	case "CREATED":
+		leida.fechaCreacion, err = parsearFechaICal(l)
+	case "DUE":
+		leida.datos.FechaVencimiento, err = parsearFechaICal(l)
+		if err == nil && len(strings.TrimSpace(l.valor)) == len(fechaICal) {
+			// Un DUE sin hora (VALUE=DATE) vence al final de ese día
+			leida.datos.FechaVencimiento = FinDelDia(leida.datos.FechaVencimiento)
+		}
+	case "PRIORITY":
+		leida.datos.Prioridad, err = parsearPrioridadICal(l.valor)
+	case "CATEGORIES":
+		for _, categoria := range dividirListaICal(l.valor) {
+			leida.datos.Etiquetas = append(leida.datos.Etiquetas, desescaparTextoICal(categoria))
+		}
+	}
+	return err
+}
+
+// desplegarICal lee las líneas de contenido, uniendo las plegadas (las que
+// empiezan por espacio o tabulador continúan la anterior).
+func desplegarICal(r io.Reader) ([]lineaICal, error) {
+	var lineas []lineaICal
+	var actual strings.Builder
+	inicio := 0
+
+	cerrar := func() {
+		if actual.Len() == 0 {
+			return
+		}
+		l := parsearLineaICal(actual.String())
+		l.numero = inicio
+		lineas = append(lineas, l)
+		actual.Reset()
+	}
+
+	escaner := bufio.NewScanner(r)
+	for numero := 1; escaner.Scan(); numero++ {
+		texto := strings.TrimSuffix(escaner.Text(), "\r")
+		if strings.HasPrefix(texto, " ") || strings.HasPrefix(texto, "\t") {
+			actual.WriteString(texto[1:])
+			continue
+		}
+		cerrar()
+		if strings.TrimSpace(texto) != "" {
+			actual.WriteString(texto)
+			inicio = numero
+		}
+	}
+	if err := escaner.Err(); err != nil {
+		return nil, err
+	}
+	cerrar()
+	return lineas, nil
+}
+
+// parsearLineaICal separa el nombre, los parámetros y el valor de una línea
+// de contenido. Los dos puntos dentro de un parámetro entre comillas no
+// separan el valor. Si la línea no tiene valor, lo indica en err.
+func parsearLineaICal(linea string) lineaICal {
+	separador := -1
+	entreComillas := false
+	for i, c := range linea {
+		if c == '"' {
+			entreComillas = !entreComillas
+		} else if c == ':' && !entreComillas {
+			separador = i
+			break
+		}
+	}
+	if separador < 0 {
+		return lineaICal{err: fmt.Errorf("falta ':' en %q", linea)}
+	}
+
+	partes := strings.Split(linea[:separador], ";")
+	l := lineaICal{
+		nombre:     strings.ToUpper(partes[0]),
+		parametros: make(map[string]string),
+		valor:      linea[separador+1:],
+	}
+	for _, parametro := range partes[1:] {
+		nombre, valor, _ := strings.Cut(parametro, "=")
+		l.parametros[strings.ToUpper(nombre)] = strings.Trim(valor, `"`)
+	}
+	return l
+}
+
+// parsearFechaICal interpreta un valor DATE o DATE-TIME: en UTC (con Z), en
+// la zona de su parámetro TZID, o en la hora local si no indica zona.
+func parsearFechaICal(l lineaICal) (time.Time, error) {
+	valor := strings.TrimSpace(l.valor)
+	zona := time.Local
+	if tzid := l.parametros["TZID"]; tzid != "" {
+		if z, err := time.LoadLocation(tzid); err == nil {
+			zona = z
+		}
+	}
+
+	var fecha time.Time
+	var err error
+	switch {
+	case len(valor) == len(fechaICal):
+		fecha, err = time.ParseInLocation(fechaICal, valor, zona)
+	case strings.HasSuffix(valor, "Z"):
+		fecha, err = time.Parse(fechaHoraICalUTC, valor)
+	default:
+		fecha, err = time.ParseInLocation(fechaHoraICalLocal, valor, zona)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("fecha inválida en %s: %q", l.nombre, valor)
+	}
+	return fecha, nil
+}
+
+// parsearPrioridadICal traduce PRIORITY: 0 es sin prioridad, 1 urgente,
+// 2-4 alta, 5 media y 6-9 baja.
+func parsearPrioridadICal(valor string) (Prioridad, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(valor))
+	switch {
+	case err != nil || n < 0 || n > 9:
+		return "", fmt.Errorf("PRIORITY debe ser un número de 0 a 9, no %q", valor)
+	case n == 0:
+		return "", nil
+	case n == 1:
+		return PrioridadUrgente, nil
+	case n <= 4:
+		return PrioridadAlta, nil
+	case n == 5:
+		return PrioridadMedia, nil
+	}
+	return PrioridadBaja, nil
+}
+
+// dividirListaICal separa un valor de varios elementos por las comas no
+// escapadas.
+func dividirListaICal(valor string) []string {
+	var elementos []string
+	inicio := 0
+	for i := 0; i < len(valor); i++ {
+		switch valor[i] {
+		case '\\':
+			i++
+		case ',':
+			elementos = append(elementos, valor[inicio:i])
+			inicio = i + 1
+		}
+	}
+	return append(elementos, valor[inicio:])
+}
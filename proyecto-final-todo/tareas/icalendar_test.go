@@ -0,0 +1,187 @@
+// Tests de exportación e importación en iCalendar
+
+package tareas
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata" // TZID de los calendarios externos, aunque el sistema no tenga zoneinfo
+)
+
+// TestExportarICalendar verifica los VTODO generados, el escapado de texto
+// y el plegado de líneas largas
+func TestExportarICalendar(t *testing.T) {
+	gestor := crearTareasExportacion(t)
+	largo := strings.Repeat("ñ", 40) + "; con punto y coma"
+	gestor.Actualizar(3, CambiosTarea{Descripcion: &largo})
+
+	var salida bytes.Buffer
+	if err := Exportar(&salida, gestor.Listar(), FormatoICalendar); err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	texto := salida.String()
+
+	esperados := []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"UID:tarea-1@todo-cli\r\n",
+		"SUMMARY:Declarar impuestos\r\n",
+		"STATUS:COMPLETED\r\n",
+		"COMPLETED:" + formatearFechaICal(time.Date(2026, 3, 3, 9, 0, 0, 0, time.Local)) + "\r\n",
+		"PRIORITY:1\r\n",
+		"DESCRIPTION:Vuelos\\, hotel\r\n",
+		"STATUS:NEEDS-ACTION\r\n",
+		"DUE:" + formatearFechaICal(time.Date(2026, 3, 12, 9, 0, 0, 0, time.Local)) + "\r\n",
+		"CATEGORIES:ocio,familia\r\n",
+		"RELATED-TO;RELTYPE=PARENT:tarea-2@todo-cli\r\n",
+		"END:VTODO\r\nEND:VCALENDAR\r\n",
+	}
+	for _, esperado := range esperados {
+		if !strings.Contains(texto, esperado) {
+			t.Errorf("Se esperaba %q en:\n%s", esperado, texto)
+		}
+	}
+
+	// Ninguna línea supera los 75 octetos, y al desplegarlas se recupera el texto
+	for _, linea := range strings.Split(texto, "\r\n") {
+		if len(linea) > maxOctetosICal {
+			t.Errorf("Línea de %d octetos sin plegar: %q", len(linea), linea)
+		}
+	}
+	if !strings.Contains(strings.ReplaceAll(texto, "\r\n ", ""), "DESCRIPTION:"+strings.Repeat("ñ", 40)+"\\; con punto y coma\r\n") {
+		t.Errorf("La descripción larga no se plegó correctamente:\n%s", texto)
+	}
+
+	// Un retorno de carro suelto también se escapa, sin cortar la línea
+	salida.Reset()
+	Exportar(&salida, []Tarea{{ID: 9, Titulo: "Retornos", Descripcion: "a\rb\r\nc"}}, FormatoICalendar)
+	if texto := salida.String(); !strings.Contains(texto, "DESCRIPTION:a\\nb\\nc\r\n") || strings.Count(texto, "\r") != strings.Count(texto, "\r\n") {
+		t.Errorf("Retornos de carro sin escapar:\n%q", texto)
+	}
+}
+
+// TestImportarICalendarIdaYVuelta verifica que los UID se conserven y que
+// volver a importar el mismo calendario no duplique tareas
+func TestImportarICalendarIdaYVuelta(t *testing.T) {
+	ahora := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	origen := nuevoGestorEnMemoria(t, ConGeneradorID(NuevoGeneradorUUID(nil)), ConReloj(func() time.Time { return ahora }))
+	origen.CrearConDatos(DatosTarea{Titulo: "Preparar viaje", Descripcion: "Vuelos,\nhotel", Prioridad: PrioridadAlta, Etiquetas: []string{"ocio"}, FechaVencimiento: ahora.AddDate(0, 0, 5)})
+	origen.CrearConDatos(DatosTarea{Titulo: "Reservar hotel", PadreID: 1})
+	origen.Completar(2)
+
+	var archivo bytes.Buffer
+	Exportar(&archivo, origen.Listar(), FormatoICalendar)
+
+	destino := nuevoGestorEnMemoria(t)
+	destino.Crear("Otra tarea")
+	informe, err := destino.Importar(bytes.NewReader(archivo.Bytes()), FormatoICalendar, false)
+	if err != nil || len(informe.Creadas) != 2 || len(informe.Errores) != 0 {
+		t.Fatalf("Importación inesperada: %v, %+v", err, informe)
+	}
+	for i, esperada := range origen.Listar() {
+		obtenida, err := destino.BuscarPorUID(esperada.UID)
+		if err != nil {
+			t.Fatalf("No se conservó el UID %q: %v", esperada.UID, err)
+		}
+		if obtenida.ID != i+2 || obtenida.Titulo != esperada.Titulo || obtenida.Descripcion != esperada.Descripcion ||
+			obtenida.Completada != esperada.Completada || obtenida.Prioridad != esperada.Prioridad ||
+			!slices.Equal(obtenida.Etiquetas, esperada.Etiquetas) ||
+			!obtenida.FechaCreacion.Equal(esperada.FechaCreacion) || !obtenida.FechaVencimiento.Equal(esperada.FechaVencimiento) ||
+			!obtenida.FechaCompletada.Equal(esperada.FechaCompletada) {
+			t.Errorf("Tarea importada distinta:\nobtenida: %+v\nesperada: %+v", obtenida, esperada)
+		}
+	}
+	if hotel, _ := destino.BuscarPorID(3); hotel.PadreID != 2 {
+		t.Errorf("La subtarea debería colgar de la tarea 2, cuelga de %d", hotel.PadreID)
+	}
+
+	// Reimportar con otro título: el UID la identifica como la misma tarea
+	cambiado := strings.Replace(archivo.String(), "SUMMARY:Preparar viaje", "SUMMARY:Viaje a Roma", 1)
+	informe, _ = destino.Importar(strings.NewReader(cambiado), FormatoICalendar, true)
+	if len(informe.Creadas) != 0 || len(informe.Duplicadas) != 2 || informe.Duplicadas[0].ExistenteID != 2 {
+		t.Errorf("Reimportar debería dar 2 duplicadas por UID: %+v", informe)
+	}
+
+	// El UID de una tarea de la papelera no se reutiliza
+	destino.EliminarConSubtareas(2)
+	informe, _ = destino.Importar(bytes.NewReader(archivo.Bytes()), FormatoICalendar, true)
+	if len(informe.Creadas) != 0 || len(informe.Errores) != 2 {
+		t.Errorf("Se esperaban errores por UID en la papelera: %+v", informe)
+	}
+}
+
+// TestImportarICalendarExterno verifica un calendario como los que generan
+// otras aplicaciones: zonas horarias, fechas sin hora, alarmas, eventos y
+// errores por línea
+func TestImportarICalendarExterno(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t, ConGeneradorID(NuevoGeneradorULID(nil)))
+	padre, _ := gestor.CrearConDatos(DatosTarea{Titulo: "Mudanza"})
+
+	calendario := strings.Join([]string{
+		"BEGIN:VCALENDAR", // 1
+		"PRODID:-//Otra app//ES",
+		"BEGIN:VEVENT",
+		"SUMMARY:Un evento, no una tarea",
+		"END:VEVENT",
+		"BEGIN:VTODO", // 6
+		"UID:abc-1",
+		"SUMMARY:Contratar camión de mu",
+		" danzas",
+		"DUE;TZID=Europe/Madrid:20260410T180000",
+		"PRIORITY:6",
+		"CATEGORIES:casa,urgente\\;ya",
+		"RELATED-TO:" + padre.UID,
+		"BEGIN:VALARM",
+		"SUMMARY:Alarma",
+		"END:VALARM",
+		"END:VTODO",
+		"BEGIN:VTODO", // 18
+		"UID:abc-2",
+		"SUMMARY:Embalar libros",
+		"DUE;VALUE=DATE:20260405",
+		"COMPLETED:20260401T100000Z",
+		"STATUS:NEEDS-ACTION", // COMPLETED prevalece aunque STATUS vaya después
+		"END:VTODO",
+		"BEGIN:VTODO", // 25
+		"SUMMARY:Fecha mal",
+		"DUE:mañana",
+		"END:VTODO",
+		"BEGIN:VTODO", // 29
+		"SUMMARY:Padre desconocido",
+		"RELATED-TO;RELTYPE=PARENT:no-existe",
+		"END:VTODO",
+		"BEGIN:VTODO", // 33
+		"SUMMARY:Línea rota",
+		"ESTO NO ES UNA PROPIEDAD",
+		"END:VTODO",
+		"BEGIN:VTODO", // 37
+		"SUMMARY:Sin cerrar",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	informe, err := gestor.Importar(strings.NewReader(calendario), FormatoICalendar, false)
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	var lineas []int
+	for _, e := range informe.Errores {
+		lineas = append(lineas, e.Linea)
+	}
+	if len(informe.Creadas) != 2 || !slices.Equal(lineas, []int{27, 29, 35, 37}) {
+		t.Fatalf("Informe inesperado: %s, errores %+v", informe, informe.Errores)
+	}
+
+	camion, embalar := informe.Creadas[0], informe.Creadas[1]
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	if camion.UID != "abc-1" || camion.Titulo != "Contratar camión de mudanzas" || camion.PadreID != padre.ID ||
+		camion.Prioridad != PrioridadBaja || !slices.Equal(camion.Etiquetas, []string{"casa", "urgente;ya"}) ||
+		!camion.FechaVencimiento.Equal(time.Date(2026, 4, 10, 18, 0, 0, 0, madrid)) {
+		t.Errorf("Primera tarea mal interpretada: %+v", camion)
+	}
+	if !embalar.Completada || !embalar.FechaCompletada.Equal(time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)) ||
+		!embalar.FechaVencimiento.Equal(time.Date(2026, 4, 5, 23, 59, 59, 0, time.Local)) {
+		t.Errorf("COMPLETED debería marcarla completada aunque STATUS diga lo contrario y DUE sin hora vencer al final del día: %+v", embalar)
+	}
+}
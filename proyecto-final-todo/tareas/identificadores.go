@@ -0,0 +1,182 @@
+package tareas
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+)
+
+// GeneradorID es la estrategia con la que el gestor asigna el UID de cada
+// tarea nueva. Se elige con ConGeneradorID.
+//
+// El ID numérico se asigna siempre de forma secuencial, porque es el que se
+// escribe en la CLI y en las rutas de la API; el UID es un identificador
+// adicional, único entre gestores, pensado para sincronizar e importar
+// tareas entre sistemas.
+type GeneradorID interface {
+	// NuevoID retorna el UID de una tarea creada en el momento ahora con
+	// el ID numérico numero. La cadena vacía significa "sin UID".
+	NuevoID(numero int, ahora time.Time) (string, error)
+}
+
+// GeneradorSecuencial es la estrategia por defecto: las tareas se
+// identifican solo por su ID numérico y no reciben UID.
+type GeneradorSecuencial struct{}
+
+// NuevoID implementa GeneradorID.
+func (GeneradorSecuencial) NuevoID(int, time.Time) (string, error) {
+	return "", nil
+}
+
+// generadorUUID genera UUID versión 4 (aleatorios).
+type generadorUUID struct {
+	aleatorio io.Reader
+}
+
+// NuevoGeneradorUUID crea una estrategia que asigna UUID versión 4, como
+// "3f2b8c1e-5d4a-4e7b-9c2d-1a6f0e8b7c3d".
+//
+// Los bytes aleatorios se leen de aleatorio; con nil se usa crypto/rand.
+// Pasar una fuente fija permite obtener UIDs reproducibles en los tests.
+//
+// Ejemplo:
+//
+//	gestor, err := NuevoGestorTareas("tareas.json", ConGeneradorID(NuevoGeneradorUUID(nil)))
+//
+func NuevoGeneradorUUID(aleatorio io.Reader) GeneradorID {
+	if aleatorio == nil {
+		aleatorio = rand.Reader
+	}
+	return generadorUUID{aleatorio: aleatorio}
+}
+
+// NuevoID implementa GeneradorID.
+func (g generadorUUID) NuevoID(int, time.Time) (string, error) {
+	var b [16]byte
+	if _, err := io.ReadFull(g.aleatorio, b[:]); err != nil {
+		return "", fmt.Errorf("error al generar UUID: %v", err)
+	}
+	b[6] = b[6]&0x0f | 0x40 // versión 4
+	b[8] = b[8]&0x3f | 0x80 // variante RFC 4122
+
+	h := hex.EncodeToString(b[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32], nil
+}
+
+// alfabetoULID es el Base32 de Crockford, sin I, L, O ni U.
+const alfabetoULID = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// generadorULID genera ULID: 48 bits de marca de tiempo en milisegundos
+// seguidos de 80 bits aleatorios.
+type generadorULID struct {
+	aleatorio io.Reader
+}
+
+// NuevoGeneradorULID crea una estrategia que asigna ULID, como
+// "01JD3Q8Z5K7T2M4X9B6C1R0VNE".
+//
+// A diferencia de los UUID, los ULID empiezan por la fecha de creación de
+// la tarea (según el reloj del gestor), de modo que ordenarlos como texto
+// los ordena cronológicamente. Los bytes aleatorios se leen de aleatorio;
+// con nil se usa crypto/rand.
+//
+// Ejemplo:
+//
+//	gestor, err := NuevoGestorTareas("tareas.json", ConGeneradorID(NuevoGeneradorULID(nil)))
+//
+func NuevoGeneradorULID(aleatorio io.Reader) GeneradorID {
+	if aleatorio == nil {
+		aleatorio = rand.Reader
+	}
+	return generadorULID{aleatorio: aleatorio}
+}
+
+// NuevoID implementa GeneradorID.
+func (g generadorULID) NuevoID(_ int, ahora time.Time) (string, error) {
+	var b [16]byte
+	milisegundos := uint64(ahora.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(milisegundos)
+		milisegundos >>= 8
+	}
+	if _, err := io.ReadFull(g.aleatorio, b[6:]); err != nil {
+		return "", fmt.Errorf("error al generar ULID: %v", err)
+	}
+
+	// 128 bits en 26 caracteres de 5 bits, empezando por los 2 bits altos
+	var ulid [26]byte
+	acumulado, bits, j := uint32(0), 2, 0
+	for _, octeto := range b {
+		acumulado = acumulado<<8 | uint32(octeto)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			ulid[j] = alfabetoULID[(acumulado>>bits)&0x1f]
+			j++
+		}
+	}
+	return string(ulid[:]), nil
+}
+
+// ConGeneradorID elige la estrategia con la que se asigna el UID de las
+// tareas nuevas (GeneradorSecuencial por defecto).
+//
+// Las tareas existentes conservan su UID; cambiar de estrategia solo
+// afecta a las que se creen a partir de entonces.
+//
+// Ejemplo:
+//
+//	gestor, err := NuevoGestorTareas("tareas.json", ConGeneradorID(NuevoGeneradorULID(nil)))
+//
+func ConGeneradorID(generador GeneradorID) Opcion {
+	return func(g *GestorTareas) {
+		g.generadorID = generador
+	}
+}
+
+// Identificador retorna el UID de la tarea o, si no tiene, su ID numérico
+// como texto.
+func (t Tarea) Identificador() string {
+	if t.UID != "" {
+		return t.UID
+	}
+	return fmt.Sprint(t.ID)
+}
+
+// BuscarPorUID encuentra una tarea por su UID.
+//
+// Las tareas de la papelera se consideran no encontradas, igual que en
+// BuscarPorID.
+//
+// Retorna:
+//   - *Tarea: copia de la tarea encontrada
+//   - error: ErrorNoEncontrada si ninguna tarea tiene ese UID
+//
+func (g *GestorTareas) BuscarPorUID(uid string) (*Tarea, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if id, ok := g.indice.uids[uid]; ok {
+		if i := g.indiceVisible(id); i >= 0 {
+			tarea := g.tareas[i].clonar()
+			return &tarea, nil
+		}
+	}
+	return nil, &ErrorNoEncontrada{UID: uid}
+}
+
+// nuevoUID pide a la estrategia configurada el UID de la tarea que va a
+// crearse con el próximo ID y comprueba que no esté repetido.
+// Debe llamarse con g.mu tomado en modo escritura.
+func (g *GestorTareas) nuevoUID(ahora time.Time) (string, error) {
+	uid, err := g.generadorID.NuevoID(g.proximoID, ahora)
+	if err != nil || uid == "" {
+		return uid, err
+	}
+	if id, ok := g.indice.uids[uid]; ok {
+		return "", fmt.Errorf("el UID generado %q ya pertenece a la tarea %d", uid, id)
+	}
+	return uid, nil
+}
@@ -0,0 +1,115 @@
+// Tests de las estrategias de identificadores
+
+package tareas
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// TestGeneradoresID verifica el formato de cada estrategia con fuentes fijas
+func TestGeneradoresID(t *testing.T) {
+	ahora := time.UnixMilli(1)
+
+	tests := []struct {
+		nombre    string
+		generador GeneradorID
+		esperado  string
+	}{
+		{"secuencial", GeneradorSecuencial{}, ""},
+		{"uuid", NuevoGeneradorUUID(bytes.NewReader(bytes.Repeat([]byte{0xff}, 16))), "ffffffff-ffff-4fff-bfff-ffffffffffff"},
+		{"ulid", NuevoGeneradorULID(bytes.NewReader(make([]byte, 10))), "00000000010000000000000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			gestor := nuevoGestorEnMemoria(t, ConGeneradorID(tt.generador), ConReloj(func() time.Time { return ahora }))
+			tarea, err := gestor.Crear("Tarea con UID")
+			if err != nil {
+				t.Fatalf("Error al crear: %v", err)
+			}
+			if tarea.ID != 1 || tarea.UID != tt.esperado {
+				t.Errorf("Se esperaba ID 1 y UID %q, se obtuvo: %d, %q", tt.esperado, tarea.ID, tarea.UID)
+			}
+			if tt.esperado == "" && tarea.Identificador() != "1" {
+				t.Errorf("Sin UID el identificador debería ser el ID numérico, es: %q", tarea.Identificador())
+			}
+		})
+	}
+
+	// Una fuente agotada se informa como error y no crea la tarea
+	gestor := nuevoGestorEnMemoria(t, ConGeneradorID(NuevoGeneradorUUID(bytes.NewReader(nil))))
+	if _, err := gestor.Crear("Sin aleatoriedad"); err == nil {
+		t.Error("Se esperaba un error al no poder leer bytes aleatorios")
+	}
+	if total, _, _ := gestor.Estadisticas(); total != 0 {
+		t.Errorf("No debería haberse creado ninguna tarea, hay: %d", total)
+	}
+}
+
+// TestFechaCreacionConReloj verifica que la fecha de creación la fije el
+// reloj del gestor, sin depender de la hora real
+func TestFechaCreacionConReloj(t *testing.T) {
+	ahora := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+	gestor := nuevoGestorEnMemoria(t, ConReloj(func() time.Time { return ahora }))
+
+	tarea, err := gestor.Crear("Tarea con fecha")
+	if err != nil {
+		t.Fatalf("Error al crear: %v", err)
+	}
+	if !tarea.FechaCreacion.Equal(ahora) {
+		t.Errorf("Fecha de creación esperada %s, obtenida: %s", ahora, tarea.FechaCreacion)
+	}
+}
+
+// TestULIDOrdenCronologico verifica que los ULID reales ordenen por fecha
+func TestULIDOrdenCronologico(t *testing.T) {
+	ahora := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	gestor := nuevoGestorEnMemoria(t, ConGeneradorID(NuevoGeneradorULID(nil)), ConReloj(func() time.Time { return ahora }))
+
+	formato := regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`)
+	var anterior string
+	for i := range 3 {
+		ahora = ahora.Add(time.Millisecond)
+		tarea, err := gestor.Crear("Tarea ordenada")
+		if err != nil {
+			t.Fatalf("Error al crear la tarea %d: %v", i+1, err)
+		}
+		if !formato.MatchString(tarea.UID) {
+			t.Errorf("ULID con formato inválido: %q", tarea.UID)
+		}
+		if tarea.UID <= anterior {
+			t.Errorf("Los ULID deberían crecer con el tiempo: %q tras %q", tarea.UID, anterior)
+		}
+		anterior = tarea.UID
+	}
+}
+
+// TestBuscarPorUID verifica la búsqueda por UID y el rechazo de UIDs repetidos
+func TestBuscarPorUID(t *testing.T) {
+	// La fuente alcanza para dos UUID idénticos
+	gestor := nuevoGestorEnMemoria(t, ConGeneradorID(NuevoGeneradorUUID(bytes.NewReader(make([]byte, 32)))))
+
+	creada, _ := gestor.Crear("Tarea buscada")
+	encontrada, err := gestor.BuscarPorUID(creada.UID)
+	if err != nil || encontrada.ID != creada.ID {
+		t.Fatalf("Se esperaba encontrar la tarea %d, se obtuvo: %+v, %v", creada.ID, encontrada, err)
+	}
+
+	if _, err := gestor.Crear("Tarea repetida"); err == nil {
+		t.Error("Un UID repetido debería rechazarse")
+	}
+
+	var noEncontrada *ErrorNoEncontrada
+	if _, err := gestor.BuscarPorUID("no-existe"); !errors.As(err, &noEncontrada) || noEncontrada.UID != "no-existe" {
+		t.Errorf("Se esperaba ErrorNoEncontrada con el UID buscado, se obtuvo: %v", err)
+	}
+
+	// Las tareas de la papelera no se encuentran
+	gestor.Eliminar(creada.ID)
+	if _, err := gestor.BuscarPorUID(creada.UID); err == nil {
+		t.Error("Una tarea en la papelera no debería encontrarse por UID")
+	}
+}
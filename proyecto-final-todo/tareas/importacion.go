@@ -0,0 +1,590 @@
+package tareas
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrorLinea es un error al leer una línea de un archivo importado.
+type ErrorLinea struct {
+	// Linea es el número de línea (desde 1) del archivo
+	Linea int `json:"linea"`
+
+	// Mensaje describe por qué no pudo importarse la tarea de esa línea
+	Mensaje string `json:"mensaje"`
+}
+
+// Error implementa la interfaz error.
+func (e ErrorLinea) Error() string {
+	return fmt.Sprintf("línea %d: %s", e.Linea, e.Mensaje)
+}
+
+// Duplicada es una tarea del archivo que no se importó porque ya existe una
+// con el mismo UID o el mismo título.
+type Duplicada struct {
+	// Linea es el número de línea de la tarea en el archivo
+	Linea int `json:"linea"`
+
+	// Titulo es el título repetido, tal como aparece en el archivo
+	Titulo string `json:"titulo"`
+
+	// ExistenteID es el ID de la tarea con ese UID o título: una del gestor
+	// o una anterior del mismo archivo
+	ExistenteID int `json:"existente_id"`
+}
+
+// InformeImportacion resume el resultado de GestorTareas.Importar.
+type InformeImportacion struct {
+	// Simulacion indica que no se creó nada: Creadas son las tareas que se
+	// crearían, con los IDs que recibirían si se importara ahora
+	Simulacion bool `json:"simulacion"`
+
+	// Creadas son las tareas nuevas, en el orden del archivo (las subtareas,
+	// siempre tras su padre)
+	Creadas []Tarea `json:"creadas"`
+
+	// Duplicadas son las tareas omitidas por tener un UID o un título ya
+	// existente
+	Duplicadas []Duplicada `json:"duplicadas"`
+
+	// Errores son las líneas que no pudieron leerse o no superan las validaciones
+	Errores []ErrorLinea `json:"errores"`
+}
+
+// String retorna un resumen de una línea, como "3 creada(s), 1 duplicada(s),
+// 0 error(es)".
+func (i *InformeImportacion) String() string {
+	verbo := "creada(s)"
+	if i.Simulacion {
+		verbo = "por crear"
+	}
+	return fmt.Sprintf("%d %s, %d duplicada(s), %d error(es)", len(i.Creadas), verbo, len(i.Duplicadas), len(i.Errores))
+}
+
+// tareaLeida es una tarea leída de un archivo, antes de importarla.
+type tareaLeida struct {
+	linea           int
+	datos           DatosTarea
+	completada      bool
+	fechaCreacion   time.Time
+	fechaCompletada time.Time
+
+	// uid es el UID del archivo (CSV e iCalendar), que se conserva
+	uid string
+
+	// padre es el índice en la lista leída de la tarea padre, o -1
+	padre int
+
+	// padreUID es el UID de la tarea padre cuando no está en el archivo
+	// (RELATED-TO de iCalendar): se busca entre las tareas del gestor
+	padreUID string
+}
+
+// Importar lee tareas de r en el formato indicado y crea las que no existan.
+//
+// Cada tarea leída se valida como en CrearConDatos. Las que no superan la
+// validación o no pueden leerse se informan con su número de línea, y las
+// que tienen el mismo UID o el mismo título (sin distinguir mayúsculas ni
+// tildes) que una tarea existente o que otra anterior del archivo se omiten
+// como duplicadas; el resto de tareas se importa igualmente. Las fechas de
+// creación y de completado y los UID del archivo se conservan (las tareas
+// sin UID reciben uno del generador configurado); las tareas reciben IDs
+// nuevos, y la jerarquía de subtareas (padre_id en CSV, sangría en
+// Markdown, RELATED-TO en iCalendar) se traduce a esos IDs. El usuario del
+// gestor queda como propietario de las tareas importadas.
+//
+// Con simular en true no se modifica nada: el informe muestra lo que se
+// crearía. Una importación real se registra como una sola operación, de
+// modo que Deshacer la revierte completa.
+//
+// Parámetros:
+//   - r: el contenido a importar
+//   - formato: FormatoCSV, FormatoMarkdown, FormatoTodoTxt o FormatoICalendar
+//   - simular: true para obtener el informe sin crear nada
+//
+// Retorna:
+//   - *InformeImportacion: tareas creadas (o por crear), duplicadas y errores
+//   - error: si r no puede leerse, el formato no existe o falla la escritura
+//     en el diario (en ese caso el informe incluye lo importado hasta entonces)
+//
+// Ejemplo:
+//
+//	archivo, _ := os.Open("todo.txt")
+//	informe, err := gestor.Importar(archivo, FormatoTodoTxt, true)
+//	if err == nil {
+//		fmt.Println("Simulación:", informe) // "12 por crear, 2 duplicada(s), 1 error(es)"
+//	}
+//
+func (g *GestorTareas) Importar(r io.Reader, formato Formato, simular bool) (*InformeImportacion, error) {
+	var leidas []tareaLeida
+	var errores []ErrorLinea
+	var err error
+	switch formato {
+	case FormatoCSV:
+		leidas, errores, err = leerCSV(r)
+	case FormatoMarkdown:
+		leidas, errores, err = leerMarkdown(r)
+	case FormatoTodoTxt:
+		leidas, errores, err = leerTodoTxt(r)
+	case FormatoICalendar:
+		leidas, errores, err = leerICalendar(r)
+	default:
+		return nil, errorFormato(string(formato))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al leer el archivo a importar: %v", err)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	informe := &InformeImportacion{Simulacion: simular, Errores: errores}
+	planificadas := g.planificarImportacion(leidas, informe)
+	slices.SortFunc(informe.Errores, func(a, b ErrorLinea) int { return a.Linea - b.Linea })
+	if simular {
+		informe.Creadas = planificadas
+		return informe, nil
+	}
+
+	var cambios []cambioTarea
+	defer func() { g.registrarOperacionCompuesta(OperacionImportar, cambios) }()
+	for _, tarea := range planificadas {
+		if tarea.UID == "" {
+			uid, err := g.nuevoUID(tarea.FechaCreacion)
+			if err != nil {
+				return informe, err
+			}
+			tarea.UID = uid
+		}
+		if err := g.escribirDiario(entradaRegistro{Op: opGuardar, Tarea: &tarea}); err != nil {
+			return informe, err
+		}
+		g.agregarTarea(tarea)
+		g.proximoID++
+		g.registrarGuardado(tarea)
+		cambios = append(cambios, nuevoCambio(nil, &tarea, len(g.tareas)-1))
+		informe.Creadas = append(informe.Creadas, tarea.clonar())
+	}
+	return informe, nil
+}
+
+// planificarImportacion decide qué tareas leídas se crean y con qué ID,
+// anotando en el informe las duplicadas y las que no superan la validación.
+// No modifica el gestor. Debe llamarse con g.mu tomado.
+func (g *GestorTareas) planificarImportacion(leidas []tareaLeida, informe *InformeImportacion) []Tarea {
+	titulos := make(map[string]int)
+	for _, t := range g.tareas {
+		if !t.EnPapelera() {
+			titulos[normalizarTexto(strings.TrimSpace(t.Titulo))] = t.ID
+		}
+	}
+
+	uids := make(map[string]int) // UID → ID de las tareas planificadas
+
+	ahora := g.reloj()
+	ids := make([]int, len(leidas)) // ID de cada tarea leída (0 si no se importa)
+	var planificadas []Tarea
+	for _, i := range ordenPadresPrimero(leidas, informe) {
+		leida := leidas[i]
+		if err := leida.datos.Validar(); err != nil {
+			informe.Errores = append(informe.Errores, ErrorLinea{leida.linea, err.Error()})
+			continue
+		}
+		padreID := 0
+		if leida.padre >= 0 {
+			if padreID = ids[leida.padre]; padreID == 0 {
+				informe.Errores = append(informe.Errores, ErrorLinea{leida.linea, fmt.Sprintf("la tarea padre (línea %d) no se importó", leidas[leida.padre].linea)})
+				continue
+			}
+		} else if leida.padreUID != "" {
+			if padreID = g.indice.uids[leida.padreUID]; padreID == 0 || g.indiceVisible(padreID) < 0 {
+				informe.Errores = append(informe.Errores, ErrorLinea{leida.linea, fmt.Sprintf("la tarea padre %q no existe", leida.padreUID)})
+				continue
+			}
+		}
+
+		// Un UID ya importado del archivo o de una tarea del gestor es la
+		// misma tarea; el de una tarea de la papelera no puede reutilizarse
+		existente, ok := uids[leida.uid]
+		if !ok && leida.uid != "" {
+			if existente, ok = g.indice.uids[leida.uid]; ok && g.indiceVisible(existente) < 0 {
+				informe.Errores = append(informe.Errores, ErrorLinea{leida.linea, fmt.Sprintf("el UID %q pertenece a la tarea %d, que está en la papelera", leida.uid, existente)})
+				continue
+			}
+		}
+		if ok {
+			informe.Duplicadas = append(informe.Duplicadas, Duplicada{leida.linea, leida.datos.Titulo, existente})
+			ids[i] = existente
+			continue
+		}
+
+		clave := normalizarTexto(strings.TrimSpace(leida.datos.Titulo))
+		if existente, ok := titulos[clave]; ok {
+			informe.Duplicadas = append(informe.Duplicadas, Duplicada{leida.linea, leida.datos.Titulo, existente})
+			ids[i] = existente
+			continue
+		}
+
+		tarea := Tarea{
+			ID:               g.proximoID + len(planificadas),
+			UID:              leida.uid,
+			Titulo:           strings.TrimSpace(leida.datos.Titulo),
+			Completada:       leida.completada,
+			FechaCreacion:    leida.fechaCreacion,
+			Descripcion:      strings.TrimSpace(leida.datos.Descripcion),
+			Prioridad:        leida.datos.Prioridad,
+			FechaVencimiento: leida.datos.FechaVencimiento,
+			Etiquetas:        normalizarEtiquetas(leida.datos.Etiquetas),
+			PadreID:          padreID,
+			Propietario:      g.usuario,
+		}
+		if tarea.FechaCreacion.IsZero() {
+			tarea.FechaCreacion = ahora
+		}
+		if tarea.Completada {
+			tarea.FechaCompletada = leida.fechaCompletada
+			if tarea.FechaCompletada.IsZero() {
+				tarea.FechaCompletada = ahora
+			}
+			// Sin fecha de creación en el archivo, no puede ser posterior al completado
+			if leida.fechaCreacion.IsZero() && tarea.FechaCompletada.Before(tarea.FechaCreacion) {
+				tarea.FechaCreacion = tarea.FechaCompletada
+			}
+		}
+
+		ids[i] = tarea.ID
+		titulos[clave] = tarea.ID
+		if tarea.UID != "" {
+			uids[tarea.UID] = tarea.ID
+		}
+		planificadas = append(planificadas, tarea)
+	}
+	return planificadas
+}
+
+// ordenPadresPrimero retorna los índices de leidas en el orden del archivo,
+// salvo que cada tarea se adelanta lo necesario para ir tras su padre. Las
+// tareas que forman un ciclo de padres se informan como errores y no se
+// incluyen.
+func ordenPadresPrimero(leidas []tareaLeida, informe *InformeImportacion) []int {
+	const (
+		sinVisitar = iota
+		visitando
+		visitada
+	)
+	estado := make([]int, len(leidas))
+	orden := make([]int, 0, len(leidas))
+
+	var visitar func(i int) bool
+	visitar = func(i int) bool {
+		switch estado[i] {
+		case visitando:
+			return false
+		case visitada:
+			return true
+		}
+		estado[i] = visitando
+		if p := leidas[i].padre; p >= 0 && !visitar(p) {
+			informe.Errores = append(informe.Errores, ErrorLinea{leidas[i].linea, "la tarea forma un ciclo de subtareas"})
+			return false
+		}
+		estado[i] = visitada
+		orden = append(orden, i)
+		return true
+	}
+	for i := range leidas {
+		visitar(i)
+	}
+	return orden
+}
+
+// leerCSV lee un CSV con cabecera. Solo la columna titulo es obligatoria;
+// id y padre_id, si están, sirven para reconstruir las subtareas.
+func leerCSV(r io.Reader) ([]tareaLeida, []ErrorLinea, error) {
+	lector := csv.NewReader(r)
+	lector.FieldsPerRecord = -1
+	cabecera, err := lector.Read()
+	if err == io.EOF {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	columnas := make(map[string]int)
+	for i, nombre := range cabecera {
+		columnas[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(nombre, "\ufeff")))] = i
+	}
+	if _, ok := columnas["titulo"]; !ok {
+		return nil, []ErrorLinea{{1, "la cabecera no tiene la columna titulo"}}, nil
+	}
+
+	var leidas []tareaLeida
+	var errores []ErrorLinea
+	porID := make(map[string]int)  // id del archivo → índice en leidas
+	padres := make(map[int]string) // índice en leidas → padre_id del archivo
+	for {
+		fila, err := lector.Read()
+		if err == io.EOF {
+			break
+		}
+		var errCSV *csv.ParseError
+		if errors.As(err, &errCSV) {
+			errores = append(errores, ErrorLinea{errCSV.StartLine, errCSV.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		linea, _ := lector.FieldPos(0)
+
+		campo := func(nombre string) string {
+			if i, ok := columnas[nombre]; ok && i < len(fila) {
+				return strings.TrimSpace(fila[i])
+			}
+			return ""
+		}
+
+		leida, err := leerFilaCSV(campo)
+		if err != nil {
+			errores = append(errores, ErrorLinea{linea, err.Error()})
+			continue
+		}
+		leida.linea = linea
+		if id := campo("id"); id != "" {
+			porID[id] = len(leidas)
+		}
+		if padre := campo("padre_id"); padre != "" {
+			padres[len(leidas)] = padre
+		}
+		leidas = append(leidas, leida)
+	}
+
+	// Resolvemos padre_id una vez leídas todas las filas: el padre puede
+	// aparecer después que la subtarea
+	var validas []tareaLeida
+	nuevoIndice := make([]int, len(leidas))
+	for i := range leidas {
+		nuevoIndice[i] = -1
+		if padre, ok := padres[i]; ok {
+			p, existe := porID[padre]
+			if !existe {
+				errores = append(errores, ErrorLinea{leidas[i].linea, fmt.Sprintf("la tarea padre %s no está en el archivo", padre)})
+				continue
+			}
+			leidas[i].padre = p
+		}
+		nuevoIndice[i] = len(validas)
+		validas = append(validas, leidas[i])
+	}
+	for i := range validas {
+		if p := validas[i].padre; p >= 0 {
+			validas[i].padre = nuevoIndice[p]
+		}
+	}
+	// Una subtarea cuyo padre se descartó también se descarta, al planificar
+	return validas, errores, nil
+}
+
+// leerFilaCSV interpreta los campos de una fila; campo retorna el valor de
+// una columna por su nombre ("" si no existe).
+func leerFilaCSV(campo func(string) string) (tareaLeida, error) {
+	leida := tareaLeida{padre: -1, uid: campo("uid")}
+	leida.datos.Titulo = campo("titulo")
+	leida.datos.Descripcion = campo("descripcion")
+	leida.datos.Prioridad = Prioridad(strings.ToLower(campo("prioridad")))
+	if etiquetas := campo("etiquetas"); etiquetas != "" {
+		leida.datos.Etiquetas = strings.Split(etiquetas, ",")
+	}
+
+	if completada := campo("completada"); completada != "" {
+		valor, err := strconv.ParseBool(completada)
+		if err != nil {
+			return leida, fmt.Errorf("completada debe ser true o false, no %q", completada)
+		}
+		leida.completada = valor
+	}
+
+	fechas := []struct {
+		columna string
+		destino *time.Time
+		alFinal bool // con solo el día, al final de ese día
+	}{
+		{"fecha_creacion", &leida.fechaCreacion, false},
+		{"fecha_vencimiento", &leida.datos.FechaVencimiento, true},
+		{"fecha_completada", &leida.fechaCompletada, false},
+	}
+	for _, f := range fechas {
+		texto := campo(f.columna)
+		if texto == "" {
+			continue
+		}
+		fecha, err := time.Parse(time.RFC3339, texto)
+		if err != nil {
+			if fecha, err = time.ParseInLocation(time.DateOnly, texto, time.Local); err != nil {
+				return leida, fmt.Errorf("%s inválida %q: usa RFC 3339 o aaaa-mm-dd", f.columna, texto)
+			}
+			if f.alFinal {
+				fecha = FinDelDia(fecha)
+			}
+		}
+		*f.destino = fecha
+	}
+	return leida, nil
+}
+
+// elementoMarkdown reconoce un elemento de lista con casilla: sangría,
+// viñeta, casilla y texto.
+var elementoMarkdown = regexp.MustCompile(`^(\s*)[-*+] \[([ xX])\]\s+(.*)$`)
+
+// leerMarkdown lee una lista de casillas. Los títulos (# ...) y las líneas
+// en blanco se ignoran; cualquier otra línea es un error. Las etiquetas son
+// las palabras #etiqueta al final del texto, y un elemento más sangrado que
+// el anterior es su subtarea.
+func leerMarkdown(r io.Reader) ([]tareaLeida, []ErrorLinea, error) {
+	var leidas []tareaLeida
+	var errores []ErrorLinea
+
+	// Tareas abiertas en cada nivel de sangría, de menos a más sangradas
+	type abierta struct {
+		sangria int
+		indice  int
+	}
+	var pila []abierta
+
+	escaner := bufio.NewScanner(r)
+	for numero := 1; escaner.Scan(); numero++ {
+		linea := strings.TrimRight(escaner.Text(), " \t\r")
+		texto := strings.TrimSpace(linea)
+		if texto == "" || strings.HasPrefix(texto, "#") {
+			continue
+		}
+		partes := elementoMarkdown.FindStringSubmatch(linea)
+		if partes == nil {
+			errores = append(errores, ErrorLinea{numero, "no es un elemento de lista con casilla, como \"- [ ] título\""})
+			continue
+		}
+
+		sangria := len(strings.ReplaceAll(partes[1], "\t", "    "))
+		for len(pila) > 0 && pila[len(pila)-1].sangria >= sangria {
+			pila = pila[:len(pila)-1]
+		}
+		leida := tareaLeida{linea: numero, completada: partes[2] != " ", padre: -1}
+		if len(pila) > 0 {
+			leida.padre = pila[len(pila)-1].indice
+		}
+
+		palabras := strings.Fields(partes[3])
+		for len(palabras) > 1 && len(palabras[len(palabras)-1]) > 1 && strings.HasPrefix(palabras[len(palabras)-1], "#") {
+			leida.datos.Etiquetas = append([]string{palabras[len(palabras)-1][1:]}, leida.datos.Etiquetas...)
+			palabras = palabras[:len(palabras)-1]
+		}
+		leida.datos.Titulo = strings.Join(palabras, " ")
+
+		pila = append(pila, abierta{sangria, len(leidas)})
+		leidas = append(leidas, leida)
+	}
+	if err := escaner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return leidas, errores, nil
+}
+
+// prioridadTodoTxt reconoce la prioridad de todo.txt, como "(A)".
+var prioridadTodoTxt = regexp.MustCompile(`^\(([A-Z])\)$`)
+
+// leerTodoTxt lee una tarea por línea con el formato de todo.txt. Las
+// prioridades A, B, C y D son urgente, alta, media y baja (de la E en
+// adelante, baja); +proyecto y @contexto se importan como etiquetas.
+func leerTodoTxt(r io.Reader) ([]tareaLeida, []ErrorLinea, error) {
+	var leidas []tareaLeida
+	var errores []ErrorLinea
+
+	escaner := bufio.NewScanner(r)
+	for numero := 1; escaner.Scan(); numero++ {
+		campos := strings.Fields(escaner.Text())
+		if len(campos) == 0 {
+			continue
+		}
+		leida, err := leerLineaTodoTxt(campos)
+		if err != nil {
+			errores = append(errores, ErrorLinea{numero, err.Error()})
+			continue
+		}
+		leida.linea = numero
+		leidas = append(leidas, leida)
+	}
+	if err := escaner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return leidas, errores, nil
+}
+
+// leerLineaTodoTxt interpreta los campos de una línea de todo.txt.
+func leerLineaTodoTxt(campos []string) (tareaLeida, error) {
+	leida := tareaLeida{padre: -1}
+	fecha := func() (time.Time, bool) {
+		if len(campos) == 0 {
+			return time.Time{}, false
+		}
+		f, err := time.ParseInLocation(time.DateOnly, campos[0], time.Local)
+		if err != nil {
+			return time.Time{}, false
+		}
+		campos = campos[1:]
+		return f, true
+	}
+
+	if campos[0] == "x" {
+		leida.completada = true
+		campos = campos[1:]
+		if f, ok := fecha(); ok {
+			leida.fechaCompletada = f
+			leida.fechaCreacion, _ = fecha()
+		}
+	} else {
+		if partes := prioridadTodoTxt.FindStringSubmatch(campos[0]); partes != nil {
+			leida.datos.Prioridad = prioridadDeLetra(partes[1])
+			campos = campos[1:]
+		}
+		leida.fechaCreacion, _ = fecha()
+	}
+
+	var titulo []string
+	for _, campo := range campos {
+		clave, valor, esClave := strings.Cut(campo, ":")
+		switch {
+		case len(campo) > 1 && (campo[0] == '+' || campo[0] == '@'):
+			leida.datos.Etiquetas = append(leida.datos.Etiquetas, campo[1:])
+		case esClave && clave == "due":
+			vence, err := time.ParseInLocation(time.DateOnly, valor, time.Local)
+			if err != nil {
+				return leida, fmt.Errorf("fecha de vencimiento inválida %q: usa due:aaaa-mm-dd", valor)
+			}
+			leida.datos.FechaVencimiento = FinDelDia(vence)
+		case esClave && clave == "pri" && len(valor) == 1 && valor[0] >= 'A' && valor[0] <= 'Z':
+			leida.datos.Prioridad = prioridadDeLetra(valor)
+		default:
+			titulo = append(titulo, campo)
+		}
+	}
+	leida.datos.Titulo = strings.Join(titulo, " ")
+	return leida, nil
+}
+
+// prioridadDeLetra traduce una prioridad de todo.txt (A-Z).
+func prioridadDeLetra(letra string) Prioridad {
+	for prioridad, l := range letrasPrioridad {
+		if l == letra {
+			return prioridad
+		}
+	}
+	return PrioridadBaja
+}
@@ -0,0 +1,226 @@
+// Tests de importación de tareas
+
+package tareas
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestImportarIdaYVuelta verifica que lo exportado en cada formato se
+// importe en un gestor vacío con los mismos datos
+func TestImportarIdaYVuelta(t *testing.T) {
+	origen := crearTareasExportacion(t)
+
+	for _, formato := range []Formato{FormatoCSV, FormatoMarkdown, FormatoTodoTxt} {
+		t.Run(string(formato), func(t *testing.T) {
+			var archivo bytes.Buffer
+			if err := Exportar(&archivo, origen.Listar(), formato); err != nil {
+				t.Fatalf("Error al exportar: %v", err)
+			}
+			destino := nuevoGestorEnMemoria(t)
+			informe, err := destino.Importar(&archivo, formato, false)
+			if err != nil {
+				t.Fatalf("Error al importar: %v", err)
+			}
+			if len(informe.Creadas) != 3 || len(informe.Duplicadas) != 0 || len(informe.Errores) != 0 {
+				t.Fatalf("Informe inesperado: %s %+v", informe, informe.Errores)
+			}
+
+			for _, esperada := range origen.Listar() {
+				obtenida, err := destino.BuscarPorID(esperada.ID)
+				if err != nil {
+					t.Fatalf("No se importó la tarea %d: %v", esperada.ID, err)
+				}
+				if obtenida.Titulo != esperada.Titulo || obtenida.Completada != esperada.Completada ||
+					!slices.Equal(obtenida.Etiquetas, esperada.Etiquetas) {
+					t.Errorf("Tarea %d importada distinta:\nobtenida: %+v\nesperada: %+v", esperada.ID, obtenida, esperada)
+				}
+				// todo.txt no guarda subtareas; Markdown, ni fechas ni prioridad
+				if formato != FormatoTodoTxt && obtenida.PadreID != esperada.PadreID {
+					t.Errorf("Tarea %d importada con padre %d, se esperaba %d", esperada.ID, obtenida.PadreID, esperada.PadreID)
+				}
+				if formato == FormatoMarkdown {
+					continue
+				}
+				mismaFecha := func(a, b time.Time) bool {
+					if formato == FormatoTodoTxt {
+						return formatearFechaTodoTxt(a) == formatearFechaTodoTxt(b) && a.IsZero() == b.IsZero()
+					}
+					return a.Equal(b)
+				}
+				if obtenida.Prioridad != esperada.Prioridad || !mismaFecha(obtenida.FechaVencimiento, esperada.FechaVencimiento) ||
+					!mismaFecha(obtenida.FechaCreacion, esperada.FechaCreacion) || !mismaFecha(obtenida.FechaCompletada, esperada.FechaCompletada) {
+					t.Errorf("Tarea %d importada con fechas o prioridad distintas:\nobtenida: %+v\nesperada: %+v", esperada.ID, obtenida, esperada)
+				}
+				if formato == FormatoCSV && obtenida.Descripcion != esperada.Descripcion {
+					t.Errorf("Descripción distinta: %q", obtenida.Descripcion)
+				}
+			}
+		})
+	}
+}
+
+// TestImportarSimulacionYDuplicadas verifica el informe de una simulación,
+// la detección de duplicadas y que deshacer revierta la importación
+func TestImportarSimulacionYDuplicadas(t *testing.T) {
+	ahora := time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)
+	gestor := nuevoGestorEnMemoria(t, ConReloj(func() time.Time { return ahora }))
+	gestor.Crear("Comprar pan")
+
+	archivo := `# Compras
+
+- [ ] Comprar PAN
+  - [ ] Integral
+- [ ] Ir al mercado #compras
+  - [x] Llevar bolsas
+- [ ] ir al MERCADO
+`
+	informe, err := gestor.Importar(strings.NewReader(archivo), FormatoMarkdown, true)
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if !informe.Simulacion || len(gestor.Listar()) != 1 {
+		t.Fatal("La simulación no debería crear tareas")
+	}
+	if informe.String() != "3 por crear, 2 duplicada(s), 0 error(es)" {
+		t.Errorf("Resumen inesperado: %s", informe)
+	}
+	esperadas := []Duplicada{{3, "Comprar PAN", 1}, {7, "ir al MERCADO", 3}}
+	if !slices.Equal(informe.Duplicadas, esperadas) {
+		t.Errorf("Duplicadas: se esperaban %+v, se obtuvieron %+v", esperadas, informe.Duplicadas)
+	}
+	// La subtarea de una duplicada se cuelga de la tarea existente
+	if sub := informe.Creadas[0]; sub.Titulo != "Integral" || sub.ID != 2 || sub.PadreID != 1 {
+		t.Errorf("Subtarea de duplicada inesperada: %+v", sub)
+	}
+	if bolsas := informe.Creadas[2]; !bolsas.Completada || bolsas.PadreID != 3 || !bolsas.FechaCompletada.Equal(ahora) {
+		t.Errorf("Subtarea completada inesperada: %+v", bolsas)
+	}
+
+	// La importación real crea lo mismo que anunció la simulación
+	real, err := gestor.Importar(strings.NewReader(archivo), FormatoMarkdown, false)
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if real.Simulacion || !slices.Equal(idsDe(real.Creadas), idsDe(informe.Creadas)) {
+		t.Errorf("La importación debería crear %v, creó %v", idsDe(informe.Creadas), idsDe(real.Creadas))
+	}
+	if len(gestor.Listar()) != 4 {
+		t.Errorf("Se esperaban 4 tareas tras importar, hay %d", len(gestor.Listar()))
+	}
+
+	// Deshacer revierte la importación completa en un paso
+	op, err := gestor.Deshacer()
+	if err != nil || op.Tipo != OperacionImportar || op.String() != "importar 3 tarea(s)" {
+		t.Fatalf("Se esperaba deshacer la importación, se obtuvo: %v, %v", op, err)
+	}
+	if len(gestor.Listar()) != 1 {
+		t.Errorf("Tras deshacer debería quedar 1 tarea, hay %d", len(gestor.Listar()))
+	}
+	comprobarIndice(t, gestor, "deshacer importación")
+}
+
+// TestImportarErroresPorLinea verifica que cada línea inválida se informe
+// con su número sin impedir importar el resto
+func TestImportarErroresPorLinea(t *testing.T) {
+	tests := []struct {
+		nombre  string
+		formato Formato
+		archivo string
+		creadas []string
+		lineas  []int
+	}{
+		{
+			nombre:  "csv",
+			formato: FormatoCSV,
+			archivo: "titulo,prioridad,completada,fecha_vencimiento,id,padre_id\n" +
+				"Válida,alta,false,2026-04-01,1,\n" +
+				"Prioridad mala,altisima,,,2,\n" +
+				"Fecha mala,,,mañana,3,\n" +
+				",,,,4,\n" +
+				"Hija de la válida,,no,,5,1\n" +
+				"Hija de nadie,,,,6,99\n" +
+				"Hija de la mala,,,,7,2\n" +
+				"\"sin cerrar,,,,8,\n",
+			creadas: []string{"Válida"},
+			lineas:  []int{3, 4, 5, 6, 7, 8, 9},
+		},
+		{
+			nombre:  "csv sin titulo",
+			formato: FormatoCSV,
+			archivo: "id,descripcion\n1,algo\n",
+			lineas:  []int{1},
+		},
+		{
+			nombre:  "markdown",
+			formato: FormatoMarkdown,
+			archivo: "# Lista\n\n- [ ] Una\nTexto suelto\n- [?] Casilla rara\n  - [x] Otra #etiqueta\n",
+			creadas: []string{"Una", "Otra"},
+			lineas:  []int{4, 5},
+		},
+		{
+			nombre:  "todotxt",
+			formato: FormatoTodoTxt,
+			archivo: "(B) Llamar a Ana @telefono\n\nx 2026-03-01 Pagar luz\nRenovar DNI due:pronto\n(Z) Leer +libros\n",
+			creadas: []string{"Llamar a Ana", "Pagar luz", "Leer"},
+			lineas:  []int{4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			gestor := nuevoGestorEnMemoria(t)
+			informe, err := gestor.Importar(strings.NewReader(tt.archivo), tt.formato, false)
+			if err != nil {
+				t.Fatalf("Error inesperado: %v", err)
+			}
+			var titulos []string
+			for _, tarea := range informe.Creadas {
+				titulos = append(titulos, tarea.Titulo)
+			}
+			var lineas []int
+			for _, e := range informe.Errores {
+				lineas = append(lineas, e.Linea)
+			}
+			if !slices.Equal(titulos, tt.creadas) || !slices.Equal(lineas, tt.lineas) {
+				t.Errorf("Se esperaban creadas %q y errores en %v; se obtuvieron %q y %v", tt.creadas, tt.lineas, titulos, informe.Errores)
+			}
+		})
+	}
+
+	// Prioridades y etiquetas de todo.txt
+	gestor := nuevoGestorEnMemoria(t)
+	gestor.Importar(strings.NewReader("(B) Llamar @telefono +trabajo\n(Z) Leer\nx 2026-03-02 2026-03-01 Pagar pri:A\n"), FormatoTodoTxt, false)
+	tareas := gestor.Listar()
+	if tareas[0].Prioridad != PrioridadAlta || !slices.Equal(tareas[0].Etiquetas, []string{"telefono", "trabajo"}) ||
+		tareas[1].Prioridad != PrioridadBaja || tareas[2].Prioridad != PrioridadUrgente ||
+		tareas[2].FechaCompletada.Format(time.DateOnly) != "2026-03-02" || tareas[2].FechaCreacion.Format(time.DateOnly) != "2026-03-01" {
+		t.Errorf("Campos de todo.txt mal interpretados: %+v", tareas)
+	}
+
+	// Un vencimiento con solo el día vence al final de ese día; el resto de
+	// fechas sin hora empiezan el día
+	finDelDia := time.Date(2026, 3, 12, 23, 59, 59, 0, time.Local)
+	for _, tt := range []struct {
+		formato Formato
+		archivo string
+	}{
+		{FormatoTodoTxt, "Preparar viaje due:2026-03-12\n"},
+		{FormatoCSV, "titulo,fecha_creacion,fecha_vencimiento\nPreparar viaje,2026-03-01,2026-03-12\n"},
+	} {
+		gestor := nuevoGestorEnMemoria(t)
+		if _, err := gestor.Importar(strings.NewReader(tt.archivo), tt.formato, false); err != nil {
+			t.Fatalf("Error al importar %s: %v", tt.formato, err)
+		}
+		tarea := gestor.Listar()[0]
+		if !tarea.FechaVencimiento.Equal(finDelDia) {
+			t.Errorf("%s: vencimiento = %v, se esperaba %v", tt.formato, tarea.FechaVencimiento, finDelDia)
+		}
+		if tt.formato == FormatoCSV && !tarea.FechaCreacion.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)) {
+			t.Errorf("%s: creación = %v, se esperaba el inicio del día", tt.formato, tarea.FechaCreacion)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package tareas
+
+import (
+	"slices"
+)
+
+// indiceTareas acelera las búsquedas del gestor sobre colecciones grandes.
+//
+// Mantiene la posición de cada tarea en g.tareas por ID y por UID, y un
+// índice invertido que asocia cada palabra normalizada (ver palabras) del
+// título, la descripción o las etiquetas con las tareas que la contienen.
+// Así BuscarPorID no recorre la colección, y BuscarPorTexto y
+// BuscarPorRelevancia solo examinan las tareas que comparten palabras con
+// el texto buscado.
+//
+// El gestor lo actualiza en cada mutación mediante agregarTarea,
+// sustituirTarea, insertarTarea y quitarTarea, o lo reconstruye entero
+// con reconstruirIndice tras cargar, reproducir el diario o purgar. Las
+// tareas de la papelera siguen indexadas: quien consulta el índice decide
+// si las descarta.
+type indiceTareas struct {
+	// posiciones asocia cada ID con su índice en g.tareas
+	posiciones map[int]int
+
+	// uids asocia cada UID no vacío con el ID de su tarea
+	uids map[string]int
+
+	// terminos asocia cada palabra con el conjunto de IDs que la contienen
+	terminos map[string]map[int]struct{}
+}
+
+// nuevoIndiceTareas crea un índice vacío.
+func nuevoIndiceTareas() indiceTareas {
+	return indiceTareas{
+		posiciones: make(map[int]int),
+		uids:       make(map[string]int),
+		terminos:   make(map[string]map[int]struct{}),
+	}
+}
+
+// indexar añade la tarea, que ocupa posicion en la colección.
+func (ix *indiceTareas) indexar(tarea Tarea, posicion int) {
+	ix.posiciones[tarea.ID] = posicion
+	if tarea.UID != "" {
+		ix.uids[tarea.UID] = tarea.ID
+	}
+	for _, termino := range terminosDe(tarea) {
+		ids, ok := ix.terminos[termino]
+		if !ok {
+			ids = make(map[int]struct{})
+			ix.terminos[termino] = ids
+		}
+		ids[tarea.ID] = struct{}{}
+	}
+}
+
+// desindexar quita la tarea del índice. Las palabras que dejan de
+// pertenecer a alguna tarea desaparecen del vocabulario.
+func (ix *indiceTareas) desindexar(tarea Tarea) {
+	delete(ix.posiciones, tarea.ID)
+	if ix.uids[tarea.UID] == tarea.ID {
+		delete(ix.uids, tarea.UID)
+	}
+	for _, termino := range terminosDe(tarea) {
+		delete(ix.terminos[termino], tarea.ID)
+		if len(ix.terminos[termino]) == 0 {
+			delete(ix.terminos, termino)
+		}
+	}
+}
+
+// candidatos retorna los IDs de las tareas con alguna palabra que cumpla
+// acepta. Recorre el vocabulario, no la colección: con muchas tareas hay
+// muchas menos palabras distintas que tareas.
+func (ix *indiceTareas) candidatos(acepta func(termino string) bool) map[int]struct{} {
+	encontrados := make(map[int]struct{})
+	for termino, ids := range ix.terminos {
+		if !acepta(termino) {
+			continue
+		}
+		for id := range ids {
+			encontrados[id] = struct{}{}
+		}
+	}
+	return encontrados
+}
+
+// terminosDe retorna las palabras distintas del título, la descripción y
+// las etiquetas de la tarea.
+func terminosDe(tarea Tarea) []string {
+	terminos := palabras(tarea.Titulo)
+	terminos = append(terminos, palabras(tarea.Descripcion)...)
+	for _, etiqueta := range tarea.Etiquetas {
+		terminos = append(terminos, palabras(etiqueta)...)
+	}
+	slices.Sort(terminos)
+	return slices.Compact(terminos)
+}
+
+// mismosTerminos indica si dos versiones de una tarea se indexan igual, para
+// no recalcular las palabras al completarla o cambiar otros campos.
+func mismosTerminos(a, b Tarea) bool {
+	return a.UID == b.UID && a.Titulo == b.Titulo && a.Descripcion == b.Descripcion &&
+		slices.Equal(a.Etiquetas, b.Etiquetas)
+}
+
+// reconstruirIndice vuelve a indexar la colección completa. Se usa tras los
+// cambios que reemplazan o reordenan muchas tareas a la vez.
+// Debe llamarse con g.mu tomado en modo escritura.
+func (g *GestorTareas) reconstruirIndice() {
+	g.indice = nuevoIndiceTareas()
+	for i, tarea := range g.tareas {
+		g.indice.indexar(tarea, i)
+	}
+}
+
+// agregarTarea añade una tarea al final de la colección y al índice.
+// Debe llamarse con g.mu tomado en modo escritura.
+func (g *GestorTareas) agregarTarea(tarea Tarea) {
+	g.tareas = append(g.tareas, tarea)
+	g.indice.indexar(tarea, len(g.tareas)-1)
+}
+
+// sustituirTarea sustituye la tarea de la posición i, actualizando sus
+// palabras en el índice solo si cambiaron.
+// Debe llamarse con g.mu tomado en modo escritura.
+func (g *GestorTareas) sustituirTarea(i int, tarea Tarea) {
+	if !mismosTerminos(g.tareas[i], tarea) {
+		g.indice.desindexar(g.tareas[i])
+		g.indice.indexar(tarea, i)
+	}
+	g.tareas[i] = tarea
+}
+
+// insertarTarea inserta una tarea en la posición i, desplazando las
+// siguientes. Cuesta lo que tarda en desplazarlas, así que es barato cuando
+// i está cerca del final, como al rehacer la creación de una tarea.
+// Debe llamarse con g.mu tomado en modo escritura.
+func (g *GestorTareas) insertarTarea(i int, tarea Tarea) {
+	g.tareas = slices.Insert(g.tareas, i, tarea)
+	for j := i + 1; j < len(g.tareas); j++ {
+		g.indice.posiciones[g.tareas[j].ID] = j
+	}
+	g.indice.indexar(tarea, i)
+}
+
+// quitarTarea elimina de la colección y del índice la tarea de la posición
+// i, desplazando las siguientes.
+// Debe llamarse con g.mu tomado en modo escritura.
+func (g *GestorTareas) quitarTarea(i int) {
+	g.indice.desindexar(g.tareas[i])
+	g.tareas = slices.Delete(g.tareas, i, i+1)
+	for j := i; j < len(g.tareas); j++ {
+		g.indice.posiciones[g.tareas[j].ID] = j
+	}
+}
+
+// tareasCandidatas retorna, en orden de creación, las tareas fuera de la
+// papelera que tienen para cada palabra de consulta alguna palabra que
+// cumpla coincide. Es un superconjunto de las que encuentran BuscarPorTexto
+// y BuscarPorRelevancia, que luego comprueban cada candidata.
+// Debe llamarse con g.mu tomado.
+func (g *GestorTareas) tareasCandidatas(consulta []string, coincide func(buscada, termino string) bool) []Tarea {
+	var ids map[int]struct{}
+	for _, buscada := range consulta {
+		encontrados := g.indice.candidatos(func(termino string) bool { return coincide(buscada, termino) })
+		if ids != nil {
+			for id := range ids {
+				if _, ok := encontrados[id]; !ok {
+					delete(ids, id)
+				}
+			}
+		} else {
+			ids = encontrados
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+	}
+
+	posiciones := make([]int, 0, len(ids))
+	for id := range ids {
+		posiciones = append(posiciones, g.indice.posiciones[id])
+	}
+	slices.Sort(posiciones)
+
+	var candidatas []Tarea
+	for _, i := range posiciones {
+		if !g.tareas[i].EnPapelera() {
+			candidatas = append(candidatas, g.tareas[i])
+		}
+	}
+	return candidatas
+}
@@ -0,0 +1,248 @@
+// Tests y benchmarks del índice de búsqueda
+
+package tareas
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// comprobarIndice verifica que el índice mantenido incrementalmente sea
+// idéntico al que se obtiene indexando la colección desde cero
+func comprobarIndice(t *testing.T, gestor *GestorTareas, paso string) {
+	t.Helper()
+	gestor.mu.RLock()
+	defer gestor.mu.RUnlock()
+
+	esperado := nuevoIndiceTareas()
+	for i, tarea := range gestor.tareas {
+		esperado.indexar(tarea, i)
+	}
+	if !reflect.DeepEqual(gestor.indice, esperado) {
+		t.Fatalf("Tras %s el índice no coincide con la colección:\nobtenido: %+v\nesperado: %+v", paso, gestor.indice, esperado)
+	}
+}
+
+// TestIndiceConsistente aplica cada tipo de mutación y comprueba el índice
+func TestIndiceConsistente(t *testing.T) {
+	ahora := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	gestor := nuevoGestorEnMemoria(t, ConGeneradorID(NuevoGeneradorUUID(nil)), ConReloj(func() time.Time { return ahora }))
+
+	nuevo := "Preparar la presentación"
+	pasos := []struct {
+		nombre string
+		accion func() error
+	}{
+		{"crear", func() error {
+			_, err := gestor.CrearConDatos(DatosTarea{Titulo: "Preparar informe", Descripcion: "Datos del trimestre", Etiquetas: []string{"trabajo"}})
+			return err
+		}},
+		{"crear subtarea", func() error {
+			_, err := gestor.CrearConDatos(DatosTarea{Titulo: "Revisar gráficos", PadreID: 1})
+			return err
+		}},
+		{"crear recurrente", func() error {
+			_, err := gestor.CrearConDatos(DatosTarea{Titulo: "Regar plantas", Recurrencia: &Recurrencia{Frecuencia: FrecuenciaDiaria}})
+			return err
+		}},
+		{"editar título", func() error {
+			_, err := gestor.Actualizar(1, CambiosTarea{Titulo: &nuevo})
+			return err
+		}},
+		{"completar recurrente", func() error {
+			_, err := gestor.CompletarConSiguiente(3)
+			return err
+		}},
+		{"agregar dependencia", func() error { return gestor.AgregarDependencia(4, 2) }},
+		{"eliminar con subtareas", func() error {
+			_, err := gestor.EliminarConSubtareas(1)
+			return err
+		}},
+		{"restaurar", func() error { return gestor.Restaurar(1) }},
+		{"deshacer", func() error {
+			_, err := gestor.Deshacer()
+			return err
+		}},
+		{"rehacer", func() error {
+			_, err := gestor.Rehacer()
+			return err
+		}},
+		{"deshacer completar (quita la ocurrencia)", func() error {
+			for range 4 {
+				if _, err := gestor.Deshacer(); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+		{"crear y deshacer", func() error {
+			gestor.Crear("Tarea final")
+			_, err := gestor.Deshacer()
+			return err
+		}},
+		{"vaciar papelera", func() error {
+			gestor.Eliminar(3)
+			_, err := gestor.VaciarPapelera()
+			return err
+		}},
+	}
+	for _, paso := range pasos {
+		if err := paso.accion(); err != nil {
+			t.Fatalf("Error al %s: %v", paso.nombre, err)
+		}
+		comprobarIndice(t, gestor, paso.nombre)
+	}
+
+	// Tras todo lo anterior, las búsquedas por índice siguen encontrando las tareas
+	if tareas := gestor.BuscarPorTexto("presentacion"); len(tareas) != 1 || tareas[0].ID != 1 {
+		t.Errorf("Se esperaba encontrar la tarea 1 por su título editado, se obtuvo: %+v", tareas)
+	}
+	if tareas := gestor.BuscarPorTexto("regar"); len(tareas) != 0 {
+		t.Errorf("Las tareas purgadas no deberían encontrarse: %+v", tareas)
+	}
+	tarea, _ := gestor.BuscarPorID(2)
+	if encontrada, err := gestor.BuscarPorUID(tarea.UID); err != nil || encontrada.ID != 2 {
+		t.Errorf("Se esperaba encontrar la tarea 2 por su UID, se obtuvo: %+v, %v", encontrada, err)
+	}
+}
+
+// TestBuscarPorTextoEntrePalabras verifica que el índice no pierda
+// coincidencias que cruzan palabras o están dentro de una palabra
+func TestBuscarPorTextoEntrePalabras(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t)
+	gestor.Crear("Comprar leche")
+	gestor.Crear("Leer: capítulo 3")
+
+	tests := []struct {
+		texto     string
+		esperados int
+	}{
+		{"ar le", 1},
+		{"mpra", 1},
+		{"r: cap", 1},
+		{"le", 2},
+		{":", 1},
+		{"lech leche", 0},
+	}
+	for _, tt := range tests {
+		if encontradas := gestor.BuscarPorTexto(tt.texto); len(encontradas) != tt.esperados {
+			t.Errorf("BuscarPorTexto(%q): se esperaban %d resultados, se obtuvieron: %d", tt.texto, tt.esperados, len(encontradas))
+		}
+	}
+}
+
+// palabrasBenchmark forman los títulos de las tareas de los benchmarks, de
+// modo que el vocabulario no crece con la cantidad de tareas
+var palabrasBenchmark = strings.Fields("revisar preparar enviar llamar comprar " +
+	"informe factura correo cliente proveedor reunión presupuesto contrato " +
+	"semanal mensual urgente pendiente equipo proyecto oficina")
+
+// gestorBenchmark crea un gestor en memoria con n tareas; solo la tarea del
+// medio menciona el pasaporte
+func gestorBenchmark(b *testing.B, n int) *GestorTareas {
+	b.Helper()
+	iniciales := make([]Tarea, n)
+	for i := range iniciales {
+		titulo := fmt.Sprintf("%s %s %s",
+			palabrasBenchmark[i%5], palabrasBenchmark[5+i%8], palabrasBenchmark[13+i%7])
+		if i == n/2 {
+			titulo = "Renovar pasaporte"
+		}
+		iniciales[i] = Tarea{ID: i + 1, Titulo: titulo, FechaCreacion: time.Now()}
+	}
+	gestor, err := NuevoGestorTareas("", ConAlmacenamiento(NuevoAlmacenamientoMemoria(iniciales...)))
+	if err != nil {
+		b.Fatalf("Error al crear gestor: %v", err)
+	}
+	return gestor
+}
+
+// buscarPorIDLineal es la búsqueda por ID anterior al índice, como referencia
+func buscarPorIDLineal(g *GestorTareas, id int) *Tarea {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	for _, tarea := range g.tareas {
+		if tarea.ID == id && !tarea.EnPapelera() {
+			tarea = tarea.clonar()
+			return &tarea
+		}
+	}
+	return nil
+}
+
+// buscarPorTextoLineal es la búsqueda por texto anterior al índice, como referencia
+func buscarPorTextoLineal(g *GestorTareas, texto string) []Tarea {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	var encontradas []Tarea
+	for _, tarea := range g.tareas {
+		if contieneNormalizado(tarea.Titulo, texto) && !tarea.EnPapelera() {
+			encontradas = append(encontradas, tarea.clonar())
+		}
+	}
+	return encontradas
+}
+
+// BenchmarkBuscarPorIDIndice compara la búsqueda por ID con índice y con
+// recorrido lineal al crecer la colección: con índice el tiempo se mantiene
+func BenchmarkBuscarPorIDIndice(b *testing.B) {
+	for _, n := range []int{1_000, 10_000, 100_000} {
+		gestor := gestorBenchmark(b, n)
+		ultima := n // el peor caso del recorrido lineal
+
+		b.Run(fmt.Sprintf("indice/%d", n), func(b *testing.B) {
+			for range b.N {
+				if _, err := gestor.BuscarPorID(ultima); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("lineal/%d", n), func(b *testing.B) {
+			for range b.N {
+				if buscarPorIDLineal(gestor, ultima) == nil {
+					b.Fatal("tarea no encontrada")
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkBuscarPorTextoIndice compara la búsqueda de una palabra poco
+// frecuente con índice invertido y con recorrido lineal: con índice el
+// tiempo depende del vocabulario y de los resultados, no de las tareas
+func BenchmarkBuscarPorTextoIndice(b *testing.B) {
+	for _, n := range []int{1_000, 10_000, 100_000} {
+		gestor := gestorBenchmark(b, n)
+
+		b.Run(fmt.Sprintf("indice/%d", n), func(b *testing.B) {
+			for range b.N {
+				if len(gestor.BuscarPorTexto("pasaporte")) != 1 {
+					b.Fatal("se esperaba un resultado")
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("lineal/%d", n), func(b *testing.B) {
+			for range b.N {
+				if len(buscarPorTextoLineal(gestor, "pasaporte")) != 1 {
+					b.Fatal("se esperaba un resultado")
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkBuscarPorRelevancia mide la búsqueda aproximada sobre una
+// colección grande, que gracias al índice solo puntúa las candidatas
+func BenchmarkBuscarPorRelevancia(b *testing.B) {
+	gestor := gestorBenchmark(b, 100_000)
+
+	b.ResetTimer()
+	for range b.N {
+		if len(gestor.BuscarPorRelevancia("pasaprte", ToleranciaPorDefecto)) != 1 {
+			b.Fatal("se esperaba un resultado")
+		}
+	}
+}
@@ -0,0 +1,529 @@
+package tareas
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// ListaPorDefecto es el nombre de la lista de las tareas que no pertenecen a
+// ninguna otra. Todas las tareas de un tareas.json anterior a las listas
+// están en ella. No puede renombrarse ni eliminarse.
+const ListaPorDefecto = "general"
+
+// ResumenLista describe una lista y cuenta sus tareas, sin las de la papelera.
+//
+// Los tags json permiten entregarlo directamente como respuesta de la API HTTP.
+type ResumenLista struct {
+	Nombre      string `json:"nombre"`
+	Total       int    `json:"total"`
+	Completadas int    `json:"completadas"`
+	Pendientes  int    `json:"pendientes"`
+}
+
+// NombreLista retorna el nombre de la lista de la tarea, ListaPorDefecto si
+// no pertenece a ninguna otra.
+func (t Tarea) NombreLista() string {
+	if t.Lista == "" {
+		return ListaPorDefecto
+	}
+	return t.Lista
+}
+
+// ValidarLista valida el nombre de una lista.
+//
+// Como las etiquetas, debe tener entre 1 y 30 caracteres y no puede
+// contener espacios ni comas; además no puede contener ':', para poder
+// usarse en las consultas (lista:nombre).
+//
+func ValidarLista(nombre string) error {
+	nombre = strings.TrimSpace(nombre)
+	if nombre == "" {
+		return &ErrorValidacion{"el nombre de la lista no puede estar vacío"}
+	}
+	if utf8.RuneCountInString(nombre) > 30 {
+		return &ErrorValidacion{fmt.Sprintf("el nombre de la lista %q no puede exceder 30 caracteres", nombre)}
+	}
+	if strings.ContainsAny(nombre, " \t,:") {
+		return &ErrorValidacion{fmt.Sprintf("el nombre de la lista %q no puede contener espacios, comas ni ':'", nombre)}
+	}
+	return nil
+}
+
+// normalizarLista recorta y pasa a minúsculas el nombre de una lista, y
+// traduce ListaPorDefecto a "", el valor con que se guarda en Tarea.Lista.
+func normalizarLista(nombre string) string {
+	nombre = strings.ToLower(strings.TrimSpace(nombre))
+	if nombre == ListaPorDefecto {
+		return ""
+	}
+	return nombre
+}
+
+// Listas retorna todas las listas con el recuento de sus tareas.
+//
+// La primera es siempre ListaPorDefecto; le siguen las creadas con
+// CrearLista, en orden de creación, y por último las que solo aparecen en
+// las tareas (por ejemplo, al cargar un archivo editado a mano), en el
+// orden en que aparecen.
+//
+// Retorna:
+//   - []ResumenLista: las listas, con al menos la lista por defecto
+//
+// Ejemplo:
+//
+//	for _, lista := range gestor.Listas() {
+//		fmt.Printf("%s: %d pendiente(s)\n", lista.Nombre, lista.Pendientes)
+//	}
+//
+func (g *GestorTareas) Listas() []ResumenLista {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	nombres := g.nombresListas()
+	resumenes := make([]ResumenLista, len(nombres))
+	posiciones := make(map[string]int, len(nombres))
+	for i, nombre := range nombres {
+		resumenes[i].Nombre = nombre
+		posiciones[nombre] = i
+	}
+	resumenes[0].Nombre = ListaPorDefecto
+
+	for _, tarea := range g.tareas {
+		if tarea.EnPapelera() {
+			continue
+		}
+		resumen := &resumenes[posiciones[tarea.Lista]]
+		resumen.Total++
+		if tarea.Completada {
+			resumen.Completadas++
+		} else {
+			resumen.Pendientes++
+		}
+	}
+	return resumenes
+}
+
+// EstadisticasLista calcula las mismas estadísticas que Estadisticas, pero
+// solo con las tareas de una lista.
+//
+// Parámetros:
+//   - nombre: la lista (ListaPorDefecto o "" para la lista por defecto)
+//
+// Retorna:
+//   - total, completadas, pendientes: los recuentos de la lista
+//   - err: ErrorNoEncontrada si la lista no existe
+//
+// Ejemplo:
+//
+//	total, completadas, pendientes, err := gestor.EstadisticasLista("trabajo")
+//
+func (g *GestorTareas) EstadisticasLista(nombre string) (total, completadas, pendientes int, err error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	lista := normalizarLista(nombre)
+	if !g.existeLista(lista) {
+		return 0, 0, 0, &ErrorNoEncontrada{Lista: nombre}
+	}
+	for _, tarea := range g.tareas {
+		if tarea.EnPapelera() || tarea.Lista != lista {
+			continue
+		}
+		total++
+		if tarea.Completada {
+			completadas++
+		} else {
+			pendientes++
+		}
+	}
+	return
+}
+
+// ListarLista retorna las tareas de una lista, en orden de creación.
+//
+// Para combinarlo con otros filtros puede usarse una consulta con
+// lista:nombre (ver ParsearConsulta).
+//
+// Retorna:
+//   - []Tarea: copias de las tareas de la lista (puede estar vacío)
+//   - error: ErrorNoEncontrada si la lista no existe
+//
+func (g *GestorTareas) ListarLista(nombre string) ([]Tarea, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	lista := normalizarLista(nombre)
+	if !g.existeLista(lista) {
+		return nil, &ErrorNoEncontrada{Lista: nombre}
+	}
+	resultado := []Tarea{}
+	for _, tarea := range g.tareas {
+		if !tarea.EnPapelera() && tarea.Lista == lista {
+			resultado = append(resultado, tarea.clonar())
+		}
+	}
+	return resultado, nil
+}
+
+// CrearLista crea una lista vacía.
+//
+// El nombre se valida con ValidarLista y se guarda en minúsculas. La lista
+// se conserva aunque no tenga tareas si el almacenamiento implementa
+// AlmacenamientoListas (como el JSON por defecto). La operación puede
+// deshacerse.
+//
+// Parámetros:
+//   - nombre: el nombre de la nueva lista (ej: "trabajo")
+//
+// Retorna:
+//   - error: ErrorValidacion si el nombre no es válido o la lista ya existe,
+//     o un error de escritura del diario
+//
+// Ejemplo:
+//
+//	if err := gestor.CrearLista("casa"); err != nil {
+//		fmt.Println("Error:", err)
+//	}
+//
+func (g *GestorTareas) CrearLista(nombre string) error {
+	if err := ValidarLista(nombre); err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	lista := normalizarLista(nombre)
+	if g.existeLista(lista) {
+		return &ErrorValidacion{fmt.Sprintf("la lista %q ya existe", strings.TrimSpace(nombre))}
+	}
+
+	antes := g.listas
+	if err := g.establecerListas(append(slices.Clone(antes), lista)); err != nil {
+		return err
+	}
+	g.apilarOperacion(Operacion{Tipo: OperacionCrearLista, Lista: lista,
+		listas: &cambioListas{antes: antes, despues: g.listas}})
+	return nil
+}
+
+// RenombrarLista cambia el nombre de una lista y el de todas sus tareas,
+// incluidas las de la papelera.
+//
+// La lista por defecto no puede renombrarse, y el nombre nuevo no puede ser
+// el de otra lista existente. Deshacer revierte el cambio completo. Como
+// cambia todas sus tareas, el usuario del gestor debe poder modificarlas
+// todas (ver ConUsuario); si no, no se cambia ninguna.
+//
+// Parámetros:
+//   - actual: el nombre de la lista a renombrar
+//   - nueva: el nombre nuevo, validado con ValidarLista
+//
+// Retorna:
+//   - error: ErrorNoEncontrada si la lista no existe, ErrorValidacion si es
+//     la lista por defecto o el nombre nuevo no es válido o ya existe,
+//     *ErrorPermiso si el usuario no puede modificar alguna de sus tareas,
+//     o un error de escritura del diario
+//
+// Ejemplo:
+//
+//	err := gestor.RenombrarLista("trabajo", "oficina")
+//
+func (g *GestorTareas) RenombrarLista(actual, nueva string) error {
+	if err := ValidarLista(nueva); err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	origen, destino := normalizarLista(actual), normalizarLista(nueva)
+	if err := g.validarListaModificable(actual, origen); err != nil {
+		return err
+	}
+	if destino == origen {
+		return nil
+	}
+	if g.existeLista(destino) {
+		return &ErrorValidacion{fmt.Sprintf("la lista %q ya existe", strings.TrimSpace(nueva))}
+	}
+	if err := g.comprobarPermisoLista(origen); err != nil {
+		return err
+	}
+
+	// La lista renombrada conserva su posición; si solo existía en las
+	// tareas, pasa a estar creada
+	antes := g.listas
+	listas := slices.Clone(antes)
+	if i := slices.Index(listas, origen); i >= 0 {
+		listas[i] = destino
+	} else {
+		listas = append(listas, destino)
+	}
+
+	cambios, err := g.cambiarListaTareas(origen, destino)
+	if err == nil {
+		err = g.establecerListas(listas)
+	}
+	op := Operacion{Tipo: OperacionRenombrarLista, Lista: origen, cambios: cambios}
+	if err == nil {
+		op.listas = &cambioListas{antes: antes, despues: g.listas}
+	}
+	// Lo ya cambiado queda registrado para poder deshacerlo
+	if len(cambios) > 0 || op.listas != nil {
+		g.apilarOperacion(op)
+	}
+	return err
+}
+
+// EliminarLista elimina una lista y pasa sus tareas, incluidas las de la
+// papelera, a la lista por defecto.
+//
+// Ninguna tarea se pierde: para eliminarlas también deben eliminarse antes
+// de la lista. La lista por defecto no puede eliminarse. Deshacer devuelve
+// la lista y sus tareas. Igual que al renombrarla, el usuario del gestor
+// debe poder modificar todas sus tareas.
+//
+// Parámetros:
+//   - nombre: la lista a eliminar
+//
+// Retorna:
+//   - int: cantidad de tareas que pasaron a la lista por defecto
+//   - error: ErrorNoEncontrada si la lista no existe, ErrorValidacion si es
+//     la lista por defecto, *ErrorPermiso si el usuario no puede modificar
+//     alguna de sus tareas, o un error de escritura del diario
+//
+// Ejemplo:
+//
+//	n, err := gestor.EliminarLista("casa")
+//	if err == nil {
+//		fmt.Printf("%d tarea(s) pasaron a %s\n", n, ListaPorDefecto)
+//	}
+//
+func (g *GestorTareas) EliminarLista(nombre string) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	lista := normalizarLista(nombre)
+	if err := g.validarListaModificable(nombre, lista); err != nil {
+		return 0, err
+	}
+	if err := g.comprobarPermisoLista(lista); err != nil {
+		return 0, err
+	}
+
+	antes := g.listas
+	listas := slices.DeleteFunc(slices.Clone(antes), func(l string) bool { return l == lista })
+
+	cambios, err := g.cambiarListaTareas(lista, "")
+	if err == nil && len(listas) != len(antes) {
+		err = g.establecerListas(listas)
+	}
+	op := Operacion{Tipo: OperacionEliminarLista, Lista: lista, cambios: cambios}
+	if err == nil && len(listas) != len(antes) {
+		op.listas = &cambioListas{antes: antes, despues: g.listas}
+	}
+	if len(cambios) > 0 || op.listas != nil {
+		g.apilarOperacion(op)
+	}
+	return len(cambios), err
+}
+
+// MoverALista mueve una tarea, junto con todas sus subtareas, a otra lista.
+//
+// Solo pueden moverse tareas de primer nivel: las subtareas siguen siempre
+// a su tarea padre. Las dependencias entre tareas de listas distintas se
+// conservan. Mover una tarea a la lista en la que ya está no tiene efecto.
+// Registra el momento en FechaActualizacion y puede deshacerse.
+//
+// Parámetros:
+//   - id: la tarea a mover
+//   - lista: la lista de destino (ListaPorDefecto para la lista por defecto)
+//
+// Retorna:
+//   - error: ErrorNoEncontrada si la tarea o la lista no existen,
+//     ErrorValidacion si la tarea es una subtarea, *ErrorPermiso si el
+//     usuario no puede modificar la tarea o alguna de sus subtareas, o un
+//     error de escritura del diario
+//
+// Ejemplo:
+//
+//	if err := gestor.MoverALista(3, "casa"); err != nil {
+//		fmt.Println("Error:", err)
+//	}
+//
+func (g *GestorTareas) MoverALista(id int, lista string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	i := g.indiceVisible(id)
+	if i < 0 {
+		return &ErrorNoEncontrada{ID: id}
+	}
+	destino := normalizarLista(lista)
+	if !g.existeLista(destino) {
+		return &ErrorNoEncontrada{Lista: lista}
+	}
+	if padre := g.tareas[i].PadreID; padre != 0 {
+		return &ErrorValidacion{fmt.Sprintf("la tarea %d es subtarea de %d: se mueve junto con su tarea padre", id, padre)}
+	}
+	if g.tareas[i].Lista == destino {
+		return nil
+	}
+	indices := append([]int{i}, g.descendientes(id, true)...)
+	for _, indice := range indices {
+		if err := g.comprobarPermiso(indice); err != nil {
+			return err
+		}
+	}
+
+	ahora := g.reloj()
+	var cambios []cambioTarea
+	for _, indice := range indices {
+		movida := g.tareas[indice].clonar()
+		movida.Lista = destino
+		movida.FechaActualizacion = ahora
+		if err := g.escribirDiario(entradaRegistro{Op: opGuardar, Tarea: &movida}); err != nil {
+			// Lo ya movido queda registrado para poder deshacerlo
+			g.apilarOperacion(Operacion{Tipo: OperacionMover, TareaID: id, Lista: destino, cambios: cambios})
+			return err
+		}
+
+		cambios = append(cambios, nuevoCambio(&g.tareas[indice], &movida, indice))
+		g.sustituirTarea(indice, movida)
+		g.registrarGuardado(movida)
+	}
+
+	g.apilarOperacion(Operacion{Tipo: OperacionMover, TareaID: id, Lista: destino, cambios: cambios})
+	return nil
+}
+
+// nombresListas retorna los nombres normalizados de todas las listas: ""
+// (la lista por defecto), las creadas y las que solo aparecen en las
+// tareas, incluidas las de la papelera. Debe llamarse con g.mu tomado.
+func (g *GestorTareas) nombresListas() []string {
+	nombres := append([]string{""}, g.listas...)
+	for _, tarea := range g.tareas {
+		if !slices.Contains(nombres, tarea.Lista) {
+			nombres = append(nombres, tarea.Lista)
+		}
+	}
+	return nombres
+}
+
+// existeLista indica si la lista normalizada existe: es la lista por
+// defecto, se creó con CrearLista o alguna tarea pertenece a ella.
+// Debe llamarse con g.mu tomado.
+func (g *GestorTareas) existeLista(lista string) bool {
+	return slices.Contains(g.nombresListas(), lista)
+}
+
+// validarListaModificable comprueba que la lista normalizada exista y no
+// sea la lista por defecto; nombre es el indicado por el usuario, para el
+// mensaje de error. Debe llamarse con g.mu tomado.
+func (g *GestorTareas) validarListaModificable(nombre, lista string) error {
+	if lista == "" {
+		return &ErrorValidacion{fmt.Sprintf("la lista %q no puede renombrarse ni eliminarse", ListaPorDefecto)}
+	}
+	if !g.existeLista(lista) {
+		return &ErrorNoEncontrada{Lista: nombre}
+	}
+	return nil
+}
+
+// listaDeTareaNueva retorna la lista normalizada en la que debe crearse una
+// tarea: la de su padre si es una subtarea, o la indicada si existe.
+// Debe llamarse con g.mu tomado.
+func (g *GestorTareas) listaDeTareaNueva(nombre string, padreID int) (string, error) {
+	lista := normalizarLista(nombre)
+	if padreID != 0 {
+		padre := g.tareas[g.indicePorID(padreID)]
+		if nombre != "" && lista != padre.Lista {
+			return "", &ErrorValidacion{fmt.Sprintf("la tarea padre %d está en la lista %q: las subtareas van en la lista de su padre", padreID, padre.NombreLista())}
+		}
+		return padre.Lista, nil
+	}
+	if !g.existeLista(lista) {
+		return "", &ErrorNoEncontrada{Lista: nombre}
+	}
+	return lista, nil
+}
+
+// validarListaPadre comprueba que una tarea con padre esté en la misma
+// lista que él. Debe llamarse con g.mu tomado.
+func (g *GestorTareas) validarListaPadre(tarea Tarea) error {
+	if tarea.PadreID == 0 {
+		return nil
+	}
+	padre := g.tareas[g.indicePorID(tarea.PadreID)]
+	if padre.Lista != tarea.Lista {
+		return &ErrorValidacion{fmt.Sprintf("la tarea padre %d está en la lista %q: mueve antes la tarea %d a esa lista", padre.ID, padre.NombreLista(), tarea.ID)}
+	}
+	return nil
+}
+
+// comprobarPermisoLista retorna *ErrorPermiso si el usuario del gestor no
+// puede modificar alguna de las tareas de la lista, incluidas las de la
+// papelera. Debe llamarse con g.mu tomado.
+func (g *GestorTareas) comprobarPermisoLista(lista string) error {
+	for i := range g.tareas {
+		if g.tareas[i].Lista != lista {
+			continue
+		}
+		if err := g.comprobarPermiso(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cambiarListaTareas pasa todas las tareas de la lista origen, incluidas
+// las de la papelera, a la lista destino, y retorna los cambios aplicados.
+// Si falla la escritura del diario, retorna los cambios ya aplicados junto
+// con el error. Debe llamarse con g.mu tomado en modo escritura.
+func (g *GestorTareas) cambiarListaTareas(origen, destino string) ([]cambioTarea, error) {
+	var cambios []cambioTarea
+	for i := range g.tareas {
+		if g.tareas[i].Lista != origen {
+			continue
+		}
+		movida := g.tareas[i].clonar()
+		movida.Lista = destino
+		if err := g.escribirDiario(entradaRegistro{Op: opGuardar, Tarea: &movida}); err != nil {
+			return cambios, err
+		}
+
+		cambios = append(cambios, nuevoCambio(&g.tareas[i], &movida, i))
+		g.sustituirTarea(i, movida)
+		g.registrarGuardado(movida)
+	}
+	return cambios, nil
+}
+
+// establecerListas reemplaza las listas creadas: escribe el diario, las
+// aplica y las persiste. Debe llamarse con g.mu tomado en modo escritura.
+func (g *GestorTareas) establecerListas(listas []string) error {
+	if err := g.escribirDiario(entradaRegistro{Op: opListas, Listas: listas}); err != nil {
+		return err
+	}
+	g.listas = slices.Clone(listas)
+	g.registrarListas()
+	return nil
+}
+
+// registrarListas persiste las listas creadas.
+//
+// Si el almacenamiento guarda listas y es incremental, las escribe en el
+// momento; si no, o la escritura falla, marca el cambio como pendiente para
+// el siguiente Guardar, igual que registrarGuardado.
+// Debe llamarse con g.mu tomado en modo escritura.
+func (g *GestorTareas) registrarListas() {
+	if al, ok := g.almacen.(AlmacenamientoListas); ok {
+		if _, incremental := g.almacen.(AlmacenamientoIncremental); incremental && al.GuardarListas(g.listas) == nil {
+			return
+		}
+	}
+	g.cambiosPendientes = true
+}
@@ -0,0 +1,255 @@
+// Tests de las listas de tareas
+
+package tareas
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// crearListas crea las listas "trabajo" y "casa" con una tarea cada una,
+// una subtarea en "trabajo" y una tarea en la lista por defecto
+func crearListas(t *testing.T, gestor *GestorTareas) {
+	t.Helper()
+	for _, nombre := range []string{"Trabajo", "casa"} {
+		if err := gestor.CrearLista(nombre); err != nil {
+			t.Fatalf("Error al crear la lista %q: %v", nombre, err)
+		}
+	}
+	crearTareas(t, gestor, []DatosTarea{
+		{Titulo: "Preparar informe", Lista: "trabajo"},
+		{Titulo: "Revisar gráficos", PadreID: 1},
+		{Titulo: "Regar plantas", Lista: "casa"},
+		{Titulo: "Llamar al banco"},
+	}, nil)
+}
+
+// nombresDeListas retorna los nombres de gestor.Listas()
+func nombresDeListas(gestor *GestorTareas) []string {
+	var nombres []string
+	for _, lista := range gestor.Listas() {
+		nombres = append(nombres, lista.Nombre)
+	}
+	return nombres
+}
+
+// TestCrearTareasEnListas verifica la lista de las tareas nuevas, de las
+// subtareas y los recuentos por lista
+func TestCrearTareasEnListas(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t)
+	crearListas(t, gestor)
+
+	esperadas := map[int]string{1: "trabajo", 2: "trabajo", 3: "casa", 4: ""}
+	for id, lista := range esperadas {
+		if tarea, _ := gestor.BuscarPorID(id); tarea.Lista != lista {
+			t.Errorf("La tarea %d debería estar en %q, está en %q", id, lista, tarea.Lista)
+		}
+	}
+
+	gestor.Completar(2)
+	esperado := []ResumenLista{
+		{Nombre: ListaPorDefecto, Total: 1, Pendientes: 1},
+		{Nombre: "trabajo", Total: 2, Completadas: 1, Pendientes: 1},
+		{Nombre: "casa", Total: 1, Pendientes: 1},
+	}
+	if listas := gestor.Listas(); !reflect.DeepEqual(listas, esperado) {
+		t.Errorf("Listas inesperadas:\n%+v\nse esperaba:\n%+v", listas, esperado)
+	}
+	if total, completadas, pendientes, err := gestor.EstadisticasLista("TRABAJO"); err != nil || total != 2 || completadas != 1 || pendientes != 1 {
+		t.Errorf("EstadisticasLista(trabajo) = %d, %d, %d, %v", total, completadas, pendientes, err)
+	}
+	if lista, _ := gestor.ListarLista(ListaPorDefecto); len(lista) != 1 || lista[0].ID != 4 {
+		t.Errorf("La lista por defecto debería tener solo la tarea 4: %+v", lista)
+	}
+
+	// Errores al crear tareas y listas
+	var noEncontrada *ErrorNoEncontrada
+	if _, err := gestor.CrearConDatos(DatosTarea{Titulo: "Sin lista", Lista: "ocio"}); !errors.As(err, &noEncontrada) || noEncontrada.Lista != "ocio" {
+		t.Errorf("Se esperaba ErrorNoEncontrada para la lista ocio, se obtuvo: %v", err)
+	}
+	if _, _, _, err := gestor.EstadisticasLista("ocio"); !errors.As(err, &noEncontrada) {
+		t.Errorf("EstadisticasLista de una lista inexistente: %v", err)
+	}
+	var validacion *ErrorValidacion
+	if _, err := gestor.CrearConDatos(DatosTarea{Titulo: "Subtarea en otra lista", PadreID: 1, Lista: "casa"}); !errors.As(err, &validacion) {
+		t.Errorf("Una subtarea no debería crearse en otra lista que su padre: %v", err)
+	}
+	for _, nombre := range []string{"casa", "general", "", "con espacio", "a:b", strings.Repeat("x", 31)} {
+		if err := gestor.CrearLista(nombre); !errors.As(err, &validacion) {
+			t.Errorf("CrearLista(%q) debería fallar con ErrorValidacion, se obtuvo: %v", nombre, err)
+		}
+	}
+	// El límite se cuenta en caracteres, no en bytes
+	if err := gestor.CrearLista(strings.Repeat("ñ", 30)); err != nil {
+		t.Errorf("Un nombre de 30 caracteres con tildes debería ser válido: %v", err)
+	}
+}
+
+// TestRenombrarYEliminarLista verifica que las tareas, incluidas las de la
+// papelera, acompañen a la lista, y que Deshacer revierta cada operación
+func TestRenombrarYEliminarLista(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t)
+	crearListas(t, gestor)
+	gestor.Eliminar(3)
+
+	if err := gestor.RenombrarLista("casa", "hogar"); err != nil {
+		t.Fatalf("Error al renombrar: %v", err)
+	}
+	if nombres := nombresDeListas(gestor); !reflect.DeepEqual(nombres, []string{"general", "trabajo", "hogar"}) {
+		t.Errorf("Listas tras renombrar: %v", nombres)
+	}
+	if papelera := gestor.ListarPapelera(); papelera[0].Lista != "hogar" {
+		t.Errorf("La tarea de la papelera debería pasar a hogar: %q", papelera[0].Lista)
+	}
+
+	n, err := gestor.EliminarLista("trabajo")
+	if err != nil || n != 2 {
+		t.Fatalf("Se esperaban 2 tareas movidas, se obtuvo %d, %v", n, err)
+	}
+	if nombres := nombresDeListas(gestor); !reflect.DeepEqual(nombres, []string{"general", "hogar"}) {
+		t.Errorf("Listas tras eliminar: %v", nombres)
+	}
+	if total, _, _, _ := gestor.EstadisticasLista(ListaPorDefecto); total != 3 {
+		t.Errorf("La lista por defecto debería tener 3 tareas, tiene %d", total)
+	}
+
+	// Deshacer devuelve las listas y sus tareas
+	if op, err := gestor.Deshacer(); err != nil || op.String() != "eliminar lista 'trabajo' y 2 tarea(s)" {
+		t.Fatalf("Deshacer eliminar lista: %v, %v", op, err)
+	}
+	if tarea, _ := gestor.BuscarPorID(2); tarea.Lista != "trabajo" {
+		t.Errorf("La subtarea debería volver a trabajo: %q", tarea.Lista)
+	}
+	gestor.Deshacer()
+	if nombres := nombresDeListas(gestor); !reflect.DeepEqual(nombres, []string{"general", "trabajo", "casa"}) {
+		t.Errorf("Listas tras deshacer: %v", nombres)
+	}
+	gestor.Rehacer()
+	if _, _, _, err := gestor.EstadisticasLista("hogar"); err != nil {
+		t.Errorf("Rehacer debería volver a renombrar la lista: %v", err)
+	}
+
+	// La lista por defecto no se renombra ni se elimina
+	var validacion *ErrorValidacion
+	if err := gestor.RenombrarLista(ListaPorDefecto, "otra"); !errors.As(err, &validacion) {
+		t.Errorf("Renombrar la lista por defecto debería fallar: %v", err)
+	}
+	if _, err := gestor.EliminarLista(""); !errors.As(err, &validacion) {
+		t.Errorf("Eliminar la lista por defecto debería fallar: %v", err)
+	}
+	if err := gestor.RenombrarLista("hogar", "trabajo"); !errors.As(err, &validacion) {
+		t.Errorf("Renombrar a una lista existente debería fallar: %v", err)
+	}
+	var noEncontrada *ErrorNoEncontrada
+	if _, err := gestor.EliminarLista("ocio"); !errors.As(err, &noEncontrada) {
+		t.Errorf("Eliminar una lista inexistente debería fallar: %v", err)
+	}
+}
+
+// TestMoverALista verifica que una tarea se mueva con sus subtareas y que
+// las subtareas no puedan moverse ni colgar de tareas de otra lista
+func TestMoverALista(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t)
+	crearListas(t, gestor)
+
+	if err := gestor.MoverALista(1, "Casa"); err != nil {
+		t.Fatalf("Error al mover: %v", err)
+	}
+	for _, id := range []int{1, 2} {
+		if tarea, _ := gestor.BuscarPorID(id); tarea.Lista != "casa" || tarea.FechaActualizacion.IsZero() {
+			t.Errorf("La tarea %d debería estar en casa y actualizada: %+v", id, tarea)
+		}
+	}
+	if resultados, _ := gestor.Buscar("lista:casa"); len(resultados) != 3 {
+		t.Errorf("La consulta lista:casa debería dar 3 tareas, da %d", len(resultados))
+	}
+	if resultados, _ := gestor.Buscar("lista:general"); len(resultados) != 1 || resultados[0].ID != 4 {
+		t.Errorf("La consulta lista:general debería dar la tarea 4: %+v", resultados)
+	}
+	if op, _ := gestor.Deshacer(); op.Tipo != OperacionMover {
+		t.Errorf("Se esperaba deshacer el movimiento, se deshizo: %v", op)
+	}
+	if tarea, _ := gestor.BuscarPorID(2); tarea.Lista != "trabajo" {
+		t.Errorf("Deshacer debería devolver la subtarea a trabajo: %q", tarea.Lista)
+	}
+
+	var validacion *ErrorValidacion
+	if err := gestor.MoverALista(2, "casa"); !errors.As(err, &validacion) {
+		t.Errorf("Una subtarea no debería moverse sola: %v", err)
+	}
+	padre := 3
+	if _, err := gestor.Actualizar(4, CambiosTarea{PadreID: &padre}); !errors.As(err, &validacion) {
+		t.Errorf("Una tarea no debería colgar de un padre de otra lista: %v", err)
+	}
+	var noEncontrada *ErrorNoEncontrada
+	if err := gestor.MoverALista(1, "ocio"); !errors.As(err, &noEncontrada) {
+		t.Errorf("Mover a una lista inexistente debería fallar: %v", err)
+	}
+}
+
+// TestListasPersistentes verifica que las listas vacías sobrevivan al
+// guardado en cada backend y que el diario reproduzca sus cambios
+func TestListasPersistentes(t *testing.T) {
+	defer eliminarConRespaldos("test_listas.json")
+	defer os.Remove("test_listas.log")
+	defer os.Remove("test_listas.diario")
+
+	tests := []struct {
+		nombre  string
+		almacen Almacenamiento
+	}{
+		{"json", NuevoAlmacenamientoJSON("test_listas.json")},
+		{"memoria", NuevoAlmacenamientoMemoria()},
+		{"registro", NuevoAlmacenamientoRegistro("test_listas.log")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			gestor := nuevoGestorEnMemoria(t, ConAlmacenamiento(tt.almacen))
+			crearListas(t, gestor)
+			gestor.CrearLista("vacia")
+			if err := gestor.Guardar(); err != nil {
+				t.Fatalf("Error al guardar: %v", err)
+			}
+
+			recargado := nuevoGestorEnMemoria(t, ConAlmacenamiento(tt.almacen))
+			if nombres := nombresDeListas(recargado); !reflect.DeepEqual(nombres, []string{"general", "trabajo", "casa", "vacia"}) {
+				t.Errorf("Listas tras recargar: %v", nombres)
+			}
+		})
+	}
+
+	// Un tareas.json anterior a las listas se carga en la lista por defecto
+	os.WriteFile("test_listas.json", []byte(`[{"id":1,"titulo":"Tarea antigua","completada":false,"fecha_creacion":"2026-01-01T00:00:00Z"}]`), 0644)
+	gestor, err := NuevoGestorTareas("test_listas.json", ConDiario("test_listas.diario"))
+	if err != nil {
+		t.Fatalf("Error al cargar el formato antiguo: %v", err)
+	}
+	if listas := gestor.Listas(); len(listas) != 1 || listas[0].Total != 1 {
+		t.Errorf("Se esperaba solo la lista por defecto con 1 tarea: %+v", listas)
+	}
+
+	// Sin listas creadas, se sigue guardando el array de siempre
+	gestor.Guardar()
+	if datos, _ := os.ReadFile("test_listas.json"); !strings.HasPrefix(string(datos), "[") {
+		t.Errorf("Sin listas el archivo debería seguir siendo un array:\n%s", datos)
+	}
+
+	// Cambios de listas sin guardar: los recupera el diario
+	gestor.CrearLista("trabajo")
+	gestor.MoverALista(1, "trabajo")
+	gestor.CrearLista("casa")
+	gestor.RenombrarLista("casa", "hogar")
+	recuperado, err := NuevoGestorTareas("test_listas.json", ConDiario("test_listas.diario"))
+	if err != nil {
+		t.Fatalf("Error al recuperar: %v", err)
+	}
+	if nombres := nombresDeListas(recuperado); !reflect.DeepEqual(nombres, []string{"general", "trabajo", "hogar"}) {
+		t.Errorf("Listas recuperadas del diario: %v", nombres)
+	}
+	if tarea, _ := recuperado.BuscarPorID(1); tarea.Lista != "trabajo" {
+		t.Errorf("La tarea debería estar en trabajo según el diario: %q", tarea.Lista)
+	}
+}
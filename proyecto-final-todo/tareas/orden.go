@@ -0,0 +1,335 @@
+package tareas
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CampoOrden es un campo por el que pueden ordenarse los listados de tareas.
+type CampoOrden string
+
+// Campos de ordenación disponibles.
+const (
+	OrdenID            CampoOrden = "id"
+	OrdenTitulo        CampoOrden = "titulo"
+	OrdenCreacion      CampoOrden = "creacion"
+	OrdenActualizacion CampoOrden = "actualizacion"
+	OrdenCompletada    CampoOrden = "completada"
+	OrdenPrioridad     CampoOrden = "prioridad"
+	OrdenVencimiento   CampoOrden = "vencimiento"
+)
+
+// camposOrden son los campos admitidos por ParsearOrden, para los mensajes
+// de error.
+var camposOrden = []CampoOrden{
+	OrdenID, OrdenTitulo, OrdenCreacion, OrdenActualizacion,
+	OrdenCompletada, OrdenPrioridad, OrdenVencimiento,
+}
+
+// CriterioOrden es un campo de ordenación y su sentido.
+type CriterioOrden struct {
+	Campo       CampoOrden
+	Descendente bool
+}
+
+// Orden es una lista de criterios: el segundo desempata el primero, y así
+// sucesivamente. Al final siempre desempata el ID, de modo que el orden de
+// dos tareas distintas nunca es ambiguo. Un Orden vacío ordena por ID, que
+// es el orden de creación.
+type Orden []CriterioOrden
+
+// ParsearOrden interpreta una lista de campos separados por comas, cada uno
+// precedido opcionalmente de '-' para ordenar de forma descendente.
+//
+// Los campos son id, titulo, creacion, actualizacion, completada (primero
+// las pendientes, después las completadas por fecha), prioridad y
+// vencimiento. Las tareas sin fecha de vencimiento van siempre al final,
+// en cualquier sentido. El título se compara sin distinguir mayúsculas ni
+// tildes.
+//
+// Parámetros:
+//   - texto: la lista de campos; vacío equivale al orden de creación
+//
+// Retorna:
+//   - Orden: los criterios en el orden indicado
+//   - error: ErrorValidacion si algún campo no existe o está repetido
+//
+// Ejemplo:
+//
+//	orden, err := ParsearOrden("-prioridad,vencimiento")
+//	// urgentes primero y, a igual prioridad, las que vencen antes
+//
+func ParsearOrden(texto string) (Orden, error) {
+	var orden Orden
+	for _, parte := range strings.Split(texto, ",") {
+		parte = strings.ToLower(strings.TrimSpace(parte))
+		if parte == "" {
+			continue
+		}
+		nombre, descendente := strings.CutPrefix(parte, "-")
+		campo := CampoOrden(nombre)
+		if !slices.Contains(camposOrden, campo) {
+			return nil, &ErrorValidacion{fmt.Sprintf("campo de orden desconocido %q: usa %s", nombre, listaCamposOrden())}
+		}
+		if slices.ContainsFunc(orden, func(c CriterioOrden) bool { return c.Campo == campo }) {
+			return nil, &ErrorValidacion{fmt.Sprintf("el campo de orden %q está repetido", nombre)}
+		}
+		orden = append(orden, CriterioOrden{Campo: campo, Descendente: descendente})
+	}
+	return orden, nil
+}
+
+// String retorna el orden con la sintaxis de ParsearOrden.
+func (o Orden) String() string {
+	partes := make([]string, len(o))
+	for i, criterio := range o {
+		partes[i] = string(criterio.Campo)
+		if criterio.Descendente {
+			partes[i] = "-" + partes[i]
+		}
+	}
+	return strings.Join(partes, ",")
+}
+
+// Comparar retorna un número negativo si a va antes que b según el orden,
+// positivo si va después y 0 solo si son la misma tarea (mismo ID).
+func (o Orden) Comparar(a, b Tarea) int {
+	for _, criterio := range o {
+		if criterio.Campo == OrdenVencimiento {
+			// Sin fecha, al final en ambos sentidos
+			if r := cmp.Compare(boolANumero(a.FechaVencimiento.IsZero()), boolANumero(b.FechaVencimiento.IsZero())); r != 0 {
+				return r
+			}
+		}
+		r := compararCampo(criterio.Campo, a, b)
+		if criterio.Descendente {
+			r = -r
+		}
+		if r != 0 {
+			return r
+		}
+	}
+	return cmp.Compare(a.ID, b.ID)
+}
+
+// Ordenar ordena lista según orden, modificándola.
+//
+// Ejemplo:
+//
+//	lista := gestor.ListarPendientes()
+//	Ordenar(lista, Orden{{Campo: OrdenVencimiento}})
+//
+func Ordenar(lista []Tarea, orden Orden) {
+	slices.SortFunc(lista, orden.Comparar)
+}
+
+// Paginacion indica cómo ordenar un listado y qué parte retornar.
+//
+// Admite dos formas de paginar: por desplazamiento (Desplazamiento y
+// Limite, cómodo para saltar a una página concreta) o por cursor (el
+// Siguiente de la página anterior y Limite). El cursor recuerda la última
+// tarea mostrada, de modo que crear o eliminar tareas entre petición y
+// petición no repite ni salta resultados.
+type Paginacion struct {
+	// Orden es el orden del listado (vacío: orden de creación)
+	Orden Orden
+
+	// Desplazamiento es la cantidad de tareas que se saltan al principio
+	Desplazamiento int
+
+	// Limite es el tamaño máximo de la página (0: sin límite)
+	Limite int
+
+	// Cursor es el Siguiente de una Pagina anterior ("" para empezar)
+	Cursor string
+}
+
+// Pagina es una parte de un listado ordenado.
+type Pagina struct {
+	// Tareas son las tareas de la página, ya ordenadas
+	Tareas []Tarea `json:"tareas"`
+
+	// Total es la cantidad de tareas del listado completo
+	Total int `json:"total"`
+
+	// Desplazamiento es la posición de la primera tarea de la página en el
+	// listado completo
+	Desplazamiento int `json:"desplazamiento"`
+
+	// Siguiente es el cursor de la página siguiente ("" si es la última o
+	// si no se indicó Limite)
+	Siguiente string `json:"siguiente,omitempty"`
+}
+
+// cursorPagina es el contenido codificado de un cursor: el orden con el que
+// se generó y los campos de ordenación de la última tarea de la página.
+type cursorPagina struct {
+	Orden  string `json:"orden"`
+	Ultima Tarea  `json:"ultima"`
+}
+
+// Paginar ordena lista y retorna la página pedida. Se combina con cualquier
+// listado (Listar, ListarPendientes, Buscar, ...) sin modificarlo.
+//
+// Parámetros:
+//   - lista: las tareas a paginar
+//   - p: orden, y desplazamiento o cursor, y límite
+//
+// Retorna:
+//   - Pagina: las tareas de la página, el total y el cursor siguiente
+//   - error: ErrorValidacion si el desplazamiento o el límite son negativos,
+//     si se indican a la vez desplazamiento y cursor, o si el cursor no es
+//     válido o se generó con otro orden
+//
+// Ejemplo:
+//
+//	p := Paginacion{Orden: Orden{{Campo: OrdenPrioridad, Descendente: true}}, Limite: 10}
+//	for {
+//		pagina, err := Paginar(gestor.ListarPendientes(), p)
+//		if err != nil {
+//			return err
+//		}
+//		procesar(pagina.Tareas)
+//		if pagina.Siguiente == "" {
+//			break
+//		}
+//		p.Cursor = pagina.Siguiente
+//	}
+//
+func Paginar(lista []Tarea, p Paginacion) (Pagina, error) {
+	if p.Desplazamiento < 0 || p.Limite < 0 {
+		return Pagina{}, &ErrorValidacion{"el desplazamiento y el límite no pueden ser negativos"}
+	}
+	if p.Desplazamiento > 0 && p.Cursor != "" {
+		return Pagina{}, &ErrorValidacion{"usa desplazamiento o cursor, no ambos"}
+	}
+
+	lista = slices.Clone(lista)
+	Ordenar(lista, p.Orden)
+
+	inicio := min(p.Desplazamiento, len(lista))
+	if p.Cursor != "" {
+		ultima, err := decodificarCursor(p.Cursor, p.Orden)
+		if err != nil {
+			return Pagina{}, err
+		}
+		inicio = sort.Search(len(lista), func(i int) bool { return p.Orden.Comparar(lista[i], ultima) > 0 })
+	}
+
+	fin := len(lista)
+	if p.Limite > 0 {
+		fin = min(inicio+p.Limite, len(lista))
+	}
+
+	pagina := Pagina{
+		Tareas:         lista[inicio:fin:fin],
+		Total:          len(lista),
+		Desplazamiento: inicio,
+	}
+	if p.Limite > 0 && fin < len(lista) {
+		pagina.Siguiente = codificarCursor(lista[fin-1], p.Orden)
+	}
+	return pagina, nil
+}
+
+// ListarPagina retorna una página de las tareas fuera de la papelera.
+// Equivale a Paginar(g.Listar(), p).
+//
+// Ejemplo:
+//
+//	pagina, err := gestor.ListarPagina(Paginacion{Limite: 20})
+//	fmt.Printf("Mostrando %d de %d tareas\n", len(pagina.Tareas), pagina.Total)
+//
+func (g *GestorTareas) ListarPagina(p Paginacion) (Pagina, error) {
+	return Paginar(g.Listar(), p)
+}
+
+// codificarCursor genera el cursor que continúa tras la tarea ultima.
+// Solo guarda los campos que intervienen en la ordenación.
+func codificarCursor(ultima Tarea, orden Orden) string {
+	datos, _ := json.Marshal(cursorPagina{
+		Orden: orden.String(),
+		Ultima: Tarea{
+			ID:                 ultima.ID,
+			Titulo:             ultima.Titulo,
+			Completada:         ultima.Completada,
+			FechaCreacion:      ultima.FechaCreacion,
+			Prioridad:          ultima.Prioridad,
+			FechaVencimiento:   ultima.FechaVencimiento,
+			FechaCompletada:    ultima.FechaCompletada,
+			FechaActualizacion: ultima.FechaActualizacion,
+		},
+	})
+	return base64.RawURLEncoding.EncodeToString(datos)
+}
+
+// decodificarCursor recupera la última tarea de la página anterior y
+// comprueba que el cursor se generó con el mismo orden.
+func decodificarCursor(cursor string, orden Orden) (Tarea, error) {
+	var c cursorPagina
+	datos, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(datos, &c)
+	}
+	if err != nil || c.Ultima.ID == 0 {
+		return Tarea{}, &ErrorValidacion{"el cursor no es válido"}
+	}
+	if c.Orden != orden.String() {
+		return Tarea{}, &ErrorValidacion{fmt.Sprintf("el cursor se generó con otro orden (%q): repite el mismo orden o empieza sin cursor", c.Orden)}
+	}
+	return c.Ultima, nil
+}
+
+// compararCampo compara a y b en un único campo, en sentido ascendente.
+func compararCampo(campo CampoOrden, a, b Tarea) int {
+	switch campo {
+	case OrdenTitulo:
+		return strings.Compare(normalizarTexto(a.Titulo), normalizarTexto(b.Titulo))
+	case OrdenCreacion:
+		return a.FechaCreacion.Compare(b.FechaCreacion)
+	case OrdenActualizacion:
+		return ultimaModificacion(a).Compare(ultimaModificacion(b))
+	case OrdenCompletada:
+		if r := cmp.Compare(boolANumero(a.Completada), boolANumero(b.Completada)); r != 0 {
+			return r
+		}
+		return a.FechaCompletada.Compare(b.FechaCompletada)
+	case OrdenPrioridad:
+		return cmp.Compare(a.Prioridad.Nivel(), b.Prioridad.Nivel())
+	case OrdenVencimiento:
+		return a.FechaVencimiento.Compare(b.FechaVencimiento)
+	}
+	return cmp.Compare(a.ID, b.ID)
+}
+
+// ultimaModificacion retorna la fecha de la última edición de la tarea, o
+// la de creación si nunca se editó.
+func ultimaModificacion(t Tarea) time.Time {
+	if t.FechaActualizacion.IsZero() {
+		return t.FechaCreacion
+	}
+	return t.FechaActualizacion
+}
+
+// boolANumero retorna 1 para true y 0 para false, para comparar booleanos.
+func boolANumero(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// listaCamposOrden retorna los campos de orden separados por comas.
+func listaCamposOrden() string {
+	nombres := make([]string, len(camposOrden))
+	for i, campo := range camposOrden {
+		nombres[i] = string(campo)
+	}
+	return strings.Join(nombres, ", ")
+}
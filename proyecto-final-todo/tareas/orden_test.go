@@ -0,0 +1,136 @@
+// Tests de ordenación y paginación de listados
+
+package tareas
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// crearTareasOrden crea un gestor con tareas de títulos, prioridades,
+// vencimientos y estados variados
+func crearTareasOrden(t *testing.T) *GestorTareas {
+	t.Helper()
+	gestor, ahora := nuevoGestorConReloj(t, time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+
+	datos := []DatosTarea{
+		{Titulo: "Comprar pan", Prioridad: PrioridadBaja, FechaVencimiento: ahora.AddDate(0, 0, 3)},           // 1
+		{Titulo: "árbol genealógico", Prioridad: PrioridadAlta},                                               // 2
+		{Titulo: "Declarar impuestos", Prioridad: PrioridadUrgente, FechaVencimiento: ahora.AddDate(0, 0, 1)}, // 3
+		{Titulo: "Bañar al perro", FechaVencimiento: ahora.AddDate(0, 0, 2)},                                  // 4
+		{Titulo: "Enviar factura", Prioridad: PrioridadAlta, FechaVencimiento: ahora.AddDate(0, 0, 1)},        // 5
+	}
+	crearTareas(t, gestor, datos, func(int) { *ahora = ahora.Add(time.Minute) })
+	*ahora = ahora.Add(time.Minute)
+	gestor.Completar(5)
+	*ahora = ahora.Add(time.Minute)
+	gestor.Completar(1)
+	return gestor
+}
+
+// TestOrdenar verifica cada campo de ordenación y su combinación
+func TestOrdenar(t *testing.T) {
+	gestor := crearTareasOrden(t)
+
+	tests := []struct {
+		orden     string
+		esperados []int
+	}{
+		{"", []int{1, 2, 3, 4, 5}},
+		{"-id", []int{5, 4, 3, 2, 1}},
+		{"titulo", []int{2, 4, 1, 3, 5}}, // sin distinguir mayúsculas ni tildes
+		{"-creacion", []int{5, 4, 3, 2, 1}},
+		{"completada", []int{2, 3, 4, 5, 1}}, // pendientes y luego por fecha de completado
+		{"-prioridad", []int{3, 2, 5, 1, 4}}, // a igual prioridad, por ID
+		{"vencimiento", []int{3, 5, 4, 1, 2}},
+		{"-vencimiento", []int{1, 4, 3, 5, 2}}, // sin vencimiento, siempre al final
+		{"-prioridad,vencimiento", []int{3, 5, 2, 1, 4}},
+		{"completada,-prioridad", []int{3, 2, 4, 5, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.orden, func(t *testing.T) {
+			orden, err := ParsearOrden(tt.orden)
+			if err != nil {
+				t.Fatalf("Error inesperado: %v", err)
+			}
+			lista := gestor.Listar()
+			Ordenar(lista, orden)
+			if obtenidos := idsDe(lista); !slices.Equal(obtenidos, tt.esperados) {
+				t.Errorf("Orden %q: se esperaban %v, se obtuvieron %v", tt.orden, tt.esperados, obtenidos)
+			}
+		})
+	}
+
+	var errValidacion *ErrorValidacion
+	for _, invalido := range []string{"fecha", "titulo,-titulo", "-"} {
+		if _, err := ParsearOrden(invalido); !errors.As(err, &errValidacion) {
+			t.Errorf("ParsearOrden(%q) debería dar ErrorValidacion, se obtuvo: %v", invalido, err)
+		}
+	}
+	if orden, _ := ParsearOrden(" -Prioridad , titulo "); orden.String() != "-prioridad,titulo" {
+		t.Errorf("String() debería normalizar el orden, se obtuvo: %q", orden.String())
+	}
+}
+
+// TestPaginar verifica la paginación por desplazamiento y por cursor
+func TestPaginar(t *testing.T) {
+	gestor := crearTareasOrden(t)
+	porPrioridad := Orden{{Campo: OrdenPrioridad, Descendente: true}}
+
+	// Por desplazamiento
+	pagina, err := gestor.ListarPagina(Paginacion{Orden: porPrioridad, Desplazamiento: 2, Limite: 2})
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if !slices.Equal(idsDe(pagina.Tareas), []int{5, 1}) || pagina.Total != 5 || pagina.Desplazamiento != 2 || pagina.Siguiente == "" {
+		t.Errorf("Página inesperada: %v %+v", idsDe(pagina.Tareas), pagina)
+	}
+	if pagina, _ := gestor.ListarPagina(Paginacion{Desplazamiento: 10}); len(pagina.Tareas) != 0 || pagina.Total != 5 {
+		t.Errorf("Un desplazamiento mayor que el total debería dar una página vacía: %+v", pagina)
+	}
+
+	// Por cursor, recorriendo todas las páginas
+	var recorridas []int
+	p := Paginacion{Orden: porPrioridad, Limite: 2}
+	for paginas := 0; ; paginas++ {
+		if paginas > 5 {
+			t.Fatal("La paginación por cursor no termina")
+		}
+		pagina, err := gestor.ListarPagina(p)
+		if err != nil {
+			t.Fatalf("Error inesperado: %v", err)
+		}
+		recorridas = append(recorridas, idsDe(pagina.Tareas)...)
+		if pagina.Siguiente == "" {
+			break
+		}
+		p.Cursor = pagina.Siguiente
+
+		// Eliminar una tarea ya mostrada no desplaza las siguientes páginas
+		if paginas == 0 {
+			gestor.Eliminar(3)
+		}
+	}
+	if !slices.Equal(recorridas, []int{3, 2, 5, 1, 4}) {
+		t.Errorf("El recorrido por cursor debería mostrar cada tarea una vez: %v", recorridas)
+	}
+
+	// Errores
+	tests := []struct {
+		nombre string
+		p      Paginacion
+	}{
+		{"límite negativo", Paginacion{Limite: -1}},
+		{"desplazamiento y cursor", Paginacion{Desplazamiento: 1, Cursor: p.Cursor}},
+		{"cursor inválido", Paginacion{Cursor: "no-es-un-cursor"}},
+		{"cursor de otro orden", Paginacion{Cursor: p.Cursor}},
+	}
+	for _, tt := range tests {
+		var errValidacion *ErrorValidacion
+		if _, err := gestor.ListarPagina(tt.p); !errors.As(err, &errValidacion) {
+			t.Errorf("%s: se esperaba ErrorValidacion, se obtuvo: %v", tt.nombre, err)
+		}
+	}
+}
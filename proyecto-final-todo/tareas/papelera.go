@@ -0,0 +1,218 @@
+package tareas
+
+import (
+	"fmt"
+	"time"
+)
+
+// RetencionPapeleraPorDefecto es el tiempo que una tarea eliminada permanece
+// en la papelera antes de purgarse, si no se indica otro con ConRetencionPapelera.
+const RetencionPapeleraPorDefecto = 30 * 24 * time.Hour
+
+// EnPapelera indica si la tarea fue eliminada y espera en la papelera.
+func (t Tarea) EnPapelera() bool {
+	return !t.FechaEliminada.IsZero()
+}
+
+// ConRetencionPapelera cambia el tiempo que las tareas eliminadas permanecen
+// en la papelera antes de purgarse automáticamente.
+//
+// Con 0 las tareas no se purgan nunca de forma automática y solo desaparecen
+// con VaciarPapelera.
+//
+// Ejemplo:
+//
+//	gestor, err := NuevoGestorTareas("tareas.json", ConRetencionPapelera(7*24*time.Hour))
+//
+func ConRetencionPapelera(retencion time.Duration) Opcion {
+	return func(g *GestorTareas) {
+		g.retencionPapelera = retencion
+	}
+}
+
+// ListarPapelera retorna las tareas eliminadas que aún pueden restaurarse,
+// en el orden en que fueron creadas.
+//
+// Ejemplo:
+//
+//	for _, t := range gestor.ListarPapelera() {
+//		fmt.Printf("🗑️  [%d] %s (eliminada el %s)\n", t.ID, t.Titulo, t.FechaEliminada.Format("02/01/2006"))
+//	}
+//
+func (g *GestorTareas) ListarPapelera() []Tarea {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var papelera []Tarea
+	for _, tarea := range g.tareas {
+		if tarea.EnPapelera() {
+			papelera = append(papelera, tarea.clonar())
+		}
+	}
+	return papelera
+}
+
+// Restaurar saca una tarea de la papelera y la devuelve a los listados.
+//
+// La tarea conserva su ID, su posición y todos sus campos; solo se borra
+// su FechaEliminada. Las subtareas eliminadas junto con ella mediante
+// EliminarConSubtareas se restauran también. La operación puede deshacerse
+// como cualquier otra.
+//
+// Una subtarea no puede restaurarse mientras su tarea padre siga en la
+// papelera.
+//
+// Parámetros:
+//   - id: el identificador de la tarea eliminada
+//
+// Retorna:
+//   - error: ErrorNoEncontrada si no hay ninguna tarea con ese ID en la
+//     papelera (nunca existió, no está eliminada o ya se purgó),
+//     ErrorValidacion si su tarea padre está en la papelera, o *ErrorPermiso
+//     si el usuario no puede modificarla a ella o a alguna de las subtareas
+//     que se restaurarían con ella (en ese caso no se restaura ninguna)
+//
+// Ejemplo:
+//
+//	if err := gestor.Restaurar(7); err != nil {
+//		fmt.Println("No se pudo restaurar:", err)
+//	}
+//
+func (g *GestorTareas) Restaurar(id int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	i := g.indicePorID(id)
+	if i < 0 || !g.tareas[i].EnPapelera() {
+		return &ErrorNoEncontrada{ID: id}
+	}
+	if padre := g.indicePorID(g.tareas[i].PadreID); padre >= 0 && g.tareas[padre].EnPapelera() {
+		return &ErrorValidacion{fmt.Sprintf("la tarea padre %d está en la papelera: restáurala primero", g.tareas[padre].ID)}
+	}
+
+	// Recuperamos también las subtareas eliminadas en la misma operación
+	indices := []int{i}
+	for _, d := range g.descendientes(id, true) {
+		if g.tareas[d].FechaEliminada.Equal(g.tareas[i].FechaEliminada) {
+			indices = append(indices, d)
+		}
+	}
+	for _, indice := range indices {
+		if err := g.comprobarPermiso(indice); err != nil {
+			return err
+		}
+	}
+
+	ahora := g.reloj()
+	var cambios []cambioTarea
+	for _, indice := range indices {
+		restaurada := g.tareas[indice].clonar()
+		restaurada.FechaEliminada = time.Time{}
+		restaurada.FechaActualizacion = ahora
+		if err := g.escribirDiario(entradaRegistro{Op: opGuardar, Tarea: &restaurada}); err != nil {
+			g.registrarOperacionCompuesta(OperacionRestaurar, cambios)
+			return err
+		}
+
+		cambios = append(cambios, nuevoCambio(&g.tareas[indice], &restaurada, indice))
+		g.sustituirTarea(indice, restaurada)
+		g.registrarGuardado(restaurada)
+	}
+
+	g.registrarOperacionCompuesta(OperacionRestaurar, cambios)
+	return nil
+}
+
+// VaciarPapelera elimina definitivamente las tareas de la papelera que el
+// usuario puede modificar.
+//
+// A diferencia de Eliminar, no puede deshacerse: las operaciones del
+// historial que afectaban a las tareas purgadas se descartan. La auditoría
+// sí conserva sus eventos, más uno "purgar" por tarea.
+//
+// Las tareas que el usuario no puede modificar no son un error: siguen en
+// la papelera, igual que sus tareas padre eliminadas, para que ninguna
+// quede huérfana. La purga automática por retención no distingue usuarios.
+//
+// Retorna:
+//   - int: cantidad de tareas purgadas
+//   - error: error si no puede escribirse el diario
+//
+func (g *GestorTareas) VaciarPapelera() (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.purgarPapelera(time.Time{}, g.papeleraAjena())
+}
+
+// papeleraAjena retorna los IDs de las tareas de la papelera que el usuario
+// no puede modificar y de sus tareas padre también eliminadas. Debe
+// llamarse con g.mu tomado.
+func (g *GestorTareas) papeleraAjena() map[int]bool {
+	ajenas := make(map[int]bool)
+	for i := range g.tareas {
+		if !g.tareas[i].EnPapelera() || g.comprobarPermiso(i) == nil {
+			continue
+		}
+		for j := i; j >= 0 && g.tareas[j].EnPapelera() && !ajenas[g.tareas[j].ID]; j = g.indicePorID(g.tareas[j].PadreID) {
+			ajenas[g.tareas[j].ID] = true
+		}
+	}
+	return ajenas
+}
+
+// purgarPapeleraVencida purga las tareas cuya retención en la papelera ya
+// terminó. No hace nada si la retención es 0.
+// Debe llamarse con g.mu tomado en modo escritura.
+func (g *GestorTareas) purgarPapeleraVencida() (int, error) {
+	if g.retencionPapelera <= 0 {
+		return 0, nil
+	}
+	return g.purgarPapelera(g.reloj().Add(-g.retencionPapelera), nil)
+}
+
+// purgarPapelera elimina definitivamente las tareas de la papelera
+// eliminadas antes de limite, salvo las de IDs en conservar; con el limite
+// cero, las elimina todas.
+//
+// Cada purga pasa por el diario y el almacenamiento igual que el resto de
+// mutaciones. Debe llamarse con g.mu tomado en modo escritura.
+func (g *GestorTareas) purgarPapelera(limite time.Time, conservar map[int]bool) (int, error) {
+	purgadas := make(map[int]bool)
+	conservadas := g.tareas[:0]
+
+	for i, tarea := range g.tareas {
+		if !tarea.EnPapelera() || conservar[tarea.ID] || (!limite.IsZero() && !tarea.FechaEliminada.Before(limite)) {
+			conservadas = append(conservadas, tarea)
+			continue
+		}
+		if err := g.escribirDiario(entradaRegistro{Op: opBorrar, ID: tarea.ID}); err != nil {
+			// Conservamos lo que aún no se purgó para no divergir del diario
+			g.tareas = append(conservadas, g.tareas[i:]...)
+			g.reconstruirIndice()
+			g.olvidarOperaciones(purgadas)
+			return len(purgadas), err
+		}
+		purgadas[tarea.ID] = true
+		g.registrarBorrado(tarea.ID)
+		g.registrarEventos([]Evento{{Tipo: OperacionPurgar, TareaID: tarea.ID, Antes: copiarTarea(&tarea),
+			Fecha: g.reloj(), Usuario: g.usuario}})
+	}
+
+	g.tareas = conservadas
+	if len(purgadas) > 0 {
+		g.reconstruirIndice()
+	}
+	g.olvidarOperaciones(purgadas)
+	return len(purgadas), nil
+}
+
+// avisarPurga informa al usuario de una purga automática.
+func (g *GestorTareas) avisarPurga(purgadas int, err error) {
+	if err != nil {
+		fmt.Fprintf(g.salida, "⚠️  Error al purgar la papelera: %v\n", err)
+	}
+	if purgadas > 0 {
+		fmt.Fprintf(g.salida, "✓ Purgadas %d tarea(s) de la papelera\n", purgadas)
+	}
+}
@@ -0,0 +1,202 @@
+// Tests de la papelera: eliminación lógica, restauración y purga
+
+package tareas
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestPapeleraOcultaTareas verifica que las tareas eliminadas no aparezcan
+// en listados, búsquedas ni estadísticas
+func TestPapeleraOcultaTareas(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t)
+	gestor.CrearConDatos(DatosTarea{Titulo: "Comprar pan", Etiquetas: []string{"casa"}, Prioridad: PrioridadAlta})
+	gestor.Crear("Comprar leche")
+	gestor.Eliminar(1)
+
+	eliminada := gestor.ListarPapelera()
+	if len(eliminada) != 1 || eliminada[0].FechaEliminada.IsZero() {
+		t.Fatalf("Se esperaba la tarea 1 en la papelera con fecha de eliminación, se obtuvo: %+v", eliminada)
+	}
+
+	tests := []struct {
+		nombre string
+		lista  []Tarea
+	}{
+		{"Listar", gestor.Listar()},
+		{"ListarPendientes", gestor.ListarPendientes()},
+		{"ListarPorEtiqueta", gestor.ListarPorEtiqueta("casa")},
+		{"ListarPorPrioridad", gestor.ListarPorPrioridad(PrioridadAlta)},
+		{"BuscarPorTexto", gestor.BuscarPorTexto("comprar")},
+	}
+	for _, tt := range tests {
+		for _, tarea := range tt.lista {
+			if tarea.ID == 1 {
+				t.Errorf("%s no debería incluir la tarea eliminada", tt.nombre)
+			}
+		}
+	}
+
+	if total, _, _ := gestor.Estadisticas(); total != 1 {
+		t.Errorf("Las estadísticas no deberían contar la papelera, total: %d", total)
+	}
+
+	var noEncontrada *ErrorNoEncontrada
+	if err := gestor.Completar(1); !errors.As(err, &noEncontrada) {
+		t.Errorf("Completar una tarea de la papelera debería dar ErrorNoEncontrada, se obtuvo: %v", err)
+	}
+}
+
+// TestRestaurarTarea verifica que la tarea vuelva intacta a los listados
+func TestRestaurarTarea(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t)
+	gestor.Crear("Tarea 1")
+	gestor.Crear("Tarea 2")
+	gestor.Completar(1)
+	gestor.Eliminar(1)
+
+	if err := gestor.Restaurar(1); err != nil {
+		t.Fatalf("Error al restaurar: %v", err)
+	}
+
+	lista := gestor.Listar()
+	if len(lista) != 2 || lista[0].ID != 1 || !lista[0].Completada || lista[0].EnPapelera() {
+		t.Errorf("La tarea 1 debería volver completada a su posición, se obtuvo: %+v", lista)
+	}
+	if len(gestor.ListarPapelera()) != 0 {
+		t.Error("La papelera debería quedar vacía")
+	}
+
+	// Solo pueden restaurarse tareas que estén en la papelera
+	var noEncontrada *ErrorNoEncontrada
+	for _, id := range []int{1, 999} {
+		if err := gestor.Restaurar(id); !errors.As(err, &noEncontrada) {
+			t.Errorf("Restaurar(%d): se esperaba ErrorNoEncontrada, se obtuvo: %v", id, err)
+		}
+	}
+
+	// Restaurar puede deshacerse
+	if op, err := gestor.Deshacer(); err != nil || op.Tipo != OperacionRestaurar {
+		t.Errorf("Se esperaba deshacer la restauración, se obtuvo: %v, %v", op, err)
+	}
+	if len(gestor.ListarPapelera()) != 1 {
+		t.Error("Deshacer la restauración debería devolver la tarea a la papelera")
+	}
+}
+
+// TestPurgaPorRetencion verifica la purga automática de tareas antiguas
+func TestPurgaPorRetencion(t *testing.T) {
+	ahora := time.Now()
+	almacen := NuevoAlmacenamientoMemoria(
+		Tarea{ID: 1, Titulo: "Eliminada hace 10 días", FechaEliminada: ahora.Add(-10 * 24 * time.Hour)},
+		Tarea{ID: 2, Titulo: "Eliminada hace 1 día", FechaEliminada: ahora.Add(-24 * time.Hour)},
+		Tarea{ID: 3, Titulo: "Tarea visible"},
+	)
+
+	tests := []struct {
+		nombre    string
+		retencion time.Duration
+		esperadas int
+	}{
+		{"retención de 7 días", 7 * 24 * time.Hour, 1},
+		{"retención de 12 horas", 12 * time.Hour, 0},
+		{"sin purga automática", 0, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			gestor, err := NuevoGestorTareas("", ConAlmacenamiento(NuevoAlmacenamientoMemoria(almacen.tareas...)), ConRetencionPapelera(tt.retencion))
+			if err != nil {
+				t.Fatalf("Error al crear gestor: %v", err)
+			}
+			if papelera := gestor.ListarPapelera(); len(papelera) != tt.esperadas {
+				t.Errorf("Se esperaban %d tareas en la papelera, hay: %+v", tt.esperadas, papelera)
+			}
+			if _, err := gestor.BuscarPorID(3); err != nil {
+				t.Error("Las tareas visibles nunca se purgan")
+			}
+		})
+	}
+
+	// El ID de una tarea purgada tampoco se reutiliza
+	gestor, _ := NuevoGestorTareas("", ConAlmacenamiento(NuevoAlmacenamientoMemoria(almacen.tareas...)), ConRetencionPapelera(time.Hour))
+	if nueva, _ := gestor.Crear("Tarea nueva"); nueva.ID != 4 {
+		t.Errorf("El próximo ID debería ser 4, es: %d", nueva.ID)
+	}
+}
+
+// TestVaciarPapelera verifica la purga manual y su efecto en el historial
+func TestVaciarPapelera(t *testing.T) {
+	archivoTemp := "test_papelera.json"
+	diarioTemp := "test_papelera.json.diario"
+	defer eliminarConRespaldos(archivoTemp)
+	defer os.Remove(diarioTemp)
+
+	gestor, _ := NuevoGestorTareas(archivoTemp, ConDiario(diarioTemp))
+	gestor.Crear("Tarea 1")
+	gestor.Crear("Tarea 2")
+	gestor.Eliminar(1)
+
+	purgadas, err := gestor.VaciarPapelera()
+	if err != nil || purgadas != 1 {
+		t.Fatalf("Se esperaba purgar 1 tarea, se obtuvo: %d, %v", purgadas, err)
+	}
+
+	// Las operaciones sobre la tarea purgada ya no pueden deshacerse
+	op, err := gestor.Deshacer()
+	if err != nil || op.TareaID != 2 {
+		t.Errorf("Se esperaba deshacer la creación de la tarea 2, se obtuvo: %v, %v", op, err)
+	}
+	if _, err := gestor.Deshacer(); !errors.Is(err, ErrNadaQueDeshacer) {
+		t.Errorf("Se esperaba ErrNadaQueDeshacer, se obtuvo: %v", err)
+	}
+
+	// La purga pasa por el diario: al reiniciar sin guardar no reaparece
+	recuperado, _ := NuevoGestorTareas(archivoTemp, ConDiario(diarioTemp))
+	if len(recuperado.ListarPapelera()) != 0 || len(recuperado.Listar()) != 0 {
+		t.Errorf("No deberían quedar tareas, hay: %+v %+v", recuperado.Listar(), recuperado.ListarPapelera())
+	}
+}
+
+// TestVaciarPapeleraRespetaPermisos verifica que cada usuario purgue solo
+// las tareas que puede modificar, sin dejar subtareas ajenas huérfanas, y
+// que no restaure subtareas ajenas junto con las suyas
+func TestVaciarPapeleraRespetaPermisos(t *testing.T) {
+	ana := nuevoGestorEnMemoria(t, ConUsuario("ana"))
+	luis := ana.ComoUsuario("luis")
+
+	ana.CrearConDatos(DatosTarea{Titulo: "Mudanza", Asignados: []string{"luis"}})
+	luis.CrearConDatos(DatosTarea{Titulo: "Cajas", PadreID: 1})
+	ana.Crear("Regar")
+	luis.Crear("Llamar")
+	luis.EliminarConSubtareas(1)
+	ana.Eliminar(3)
+	luis.Eliminar(4)
+
+	// La 1 es de ana, pero su subtarea 2 no: ambas siguen en la papelera
+	purgadas, err := ana.VaciarPapelera()
+	if err != nil || purgadas != 1 {
+		t.Fatalf("ana debería purgar 1 tarea, se obtuvo: %d, %v", purgadas, err)
+	}
+	if ids := idsDe(ana.ListarPapelera()); !slices.Equal(ids, []int{1, 2, 4}) {
+		t.Errorf("Papelera tras purgar ana = %v, se esperaba [1 2 4]", ids)
+	}
+
+	// ana no puede restaurar la 1 porque arrastraría la subtarea de luis
+	var permiso *ErrorPermiso
+	if err := ana.Restaurar(1); !errors.As(err, &permiso) || permiso.ID != 2 {
+		t.Errorf("Se esperaba *ErrorPermiso sobre la tarea 2, se obtuvo: %v", err)
+	}
+	if len(ana.ListarPapelera()) != 3 {
+		t.Errorf("No debería haberse restaurado ninguna tarea: %+v", ana.ListarPapelera())
+	}
+
+	purgadas, err = luis.VaciarPapelera()
+	if err != nil || purgadas != 3 || len(luis.ListarPapelera()) != 0 {
+		t.Errorf("luis debería purgar las 3 restantes, se obtuvo: %d, %v, %+v", purgadas, err, luis.ListarPapelera())
+	}
+}
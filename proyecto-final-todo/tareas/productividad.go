@@ -0,0 +1,295 @@
+package tareas
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Periodos por defecto de un InformeProductividad.
+const (
+	// DiasInformePorDefecto es la cantidad de días del rendimiento diario.
+	DiasInformePorDefecto = 14
+
+	// SemanasInformePorDefecto es la cantidad de semanas del rendimiento semanal.
+	SemanasInformePorDefecto = 8
+)
+
+// tramosAntiguedad son los tramos en que se reparten las tareas pendientes
+// según los días completos transcurridos desde su creación: cada tramo
+// empieza en desde y termina donde empieza el siguiente.
+var tramosAntiguedad = []struct {
+	nombre string
+	desde  int
+}{
+	{"< 1 día", 0},
+	{"1-7 días", 1},
+	{"8-30 días", 8},
+	{"31-90 días", 31},
+	{"> 90 días", 91},
+}
+
+// ParametrosInforme configura los periodos de un InformeProductividad. Los
+// valores 0 (o negativos) toman los valores por defecto.
+type ParametrosInforme struct {
+	// Dias es la cantidad de días, hasta hoy incluido, del rendimiento diario
+	Dias int
+
+	// Semanas es la cantidad de semanas, hasta la actual incluida, del
+	// rendimiento semanal
+	Semanas int
+
+	// Lista limita el informe del gestor a las tareas de una lista; vacío
+	// para todas (ver GestorTareas.InformeProductividad)
+	Lista string
+}
+
+// Recuento es la cantidad de tareas completadas en un día o una semana.
+type Recuento struct {
+	// Inicio es el comienzo del periodo: las 00:00 del día, o del lunes de
+	// la semana, en la zona horaria del informe
+	Inicio time.Time `json:"inicio"`
+
+	// Etiqueta describe el periodo para mostrarlo (ej: "lun 02/03")
+	Etiqueta string `json:"etiqueta"`
+
+	// Completadas es la cantidad de tareas completadas en el periodo
+	Completadas int `json:"completadas"`
+}
+
+// TiempoEntrega resume cuánto tardan las tareas en completarse, desde su
+// FechaCreacion hasta su FechaCompletada.
+type TiempoEntrega struct {
+	// Tareas es la cantidad de tareas completadas con fecha de completado
+	// (las completadas en versiones anteriores pueden no tenerla)
+	Tareas int `json:"tareas"`
+
+	// MediaDias y MedianaDias son la media y la mediana, en días
+	MediaDias   float64 `json:"media_dias"`
+	MedianaDias float64 `json:"mediana_dias"`
+}
+
+// TramoAntiguedad es la cantidad de tareas pendientes creadas hace una
+// cantidad de días dentro de un tramo.
+type TramoAntiguedad struct {
+	// Nombre describe el tramo (ej: "8-30 días")
+	Nombre string `json:"nombre"`
+
+	// DesdeDias es el primer día completo de antigüedad del tramo, que
+	// termina donde empieza el siguiente
+	DesdeDias int `json:"desde_dias"`
+
+	// Pendientes es la cantidad de tareas pendientes del tramo
+	Pendientes int `json:"pendientes"`
+}
+
+// InformeProductividad resume el trabajo hecho con un conjunto de tareas,
+// más allá de los recuentos de Estadisticas: cuántas se completan por día y
+// por semana, cuánto tardan en completarse, cuánto llevan esperando las
+// pendientes y las rachas de días seguidos completando alguna tarea.
+//
+// Los días y las semanas (de lunes a domingo) se cuentan en la zona horaria
+// del momento del informe. Se serializa a JSON para otros programas.
+type InformeProductividad struct {
+	// Fecha es el momento en que se generó el informe
+	Fecha time.Time `json:"fecha"`
+
+	// Total, Completadas y Pendientes son los recuentos de las tareas
+	Total       int `json:"total"`
+	Completadas int `json:"completadas"`
+	Pendientes  int `json:"pendientes"`
+
+	// PorDia y PorSemana son las tareas completadas en cada uno de los
+	// últimos días y semanas, del más antiguo al actual
+	PorDia    []Recuento `json:"por_dia"`
+	PorSemana []Recuento `json:"por_semana"`
+
+	// TiempoEntrega es el tiempo de creación a completado de todas las
+	// tareas completadas, no solo las de los periodos anteriores
+	TiempoEntrega TiempoEntrega `json:"tiempo_entrega"`
+
+	// Antiguedad reparte las tareas pendientes según su antigüedad
+	Antiguedad []TramoAntiguedad `json:"antiguedad"`
+
+	// RachaActual es la cantidad de días seguidos, hasta hoy o hasta ayer
+	// si hoy aún no se completó nada, con alguna tarea completada
+	RachaActual int `json:"racha_actual"`
+
+	// RachaMaxima es la racha más larga registrada, y InicioRachaMaxima su
+	// primer día (cero si no se completó ninguna tarea)
+	RachaMaxima       int       `json:"racha_maxima"`
+	InicioRachaMaxima time.Time `json:"inicio_racha_maxima,omitzero"`
+}
+
+// GenerarInformeProductividad calcula el informe de productividad de las
+// tareas de lista en el momento ahora.
+//
+// Es una función pura, para poder generar el informe de cualquier listado
+// (por ejemplo, el de una lista o el resultado de una consulta); el gestor
+// ofrece InformeProductividad para todas sus tareas.
+//
+// Parámetros:
+//   - lista: las tareas a analizar
+//   - ahora: el momento del informe, que fija el día actual y su zona horaria
+//   - p: los días y semanas del rendimiento (0 = valores por defecto)
+//
+// Retorna:
+//   - InformeProductividad: el informe calculado
+//
+// Ejemplo:
+//
+//	informe := GenerarInformeProductividad(gestor.ListarPorEtiqueta("trabajo"), time.Now(), ParametrosInforme{Dias: 7})
+//	fmt.Printf("Racha actual: %d día(s)\n", informe.RachaActual)
+//
+func GenerarInformeProductividad(lista []Tarea, ahora time.Time, p ParametrosInforme) InformeProductividad {
+	if p.Dias <= 0 {
+		p.Dias = DiasInformePorDefecto
+	}
+	if p.Semanas <= 0 {
+		p.Semanas = SemanasInformePorDefecto
+	}
+
+	hoy := inicioDia(ahora)
+	esteLunes := hoy.AddDate(0, 0, -(int(hoy.Weekday())+6)%7)
+	informe := InformeProductividad{Fecha: ahora, Total: len(lista)}
+
+	for i := range p.Dias {
+		dia := hoy.AddDate(0, 0, i-p.Dias+1)
+		etiqueta := fmt.Sprintf("%s %s", nombresDiasSemana[dia.Weekday()], dia.Format("02/01"))
+		informe.PorDia = append(informe.PorDia, Recuento{Inicio: dia, Etiqueta: etiqueta})
+	}
+	for i := range p.Semanas {
+		lunes := esteLunes.AddDate(0, 0, 7*(i-p.Semanas+1))
+		informe.PorSemana = append(informe.PorSemana, Recuento{Inicio: lunes, Etiqueta: "sem " + lunes.Format("02/01")})
+	}
+	for _, tramo := range tramosAntiguedad {
+		informe.Antiguedad = append(informe.Antiguedad, TramoAntiguedad{Nombre: tramo.nombre, DesdeDias: tramo.desde})
+	}
+
+	var entregas []float64
+	diasConCompletadas := make(map[time.Time]bool)
+	for _, tarea := range lista {
+		if !tarea.Completada {
+			informe.Pendientes++
+			// Una tarea creada "después" de ahora (relojes desajustados) es nueva
+			antiguedad := max(0, int(ahora.Sub(tarea.FechaCreacion)/(24*time.Hour)))
+			for i := len(tramosAntiguedad) - 1; i >= 0; i-- {
+				if antiguedad >= tramosAntiguedad[i].desde {
+					informe.Antiguedad[i].Pendientes++
+					break
+				}
+			}
+			continue
+		}
+
+		informe.Completadas++
+		if tarea.FechaCompletada.IsZero() {
+			continue
+		}
+		completada := tarea.FechaCompletada.In(ahora.Location())
+		dia := inicioDia(completada)
+		diasConCompletadas[dia] = true
+		sumarCompletada(informe.PorDia, completada, 1)
+		sumarCompletada(informe.PorSemana, completada, 7)
+		if !tarea.FechaCreacion.IsZero() {
+			entregas = append(entregas, max(completada.Sub(tarea.FechaCreacion).Hours()/24, 0))
+		}
+	}
+
+	informe.TiempoEntrega = calcularTiempoEntrega(entregas)
+	informe.RachaActual, informe.RachaMaxima, informe.InicioRachaMaxima = calcularRachas(diasConCompletadas, hoy)
+	return informe
+}
+
+// InformeProductividad calcula el informe de productividad de las tareas
+// fuera de la papelera, de todas o de la lista p.Lista, en el momento
+// actual según el reloj del gestor (ver GenerarInformeProductividad).
+//
+// Retorna:
+//   - InformeProductividad: el informe
+//   - error: ErrorNoEncontrada si p.Lista no existe
+//
+// Ejemplo:
+//
+//	informe, err := gestor.InformeProductividad(ParametrosInforme{Lista: "trabajo"})
+//	if err == nil {
+//		fmt.Printf("Tiempo medio de entrega: %.1f días\n", informe.TiempoEntrega.MediaDias)
+//	}
+//
+func (g *GestorTareas) InformeProductividad(p ParametrosInforme) (InformeProductividad, error) {
+	lista := g.Listar()
+	if p.Lista != "" {
+		var err error
+		if lista, err = g.ListarLista(p.Lista); err != nil {
+			return InformeProductividad{}, err
+		}
+	}
+	return GenerarInformeProductividad(lista, g.reloj(), p), nil
+}
+
+// inicioDia retorna las 00:00 del día de t, en su zona horaria.
+func inicioDia(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// sumarCompletada suma una tarea completada en el momento dado al periodo
+// que lo contiene, si alguno lo contiene. Cada periodo dura duracionDias.
+func sumarCompletada(periodos []Recuento, completada time.Time, duracionDias int) {
+	for i := range periodos {
+		if !completada.Before(periodos[i].Inicio) && completada.Before(periodos[i].Inicio.AddDate(0, 0, duracionDias)) {
+			periodos[i].Completadas++
+			return
+		}
+	}
+}
+
+// calcularTiempoEntrega calcula la media y la mediana de los tiempos de
+// entrega, en días.
+func calcularTiempoEntrega(dias []float64) TiempoEntrega {
+	if len(dias) == 0 {
+		return TiempoEntrega{}
+	}
+	slices.Sort(dias)
+	suma := 0.0
+	for _, d := range dias {
+		suma += d
+	}
+	mediana := dias[len(dias)/2]
+	if len(dias)%2 == 0 {
+		mediana = (dias[len(dias)/2-1] + dias[len(dias)/2]) / 2
+	}
+	return TiempoEntrega{Tareas: len(dias), MediaDias: suma / float64(len(dias)), MedianaDias: mediana}
+}
+
+// calcularRachas calcula la racha actual y la máxima de días seguidos con
+// alguna tarea completada, y el primer día de la racha máxima.
+func calcularRachas(dias map[time.Time]bool, hoy time.Time) (actual, maxima int, inicioMaxima time.Time) {
+	ordenados := make([]time.Time, 0, len(dias))
+	for dia := range dias {
+		ordenados = append(ordenados, dia)
+	}
+	slices.SortFunc(ordenados, func(a, b time.Time) int { return a.Compare(b) })
+
+	racha, inicio := 0, time.Time{}
+	for i, dia := range ordenados {
+		if i > 0 && ordenados[i-1].AddDate(0, 0, 1).Equal(dia) {
+			racha++
+		} else {
+			racha, inicio = 1, dia
+		}
+		if racha > maxima {
+			maxima, inicioMaxima = racha, inicio
+		}
+	}
+
+	// La racha actual sigue viva si hoy aún no se completó nada pero ayer sí
+	dia := hoy
+	if !dias[dia] {
+		dia = dia.AddDate(0, 0, -1)
+	}
+	for dias[dia] {
+		actual++
+		dia = dia.AddDate(0, 0, -1)
+	}
+	return actual, maxima, inicioMaxima
+}
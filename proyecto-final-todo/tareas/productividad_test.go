@@ -0,0 +1,144 @@
+// Tests del informe de productividad
+
+package tareas
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// tareasDeProductividad retorna tareas completadas y pendientes con fechas
+// conocidas, alrededor del lunes 2 de marzo de 2026
+func tareasDeProductividad() []Tarea {
+	fecha := func(mes time.Month, dia, hora int) time.Time {
+		return time.Date(2026, mes, dia, hora, 0, 0, 0, time.UTC)
+	}
+	return []Tarea{
+		{ID: 1, Titulo: "Lead de un día", Completada: true, FechaCreacion: fecha(3, 1, 10), FechaCompletada: fecha(3, 2, 10)},
+		{ID: 2, Titulo: "Lead de día y medio", Completada: true, FechaCreacion: fecha(3, 2, 9), FechaCompletada: fecha(3, 3, 21)},
+		{ID: 3, Titulo: "Lead de medio día", Completada: true, FechaCreacion: fecha(3, 3, 0), FechaCompletada: fecha(3, 3, 12)},
+		{ID: 4, Titulo: "Semana anterior", Completada: true, FechaCreacion: fecha(2, 20, 0), FechaCompletada: fecha(2, 25, 0)},
+		{ID: 5, Titulo: "Pendiente de hoy", FechaCreacion: fecha(3, 4, 8)},
+		{ID: 6, Titulo: "Pendiente de una semana", FechaCreacion: fecha(2, 25, 0)},
+		{ID: 7, Titulo: "Pendiente antigua", FechaCreacion: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: 8, Titulo: "Completada sin fecha", Completada: true, FechaCreacion: fecha(1, 1, 0)},
+	}
+}
+
+// completadasDe retorna las completadas de cada periodo, en orden
+func completadasDe(periodos []Recuento) []int {
+	var completadas []int
+	for _, periodo := range periodos {
+		completadas = append(completadas, periodo.Completadas)
+	}
+	return completadas
+}
+
+// TestInformeProductividad verifica el rendimiento por día y semana, el
+// tiempo de entrega, la antigüedad de las pendientes y las rachas
+func TestInformeProductividad(t *testing.T) {
+	miercoles := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	informe := GenerarInformeProductividad(tareasDeProductividad(), miercoles, ParametrosInforme{Dias: 3, Semanas: 2})
+
+	if informe.Total != 8 || informe.Completadas != 5 || informe.Pendientes != 3 {
+		t.Errorf("Recuentos = %d/%d/%d, se esperaba 8/5/3", informe.Total, informe.Completadas, informe.Pendientes)
+	}
+
+	// Rendimiento
+	if completadas := completadasDe(informe.PorDia); !reflect.DeepEqual(completadas, []int{1, 2, 0}) {
+		t.Errorf("Completadas por día = %v", completadas)
+	}
+	if informe.PorDia[0].Etiqueta != "lun 02/03" || !informe.PorDia[2].Inicio.Equal(time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Días inesperados: %+v", informe.PorDia)
+	}
+	if completadas := completadasDe(informe.PorSemana); !reflect.DeepEqual(completadas, []int{1, 3}) {
+		t.Errorf("Completadas por semana = %v", completadas)
+	}
+	if informe.PorSemana[0].Etiqueta != "sem 23/02" {
+		t.Errorf("La primera semana debería empezar el lunes 23/02: %q", informe.PorSemana[0].Etiqueta)
+	}
+
+	// Tiempo de entrega: 0,5, 1, 1,5 y 5 días (la completada sin fecha no cuenta)
+	esperado := TiempoEntrega{Tareas: 4, MediaDias: 2, MedianaDias: 1.25}
+	if informe.TiempoEntrega != esperado {
+		t.Errorf("Tiempo de entrega = %+v, se esperaba %+v", informe.TiempoEntrega, esperado)
+	}
+
+	// Antigüedad de las pendientes
+	var pendientes []int
+	for _, tramo := range informe.Antiguedad {
+		pendientes = append(pendientes, tramo.Pendientes)
+	}
+	if !reflect.DeepEqual(pendientes, []int{1, 1, 0, 0, 1}) {
+		t.Errorf("Pendientes por tramo = %v", pendientes)
+	}
+
+	// Rachas: 25/02, y 02/03-03/03; hoy aún no se completó nada
+	if informe.RachaActual != 2 || informe.RachaMaxima != 2 || !informe.InicioRachaMaxima.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Rachas = %d y %d desde %v", informe.RachaActual, informe.RachaMaxima, informe.InicioRachaMaxima)
+	}
+}
+
+// TestRachasYValoresPorDefecto verifica una racha interrumpida, un informe
+// sin tareas, una pendiente con fecha futura y los periodos por defecto del
+// gestor
+func TestRachasYValoresPorDefecto(t *testing.T) {
+	viernes := time.Date(2026, 3, 6, 9, 0, 0, 0, time.UTC)
+	if informe := GenerarInformeProductividad(tareasDeProductividad(), viernes, ParametrosInforme{}); informe.RachaActual != 0 || informe.RachaMaxima != 2 {
+		t.Errorf("Tras un día sin completar nada la racha actual debería ser 0: %d (máxima %d)", informe.RachaActual, informe.RachaMaxima)
+	}
+
+	vacio := GenerarInformeProductividad(nil, viernes, ParametrosInforme{})
+	if vacio.TiempoEntrega != (TiempoEntrega{}) || vacio.RachaMaxima != 0 || !vacio.InicioRachaMaxima.IsZero() {
+		t.Errorf("Informe vacío inesperado: %+v", vacio)
+	}
+
+	// Una pendiente creada después de ahora (relojes desajustados) cae en
+	// el tramo más reciente
+	futura := []Tarea{{ID: 1, Titulo: "Del futuro", FechaCreacion: viernes.Add(36 * time.Hour)}}
+	if informe := GenerarInformeProductividad(futura, viernes, ParametrosInforme{}); informe.Antiguedad[0].Pendientes != 1 {
+		t.Errorf("La pendiente futura debería contar en %q: %+v", informe.Antiguedad[0].Nombre, informe.Antiguedad)
+	}
+
+	ahora := viernes
+	gestor := nuevoGestorEnMemoria(t, ConReloj(func() time.Time { return ahora }))
+	gestor.Crear("Completada hoy")
+	gestor.Completar(1)
+	informe, _ := gestor.InformeProductividad(ParametrosInforme{})
+	if len(informe.PorDia) != DiasInformePorDefecto || len(informe.PorSemana) != SemanasInformePorDefecto {
+		t.Errorf("Periodos = %d días y %d semanas", len(informe.PorDia), len(informe.PorSemana))
+	}
+	if informe.PorDia[len(informe.PorDia)-1].Completadas != 1 || informe.RachaActual != 1 {
+		t.Errorf("La tarea completada hoy debería contar: %+v", informe.PorDia[len(informe.PorDia)-1])
+	}
+}
+
+// TestInformeProductividadDeLista verifica que el informe del gestor se
+// limite a una lista y use el reloj del gestor
+func TestInformeProductividadDeLista(t *testing.T) {
+	ahora := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	gestor := nuevoGestorEnMemoria(t, ConReloj(func() time.Time { return ahora }))
+	gestor.CrearLista("casa")
+	gestor.Crear("Enviar informe")
+	gestor.CrearConDatos(DatosTarea{Titulo: "Regar", Lista: "casa"})
+	gestor.CrearConDatos(DatosTarea{Titulo: "Pintar", Lista: "casa"})
+	gestor.Completar(2)
+
+	informe, err := gestor.InformeProductividad(ParametrosInforme{Dias: 3, Lista: " Casa "})
+	if err != nil {
+		t.Fatalf("Error al generar el informe de la lista: %v", err)
+	}
+	if informe.Total != 2 || informe.Completadas != 1 || !informe.Fecha.Equal(ahora) {
+		t.Errorf("Informe inesperado: %d tareas, %d completadas, fecha %v", informe.Total, informe.Completadas, informe.Fecha)
+	}
+	if completadas := completadasDe(informe.PorDia); !reflect.DeepEqual(completadas, []int{0, 0, 1}) {
+		t.Errorf("La completada hoy según el reloj del gestor debería contar hoy: %v", completadas)
+	}
+
+	var noEncontrada *ErrorNoEncontrada
+	if _, err := gestor.InformeProductividad(ParametrosInforme{Lista: "ocio"}); !errors.As(err, &noEncontrada) {
+		t.Errorf("Una lista inexistente debería dar ErrorNoEncontrada: %v", err)
+	}
+}
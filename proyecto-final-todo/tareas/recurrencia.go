@@ -0,0 +1,379 @@
+package tareas
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Frecuencia es la unidad de tiempo en que se repite una tarea recurrente.
+type Frecuencia string
+
+// Frecuencias admitidas.
+const (
+	FrecuenciaDiaria  Frecuencia = "diaria"
+	FrecuenciaSemanal Frecuencia = "semanal"
+	FrecuenciaMensual Frecuencia = "mensual"
+)
+
+// Recurrencia es la regla con la que se repite una tarea.
+//
+// Al completar una tarea recurrente con Completar, el gestor crea su
+// siguiente ocurrencia: una tarea nueva, con otro ID, los mismos datos y
+// la fecha de vencimiento desplazada según la regla.
+//
+// Ejemplos de reglas:
+//
+//	// Todos los días
+//	Recurrencia{Frecuencia: FrecuenciaDiaria}
+//
+//	// Cada dos semanas, los lunes y jueves, hasta fin de año
+//	Recurrencia{
+//		Frecuencia: FrecuenciaSemanal,
+//		Intervalo:  2,
+//		DiasSemana: []time.Weekday{time.Monday, time.Thursday},
+//		Hasta:      time.Date(2026, 12, 31, 0, 0, 0, 0, time.Local),
+//	}
+type Recurrencia struct {
+	// Frecuencia es la unidad de repetición: diaria, semanal o mensual
+	Frecuencia Frecuencia `json:"frecuencia"`
+
+	// Intervalo es cada cuántas unidades se repite (1 = cada día, semana o
+	// mes). El valor 0 equivale a 1.
+	Intervalo int `json:"intervalo"`
+
+	// DiasSemana restringe una recurrencia semanal a esos días (0 = domingo).
+	// Vacío: se repite el mismo día de la semana que la ocurrencia anterior.
+	DiasSemana []time.Weekday `json:"dias_semana,omitempty"`
+
+	// DiaMes es el día del mes en que vence una recurrencia mensual. El
+	// valor 0 toma el del primer vencimiento; el gestor lo fija al crear la
+	// siguiente ocurrencia para que un mes corto no desplace las demás
+	// (31/01 → 28/02 → 31/03).
+	DiaMes int `json:"dia_mes,omitempty"`
+
+	// Hasta es el último día (inclusive) en que puede vencer una ocurrencia.
+	// El valor cero significa que la tarea se repite indefinidamente.
+	Hasta time.Time `json:"hasta,omitzero"`
+}
+
+// nombresDiasSemana son las abreviaturas de los días, indexadas por time.Weekday.
+var nombresDiasSemana = [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"}
+
+// String describe la regla de forma legible, por ejemplo
+// "cada 2 semanas (lun, jue) hasta 31/12/2026".
+func (r Recurrencia) String() string {
+	intervalo := max(r.Intervalo, 1)
+	unidades := map[Frecuencia]string{
+		FrecuenciaDiaria:  "días",
+		FrecuenciaSemanal: "semanas",
+		FrecuenciaMensual: "meses",
+	}
+
+	descripcion := string(r.Frecuencia)
+	if intervalo > 1 {
+		descripcion = fmt.Sprintf("cada %d %s", intervalo, unidades[r.Frecuencia])
+	}
+	if len(r.DiasSemana) > 0 {
+		dias := make([]string, len(r.DiasSemana))
+		for i, dia := range r.DiasSemana {
+			dias[i] = nombresDiasSemana[dia]
+		}
+		descripcion += fmt.Sprintf(" (%s)", strings.Join(dias, ", "))
+	}
+	if r.DiaMes > 0 {
+		descripcion += fmt.Sprintf(" (día %d)", r.DiaMes)
+	}
+	if !r.Hasta.IsZero() {
+		descripcion += " hasta " + r.Hasta.Format("02/01/2006")
+	}
+	return descripcion
+}
+
+// Siguiente calcula la fecha de la ocurrencia que sigue a desde, conservando
+// la hora del día. No tiene en cuenta Hasta.
+//
+// Las recurrencias mensuales vencen el día DiaMes (el de desde si es 0); si
+// el mes destino es más corto, usan su último día (31/01 → 28/02), y con
+// DiaMes 31 la siguiente vuelve al día 31 (28/02 → 31/03).
+//
+// Ejemplo:
+//
+//	r := Recurrencia{Frecuencia: FrecuenciaSemanal, DiasSemana: []time.Weekday{time.Monday, time.Thursday}}
+//	r.Siguiente(lunes) // el jueves de esa misma semana
+//
+func (r Recurrencia) Siguiente(desde time.Time) time.Time {
+	intervalo := max(r.Intervalo, 1)
+
+	switch r.Frecuencia {
+	case FrecuenciaSemanal:
+		if len(r.DiasSemana) == 0 {
+			return desde.AddDate(0, 0, 7*intervalo)
+		}
+		// Probamos día a día; solo valen las semanas múltiplo del intervalo
+		semanaInicial := inicioDeSemana(desde)
+		for dia := 1; dia <= 7*intervalo; dia++ {
+			candidata := desde.AddDate(0, 0, dia)
+			semanas := int(inicioDeSemana(candidata).Sub(semanaInicial).Hours()) / (7 * 24)
+			if semanas%intervalo == 0 && slices.Contains(r.DiasSemana, candidata.Weekday()) {
+				return candidata
+			}
+		}
+		return desde.AddDate(0, 0, 7*intervalo)
+
+	case FrecuenciaMensual:
+		return sumarMeses(desde, intervalo, r.DiaMes)
+
+	default:
+		return desde.AddDate(0, 0, intervalo)
+	}
+}
+
+// ValidarRecurrencia valida una regla de recurrencia; nil (sin recurrencia)
+// siempre es válida.
+//
+// La frecuencia debe ser diaria, semanal o mensual, el intervalo estar
+// entre 0 y 365 (0 equivale a 1), los días de la semana solo pueden
+// indicarse en recurrencias semanales y el día del mes, entre 1 y 31, en
+// las mensuales.
+//
+func ValidarRecurrencia(r *Recurrencia) error {
+	if r == nil {
+		return nil
+	}
+	switch r.Frecuencia {
+	case FrecuenciaDiaria, FrecuenciaSemanal, FrecuenciaMensual:
+	default:
+		return &ErrorValidacion{fmt.Sprintf("frecuencia inválida %q: debe ser diaria, semanal o mensual", r.Frecuencia)}
+	}
+	if r.Intervalo < 0 || r.Intervalo > 365 {
+		return &ErrorValidacion{"el intervalo de repetición debe estar entre 0 y 365 (0 equivale a 1)"}
+	}
+	if len(r.DiasSemana) > 0 && r.Frecuencia != FrecuenciaSemanal {
+		return &ErrorValidacion{"los días de la semana solo se admiten en recurrencias semanales"}
+	}
+	if r.DiaMes < 0 || r.DiaMes > 31 {
+		return &ErrorValidacion{fmt.Sprintf("día del mes inválido %d: debe estar entre 1 y 31", r.DiaMes)}
+	}
+	if r.DiaMes > 0 && r.Frecuencia != FrecuenciaMensual {
+		return &ErrorValidacion{"el día del mes solo se admite en recurrencias mensuales"}
+	}
+	for _, dia := range r.DiasSemana {
+		if dia < time.Sunday || dia > time.Saturday {
+			return &ErrorValidacion{fmt.Sprintf("día de la semana inválido %d: debe estar entre 0 (domingo) y 6 (sábado)", dia)}
+		}
+	}
+	return nil
+}
+
+// ParsearDiasSemana convierte una lista de días separados por comas, como
+// "lun,mié,vie", en días de la semana. Acepta las abreviaturas de tres
+// letras o el nombre completo, con o sin tilde y sin distinguir mayúsculas.
+//
+// Ejemplo:
+//
+//	dias, err := ParsearDiasSemana("lunes, jueves")
+//	// dias == []time.Weekday{time.Monday, time.Thursday}
+//
+func ParsearDiasSemana(texto string) ([]time.Weekday, error) {
+	var dias []time.Weekday
+	for _, parte := range strings.Split(texto, ",") {
+		nombre := normalizarTexto(strings.TrimSpace(parte))
+		if nombre == "" {
+			continue
+		}
+		encontrado := false
+		for dia, abreviatura := range nombresDiasSemana {
+			if len(nombre) >= 3 && strings.HasPrefix(nombre, normalizarTexto(abreviatura)) {
+				dias = append(dias, time.Weekday(dia))
+				encontrado = true
+				break
+			}
+		}
+		if !encontrado {
+			return nil, &ErrorValidacion{fmt.Sprintf("día de la semana desconocido %q", strings.TrimSpace(parte))}
+		}
+	}
+	return dias, nil
+}
+
+// CompletarConSiguiente completa una tarea igual que Completar y, si es
+// recurrente, crea su siguiente ocurrencia.
+//
+// La nueva tarea recibe un ID (y UID) nuevo, los mismos datos (título,
+// descripción, prioridad, etiquetas, tarea padre, lista, propietario,
+// asignados y regla de recurrencia, pero no las dependencias) y vence en la
+// siguiente fecha de la regla posterior al momento actual: si la tarea se
+// completa con retraso, las ocurrencias ya pasadas se saltan. Sin fecha de
+// vencimiento, las ocurrencias se cuentan desde el momento en que se
+// completa. La tarea completada pierde la regla, que pasa a la nueva
+// ocurrencia, de modo que reabrirla y completarla otra vez no genera
+// duplicados.
+//
+// No se crea ninguna ocurrencia si la siguiente fecha supera Recurrencia.Hasta.
+// Deshacer revierte la operación completa, incluida la nueva tarea.
+//
+// Parámetros:
+//   - id: el identificador único de la tarea a completar
+//
+// Retorna:
+//   - *Tarea: copia de la siguiente ocurrencia, o nil si no se creó ninguna
+//   - error: los mismos errores que Completar
+//
+// Ejemplo:
+//
+//	siguiente, err := gestor.CompletarConSiguiente(3)
+//	if err == nil && siguiente != nil {
+//		fmt.Printf("🔁 Próxima: [%d] vence el %s\n", siguiente.ID, siguiente.FechaVencimiento.Format("02/01/2006"))
+//	}
+//
+func (g *GestorTareas) CompletarConSiguiente(id int) (*Tarea, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	i := g.indiceVisible(id)
+	if i < 0 {
+		return nil, &ErrorNoEncontrada{ID: id}
+	}
+	if err := g.comprobarPermiso(i); err != nil {
+		return nil, err
+	}
+	if g.tareas[i].Completada {
+		return nil, ErrTareaYaCompletada
+	}
+	if pendientes := g.requisitosPendientes(g.tareas[i]); len(pendientes) > 0 {
+		return nil, &ErrorBloqueada{ID: id, Pendientes: pendientes}
+	}
+
+	ahora := g.reloj()
+	completada := g.tareas[i].clonar()
+	completada.Completada = true
+	completada.FechaCompletada = ahora
+	completada.FechaActualizacion = ahora
+
+	siguiente, repetir := g.siguienteOcurrencia(completada, ahora)
+	if repetir {
+		uid, err := g.nuevoUID(ahora)
+		if err != nil {
+			return nil, err
+		}
+		siguiente.UID = uid
+		completada.Recurrencia = nil
+	}
+
+	if err := g.escribirDiario(entradaRegistro{Op: opGuardar, Tarea: &completada}); err != nil {
+		return nil, err
+	}
+	cambios := []cambioTarea{nuevoCambio(&g.tareas[i], &completada, i)}
+	g.sustituirTarea(i, completada)
+	g.registrarGuardado(completada)
+
+	if !repetir {
+		g.registrarOperacionCompuesta(OperacionCompletar, cambios)
+		return nil, nil
+	}
+
+	if err := g.escribirDiario(entradaRegistro{Op: opGuardar, Tarea: &siguiente}); err != nil {
+		// La tarea ya quedó completada: lo registramos para poder deshacerlo
+		g.registrarOperacionCompuesta(OperacionCompletar, cambios)
+		return nil, err
+	}
+	g.agregarTarea(siguiente)
+	g.proximoID++
+	g.registrarGuardado(siguiente)
+	cambios = append(cambios, nuevoCambio(nil, &siguiente, len(g.tareas)-1))
+	g.registrarOperacionCompuesta(OperacionCompletar, cambios)
+
+	siguiente = siguiente.clonar()
+	return &siguiente, nil
+}
+
+// siguienteOcurrencia construye la ocurrencia que sigue a la tarea
+// recurrente completada en el momento ahora. Retorna false si la tarea no
+// es recurrente o la regla ya terminó. Debe llamarse con g.mu tomado.
+func (g *GestorTareas) siguienteOcurrencia(tarea Tarea, ahora time.Time) (Tarea, bool) {
+	regla := tarea.Recurrencia
+	if regla == nil {
+		return Tarea{}, false
+	}
+
+	vencimiento := tarea.FechaVencimiento
+	if vencimiento.IsZero() {
+		vencimiento = ahora
+	}
+	// Las mensuales quedan ancladas al día de su primer vencimiento
+	regla = normalizarRecurrencia(regla)
+	if regla.Frecuencia == FrecuenciaMensual && regla.DiaMes == 0 {
+		regla.DiaMes = vencimiento.Day()
+	}
+	vencimiento = regla.Siguiente(vencimiento)
+	for !vencimiento.After(ahora) {
+		vencimiento = regla.Siguiente(vencimiento)
+	}
+	if !regla.Hasta.IsZero() && vencimiento.After(regla.Hasta) && !mismoDia(vencimiento, regla.Hasta) {
+		return Tarea{}, false
+	}
+
+	// La padre pudo eliminarse después de crear la tarea (p. ej. con Deshacer)
+	padreID := tarea.PadreID
+	if g.indiceVisible(padreID) < 0 {
+		padreID = 0
+	}
+
+	siguiente := tarea.clonar()
+	return Tarea{
+		ID:               g.proximoID,
+		Titulo:           siguiente.Titulo,
+		FechaCreacion:    ahora,
+		Descripcion:      siguiente.Descripcion,
+		Prioridad:        siguiente.Prioridad,
+		FechaVencimiento: vencimiento,
+		Etiquetas:        siguiente.Etiquetas,
+		PadreID:          padreID,
+		Recurrencia:      regla,
+		Lista:            siguiente.Lista,
+		Propietario:      siguiente.Propietario,
+		Asignados:        siguiente.Asignados,
+	}, true
+}
+
+// normalizarRecurrencia retorna una copia de la regla con el intervalo
+// explícito y los días de la semana ordenados y sin repetir, o nil si no
+// hay regla.
+func normalizarRecurrencia(r *Recurrencia) *Recurrencia {
+	if r == nil {
+		return nil
+	}
+	normalizada := *r
+	normalizada.Intervalo = max(r.Intervalo, 1)
+	if len(r.DiasSemana) > 0 {
+		normalizada.DiasSemana = slices.Compact(slices.Sorted(slices.Values(r.DiasSemana)))
+	}
+	return &normalizada
+}
+
+// inicioDeSemana retorna el lunes de la semana de t, a medianoche UTC, para
+// contar semanas completas sin que influyan los cambios de horario.
+func inicioDeSemana(t time.Time) time.Time {
+	dia := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return dia.AddDate(0, 0, -((int(dia.Weekday()) + 6) % 7))
+}
+
+// sumarMeses suma n meses a t y retorna el día dia del mes destino (el de
+// t si es 0), o su último día si el mes es más corto.
+func sumarMeses(t time.Time, n, dia int) time.Time {
+	anio, mes, diaT := t.Date()
+	if dia == 0 {
+		dia = diaT
+	}
+	primero := time.Date(anio, mes+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	ultimo := primero.AddDate(0, 1, -1).Day()
+	return time.Date(primero.Year(), primero.Month(), min(dia, ultimo), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// mismoDia indica si a y b caen en la misma fecha del calendario de a.
+func mismoDia(a, b time.Time) bool {
+	b = b.In(a.Location())
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
@@ -0,0 +1,213 @@
+// Tests de tareas recurrentes
+
+package tareas
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestRecurrenciaSiguiente verifica el cálculo de la siguiente fecha de cada regla
+func TestRecurrenciaSiguiente(t *testing.T) {
+	lunes := time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC)
+	jueves := time.Date(2026, 3, 5, 18, 0, 0, 0, time.UTC)
+	lunYJue := []time.Weekday{time.Monday, time.Thursday}
+
+	tests := []struct {
+		nombre   string
+		regla    Recurrencia
+		desde    time.Time
+		esperada time.Time
+	}{
+		{"diaria", Recurrencia{Frecuencia: FrecuenciaDiaria}, lunes, lunes.AddDate(0, 0, 1)},
+		{"cada 3 días", Recurrencia{Frecuencia: FrecuenciaDiaria, Intervalo: 3}, lunes, jueves},
+		{"semanal sin días", Recurrencia{Frecuencia: FrecuenciaSemanal}, jueves, jueves.AddDate(0, 0, 7)},
+		{"lunes y jueves desde lunes", Recurrencia{Frecuencia: FrecuenciaSemanal, DiasSemana: lunYJue}, lunes, jueves},
+		{"lunes y jueves desde jueves", Recurrencia{Frecuencia: FrecuenciaSemanal, DiasSemana: lunYJue}, jueves, lunes.AddDate(0, 0, 7)},
+		{"cada 2 semanas desde jueves", Recurrencia{Frecuencia: FrecuenciaSemanal, Intervalo: 2, DiasSemana: lunYJue}, jueves, lunes.AddDate(0, 0, 14)},
+		{"mensual", Recurrencia{Frecuencia: FrecuenciaMensual}, lunes, time.Date(2026, 4, 2, 18, 0, 0, 0, time.UTC)},
+		{"mensual a un mes más corto", Recurrencia{Frecuencia: FrecuenciaMensual}, time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC), time.Date(2026, 2, 28, 9, 0, 0, 0, time.UTC)},
+		{"mensual anclada al 31", Recurrencia{Frecuencia: FrecuenciaMensual, DiaMes: 31}, time.Date(2026, 2, 28, 9, 0, 0, 0, time.UTC), time.Date(2026, 3, 31, 9, 0, 0, 0, time.UTC)},
+		{"trimestral cruzando el año", Recurrencia{Frecuencia: FrecuenciaMensual, Intervalo: 3}, time.Date(2026, 11, 15, 9, 0, 0, 0, time.UTC), time.Date(2027, 2, 15, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			if obtenida := tt.regla.Siguiente(tt.desde); !obtenida.Equal(tt.esperada) {
+				t.Errorf("Siguiente(%s) = %s, se esperaba %s", tt.desde.Format(time.DateTime), obtenida.Format(time.DateTime), tt.esperada.Format(time.DateTime))
+			}
+		})
+	}
+}
+
+// TestCompletarTareaRecurrente verifica que completar cree la siguiente
+// ocurrencia y que Deshacer la elimine
+func TestCompletarTareaRecurrente(t *testing.T) {
+	ahora := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	gestor := nuevoGestorEnMemoria(t, ConReloj(func() time.Time { return ahora }))
+
+	gestor.CrearConDatos(DatosTarea{
+		Titulo:           "Sacar la basura",
+		Prioridad:        PrioridadMedia,
+		FechaVencimiento: time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC),
+		Etiquetas:        []string{"casa"},
+		Recurrencia: &Recurrencia{
+			Frecuencia: FrecuenciaSemanal,
+			DiasSemana: []time.Weekday{time.Thursday, time.Monday},
+		},
+	})
+
+	ahora = ahora.Add(2 * time.Hour)
+	siguiente, err := gestor.CompletarConSiguiente(1)
+	if err != nil {
+		t.Fatalf("Error al completar: %v", err)
+	}
+	if siguiente == nil || siguiente.ID != 2 {
+		t.Fatalf("Se esperaba la siguiente ocurrencia con ID 2, se obtuvo: %+v", siguiente)
+	}
+	if esperado := time.Date(2026, 3, 5, 18, 0, 0, 0, time.UTC); !siguiente.FechaVencimiento.Equal(esperado) {
+		t.Errorf("La siguiente ocurrencia debería vencer el jueves %s, vence: %s", esperado, siguiente.FechaVencimiento)
+	}
+	if siguiente.Completada || !siguiente.FechaCreacion.Equal(ahora) {
+		t.Errorf("La ocurrencia debería estar pendiente y creada con el reloj del gestor: %+v", siguiente)
+	}
+	if siguiente.Prioridad != PrioridadMedia || !slices.Equal(siguiente.Etiquetas, []string{"casa"}) {
+		t.Errorf("La ocurrencia debería conservar prioridad y etiquetas: %+v", siguiente)
+	}
+	if siguiente.Recurrencia == nil || !slices.Equal(siguiente.Recurrencia.DiasSemana, []time.Weekday{time.Monday, time.Thursday}) {
+		t.Errorf("La regla debería pasar a la ocurrencia con los días ordenados: %+v", siguiente.Recurrencia)
+	}
+
+	completada, _ := gestor.BuscarPorID(1)
+	if !completada.FechaCompletada.Equal(ahora) || completada.Recurrencia != nil {
+		t.Errorf("La tarea completada debería fecharse con el reloj y perder la regla: %+v", completada)
+	}
+
+	// Deshacer revierte el completado y la creación de la ocurrencia juntos
+	op, err := gestor.Deshacer()
+	if err != nil || op.Tipo != OperacionCompletar {
+		t.Fatalf("Se esperaba deshacer el completado, se obtuvo: %v, %v", op, err)
+	}
+	if lista := gestor.Listar(); len(lista) != 1 || lista[0].Completada || lista[0].Recurrencia == nil {
+		t.Errorf("Solo debería quedar la tarea original, pendiente y recurrente: %+v", lista)
+	}
+}
+
+// TestRecurrenciaConRetrasoYFin verifica que se salten las ocurrencias ya
+// pasadas y que la regla termine en su fecha límite
+func TestRecurrenciaConRetrasoYFin(t *testing.T) {
+	ahora := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	gestor := nuevoGestorEnMemoria(t, ConReloj(func() time.Time { return ahora }))
+
+	gestor.CrearConDatos(DatosTarea{
+		Titulo:           "Regar las plantas",
+		FechaVencimiento: time.Date(2026, 2, 20, 18, 0, 0, 0, time.UTC),
+		Recurrencia: &Recurrencia{
+			Frecuencia: FrecuenciaDiaria,
+			Intervalo:  2,
+			Hasta:      time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC),
+		},
+	})
+
+	// Completada con diez días de retraso: la siguiente es la primera futura
+	siguiente, err := gestor.CompletarConSiguiente(1)
+	if err != nil || siguiente == nil {
+		t.Fatalf("Se esperaba una nueva ocurrencia, se obtuvo: %+v, %v", siguiente, err)
+	}
+	if esperado := time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC); !siguiente.FechaVencimiento.Equal(esperado) {
+		t.Errorf("Vencimiento esperado %s, obtenido: %s", esperado, siguiente.FechaVencimiento)
+	}
+
+	// El día límite todavía admite una ocurrencia
+	siguiente, err = gestor.CompletarConSiguiente(2)
+	if err != nil || siguiente == nil || !siguiente.FechaVencimiento.Equal(time.Date(2026, 3, 4, 18, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Se esperaba una ocurrencia el día límite, se obtuvo: %+v, %v", siguiente, err)
+	}
+
+	// La siguiente superaría Hasta: no se crea ninguna más
+	siguiente, err = gestor.CompletarConSiguiente(3)
+	if err != nil || siguiente != nil {
+		t.Errorf("No debería crearse otra ocurrencia tras la fecha límite, se obtuvo: %+v, %v", siguiente, err)
+	}
+	if total, completadas, _ := gestor.Estadisticas(); total != 3 || completadas != 3 {
+		t.Errorf("Se esperaban 3 tareas completadas, hay %d de %d", completadas, total)
+	}
+
+	// Una tarea sin vencimiento cuenta desde el momento en que se completa
+	gestor.CrearConDatos(DatosTarea{Titulo: "Revisar el correo", Recurrencia: &Recurrencia{Frecuencia: FrecuenciaDiaria}})
+	if siguiente, _ := gestor.CompletarConSiguiente(4); siguiente == nil || !siguiente.FechaVencimiento.Equal(ahora.AddDate(0, 0, 1)) {
+		t.Errorf("La ocurrencia debería vencer un día después de completarse: %+v", siguiente)
+	}
+}
+
+// TestRecurrenciaMensualFinDeMes verifica que una recurrencia mensual que
+// empieza el 31 no se desplace al pasar por un mes más corto
+func TestRecurrenciaMensualFinDeMes(t *testing.T) {
+	ahora := time.Date(2026, 1, 30, 9, 0, 0, 0, time.UTC)
+	gestor := nuevoGestorEnMemoria(t, ConReloj(func() time.Time { return ahora }))
+	tarea, _ := gestor.CrearConDatos(DatosTarea{
+		Titulo:           "Pagar el alquiler",
+		FechaVencimiento: time.Date(2026, 1, 31, 18, 0, 0, 0, time.UTC),
+		Recurrencia:      &Recurrencia{Frecuencia: FrecuenciaMensual},
+	})
+
+	id := tarea.ID
+	for _, esperado := range []time.Time{
+		time.Date(2026, 2, 28, 18, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 31, 18, 0, 0, 0, time.UTC),
+		time.Date(2026, 4, 30, 18, 0, 0, 0, time.UTC),
+	} {
+		siguiente, err := gestor.CompletarConSiguiente(id)
+		if err != nil || siguiente == nil {
+			t.Fatalf("Se esperaba una nueva ocurrencia, se obtuvo: %+v, %v", siguiente, err)
+		}
+		if !siguiente.FechaVencimiento.Equal(esperado) {
+			t.Errorf("Vencimiento esperado %s, obtenido: %s", esperado.Format("02/01"), siguiente.FechaVencimiento.Format("02/01"))
+		}
+		if siguiente.Recurrencia.DiaMes != 31 {
+			t.Errorf("La regla debería quedar anclada al día 31: %+v", siguiente.Recurrencia)
+		}
+		id = siguiente.ID
+	}
+}
+
+// TestValidarRecurrencia verifica las reglas de validación y el parseo de días
+func TestValidarRecurrencia(t *testing.T) {
+	tests := []struct {
+		nombre string
+		regla  *Recurrencia
+		valida bool
+	}{
+		{"sin recurrencia", nil, true},
+		{"semanal con días", &Recurrencia{Frecuencia: FrecuenciaSemanal, DiasSemana: []time.Weekday{time.Friday}}, true},
+		{"frecuencia desconocida", &Recurrencia{Frecuencia: "anual"}, false},
+		{"intervalo negativo", &Recurrencia{Frecuencia: FrecuenciaDiaria, Intervalo: -1}, false},
+		{"días en una diaria", &Recurrencia{Frecuencia: FrecuenciaDiaria, DiasSemana: []time.Weekday{time.Monday}}, false},
+		{"día fuera de rango", &Recurrencia{Frecuencia: FrecuenciaSemanal, DiasSemana: []time.Weekday{7}}, false},
+		{"intervalo cero", &Recurrencia{Frecuencia: FrecuenciaDiaria, Intervalo: 0}, true},
+		{"mensual con día del mes", &Recurrencia{Frecuencia: FrecuenciaMensual, DiaMes: 31}, true},
+		{"día del mes fuera de rango", &Recurrencia{Frecuencia: FrecuenciaMensual, DiaMes: 32}, false},
+		{"día del mes en una semanal", &Recurrencia{Frecuencia: FrecuenciaSemanal, DiaMes: 1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			err := ValidarRecurrencia(tt.regla)
+			var errValidacion *ErrorValidacion
+			if tt.valida && err != nil {
+				t.Errorf("Error inesperado: %v", err)
+			}
+			if !tt.valida && !errors.As(err, &errValidacion) {
+				t.Errorf("Se esperaba ErrorValidacion, se obtuvo: %v", err)
+			}
+		})
+	}
+
+	dias, err := ParsearDiasSemana("Lunes, mié,sab")
+	if err != nil || !slices.Equal(dias, []time.Weekday{time.Monday, time.Wednesday, time.Saturday}) {
+		t.Errorf("Días esperados [lunes miércoles sábado], obtenidos: %v, %v", dias, err)
+	}
+	if _, err := ParsearDiasSemana("lun,festivo"); err == nil {
+		t.Error("Un día desconocido debería dar error")
+	}
+}
@@ -0,0 +1,333 @@
+package tareas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+)
+
+// Operaciones que puede contener una entrada del registro.
+const (
+	opGuardar = "guardar"
+	opBorrar  = "borrar"
+	opListas  = "listas"
+
+	// opProximoID fija el siguiente ID a asignar, que la compactación no
+	// debe perder al descartar las operaciones "borrar"
+	opProximoID = "proximo_id"
+)
+
+// entradaRegistro es una línea del archivo de registro (o del diario).
+//
+// Cada línea es un objeto JSON independiente (formato JSON Lines), de modo
+// que un corte a mitad de escritura solo puede dañar la última línea.
+type entradaRegistro struct {
+	Op     string   `json:"op"`
+	Tarea  *Tarea   `json:"tarea,omitempty"`
+	ID     int      `json:"id,omitempty"`
+	Listas []string `json:"listas,omitempty"`
+}
+
+// AlmacenamientoRegistro persiste las tareas como un registro de solo-añadir.
+//
+// Cada cambio se agrega al final del archivo como una operación "guardar"
+// (alta o modificación de una tarea) o "borrar", y cada cambio de las
+// listas como una operación "listas" con todos sus nombres. Al cargar, las
+// operaciones se reproducen en orden para reconstruir el estado.
+// GuardarTodas compacta el registro reescribiéndolo con el siguiente ID
+// (operación "proximo_id"), las listas y una operación "guardar" por tarea.
+//
+// No requiere ninguna base de datos: es un archivo de texto embebido en el
+// propio programa.
+type AlmacenamientoRegistro struct {
+	// ruta es la ruta del archivo de registro
+	ruta string
+
+	// listas son los nombres de las listas tras la última carga o escritura
+	listas []string
+
+	// proximoID es el siguiente ID tras la última carga o por escribir
+	proximoID int
+}
+
+// NuevoAlmacenamientoRegistro crea un almacenamiento de registro sobre el archivo indicado.
+//
+// El archivo no se crea hasta la primera escritura.
+//
+// Ejemplo:
+//
+//	gestor, err := NuevoGestorTareas("", ConAlmacenamiento(NuevoAlmacenamientoRegistro("tareas.log")))
+//
+func NuevoAlmacenamientoRegistro(ruta string) *AlmacenamientoRegistro {
+	return &AlmacenamientoRegistro{ruta: ruta}
+}
+
+// CargarTodas reproduce el registro completo y retorna el estado resultante.
+//
+// Las tareas conservan el orden en que aparecieron por primera vez. Una
+// última línea incompleta (escritura interrumpida) se recorta del archivo
+// para que no quede en medio al añadir la siguiente operación; cualquier
+// otra línea inválida se reporta con su número.
+func (a *AlmacenamientoRegistro) CargarTodas() ([]Tarea, error) {
+	datos, err := os.ReadFile(a.ruta)
+	if err != nil {
+		return nil, err
+	}
+	if datos, err = descartarLineaIncompleta(a.ruta, datos); err != nil {
+		return nil, err
+	}
+
+	r, err := aplicarEntradas(nil, datos)
+	a.listas, a.proximoID = r.listas, r.maxID+1
+	return r.tareas, err
+}
+
+// reproduccion es el estado que resulta de aplicar entradas de registro.
+type reproduccion struct {
+	tareas []Tarea
+
+	// listas son los nombres de la última entrada "listas"; hayListas
+	// indica si había alguna
+	listas    []string
+	hayListas bool
+
+	// aplicadas es la cantidad de entradas aplicadas
+	aplicadas int
+
+	// maxID es el mayor ID mencionado (incluidas tareas ya borradas y el
+	// anterior al de una operación "proximo_id", para no reutilizarlo)
+	maxID int
+}
+
+// aplicarEntradas reproduce en orden las entradas JSON Lines de datos sobre
+// tareas y retorna el estado resultante.
+//
+// Una operación "guardar" reemplaza la tarea con el mismo ID o la añade al
+// final; "borrar" la elimina si existe; "listas" reemplaza los nombres de
+// las listas y "proximo_id" solo reserva los IDs anteriores. Una última
+// línea incompleta (escritura interrumpida) se ignora; cualquier otra línea
+// inválida se reporta con su número.
+func aplicarEntradas(tareas []Tarea, datos []byte) (reproduccion, error) {
+	posiciones := make(map[int]int) // ID -> índice en tareas
+	for i, tarea := range tareas {
+		posiciones[tarea.ID] = i
+	}
+	r := reproduccion{}
+
+	lineas := bytes.Split(datos, []byte("\n"))
+	for i, linea := range lineas {
+		if len(bytes.TrimSpace(linea)) == 0 {
+			continue
+		}
+
+		var entrada entradaRegistro
+		if err := json.Unmarshal(linea, &entrada); err != nil {
+			if i == len(lineas)-1 {
+				// Última línea truncada por una escritura interrumpida
+				break
+			}
+			return reproduccion{}, fmt.Errorf("error al parsear registro (línea %d): %v", i+1, err)
+		}
+
+		switch entrada.Op {
+		case opGuardar:
+			if entrada.Tarea == nil {
+				return reproduccion{}, fmt.Errorf("error al parsear registro (línea %d): falta la tarea", i+1)
+			}
+			r.maxID = max(r.maxID, entrada.Tarea.ID)
+			if pos, ok := posiciones[entrada.Tarea.ID]; ok {
+				tareas[pos] = *entrada.Tarea
+			} else {
+				posiciones[entrada.Tarea.ID] = len(tareas)
+				tareas = append(tareas, *entrada.Tarea)
+			}
+		case opBorrar:
+			r.maxID = max(r.maxID, entrada.ID)
+			if pos, ok := posiciones[entrada.ID]; ok {
+				tareas = append(tareas[:pos], tareas[pos+1:]...)
+				delete(posiciones, entrada.ID)
+				for id, p := range posiciones {
+					if p > pos {
+						posiciones[id] = p - 1
+					}
+				}
+			}
+		case opListas:
+			r.listas, r.hayListas = entrada.Listas, true
+		case opProximoID:
+			r.maxID = max(r.maxID, entrada.ID-1)
+		default:
+			return reproduccion{}, fmt.Errorf("error al parsear registro (línea %d): operación desconocida %q", i+1, entrada.Op)
+		}
+		r.aplicadas++
+	}
+
+	r.tareas = tareas
+	return r, nil
+}
+
+// GuardarTodas compacta el registro: lo reescribe con el siguiente ID, si
+// se purgaron las tareas con los IDs más altos, las listas, si hay alguna,
+// y una entrada por tarea.
+func (a *AlmacenamientoRegistro) GuardarTodas(tareas []Tarea) error {
+	var buf bytes.Buffer
+	if a.proximoID > maximoID(tareas)+1 {
+		if err := escribirEntrada(&buf, entradaRegistro{Op: opProximoID, ID: a.proximoID}); err != nil {
+			return err
+		}
+	}
+	if len(a.listas) > 0 {
+		if err := escribirEntrada(&buf, entradaRegistro{Op: opListas, Listas: a.listas}); err != nil {
+			return err
+		}
+	}
+	for i := range tareas {
+		if err := escribirEntrada(&buf, entradaRegistro{Op: opGuardar, Tarea: &tareas[i]}); err != nil {
+			return err
+		}
+	}
+
+	// Reescritura atómica: un corte durante la compactación no pierde el registro
+	return escribirArchivoAtomico(a.ruta, buf.Bytes(), 0644)
+}
+
+// GuardarTarea añade una operación "guardar" al final del registro.
+func (a *AlmacenamientoRegistro) GuardarTarea(tarea Tarea) error {
+	return anadirEntrada(a.ruta, entradaRegistro{Op: opGuardar, Tarea: &tarea})
+}
+
+// BorrarTarea añade una operación "borrar" al final del registro.
+func (a *AlmacenamientoRegistro) BorrarTarea(id int) error {
+	return anadirEntrada(a.ruta, entradaRegistro{Op: opBorrar, ID: id})
+}
+
+// CargarListas implementa AlmacenamientoListas.
+func (a *AlmacenamientoRegistro) CargarListas() []string {
+	return slices.Clone(a.listas)
+}
+
+// GuardarListas implementa AlmacenamientoListas: añade una operación
+// "listas" al final del registro.
+func (a *AlmacenamientoRegistro) GuardarListas(listas []string) error {
+	if err := anadirEntrada(a.ruta, entradaRegistro{Op: opListas, Listas: listas}); err != nil {
+		return err
+	}
+	a.listas = slices.Clone(listas)
+	return nil
+}
+
+// CargarProximoID implementa AlmacenamientoProximoID.
+func (a *AlmacenamientoRegistro) CargarProximoID() int {
+	return a.proximoID
+}
+
+// GuardarProximoID implementa AlmacenamientoProximoID: cada "borrar" ya
+// reserva su ID, así que el siguiente ID se escribe al compactar, en el
+// siguiente GuardarTodas.
+func (a *AlmacenamientoRegistro) GuardarProximoID(id int) error {
+	a.proximoID = id
+	return nil
+}
+
+// String retorna la ruta del archivo de registro.
+func (a *AlmacenamientoRegistro) String() string {
+	return a.ruta
+}
+
+// anadirEntrada escribe una o varias entradas al final del archivo ruta,
+// creándolo si no existe, y las sincroniza a disco antes de retornar. Las
+// entradas suelen ser de tipo entradaRegistro; la auditoría escribe Evento.
+//
+// Si el archivo no termina en un salto de línea (una escritura anterior se
+// interrumpió), la línea incompleta se descarta antes de añadir, o se
+// cierra con un salto si era válida: así las entradas nuevas nunca quedan
+// pegadas a ella.
+func anadirEntrada(ruta string, entradas ...any) error {
+	archivo, err := os.OpenFile(ruta, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("error al abrir registro: %v", err)
+	}
+
+	var buf bytes.Buffer
+	incompleta, err := terminaSinSalto(archivo)
+	if err != nil {
+		archivo.Close()
+		return fmt.Errorf("error al leer registro: %v", err)
+	}
+	if incompleta {
+		datos, err := os.ReadFile(ruta)
+		if err == nil {
+			datos, err = descartarLineaIncompleta(ruta, datos)
+		}
+		if err != nil {
+			archivo.Close()
+			return err
+		}
+		if len(datos) > 0 && datos[len(datos)-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+	}
+
+	for _, entrada := range entradas {
+		if err := escribirEntrada(&buf, entrada); err != nil {
+			archivo.Close()
+			return err
+		}
+	}
+	if _, err := archivo.Write(buf.Bytes()); err != nil {
+		archivo.Close()
+		return fmt.Errorf("error al escribir registro: %v", err)
+	}
+	if err := archivo.Sync(); err != nil {
+		archivo.Close()
+		return fmt.Errorf("error al sincronizar registro: %v", err)
+	}
+	return archivo.Close()
+}
+
+// terminaSinSalto indica si el archivo no está vacío y su último byte no es
+// un salto de línea.
+func terminaSinSalto(archivo *os.File) (bool, error) {
+	info, err := archivo.Stat()
+	if err != nil || info.Size() == 0 {
+		return false, err
+	}
+	ultimo := make([]byte, 1)
+	if _, err := archivo.ReadAt(ultimo, info.Size()-1); err != nil {
+		return false, err
+	}
+	return ultimo[0] != '\n', nil
+}
+
+// descartarLineaIncompleta recorta del archivo ruta, cuyo contenido es
+// datos, una última línea sin salto final que no sea JSON válido (una
+// escritura interrumpida) y retorna el contenido restante.
+//
+// Recortarla al cargar evita que la siguiente entrada añadida la deje en
+// medio del archivo, donde ya sería un error.
+func descartarLineaIncompleta(ruta string, datos []byte) ([]byte, error) {
+	inicio := bytes.LastIndexByte(datos, '\n') + 1
+	if inicio == len(datos) || json.Valid(datos[inicio:]) {
+		return datos, nil
+	}
+	if err := os.Truncate(ruta, int64(inicio)); err != nil {
+		return nil, fmt.Errorf("error al descartar la línea incompleta de %s: %v", ruta, err)
+	}
+	return datos[:inicio], nil
+}
+
+// escribirEntrada serializa una entrada como una línea JSON.
+func escribirEntrada(w io.Writer, entrada any) error {
+	datos, err := json.Marshal(entrada)
+	if err != nil {
+		return fmt.Errorf("error al serializar entrada de registro: %v", err)
+	}
+	datos = append(datos, '\n')
+	if _, err := w.Write(datos); err != nil {
+		return fmt.Errorf("error al escribir registro: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package tareas
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrTieneSubtareas se retorna al intentar eliminar una tarea que aún tiene
+// subtareas; debe usarse EliminarConSubtareas o eliminarlas antes.
+var ErrTieneSubtareas = errors.New("la tarea tiene subtareas")
+
+// ListarSubtareas retorna las subtareas directas de una tarea, en el orden
+// en que fueron creadas. Las subtareas de la papelera no se incluyen.
+//
+// Ejemplo:
+//
+//	for _, sub := range gestor.ListarSubtareas(3) {
+//		fmt.Printf("  ↳ [%d] %s\n", sub.ID, sub.Titulo)
+//	}
+//
+func (g *GestorTareas) ListarSubtareas(id int) []Tarea {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var subtareas []Tarea
+	for _, tarea := range g.tareas {
+		if tarea.PadreID == id && id != 0 && !tarea.EnPapelera() {
+			subtareas = append(subtareas, tarea.clonar())
+		}
+	}
+	return subtareas
+}
+
+// ListarDescendientes retorna las subtareas de una tarea de cualquier nivel
+// (subtareas de subtareas incluidas), recorriendo el árbol a lo ancho: las
+// mismas que EliminarConSubtareas mueve a la papelera junto con ella. Las
+// subtareas de la papelera no se incluyen.
+//
+// Ejemplo:
+//
+//	if n := len(gestor.ListarDescendientes(3)); n > 0 {
+//		fmt.Printf("Se eliminarán también %d subtarea(s)\n", n)
+//	}
+//
+func (g *GestorTareas) ListarDescendientes(id int) []Tarea {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var descendientes []Tarea
+	for _, i := range g.descendientes(id, false) {
+		descendientes = append(descendientes, g.tareas[i].clonar())
+	}
+	return descendientes
+}
+
+// PorcentajeCompletado calcula el avance de una tarea a partir de sus subtareas.
+//
+// Cuenta todas las subtareas de cualquier nivel (subtareas de subtareas
+// incluidas) que no estén en la papelera y retorna el porcentaje completado,
+// redondeado hacia abajo. Una tarea sin subtareas está al 0% o al 100% según
+// su propio estado.
+//
+// Parámetros:
+//   - id: el identificador de la tarea padre
+//
+// Retorna:
+//   - int: porcentaje entre 0 y 100
+//   - error: ErrorNoEncontrada si la tarea no existe
+//
+// Ejemplo:
+//
+//	if pct, err := gestor.PorcentajeCompletado(3); err == nil {
+//		fmt.Printf("📊 %d%% completado\n", pct)
+//	}
+//
+func (g *GestorTareas) PorcentajeCompletado(id int) (int, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	i := g.indiceVisible(id)
+	if i < 0 {
+		return 0, &ErrorNoEncontrada{ID: id}
+	}
+
+	descendientes := g.descendientes(id, false)
+	if len(descendientes) == 0 {
+		if g.tareas[i].Completada {
+			return 100, nil
+		}
+		return 0, nil
+	}
+
+	completadas := 0
+	for _, d := range descendientes {
+		if g.tareas[d].Completada {
+			completadas++
+		}
+	}
+	return completadas * 100 / len(descendientes), nil
+}
+
+// EliminarConSubtareas mueve a la papelera una tarea junto con todas sus
+// subtareas, de cualquier nivel.
+//
+// Todas reciben la misma FechaEliminada, de modo que Restaurar sobre la
+// tarea padre las recupera juntas y Deshacer revierte la operación completa.
+//
+// Retorna:
+//   - int: cantidad de tareas movidas a la papelera (incluida la padre)
+//   - error: ErrorNoEncontrada si la tarea no existe, *ErrorPermiso si el
+//     usuario no puede modificar alguna de ellas (entonces no se elimina
+//     ninguna), o un error de escritura del diario
+//
+// Ejemplo:
+//
+//	if err := gestor.Eliminar(3); errors.Is(err, ErrTieneSubtareas) {
+//		n, err := gestor.EliminarConSubtareas(3)
+//	}
+//
+func (g *GestorTareas) EliminarConSubtareas(id int) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	i := g.indiceVisible(id)
+	if i < 0 {
+		return 0, &ErrorNoEncontrada{ID: id}
+	}
+
+	indices := append([]int{i}, g.descendientes(id, false)...)
+	for _, indice := range indices {
+		if err := g.comprobarPermiso(indice); err != nil {
+			return 0, err
+		}
+	}
+
+	ahora := g.reloj()
+	var cambios []cambioTarea
+	for _, indice := range indices {
+		eliminada := g.tareas[indice].clonar()
+		eliminada.FechaEliminada = ahora
+		if err := g.escribirDiario(entradaRegistro{Op: opGuardar, Tarea: &eliminada}); err != nil {
+			// Lo ya eliminado queda registrado para poder deshacerlo
+			g.registrarOperacionCompuesta(OperacionEliminar, cambios)
+			return len(cambios), err
+		}
+
+		cambios = append(cambios, nuevoCambio(&g.tareas[indice], &eliminada, indice))
+		g.sustituirTarea(indice, eliminada)
+		g.registrarGuardado(eliminada)
+	}
+
+	g.registrarOperacionCompuesta(OperacionEliminar, cambios)
+	return len(cambios), nil
+}
+
+// validarPadre comprueba que padreID pueda ser la tarea padre de la tarea id:
+// debe existir fuera de la papelera y no puede ser la propia tarea ni una de
+// sus subtareas, lo que crearía un ciclo. padreID 0 (sin padre) siempre es
+// válido. Debe llamarse con g.mu tomado.
+func (g *GestorTareas) validarPadre(id, padreID int) error {
+	if padreID == 0 {
+		return nil
+	}
+	if padreID == id {
+		return &ErrorValidacion{"una tarea no puede ser subtarea de sí misma"}
+	}
+	if g.indiceVisible(padreID) < 0 {
+		return &ErrorValidacion{fmt.Sprintf("la tarea padre %d no existe", padreID)}
+	}
+
+	// Subimos por los ancestros del nuevo padre buscando la propia tarea
+	visitadas := make(map[int]bool)
+	for actual := padreID; actual != 0 && !visitadas[actual]; {
+		if actual == id {
+			return &ErrorValidacion{fmt.Sprintf("la tarea %d es subtarea de %d: asignarla como padre crearía un ciclo", padreID, id)}
+		}
+		visitadas[actual] = true
+
+		i := g.indicePorID(actual)
+		if i < 0 {
+			break
+		}
+		actual = g.tareas[i].PadreID
+	}
+	return nil
+}
+
+// tieneSubtareas indica si alguna tarea tiene a id como padre. Con
+// incluirPapelera se cuentan también las subtareas eliminadas.
+// Debe llamarse con g.mu tomado.
+func (g *GestorTareas) tieneSubtareas(id int, incluirPapelera bool) bool {
+	for _, tarea := range g.tareas {
+		if tarea.PadreID == id && (incluirPapelera || !tarea.EnPapelera()) {
+			return true
+		}
+	}
+	return false
+}
+
+// descendientes retorna los índices de todas las subtareas de id, de
+// cualquier nivel, recorriendo el árbol a lo ancho. Con incluirPapelera se
+// recorren también las subtareas eliminadas. Debe llamarse con g.mu tomado.
+func (g *GestorTareas) descendientes(id int, incluirPapelera bool) []int {
+	var indices []int
+	visitadas := map[int]bool{id: true}
+	pendientes := []int{id}
+
+	for len(pendientes) > 0 {
+		padre := pendientes[0]
+		pendientes = pendientes[1:]
+		for i, tarea := range g.tareas {
+			if tarea.PadreID != padre || visitadas[tarea.ID] {
+				continue
+			}
+			if !incluirPapelera && tarea.EnPapelera() {
+				continue
+			}
+			visitadas[tarea.ID] = true
+			indices = append(indices, i)
+			pendientes = append(pendientes, tarea.ID)
+		}
+	}
+	return indices
+}
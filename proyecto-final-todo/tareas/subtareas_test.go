@@ -0,0 +1,198 @@
+// Tests de subtareas y jerarquías de tareas
+
+package tareas
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// crearArbol crea la jerarquía usada por los tests:
+//
+//	1 Proyecto
+//	├── 2 Diseño
+//	│   └── 4 Bocetos
+//	└── 3 Desarrollo
+//	5 Otra tarea
+func crearArbol(t *testing.T, gestor *GestorTareas) {
+	t.Helper()
+	crearTareas(t, gestor, []DatosTarea{
+		{Titulo: "Proyecto"},
+		{Titulo: "Diseño", PadreID: 1},
+		{Titulo: "Desarrollo", PadreID: 1},
+		{Titulo: "Bocetos", PadreID: 2},
+		{Titulo: "Otra tarea"},
+	}, nil)
+}
+
+// TestCrearSubtarea verifica la asignación y validación de la tarea padre
+func TestCrearSubtarea(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t)
+	crearArbol(t, gestor)
+
+	subtareas := gestor.ListarSubtareas(1)
+	if len(subtareas) != 2 || subtareas[0].ID != 2 || subtareas[1].ID != 3 {
+		t.Errorf("Se esperaban las subtareas 2 y 3, se obtuvo: %+v", subtareas)
+	}
+
+	tests := []struct {
+		nombre  string
+		padreID int
+	}{
+		{"padre inexistente", 999},
+		{"padre negativo", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			var errValidacion *ErrorValidacion
+			if _, err := gestor.CrearConDatos(DatosTarea{Titulo: "Huérfana", PadreID: tt.padreID}); !errors.As(err, &errValidacion) {
+				t.Errorf("Se esperaba ErrorValidacion, se obtuvo: %v", err)
+			}
+		})
+	}
+
+	// Un padre en la papelera tampoco es válido
+	gestor.Eliminar(5)
+	if _, err := gestor.CrearConDatos(DatosTarea{Titulo: "Hija", PadreID: 5}); err == nil {
+		t.Error("No debería poder crearse una subtarea de una tarea en la papelera")
+	}
+}
+
+// TestMoverSubtareaSinCiclos verifica la detección de ciclos en Actualizar
+func TestMoverSubtareaSinCiclos(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t)
+	crearArbol(t, gestor)
+
+	tests := []struct {
+		nombre  string
+		id      int
+		padreID int
+		valido  bool
+	}{
+		{"a sí misma", 1, 1, false},
+		{"bajo su hija", 1, 2, false},
+		{"bajo su nieta", 1, 4, false},
+		{"bajo otra rama", 4, 3, true},
+		{"a primer nivel", 3, 0, true},
+		{"bajo una tarea de primer nivel", 5, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			padreID := tt.padreID
+			tarea, err := gestor.Actualizar(tt.id, CambiosTarea{PadreID: &padreID})
+			if tt.valido && (err != nil || tarea.PadreID != tt.padreID) {
+				t.Errorf("Se esperaba mover la tarea %d bajo %d, error: %v", tt.id, tt.padreID, err)
+			}
+			var errValidacion *ErrorValidacion
+			if !tt.valido && !errors.As(err, &errValidacion) {
+				t.Errorf("Se esperaba ErrorValidacion, se obtuvo: %v", err)
+			}
+		})
+	}
+}
+
+// TestEliminarTareaConSubtareas verifica el bloqueo y el borrado en cascada
+func TestEliminarTareaConSubtareas(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t)
+	crearArbol(t, gestor)
+
+	if err := gestor.Eliminar(1); !errors.Is(err, ErrTieneSubtareas) {
+		t.Fatalf("Se esperaba ErrTieneSubtareas, se obtuvo: %v", err)
+	}
+
+	// ListarDescendientes anticipa lo que eliminará EliminarConSubtareas
+	if ids := idsDe(gestor.ListarDescendientes(1)); !slices.Equal(ids, []int{2, 3, 4}) {
+		t.Errorf("Descendientes de 1 = %v, se esperaban [2 3 4]", ids)
+	}
+
+	eliminadas, err := gestor.EliminarConSubtareas(1)
+	if err != nil || eliminadas != 4 {
+		t.Fatalf("Se esperaba eliminar 4 tareas, se obtuvo: %d, %v", eliminadas, err)
+	}
+	if lista := gestor.Listar(); len(lista) != 1 || lista[0].ID != 5 {
+		t.Errorf("Solo debería quedar la tarea 5, se obtuvo: %+v", lista)
+	}
+
+	// Una subtarea no puede restaurarse antes que su padre
+	if err := gestor.Restaurar(4); err == nil {
+		t.Error("No debería poder restaurarse una subtarea con el padre en la papelera")
+	}
+
+	// Restaurar el padre recupera todo el árbol
+	if err := gestor.Restaurar(1); err != nil {
+		t.Fatalf("Error al restaurar: %v", err)
+	}
+	if total, _, _ := gestor.Estadisticas(); total != 5 {
+		t.Errorf("Deberían volver las 5 tareas, hay: %d", total)
+	}
+
+	// Deshacer revierte la restauración completa y luego la eliminación completa
+	gestor.Deshacer()
+	if len(gestor.ListarPapelera()) != 4 {
+		t.Errorf("Deshacer la restauración debería devolver 4 tareas a la papelera")
+	}
+	op, _ := gestor.Deshacer()
+	if op.Tipo != OperacionEliminar || len(gestor.ListarPapelera()) != 0 {
+		t.Errorf("Deshacer la eliminación en cascada debería vaciar la papelera, op: %v", op)
+	}
+}
+
+// TestPorcentajeCompletado verifica el cálculo del avance a partir de las subtareas
+func TestPorcentajeCompletado(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t)
+	crearArbol(t, gestor)
+	gestor.Completar(3)
+	gestor.Completar(5)
+
+	tests := []struct {
+		nombre     string
+		id         int
+		porcentaje int
+	}{
+		{"padre con 1 de 3 descendientes", 1, 33},
+		{"padre con 0 de 1", 2, 0},
+		{"sin subtareas y pendiente", 4, 0},
+		{"sin subtareas y completada", 5, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			porcentaje, err := gestor.PorcentajeCompletado(tt.id)
+			if err != nil {
+				t.Fatalf("Error inesperado: %v", err)
+			}
+			if porcentaje != tt.porcentaje {
+				t.Errorf("Porcentaje esperado: %d, obtenido: %d", tt.porcentaje, porcentaje)
+			}
+		})
+	}
+
+	// Las subtareas de la papelera no cuentan
+	gestor.Eliminar(4)
+	if porcentaje, _ := gestor.PorcentajeCompletado(1); porcentaje != 50 {
+		t.Errorf("Sin la subtarea eliminada se esperaba 50%%, obtenido: %d", porcentaje)
+	}
+
+	var noEncontrada *ErrorNoEncontrada
+	if _, err := gestor.PorcentajeCompletado(999); !errors.As(err, &noEncontrada) {
+		t.Errorf("Se esperaba ErrorNoEncontrada, se obtuvo: %v", err)
+	}
+}
+
+// TestDeshacerCrearPadreConSubtareas verifica que deshacer no deje huérfanas
+// cuando la creación de las subtareas ya salió del historial
+func TestDeshacerCrearPadreConSubtareas(t *testing.T) {
+	gestor := nuevoGestorEnMemoria(t)
+	gestor.Crear("Padre")
+	gestor.CrearConDatos(DatosTarea{Titulo: "Hija", PadreID: 1})
+
+	// Simulamos que la creación de la hija se descartó por el límite
+	gestor.deshacer = gestor.deshacer[:1]
+
+	if _, err := gestor.Deshacer(); !errors.Is(err, ErrTieneSubtareas) {
+		t.Errorf("Se esperaba ErrTieneSubtareas, se obtuvo: %v", err)
+	}
+	if total, _, _ := gestor.Estadisticas(); total != 2 {
+		t.Errorf("No debería eliminarse ninguna tarea, quedan: %d", total)
+	}
+}
@@ -0,0 +1,1504 @@
+// Package tareas implementa el núcleo del sistema de gestión de tareas.
+//
+// El paquete proporciona operaciones CRUD (Crear, Leer, Actualizar, Eliminar) para tareas,
+// con persistencia en archivos JSON, validaciones de entrada, manejo robusto de errores,
+// y guardado automático mediante concurrencia con goroutines.
+//
+// # Características principales
+//
+//   - CRUD completo de tareas con validaciones
+//   - Descripción, prioridad, vencimiento y etiquetas opcionales
+//   - Persistencia automática en formato JSON
+//   - Almacenamiento intercambiable (JSON, memoria o registro de operaciones)
+//   - Búsqueda por ID o texto (case-insensitive)
+//   - Filtrado por estado (completadas/pendientes)
+//   - Estadísticas en tiempo real
+//   - Autoguardado periódico con goroutines
+//   - Historial acotado para deshacer y rehacer operaciones
+//   - Subtareas con detección de ciclos y porcentaje de avance
+//   - Dependencias entre tareas que bloquean Completar y orden de trabajo sugerido
+//   - Tareas recurrentes que generan su siguiente ocurrencia al completarse
+//   - Reloj y estrategia de identificadores inyectables (secuencial, UUID o ULID)
+//   - Papelera con restauración y purga automática tras un período de retención
+//   - Errores tipados para distinguir validaciones, conflictos y tareas inexistentes
+//
+// El paquete es compartido por la CLI interactiva (proyecto-final-todo) y por
+// la API REST del repositorio, que exponen la misma lógica por distintos canales.
+//
+// # Uso básico
+//
+// Crear un gestor y realizar operaciones:
+//
+//	gestor, err := NuevoGestorTareas("tareas.json")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	// Crear una tarea
+//	tarea, err := gestor.Crear("Estudiar concurrencia en Go")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	// Listar tareas pendientes
+//	pendientes := gestor.ListarPendientes()
+//	for _, t := range pendientes {
+//		fmt.Printf("[%d] %s\n", t.ID, t.Titulo)
+//	}
+//
+//	// Completar una tarea
+//	err = gestor.Completar(tarea.ID)
+//
+//	// Guardar cambios
+//	err = gestor.Guardar()
+//
+// # Autoguardado
+//
+// El sistema soporta guardado automático periódico:
+//
+//	detener := make(chan bool)
+//	gestor.IniciarAutoguardado(30*time.Second, detener)
+//	// ... operaciones ...
+//	detener <- true  // Detener cuando termine
+//
+package tareas
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tarea representa una tarea individual en el sistema de gestión.
+//
+// Cada tarea contiene un identificador único auto-incremental, un título
+// descriptivo que debe cumplir validaciones de longitud (3-100 caracteres),
+// un estado de completitud booleano, y un timestamp de creación. Además
+// puede llevar datos opcionales de planificación: descripción, prioridad,
+// fecha de vencimiento y etiquetas.
+//
+// Las tareas se serializan a JSON para persistencia usando los tags json.
+// Los campos opcionales se omiten cuando están vacíos, de modo que los
+// archivos tareas.json anteriores a ellos se siguen cargando sin cambios.
+type Tarea struct {
+	// ID es el identificador único auto-incremental de la tarea.
+	// Los IDs se asignan secuencialmente y nunca se reutilizan.
+	ID            int       `json:"id"`
+
+	// UID es un identificador global opcional (UUID o ULID) asignado según
+	// la estrategia elegida con ConGeneradorID; vacío con la secuencial.
+	UID string `json:"uid,omitempty"`
+	
+	// Titulo es la descripción de la tarea.
+	// Debe tener entre 3 y 100 caracteres y no puede estar vacío.
+	Titulo        string    `json:"titulo"`
+	
+	// Completada indica si la tarea ha sido marcada como finalizada.
+	// Las tareas nuevas siempre comienzan con false.
+	Completada    bool      `json:"completada"`
+	
+	// FechaCreacion es el timestamp UTC de cuando se creó la tarea.
+	// Se asigna automáticamente al crear la tarea.
+	FechaCreacion time.Time `json:"fecha_creacion"`
+
+	// Descripcion amplía el título con detalles libres (opcional, máx. 1000 caracteres).
+	Descripcion string `json:"descripcion,omitempty"`
+
+	// Prioridad indica la urgencia de la tarea (opcional).
+	Prioridad Prioridad `json:"prioridad,omitempty"`
+
+	// FechaVencimiento es el momento límite para completar la tarea.
+	// El valor cero significa que la tarea no vence.
+	FechaVencimiento time.Time `json:"fecha_vencimiento,omitzero"`
+
+	// Etiquetas clasifican la tarea; se guardan en minúsculas y sin repetir.
+	Etiquetas []string `json:"etiquetas,omitempty"`
+
+	// FechaCompletada es el momento en que se completó la tarea.
+	// Se asigna automáticamente en Completar; cero si está pendiente.
+	FechaCompletada time.Time `json:"fecha_completada,omitzero"`
+
+	// FechaActualizacion es el momento de la última modificación (edición o
+	// cambio de estado). Cero si la tarea no se ha modificado desde su creación.
+	FechaActualizacion time.Time `json:"fecha_actualizacion,omitzero"`
+
+	// FechaEliminada es el momento en que la tarea se movió a la papelera.
+	// Cero si la tarea no está eliminada.
+	FechaEliminada time.Time `json:"fecha_eliminada,omitzero"`
+
+	// PadreID es el ID de la tarea de la que esta es subtarea; 0 si es una
+	// tarea de primer nivel. El gestor impide que se formen ciclos.
+	PadreID int `json:"padre_id,omitempty"`
+
+	// Dependencias son los IDs de las tareas que deben completarse antes
+	// que esta. El gestor impide que se formen ciclos.
+	Dependencias []int `json:"dependencias,omitempty"`
+
+	// Recurrencia es la regla con la que se repite la tarea; nil si no se
+	// repite. Al completarla se crea la siguiente ocurrencia.
+	Recurrencia *Recurrencia `json:"recurrencia,omitempty"`
+
+	// Lista es el nombre de la lista a la que pertenece la tarea; vacío para
+	// la lista por defecto (ListaPorDefecto). Las subtareas están siempre en
+	// la lista de su tarea padre.
+	Lista string `json:"lista,omitempty"`
+
+	// Propietario es el usuario que creó la tarea (ver ConUsuario); vacío si
+	// se creó sin usuario, en cuyo caso cualquiera puede modificarla.
+	Propietario string `json:"propietario,omitempty"`
+
+	// Asignados son los usuarios que, además del propietario, pueden
+	// modificar la tarea; se guardan en minúsculas y sin repetir.
+	Asignados []string `json:"asignados,omitempty"`
+}
+
+// Vencida indica si la tarea está pendiente y su fecha de vencimiento ya pasó.
+func (t Tarea) Vencida(ahora time.Time) bool {
+	return !t.Completada && !t.FechaVencimiento.IsZero() && t.FechaVencimiento.Before(ahora)
+}
+
+// FinDelDia retorna el último segundo (23:59:59) del día de fecha en su
+// zona horaria, que es cuando vence una tarea con solo un día de
+// vencimiento. Respeta los días de 23 o 25 horas de los cambios de horario.
+//
+// Ejemplo:
+//
+//	dia, _ := time.ParseInLocation(time.DateOnly, "2026-06-15", time.Local)
+//	vence := tareas.FinDelDia(dia) // 2026-06-15 23:59:59
+//
+func FinDelDia(fecha time.Time) time.Time {
+	anio, mes, dia := fecha.Date()
+	return time.Date(anio, mes, dia+1, 0, 0, 0, 0, fecha.Location()).Add(-time.Second)
+}
+
+// TieneEtiqueta indica si la tarea lleva la etiqueta dada (sin distinguir mayúsculas).
+func (t Tarea) TieneEtiqueta(etiqueta string) bool {
+	etiqueta = strings.ToLower(strings.TrimSpace(etiqueta))
+	for _, e := range t.Etiquetas {
+		if e == etiqueta {
+			return true
+		}
+	}
+	return false
+}
+
+// clonar retorna una copia de la tarea que no comparte memoria con el original.
+//
+// Se usa al entregar tareas fuera del gestor: sin ella, los slices de
+// etiquetas, dependencias y asignados y la regla de recurrencia quedarían
+// compartidos con el estado interno.
+func (t Tarea) clonar() Tarea {
+	if t.Etiquetas != nil {
+		t.Etiquetas = append([]string(nil), t.Etiquetas...)
+	}
+	if t.Asignados != nil {
+		t.Asignados = append([]string(nil), t.Asignados...)
+	}
+	if t.Dependencias != nil {
+		t.Dependencias = append([]int(nil), t.Dependencias...)
+	}
+	if t.Recurrencia != nil {
+		recurrencia := *t.Recurrencia
+		recurrencia.DiasSemana = slices.Clone(recurrencia.DiasSemana)
+		t.Recurrencia = &recurrencia
+	}
+	return t
+}
+
+// Prioridad es el nivel de urgencia de una tarea.
+//
+// La cadena vacía significa "sin prioridad" y es el valor de las tareas
+// creadas antes de que existiera este campo.
+type Prioridad string
+
+// Prioridades admitidas, de menor a mayor urgencia.
+const (
+	PrioridadBaja    Prioridad = "baja"
+	PrioridadMedia   Prioridad = "media"
+	PrioridadAlta    Prioridad = "alta"
+	PrioridadUrgente Prioridad = "urgente"
+)
+
+// Nivel retorna un número comparable para ordenar por prioridad:
+// 0 sin prioridad, 1 baja, 2 media, 3 alta y 4 urgente (-1 si no es válida).
+func (p Prioridad) Nivel() int {
+	switch p {
+	case "":
+		return 0
+	case PrioridadBaja:
+		return 1
+	case PrioridadMedia:
+		return 2
+	case PrioridadAlta:
+		return 3
+	case PrioridadUrgente:
+		return 4
+	}
+	return -1
+}
+
+// DatosTarea agrupa los campos que el usuario puede indicar al crear una tarea.
+//
+// Solo Titulo es obligatorio. Los tags json permiten decodificarlo
+// directamente desde el cuerpo de una petición HTTP.
+type DatosTarea struct {
+	Titulo           string       `json:"titulo"`
+	Descripcion      string       `json:"descripcion,omitempty"`
+	Prioridad        Prioridad    `json:"prioridad,omitempty"`
+	FechaVencimiento time.Time    `json:"fecha_vencimiento,omitzero"`
+	Etiquetas        []string     `json:"etiquetas,omitempty"`
+	PadreID          int          `json:"padre_id,omitempty"`
+	Recurrencia      *Recurrencia `json:"recurrencia,omitempty"`
+	Lista            string       `json:"lista,omitempty"`
+	Asignados        []string     `json:"asignados,omitempty"`
+}
+
+// ErrTareaYaCompletada se retorna al intentar completar una tarea que ya
+// estaba marcada como completada.
+var ErrTareaYaCompletada = errors.New("la tarea ya está completada")
+
+// ErrorNoEncontrada indica que no existe ninguna tarea con el ID solicitado.
+//
+// Los llamadores pueden detectarlo con errors.As para, por ejemplo,
+// traducirlo a un 404 en la API HTTP.
+type ErrorNoEncontrada struct {
+	// ID es el identificador que se buscó sin éxito.
+	ID int
+
+	// UID es el identificador global buscado, si la búsqueda fue por UID.
+	UID string
+
+	// Lista es el nombre de la lista buscada, si lo que no existe es una lista.
+	Lista string
+}
+
+// Error implementa la interfaz error.
+func (e *ErrorNoEncontrada) Error() string {
+	if e.Lista != "" {
+		return fmt.Sprintf("lista %q no encontrada", e.Lista)
+	}
+	if e.UID != "" {
+		return fmt.Sprintf("tarea con UID %q no encontrada", e.UID)
+	}
+	return fmt.Sprintf("tarea con ID %d no encontrada", e.ID)
+}
+
+// ErrorValidacion indica que los datos de una tarea no cumplen las reglas
+// de validación (por ejemplo, un título demasiado corto).
+type ErrorValidacion struct {
+	// Mensaje describe la regla que no se cumplió.
+	Mensaje string
+}
+
+// Error implementa la interfaz error.
+func (e *ErrorValidacion) Error() string {
+	return e.Mensaje
+}
+
+// GestorTareas maneja la colección de tareas y su persistencia en disco.
+//
+// Este tipo es el núcleo del sistema, encapsulando todas las operaciones
+// sobre tareas (CRUD), la persistencia en JSON, y el control del autoguardado.
+//
+// Los campos no exportados garantizan la integridad de los datos y evitan
+// modificaciones directas desde código externo.
+//
+// Todos los métodos son seguros para uso concurrente: las lecturas (Listar,
+// Buscar*, Estadisticas) toman un bloqueo compartido y pueden ejecutarse en
+// paralelo, mientras que las mutaciones y el guardado toman un bloqueo
+// exclusivo. Las tareas retornadas son copias, por lo que modificarlas no
+// altera el estado interno del gestor.
+//
+// Cada gestor actúa en nombre de un usuario (ver ConUsuario), que queda
+// como propietario de las tareas que crea y solo puede modificar las suyas
+// y las que tiene asignadas. ComoUsuario retorna otro gestor sobre las
+// mismas tareas que actúa en nombre de otro usuario.
+type GestorTareas struct {
+	// estadoGestor son las tareas y la configuración, compartidas por el
+	// gestor y todas sus vistas de ComoUsuario
+	*estadoGestor
+
+	// usuario es el usuario en cuyo nombre actúa el gestor; vacío si no se
+	// configuró ninguno (entonces no se comprueban permisos)
+	usuario string
+}
+
+// estadoGestor es el estado de un GestorTareas, compartido entre sus vistas
+// de usuario; sus campos se usan directamente desde los métodos del gestor.
+type estadoGestor struct {
+	// mu protege todos los campos siguientes frente al acceso concurrente
+	// de los llamadores, el autoguardado y los handlers HTTP
+	mu sync.RWMutex
+
+	// tareas almacena la colección completa de tareas en memoria
+	tareas         []Tarea
+
+	// indice localiza las tareas por ID, UID y palabras sin recorrer la
+	// colección; se actualiza junto con tareas (ver indiceTareas)
+	indice indiceTareas
+	
+	// almacen es el backend donde se persisten las tareas
+	almacen Almacenamiento
+
+	// listas son los nombres de las listas creadas con CrearLista, en orden
+	// de creación y sin la lista por defecto (ver Listas)
+	listas []string
+
+	// diario es la ruta del diario de escritura anticipada ("" si está desactivado)
+	diario string
+
+	// deshacer y rehacer son las pilas del historial de operaciones;
+	// limiteHistorial acota cuántas operaciones pueden deshacerse
+	deshacer        []Operacion
+	rehacer         []Operacion
+	limiteHistorial int
+
+	// auditoria son todos los cambios registrados, en orden (ver Evento);
+	// rutaAuditoria es el archivo donde se conservan ("" = solo en memoria)
+	auditoria     []Evento
+	rutaAuditoria string
+
+	// retencionPapelera es el tiempo que una tarea eliminada permanece en la
+	// papelera antes de purgarse (0 = no purgar automáticamente)
+	retencionPapelera time.Duration
+
+	// reloj da la hora actual para las fechas de las tareas, los
+	// vencimientos y la papelera (time.Now salvo que se indique ConReloj)
+	reloj func() time.Time
+
+	// generadorID asigna el UID de las tareas nuevas (ver ConGeneradorID)
+	generadorID GeneradorID
+
+	// salida recibe los mensajes informativos del gestor (os.Stdout salvo
+	// que se indique ConSalida)
+	salida io.Writer
+	
+	// proximoID es el siguiente ID disponible para asignar a nuevas tareas
+	proximoID      int
+	
+	// cambiosPendientes indica si hay modificaciones sin guardar en disco
+	cambiosPendientes bool
+	
+	// autoguardadoActivo indica si el goroutine de autoguardado está ejecutándose
+	autoguardadoActivo bool
+}
+
+// Opcion configura un GestorTareas al crearlo con NuevoGestorTareas.
+type Opcion func(*GestorTareas)
+
+// ConAlmacenamiento reemplaza el archivo JSON por defecto por otro backend.
+//
+// Ejemplo:
+//
+//	gestor, err := NuevoGestorTareas("", ConAlmacenamiento(NuevoAlmacenamientoMemoria()))
+//
+func ConAlmacenamiento(almacen Almacenamiento) Opcion {
+	return func(g *GestorTareas) {
+		g.almacen = almacen
+	}
+}
+
+// ConReloj reemplaza time.Now como fuente de la hora actual del gestor.
+//
+// Todas las fechas que el gestor asigna (creación, completado, edición y
+// eliminación), las tareas vencidas, la purga de la papelera y las fechas
+// de las tareas recurrentes se calculan con este reloj. Permite, por
+// ejemplo, escribir tests deterministas.
+//
+// Ejemplo:
+//
+//	ahora := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+//	gestor, err := NuevoGestorTareas("", ConReloj(func() time.Time { return ahora }))
+//
+func ConReloj(reloj func() time.Time) Opcion {
+	return func(g *GestorTareas) {
+		g.reloj = reloj
+	}
+}
+
+// ConSalida cambia dónde escribe el gestor sus mensajes informativos (las
+// tareas cargadas, los respaldos recuperados, el autoguardado, ...), que
+// por defecto van a la salida estándar.
+//
+// Permite separarlos de la salida de un programa, por ejemplo enviándolos a
+// os.Stderr cuando la salida estándar es JSON, o descartarlos con io.Discard.
+//
+// Ejemplo:
+//
+//	gestor, err := NuevoGestorTareas("tareas.json", ConSalida(os.Stderr))
+//
+func ConSalida(salida io.Writer) Opcion {
+	return func(g *GestorTareas) {
+		g.salida = salida
+	}
+}
+
+// NuevoGestorTareas crea un nuevo gestor de tareas con persistencia en archivo.
+//
+// Si el archivo especificado existe, carga automáticamente las tareas desde él
+// y actualiza el próximo ID para evitar colisiones. Si el archivo no existe,
+// crea un gestor vacío que creará el archivo en el primer guardado. Si se
+// activó un diario con ConDiario, sus operaciones se reproducen a continuación,
+// y si se indicó ConAuditoria se cargan los eventos de la auditoría.
+// Por último se purgan de la papelera las tareas cuya retención ya terminó.
+//
+// Por defecto las tareas se guardan como un array JSON en archivoRuta. Con la
+// opción ConAlmacenamiento puede elegirse otro backend, en cuyo caso
+// archivoRuta se ignora.
+//
+// Parámetros:
+//   - archivoRuta: ruta del archivo JSON para persistencia (ej: "tareas.json")
+//   - opciones: configuración adicional (ej: ConAlmacenamiento)
+//
+// Retorna:
+//   - *GestorTareas: puntero al gestor creado y listo para usar
+//   - error: error si hay problemas leyendo/parseando el archivo existente
+//
+// Ejemplo:
+//
+//	gestor, err := NuevoGestorTareas("mis_tareas.json")
+//	if err != nil {
+//		return err
+//	}
+//
+func NuevoGestorTareas(archivoRuta string, opciones ...Opcion) (*GestorTareas, error) {
+	gestor := &GestorTareas{estadoGestor: &estadoGestor{
+		tareas:         []Tarea{},
+		indice:         nuevoIndiceTareas(),
+		almacen:        NuevoAlmacenamientoJSON(archivoRuta),
+		proximoID:      1,
+		cambiosPendientes: false,
+		autoguardadoActivo: false,
+		limiteHistorial: HistorialPorDefecto,
+		retencionPapelera: RetencionPapeleraPorDefecto,
+		reloj:             time.Now,
+		generadorID:       GeneradorSecuencial{},
+		salida:            os.Stdout,
+	}}
+
+	for _, opcion := range opciones {
+		opcion(gestor)
+	}
+
+	// Intentamos cargar tareas existentes
+	if err := gestor.Cargar(); err != nil {
+		// Si no existe el archivo, no es un error crítico
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	// Recuperamos las operaciones posteriores a la última instantánea
+	if gestor.diario != "" {
+		if err := gestor.reproducirDiario(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Cargamos la auditoría antes de la purga, que también se audita
+	if gestor.rutaAuditoria != "" {
+		if err := gestor.cargarAuditoria(); err != nil {
+			return nil, err
+		}
+	}
+
+	gestor.mu.Lock()
+	gestor.avisarPurga(gestor.purgarPapeleraVencida())
+	gestor.mu.Unlock()
+
+	return gestor, nil
+}
+
+// Guardar persiste todas las tareas en el almacenamiento configurado.
+//
+// Entrega la colección completa al almacenamiento, que la sobrescribe (con
+// el almacenamiento JSON por defecto, el archivo se reescribe entero con
+// indentación para legibilidad), junto con las listas creadas si el
+// almacenamiento las admite. Si el guardado es exitoso, resetea la
+// bandera de cambios pendientes y vacía el diario, si está activo.
+//
+// Antes de guardar purga de la papelera las tareas cuya retención terminó;
+// las tareas que siguen en la papelera se guardan con el resto.
+//
+// Retorna:
+//   - error: error si falla la serialización o escritura
+//
+// Ejemplo:
+//
+//	if err := gestor.Guardar(); err != nil {
+//		log.Printf("Error al guardar: %v", err)
+//	}
+//
+func (g *GestorTareas) Guardar() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.avisarPurga(g.purgarPapeleraVencida())
+
+	if al, ok := g.almacen.(AlmacenamientoListas); ok {
+		if err := al.GuardarListas(g.listas); err != nil {
+			return err
+		}
+	}
+	if ap, ok := g.almacen.(AlmacenamientoProximoID); ok {
+		if err := ap.GuardarProximoID(g.proximoID); err != nil {
+			return err
+		}
+	}
+	if err := g.almacen.GuardarTodas(g.tareas); err != nil {
+		return err
+	}
+
+	// La instantánea ya contiene todo lo registrado en el diario
+	if err := g.vaciarDiario(); err != nil {
+		fmt.Fprintf(g.salida, "⚠️  %v\n", err)
+	}
+
+	g.cambiosPendientes = false
+	return nil
+}
+
+// Cargar lee las tareas desde el almacenamiento configurado.
+//
+// Reemplaza la colección en memoria por la persistida (con el almacenamiento
+// por defecto, parseando el archivo JSON completo), y actualiza el próximo
+// ID basándose en el ID más alto encontrado o, si el almacenamiento lo
+// guarda (AlmacenamientoProximoID), en el siguiente ID guardado.
+// Si el almacenamiento guarda listas (AlmacenamientoListas), recupera
+// también las listas creadas.
+// Imprime un mensaje confirmando cuántas tareas se cargaron.
+//
+// Si el almacenamiento es recuperable y las tareas provienen de un respaldo
+// (porque el archivo principal estaba dañado), lo informa indicando qué
+// archivo se usó. El siguiente Guardar reescribe el archivo principal.
+//
+// Esta función se llama automáticamente por NuevoGestorTareas, pero puede
+// invocarse manualmente para recargar tareas desde disco.
+//
+// Retorna:
+//   - error: error si el almacenamiento no existe, no se puede leer, o es inválido
+//
+// Nota: Si el archivo no existe, retorna os.IsNotExist error que puede
+// manejarse con os.IsNotExist(err).
+//
+func (g *GestorTareas) Cargar() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	tareas, err := g.almacen.CargarTodas()
+	if err != nil {
+		return err
+	}
+	if tareas == nil {
+		tareas = []Tarea{}
+	}
+	g.tareas = tareas
+	g.reconstruirIndice()
+	if al, ok := g.almacen.(AlmacenamientoListas); ok {
+		g.listas = al.CargarListas()
+	}
+
+	// Actualizamos el próximo ID
+	for _, tarea := range g.tareas {
+		if tarea.ID >= g.proximoID {
+			g.proximoID = tarea.ID + 1
+		}
+	}
+	// Sin reutilizar los IDs de las tareas ya purgadas
+	if ap, ok := g.almacen.(AlmacenamientoProximoID); ok {
+		g.proximoID = max(g.proximoID, ap.CargarProximoID())
+	}
+
+	if rec, ok := g.almacen.(AlmacenamientoRecuperable); ok {
+		if archivo, causa := rec.Recuperacion(); archivo != "" {
+			fmt.Fprintf(g.salida, "⚠️  %s está dañado (%v)\n", g.almacen, causa)
+			fmt.Fprintf(g.salida, "✓ Recuperadas %d tarea(s) desde el respaldo %s\n", len(g.tareas), archivo)
+			return nil
+		}
+	}
+
+	fmt.Fprintf(g.salida, "✓ Cargadas %d tarea(s) desde %s\n", len(g.tareas), g.almacen)
+	return nil
+}
+
+// ValidarTitulo valida que el título de una tarea cumpla todos los requisitos.
+//
+// El título se considera válido si:
+//   - No está vacío después de eliminar espacios en blanco
+//   - Tiene al menos 3 caracteres
+//   - No excede los 100 caracteres
+//
+// Esta función se usa internamente por Crear antes de añadir una nueva tarea.
+//
+// Parámetros:
+//   - titulo: el título a validar (puede contener espacios al inicio/fin)
+//
+// Retorna:
+//   - error: error descriptivo si la validación falla, nil si es válido
+//
+// Ejemplo:
+//
+//	if err := ValidarTitulo("Comprar leche"); err != nil {
+//		fmt.Println("Título inválido:", err)
+//	}
+//
+func ValidarTitulo(titulo string) error {
+	titulo = strings.TrimSpace(titulo)
+	
+	if titulo == "" {
+		return &ErrorValidacion{"el título no puede estar vacío"}
+	}
+	
+	if len(titulo) < 3 {
+		return &ErrorValidacion{"el título debe tener al menos 3 caracteres"}
+	}
+	
+	if len(titulo) > 100 {
+		return &ErrorValidacion{"el título no puede exceder 100 caracteres"}
+	}
+	
+	return nil
+}
+
+// ValidarDescripcion valida que la descripción no exceda los 1000 caracteres.
+//
+// La descripción es opcional: una cadena vacía siempre es válida.
+//
+func ValidarDescripcion(descripcion string) error {
+	if len(strings.TrimSpace(descripcion)) > 1000 {
+		return &ErrorValidacion{"la descripción no puede exceder 1000 caracteres"}
+	}
+	return nil
+}
+
+// ValidarPrioridad valida que la prioridad sea vacía o uno de los valores
+// admitidos: baja, media, alta o urgente.
+//
+func ValidarPrioridad(prioridad Prioridad) error {
+	if prioridad.Nivel() < 0 {
+		return &ErrorValidacion{fmt.Sprintf("prioridad inválida %q: debe ser baja, media, alta o urgente", prioridad)}
+	}
+	return nil
+}
+
+// ValidarEtiquetas valida la lista de etiquetas de una tarea.
+//
+// Se admiten hasta 10 etiquetas; cada una debe tener entre 1 y 30
+// caracteres y no puede contener espacios ni comas.
+//
+func ValidarEtiquetas(etiquetas []string) error {
+	if len(etiquetas) > 10 {
+		return &ErrorValidacion{"una tarea no puede tener más de 10 etiquetas"}
+	}
+	for _, etiqueta := range etiquetas {
+		etiqueta = strings.TrimSpace(etiqueta)
+		if etiqueta == "" {
+			return &ErrorValidacion{"las etiquetas no pueden estar vacías"}
+		}
+		if len(etiqueta) > 30 {
+			return &ErrorValidacion{fmt.Sprintf("la etiqueta %q no puede exceder 30 caracteres", etiqueta)}
+		}
+		if strings.ContainsAny(etiqueta, " \t,") {
+			return &ErrorValidacion{fmt.Sprintf("la etiqueta %q no puede contener espacios ni comas", etiqueta)}
+		}
+	}
+	return nil
+}
+
+// Validar aplica todas las validaciones de campos a los datos de una tarea.
+//
+// Retorna el primer error encontrado, en el orden: título, descripción,
+// prioridad, etiquetas, recurrencia y asignados.
+//
+func (d DatosTarea) Validar() error {
+	if err := ValidarTitulo(d.Titulo); err != nil {
+		return err
+	}
+	if err := ValidarDescripcion(d.Descripcion); err != nil {
+		return err
+	}
+	if err := ValidarPrioridad(d.Prioridad); err != nil {
+		return err
+	}
+	if err := ValidarEtiquetas(d.Etiquetas); err != nil {
+		return err
+	}
+	if err := ValidarRecurrencia(d.Recurrencia); err != nil {
+		return err
+	}
+	for _, usuario := range d.Asignados {
+		if err := ValidarUsuario(usuario); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizarEtiquetas recorta, pasa a minúsculas y elimina duplicados,
+// conservando el orden de aparición. Retorna nil si no queda ninguna.
+func normalizarEtiquetas(etiquetas []string) []string {
+	var normalizadas []string
+	vistas := make(map[string]bool)
+	for _, etiqueta := range etiquetas {
+		etiqueta = strings.ToLower(strings.TrimSpace(etiqueta))
+		if etiqueta == "" || vistas[etiqueta] {
+			continue
+		}
+		vistas[etiqueta] = true
+		normalizadas = append(normalizadas, etiqueta)
+	}
+	return normalizadas
+}
+
+// Crear añade una nueva tarea a la colección con el título especificado.
+//
+// Valida el título usando ValidarTitulo, asigna un ID único auto-incremental
+// (y un UID si se configuró ConGeneradorID), establece el estado como no
+// completada, y registra el timestamp de creación según el reloj del gestor.
+// Los espacios en blanco al inicio/fin del título se eliminan automáticamente.
+//
+// Persiste el cambio en el momento si el almacenamiento es incremental; si no,
+// marca el gestor como teniendo cambios pendientes para el autoguardado.
+//
+// Parámetros:
+//   - titulo: descripción de la tarea (se validará longitud)
+//
+// Retorna:
+//   - *Tarea: puntero a la tarea recién creada
+//   - error: error si la validación del título falla o no puede escribirse el diario
+//
+// Ejemplo:
+//
+//	tarea, err := gestor.Crear("Estudiar goroutines")
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Printf("Tarea creada con ID: %d\n", tarea.ID)
+//
+func (g *GestorTareas) Crear(titulo string) (*Tarea, error) {
+	return g.CrearConDatos(DatosTarea{Titulo: titulo})
+}
+
+// CrearConDatos añade una nueva tarea con título y campos opcionales.
+//
+// Funciona igual que Crear, pero además acepta descripción, prioridad,
+// fecha de vencimiento, etiquetas, tarea padre y regla de recurrencia. Todos
+// los campos se validan con DatosTarea.Validar antes de crear la tarea; las
+// etiquetas se normalizan a minúsculas sin duplicados. Si se indica PadreID,
+// la tarea padre debe existir y no estar en la papelera, y la subtarea se
+// crea en la lista de su padre. Si no, se crea en la lista indicada en
+// Lista, que debe existir (vacía o ListaPorDefecto para la lista por defecto).
+//
+// El usuario del gestor (ver ConUsuario) queda como propietario de la
+// tarea, y los usuarios de Asignados pueden modificarla también. Crear una
+// subtarea modifica a su padre, por lo que requiere poder modificarlo
+// (*ErrorPermiso si no).
+//
+// Ejemplo:
+//
+//	tarea, err := gestor.CrearConDatos(DatosTarea{
+//		Titulo:    "Preparar demo",
+//		Prioridad: PrioridadAlta,
+//		Etiquetas: []string{"trabajo"},
+//	})
+//
+func (g *GestorTareas) CrearConDatos(datos DatosTarea) (*Tarea, error) {
+	// Validamos todos los campos
+	if err := datos.Validar(); err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.validarPadre(g.proximoID, datos.PadreID); err != nil {
+		return nil, err
+	}
+	if err := g.comprobarPermiso(g.indicePorID(datos.PadreID)); err != nil {
+		return nil, err
+	}
+	lista, err := g.listaDeTareaNueva(datos.Lista, datos.PadreID)
+	if err != nil {
+		return nil, err
+	}
+
+	ahora := g.reloj()
+	uid, err := g.nuevoUID(ahora)
+	if err != nil {
+		return nil, err
+	}
+
+	tarea := Tarea{
+		ID:               g.proximoID,
+		UID:              uid,
+		Titulo:           strings.TrimSpace(datos.Titulo),
+		Completada:       false,
+		FechaCreacion:    ahora,
+		Descripcion:      strings.TrimSpace(datos.Descripcion),
+		Prioridad:        datos.Prioridad,
+		FechaVencimiento: datos.FechaVencimiento,
+		Etiquetas:        normalizarEtiquetas(datos.Etiquetas),
+		PadreID:          datos.PadreID,
+		Recurrencia:      normalizarRecurrencia(datos.Recurrencia),
+		Lista:            lista,
+		Propietario:      g.usuario,
+		Asignados:        normalizarUsuarios(datos.Asignados),
+	}
+
+	if err := g.escribirDiario(entradaRegistro{Op: opGuardar, Tarea: &tarea}); err != nil {
+		return nil, err
+	}
+
+	g.agregarTarea(tarea)
+	g.proximoID++
+	g.registrarGuardado(tarea)
+	g.registrarOperacion(OperacionCrear, nil, &tarea, len(g.tareas)-1)
+
+	tarea = tarea.clonar()
+	return &tarea, nil
+}
+
+// Listar retorna todas las tareas sin filtrar.
+//
+// Devuelve una copia del slice de tareas, incluyendo tanto completadas
+// como pendientes, en el orden en que fueron creadas. Al ser una copia,
+// el llamador puede recorrerla sin bloquear al gestor. Las tareas de la
+// papelera no se incluyen en este ni en ningún otro listado (ver ListarPapelera).
+//
+// Retorna:
+//   - []Tarea: slice con todas las tareas (puede estar vacío)
+//
+// Ver también: ListarPendientes, ListarCompletadas
+//
+func (g *GestorTareas) Listar() []Tarea {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	tareas := []Tarea{}
+	for _, tarea := range g.tareas {
+		if !tarea.EnPapelera() {
+			tareas = append(tareas, tarea.clonar())
+		}
+	}
+	return tareas
+}
+
+// ListarPendientes retorna solo las tareas que no han sido completadas.
+//
+// Filtra la colección completa y retorna únicamente las tareas con
+// Completada == false.
+//
+// Retorna:
+//   - []Tarea: slice con tareas pendientes (vacío si no hay pendientes)
+//
+// Ejemplo:
+//
+//	pendientes := gestor.ListarPendientes()
+//	fmt.Printf("Tienes %d tareas pendientes\n", len(pendientes))
+//
+func (g *GestorTareas) ListarPendientes() []Tarea {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var pendientes []Tarea
+	for _, tarea := range g.tareas {
+		if !tarea.Completada && !tarea.EnPapelera() {
+			pendientes = append(pendientes, tarea.clonar())
+		}
+	}
+	return pendientes
+}
+
+// ListarCompletadas retorna solo las tareas que han sido marcadas como completadas.
+//
+// Filtra la colección completa y retorna únicamente las tareas con
+// Completada == true.
+//
+// Retorna:
+//   - []Tarea: slice con tareas completadas (vacío si no hay completadas)
+//
+// Ejemplo:
+//
+//	completadas := gestor.ListarCompletadas()
+//	fmt.Printf("Has completado %d tareas\n", len(completadas))
+//
+func (g *GestorTareas) ListarCompletadas() []Tarea {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var completadas []Tarea
+	for _, tarea := range g.tareas {
+		if tarea.Completada && !tarea.EnPapelera() {
+			completadas = append(completadas, tarea.clonar())
+		}
+	}
+	return completadas
+}
+
+// ListarVencidas retorna las tareas pendientes cuya fecha de vencimiento ya pasó.
+//
+// Las tareas sin fecha de vencimiento y las completadas nunca se consideran
+// vencidas.
+//
+// Ejemplo:
+//
+//	for _, t := range gestor.ListarVencidas() {
+//		fmt.Printf("⏰ %s venció el %s\n", t.Titulo, t.FechaVencimiento.Format("02/01/2006"))
+//	}
+//
+func (g *GestorTareas) ListarVencidas() []Tarea {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	ahora := g.reloj()
+	var vencidas []Tarea
+	for _, tarea := range g.tareas {
+		if tarea.Vencida(ahora) && !tarea.EnPapelera() {
+			vencidas = append(vencidas, tarea.clonar())
+		}
+	}
+	return vencidas
+}
+
+// ListarPorEtiqueta retorna las tareas que llevan la etiqueta indicada.
+//
+// La comparación no distingue mayúsculas/minúsculas.
+//
+// Ejemplo:
+//
+//	trabajo := gestor.ListarPorEtiqueta("trabajo")
+//
+func (g *GestorTareas) ListarPorEtiqueta(etiqueta string) []Tarea {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var encontradas []Tarea
+	for _, tarea := range g.tareas {
+		if tarea.TieneEtiqueta(etiqueta) && !tarea.EnPapelera() {
+			encontradas = append(encontradas, tarea.clonar())
+		}
+	}
+	return encontradas
+}
+
+// ListarPorPrioridad retorna las tareas con exactamente la prioridad indicada.
+//
+func (g *GestorTareas) ListarPorPrioridad(prioridad Prioridad) []Tarea {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var encontradas []Tarea
+	for _, tarea := range g.tareas {
+		if tarea.Prioridad == prioridad && !tarea.EnPapelera() {
+			encontradas = append(encontradas, tarea.clonar())
+		}
+	}
+	return encontradas
+}
+
+// BuscarPorID encuentra y retorna una tarea específica por su identificador único.
+//
+// Realiza una búsqueda lineal en la colección de tareas. Si encuentra
+// una tarea con el ID especificado, retorna un puntero a una copia de ella;
+// para modificarla deben usarse los métodos del gestor. Las tareas de la
+// papelera se consideran no encontradas.
+//
+// Parámetros:
+//   - id: el identificador único de la tarea a buscar
+//
+// Retorna:
+//   - *Tarea: puntero a la tarea encontrada
+//   - error: error si no existe ninguna tarea con ese ID
+//
+// Ejemplo:
+//
+//	tarea, err := gestor.BuscarPorID(5)
+//	if err != nil {
+//		fmt.Println("Tarea no encontrada")
+//	} else {
+//		fmt.Println("Tarea:", tarea.Titulo)
+//	}
+//
+func (g *GestorTareas) BuscarPorID(id int) (*Tarea, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if i := g.indiceVisible(id); i >= 0 {
+		tarea := g.tareas[i].clonar()
+		return &tarea, nil
+	}
+	return nil, &ErrorNoEncontrada{ID: id}
+}
+
+// BuscarPorTexto encuentra todas las tareas cuyos títulos contengan el texto especificado.
+//
+// Realiza una búsqueda que no distingue mayúsculas/minúsculas ni tildes
+// ("tarea" encuentra "Tárea") en todos los títulos de tareas, y retorna las
+// coincidencias en el orden en que fueron creadas. Para tolerar errores de
+// escritura y ordenar por relevancia, usa BuscarPorRelevancia.
+//
+// Si el texto está vacío, retorna todas las tareas.
+//
+// Parámetros:
+//   - texto: el texto a buscar en los títulos (sin distinguir mayúsculas ni tildes)
+//
+// Retorna:
+//   - []Tarea: slice con todas las tareas que contienen el texto (puede estar vacío)
+//
+// Ejemplo:
+//
+//	resultados := gestor.BuscarPorTexto("comprar")
+//	fmt.Printf("Se encontraron %d tareas\n", len(resultados))
+//	for _, t := range resultados {
+//		fmt.Printf("  - %s\n", t.Titulo)
+//	}
+//
+func (g *GestorTareas) BuscarPorTexto(texto string) []Tarea {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	// Con palabras que buscar, solo revisamos las tareas que las contienen
+	candidatas := g.tareas
+	if consulta := palabras(texto); len(consulta) > 0 {
+		candidatas = g.tareasCandidatas(consulta, func(buscada, termino string) bool {
+			return strings.Contains(termino, buscada)
+		})
+	}
+
+	var encontradas []Tarea
+	for _, tarea := range candidatas {
+		if contieneNormalizado(tarea.Titulo, texto) && !tarea.EnPapelera() {
+			encontradas = append(encontradas, tarea.clonar())
+		}
+	}
+
+	return encontradas
+}
+
+// Completar marca una tarea específica como completada.
+//
+// Busca la tarea por su ID, establece su campo Completada en true y
+// registra el momento en FechaCompletada y FechaActualizacion.
+// Verifica que la tarea no esté ya completada para evitar operaciones redundantes,
+// y que no tenga requisitos pendientes (ver AgregarDependencia).
+//
+// Si la tarea es recurrente, crea además su siguiente ocurrencia; para
+// obtenerla debe usarse CompletarConSiguiente.
+//
+// Persiste el cambio en el momento si el almacenamiento es incremental; si no,
+// marca el gestor como teniendo cambios pendientes para el autoguardado.
+//
+// Parámetros:
+//   - id: el identificador único de la tarea a completar
+//
+// Retorna:
+//   - error: error si el ID no existe, el usuario no puede modificarla
+//     (*ErrorPermiso), la tarea ya está completada, está bloqueada por
+//     requisitos pendientes (*ErrorBloqueada) o no puede escribirse el diario
+//
+// Ejemplo:
+//
+//	if err := gestor.Completar(3); err != nil {
+//		fmt.Println("Error:", err)
+//	} else {
+//		fmt.Println("Tarea completada exitosamente")
+//	}
+//
+func (g *GestorTareas) Completar(id int) error {
+	_, err := g.CompletarConSiguiente(id)
+	return err
+}
+
+// CambiosTarea describe una modificación parcial de una tarea para Actualizar.
+//
+// Cada campo es un puntero: nil significa "no modificar". Para quitar la
+// fecha de vencimiento se indica un puntero a la fecha cero, para quitar
+// las etiquetas un puntero a un slice vacío, para convertir una subtarea
+// en tarea de primer nivel un puntero a 0, y para que deje de repetirse un
+// puntero a una Recurrencia vacía. Los tags json permiten decodificarlo
+// directamente desde el cuerpo de un PATCH.
+//
+// Propietario y Asignados solo puede cambiarlos el propietario de la tarea.
+type CambiosTarea struct {
+	Titulo           *string      `json:"titulo,omitempty"`
+	Descripcion      *string      `json:"descripcion,omitempty"`
+	Prioridad        *Prioridad   `json:"prioridad,omitempty"`
+	FechaVencimiento *time.Time   `json:"fecha_vencimiento,omitempty"`
+	Etiquetas        *[]string    `json:"etiquetas,omitempty"`
+	Completada       *bool        `json:"completada,omitempty"`
+	PadreID          *int         `json:"padre_id,omitempty"`
+	Recurrencia      *Recurrencia `json:"recurrencia,omitempty"`
+	Propietario      *string      `json:"propietario,omitempty"`
+	Asignados        *[]string    `json:"asignados,omitempty"`
+}
+
+// vacio indica si los cambios no modifican ningún campo.
+func (c CambiosTarea) vacio() bool {
+	return c.Titulo == nil && c.Descripcion == nil && c.Prioridad == nil &&
+		c.FechaVencimiento == nil && c.Etiquetas == nil && c.Completada == nil &&
+		c.PadreID == nil && c.Recurrencia == nil && c.Propietario == nil &&
+		c.Asignados == nil
+}
+
+// Actualizar aplica una modificación parcial a una tarea existente.
+//
+// Solo se modifican los campos indicados en cambios. El resultado se valida
+// completo con las mismas reglas que CrearConDatos, de modo que una tarea
+// nunca queda en un estado inválido. Permite también reabrir una tarea
+// completada (Completada = false), lo que borra su FechaCompletada, y
+// moverla bajo otra tarea padre siempre que no se forme un ciclo.
+//
+// Registra el momento en FechaActualizacion y persiste el cambio igual que
+// Completar. Marcarla como completada está sujeto a las mismas dependencias,
+// pero no genera la siguiente ocurrencia de una tarea recurrente: para eso
+// debe usarse Completar.
+//
+// Solo el propietario de la tarea y sus asignados pueden modificarla, y solo
+// el propietario puede cambiar Propietario y Asignados (ver ConUsuario).
+//
+// Parámetros:
+//   - id: el identificador único de la tarea a modificar
+//   - cambios: campos a modificar (nil = mantener)
+//
+// Retorna:
+//   - *Tarea: copia de la tarea tal como quedó
+//   - error: error si el ID no existe, no hay cambios, el usuario no tiene
+//     permiso (*ErrorPermiso), la validación falla o no puede escribirse el diario
+//
+// Ejemplo:
+//
+//	nuevo := "Comprar leche sin lactosa"
+//	reabrir := false
+//	tarea, err := gestor.Actualizar(3, CambiosTarea{Titulo: &nuevo, Completada: &reabrir})
+//
+func (g *GestorTareas) Actualizar(id int, cambios CambiosTarea) (*Tarea, error) {
+	if cambios.vacio() {
+		return nil, &ErrorValidacion{"no se indicó ningún cambio"}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	i := g.indiceVisible(id)
+	if i < 0 {
+		return nil, &ErrorNoEncontrada{ID: id}
+	}
+	if err := g.comprobarPermiso(i); err != nil {
+		return nil, err
+	}
+	if cambios.Propietario != nil || cambios.Asignados != nil {
+		if err := g.comprobarPropietario(i); err != nil {
+			return nil, err
+		}
+	}
+
+	actualizada := g.tareas[i].clonar()
+	aplicarCambios(&actualizada, cambios, g.reloj())
+
+	// Completar mediante Actualizar respeta las mismas dependencias que Completar
+	if actualizada.Completada && !g.tareas[i].Completada {
+		if pendientes := g.requisitosPendientes(actualizada); len(pendientes) > 0 {
+			return nil, &ErrorBloqueada{ID: id, Pendientes: pendientes}
+		}
+	}
+
+	// Validamos la tarea resultante completa
+	datos := DatosTarea{
+		Titulo:      actualizada.Titulo,
+		Descripcion: actualizada.Descripcion,
+		Prioridad:   actualizada.Prioridad,
+		Etiquetas:   actualizada.Etiquetas,
+		Recurrencia: actualizada.Recurrencia,
+		Asignados:   actualizada.Asignados,
+	}
+	if err := datos.Validar(); err != nil {
+		return nil, err
+	}
+	if cambios.Propietario != nil {
+		if err := ValidarUsuario(actualizada.Propietario); err != nil {
+			return nil, err
+		}
+	}
+	if cambios.PadreID != nil {
+		if err := g.validarPadre(id, actualizada.PadreID); err != nil {
+			return nil, err
+		}
+		if err := g.validarListaPadre(actualizada); err != nil {
+			return nil, err
+		}
+		if err := g.comprobarPermiso(g.indicePorID(actualizada.PadreID)); err != nil {
+			return nil, err
+		}
+	}
+	actualizada.Etiquetas = normalizarEtiquetas(actualizada.Etiquetas)
+	actualizada.Propietario = normalizarUsuario(actualizada.Propietario)
+	actualizada.Asignados = normalizarUsuarios(actualizada.Asignados)
+	actualizada.Recurrencia = normalizarRecurrencia(actualizada.Recurrencia)
+
+	if err := g.escribirDiario(entradaRegistro{Op: opGuardar, Tarea: &actualizada}); err != nil {
+		return nil, err
+	}
+
+	g.registrarOperacion(OperacionEditar, &g.tareas[i], &actualizada, i)
+	g.sustituirTarea(i, actualizada)
+	g.registrarGuardado(actualizada)
+
+	actualizada = actualizada.clonar()
+	return &actualizada, nil
+}
+
+// aplicarCambios modifica la tarea con los campos no nulos de cambios,
+// registrando ahora como momento de la modificación.
+//
+// Recorta los espacios de título y descripción; las etiquetas y la
+// recurrencia, el propietario y los asignados se copian tal cual para
+// validarlos y el llamador los normaliza después.
+func aplicarCambios(tarea *Tarea, cambios CambiosTarea, ahora time.Time) {
+	if cambios.Titulo != nil {
+		tarea.Titulo = strings.TrimSpace(*cambios.Titulo)
+	}
+	if cambios.Descripcion != nil {
+		tarea.Descripcion = strings.TrimSpace(*cambios.Descripcion)
+	}
+	if cambios.Prioridad != nil {
+		tarea.Prioridad = *cambios.Prioridad
+	}
+	if cambios.FechaVencimiento != nil {
+		tarea.FechaVencimiento = *cambios.FechaVencimiento
+	}
+	if cambios.Etiquetas != nil {
+		tarea.Etiquetas = *cambios.Etiquetas
+	}
+	if cambios.PadreID != nil {
+		tarea.PadreID = *cambios.PadreID
+	}
+	if cambios.Recurrencia != nil {
+		tarea.Recurrencia = nil
+		if cambios.Recurrencia.Frecuencia != "" {
+			tarea.Recurrencia = cambios.Recurrencia
+		}
+	}
+	if cambios.Propietario != nil {
+		tarea.Propietario = *cambios.Propietario
+	}
+	if cambios.Asignados != nil {
+		tarea.Asignados = *cambios.Asignados
+	}
+	if cambios.Completada != nil && *cambios.Completada != tarea.Completada {
+		tarea.Completada = *cambios.Completada
+		if tarea.Completada {
+			tarea.FechaCompletada = ahora
+		} else {
+			tarea.FechaCompletada = time.Time{}
+		}
+	}
+
+	tarea.FechaActualizacion = ahora
+}
+
+// Eliminar mueve una tarea a la papelera.
+//
+// Busca la tarea por su ID y registra el momento en FechaEliminada: desde
+// entonces deja de aparecer en los listados y búsquedas, pero puede
+// recuperarse con Restaurar (o con Deshacer) hasta que se purgue, ya sea
+// con VaciarPapelera o automáticamente al vencer la retención configurada
+// con ConRetencionPapelera. El ID eliminado no se reutiliza.
+//
+// Una tarea con subtareas no puede eliminarse sola: retorna ErrTieneSubtareas
+// y debe usarse EliminarConSubtareas.
+//
+// Persiste el cambio en el momento si el almacenamiento es incremental; si no,
+// marca el gestor como teniendo cambios pendientes para el autoguardado.
+//
+// Parámetros:
+//   - id: el identificador único de la tarea a eliminar
+//
+// Retorna:
+//   - error: error si no existe ninguna tarea con ese ID fuera de la papelera,
+//     ErrTieneSubtareas, o *ErrorPermiso si el usuario no puede modificarla
+//
+// Ejemplo:
+//
+//	if err := gestor.Eliminar(7); err != nil {
+//		fmt.Println("No se pudo eliminar:", err)
+//	} else {
+//		fmt.Println("Tarea movida a la papelera")
+//	}
+//
+func (g *GestorTareas) Eliminar(id int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	i := g.indiceVisible(id)
+	if i < 0 {
+		return &ErrorNoEncontrada{ID: id}
+	}
+	if err := g.comprobarPermiso(i); err != nil {
+		return err
+	}
+	if g.tieneSubtareas(id, false) {
+		return ErrTieneSubtareas
+	}
+
+	eliminada := g.tareas[i].clonar()
+	eliminada.FechaEliminada = g.reloj()
+	if err := g.escribirDiario(entradaRegistro{Op: opGuardar, Tarea: &eliminada}); err != nil {
+		return err
+	}
+
+	g.registrarOperacion(OperacionEliminar, &g.tareas[i], &eliminada, i)
+	g.sustituirTarea(i, eliminada)
+	g.registrarGuardado(eliminada)
+	return nil
+}
+
+// indiceVisible retorna la posición de la tarea con el ID dado si no está en
+// la papelera, o -1. Debe llamarse con g.mu tomado.
+func (g *GestorTareas) indiceVisible(id int) int {
+	i := g.indicePorID(id)
+	if i >= 0 && g.tareas[i].EnPapelera() {
+		return -1
+	}
+	return i
+}
+
+// registrarGuardado persiste una tarea nueva o modificada.
+//
+// Si el almacenamiento es incremental, escribe el cambio en el momento; si no
+// lo es, o la escritura falla, marca el cambio como pendiente para que el
+// siguiente Guardar (manual o automático) reescriba la colección completa.
+// Debe llamarse con g.mu tomado en modo escritura.
+func (g *GestorTareas) registrarGuardado(tarea Tarea) {
+	if inc, ok := g.almacen.(AlmacenamientoIncremental); ok {
+		if err := inc.GuardarTarea(tarea); err == nil {
+			return
+		}
+	}
+	g.cambiosPendientes = true
+}
+
+// registrarBorrado persiste la eliminación de una tarea.
+//
+// Sigue las mismas reglas que registrarGuardado.
+func (g *GestorTareas) registrarBorrado(id int) {
+	if inc, ok := g.almacen.(AlmacenamientoIncremental); ok {
+		if err := inc.BorrarTarea(id); err == nil {
+			return
+		}
+	}
+	g.cambiosPendientes = true
+}
+
+// Estadisticas calcula y retorna estadísticas sobre las tareas.
+//
+// Recorre todas las tareas y cuenta el total, cuántas están completadas,
+// y cuántas están pendientes. Es útil para mostrar resúmenes al usuario.
+// Las tareas de la papelera no se cuentan.
+//
+// Retorna (retornos con nombre):
+//   - total: número total de tareas en la colección
+//   - completadas: número de tareas con Completada == true
+//   - pendientes: número de tareas con Completada == false
+//
+// Ejemplo:
+//
+//	total, completadas, pendientes := gestor.Estadisticas()
+//	fmt.Printf("Total: %d | Completadas: %d | Pendientes: %d\n",
+//		total, completadas, pendientes)
+//
+func (g *GestorTareas) Estadisticas() (total, completadas, pendientes int) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	for _, tarea := range g.tareas {
+		if tarea.EnPapelera() {
+			continue
+		}
+		total++
+		if tarea.Completada {
+			completadas++
+		} else {
+			pendientes++
+		}
+	}
+	return
+}
+
+// TieneCambiosPendientes indica si hay modificaciones en memoria que aún no
+// se han guardado en disco.
+//
+// Útil para preguntar al usuario si desea guardar antes de salir.
+//
+func (g *GestorTareas) TieneCambiosPendientes() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.cambiosPendientes
+}
+
+// IniciarAutoguardado inicia una goroutine que guarda automáticamente las tareas periódicamente.
+//
+// Crea un ticker que dispara cada intervalo especificado. En cada tick,
+// verifica si hay cambios pendientes y solo guarda si es necesario para
+// optimizar I/O. La goroutine se ejecuta en segundo plano hasta recibir
+// una señal por el canal detener.
+//
+// Solo puede haber un autoguardado activo a la vez. Llamadas adicionales
+// son ignoradas mientras hay uno en ejecución; una vez detenido, puede
+// iniciarse de nuevo.
+//
+// Parámetros:
+//   - intervalo: frecuencia de guardado (ej: 30*time.Second para cada 30s)
+//   - detener: canal de solo lectura para señalizar terminación del autoguardado
+//
+// Ejemplo:
+//
+//	detener := make(chan bool)
+//	gestor.IniciarAutoguardado(30*time.Second, detener)
+//	defer func() { detener <- true }()
+//	// ... realizar operaciones ...
+//
+// Nota: Es responsabilidad del llamador cerrar o enviar señal por el canal
+// cuando desee detener el autoguardado.
+//
+func (g *GestorTareas) IniciarAutoguardado(intervalo time.Duration, detener <-chan bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.autoguardadoActivo {
+		return
+	}
+
+	g.autoguardadoActivo = true
+
+	go func() {
+		ticker := time.NewTicker(intervalo)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if g.TieneCambiosPendientes() {
+					if err := g.Guardar(); err != nil {
+						fmt.Fprintf(g.salida, "\n⚠️  Error en autoguardado: %v\n", err)
+					} else {
+						fmt.Fprintf(g.salida, "\n💾 Autoguardado realizado (%s)\n", time.Now().Format("15:04:05"))
+					}
+				}
+			case <-detener:
+				g.mu.Lock()
+				g.autoguardadoActivo = false
+				g.mu.Unlock()
+
+				fmt.Fprintln(g.salida, "\n🛑 Autoguardado detenido")
+				return
+			}
+		}
+	}()
+}
+
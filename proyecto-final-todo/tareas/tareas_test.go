@@ -0,0 +1,707 @@
+// Tests unitarios para el sistema de gestión de tareas
+
+package tareas
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestValidarTitulo prueba la validación de títulos
+func TestValidarTitulo(t *testing.T) {
+	tests := []struct {
+		nombre    string
+		titulo    string
+		debeErrar bool
+	}{
+		{"título válido", "Comprar leche", false},
+		{"título vacío", "", true},
+		{"título con espacios", "   ", true},
+		{"título muy corto", "ab", true},
+		{"título exacto 3 chars", "abc", false},
+		{"título largo válido", "Esta es una tarea con un título bastante largo pero válido", false},
+		{"título muy largo", string(make([]byte, 101)), true},
+		{"título con 100 chars", string(make([]byte, 100)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			err := ValidarTitulo(tt.titulo)
+			
+			if tt.debeErrar && err == nil {
+				t.Error("Se esperaba un error pero no se obtuvo ninguno")
+			}
+			
+			if !tt.debeErrar && err != nil {
+				t.Errorf("No se esperaba error pero se obtuvo: %v", err)
+			}
+		})
+	}
+}
+
+// TestCrearTarea prueba la creación de tareas
+func TestCrearTarea(t *testing.T) {
+	// Creamos un archivo temporal para las pruebas
+	archivoTemp := "test_tareas.json"
+	defer os.Remove(archivoTemp)
+
+	gestor, err := NuevoGestorTareas(archivoTemp)
+	if err != nil {
+		t.Fatalf("Error al crear gestor: %v", err)
+	}
+
+	// Test: Crear tarea válida
+	tarea, err := gestor.Crear("Tarea de prueba")
+	if err != nil {
+		t.Errorf("Error al crear tarea válida: %v", err)
+	}
+
+	if tarea.ID != 1 {
+		t.Errorf("ID esperado: 1, obtenido: %d", tarea.ID)
+	}
+
+	if tarea.Titulo != "Tarea de prueba" {
+		t.Errorf("Título esperado: 'Tarea de prueba', obtenido: '%s'", tarea.Titulo)
+	}
+
+	if tarea.Completada {
+		t.Error("La tarea nueva debe estar incompleta")
+	}
+
+	// Test: Crear tarea inválida (título corto)
+	_, err = gestor.Crear("ab")
+	if err == nil {
+		t.Error("Se esperaba error para título muy corto")
+	}
+
+	// Test: Incremento de ID
+	tarea2, _ := gestor.Crear("Segunda tarea")
+	if tarea2.ID != 2 {
+		t.Errorf("ID esperado: 2, obtenido: %d", tarea2.ID)
+	}
+}
+
+// TestBuscarPorID prueba la búsqueda por ID
+func TestBuscarPorID(t *testing.T) {
+	archivoTemp := "test_buscar.json"
+	defer os.Remove(archivoTemp)
+
+	gestor, _ := NuevoGestorTareas(archivoTemp)
+	
+	// Creamos algunas tareas
+	gestor.Crear("Tarea 1")
+	gestor.Crear("Tarea 2")
+	gestor.Crear("Tarea 3")
+
+	// Test: Buscar tarea existente
+	tarea, err := gestor.BuscarPorID(2)
+	if err != nil {
+		t.Errorf("Error al buscar tarea existente: %v", err)
+	}
+
+	if tarea.Titulo != "Tarea 2" {
+		t.Errorf("Título esperado: 'Tarea 2', obtenido: '%s'", tarea.Titulo)
+	}
+
+	// Test: Buscar tarea inexistente
+	_, err = gestor.BuscarPorID(999)
+	if err == nil {
+		t.Error("Se esperaba error para ID inexistente")
+	}
+}
+
+// TestBuscarPorTexto prueba la búsqueda por texto
+func TestBuscarPorTexto(t *testing.T) {
+	archivoTemp := "test_buscar_texto.json"
+	defer os.Remove(archivoTemp)
+
+	gestor, _ := NuevoGestorTareas(archivoTemp)
+	
+	// Creamos tareas con diferentes títulos
+	gestor.Crear("Comprar leche")
+	gestor.Crear("Comprar pan")
+	gestor.Crear("Estudiar Go")
+	gestor.Crear("Leer libro de programación")
+
+	// Test: Búsqueda que encuentra múltiples resultados
+	encontradas := gestor.BuscarPorTexto("Comprar")
+	if len(encontradas) != 2 {
+		t.Errorf("Se esperaban 2 resultados, se obtuvieron: %d", len(encontradas))
+	}
+
+	// Test: Búsqueda case-insensitive
+	encontradas = gestor.BuscarPorTexto("go")
+	if len(encontradas) != 1 {
+		t.Errorf("Se esperaba 1 resultado, se obtuvieron: %d", len(encontradas))
+	}
+
+	// Test: Búsqueda sin tildes
+	encontradas = gestor.BuscarPorTexto("PROGRAMACION")
+	if len(encontradas) != 1 {
+		t.Errorf("Se esperaba 1 resultado sin distinguir tildes, se obtuvieron: %d", len(encontradas))
+	}
+
+	// Test: Búsqueda sin resultados
+	encontradas = gestor.BuscarPorTexto("inexistente")
+	if len(encontradas) != 0 {
+		t.Errorf("Se esperaban 0 resultados, se obtuvieron: %d", len(encontradas))
+	}
+
+	// Test: Búsqueda vacía retorna nada
+	encontradas = gestor.BuscarPorTexto("")
+	if len(encontradas) != 4 {
+		t.Errorf("Búsqueda vacía debería retornar todas (4), se obtuvieron: %d", len(encontradas))
+	}
+}
+
+// TestCompletarTarea prueba completar tareas
+func TestCompletarTarea(t *testing.T) {
+	archivoTemp := "test_completar.json"
+	defer os.Remove(archivoTemp)
+
+	gestor, _ := NuevoGestorTareas(archivoTemp)
+	tarea, _ := gestor.Crear("Tarea por completar")
+
+	// Test: Completar tarea existente
+	err := gestor.Completar(tarea.ID)
+	if err != nil {
+		t.Errorf("Error al completar tarea: %v", err)
+	}
+
+	// Verificamos que esté completada
+	tareaActualizada, _ := gestor.BuscarPorID(tarea.ID)
+	if !tareaActualizada.Completada {
+		t.Error("La tarea debería estar completada")
+	}
+
+	// Test: Completar tarea ya completada
+	err = gestor.Completar(tarea.ID)
+	if err == nil {
+		t.Error("Se esperaba error al completar tarea ya completada")
+	}
+
+	// Test: Completar tarea inexistente
+	err = gestor.Completar(999)
+	if err == nil {
+		t.Error("Se esperaba error para tarea inexistente")
+	}
+}
+
+// TestActualizarTarea prueba la edición parcial de tareas
+func TestActualizarTarea(t *testing.T) {
+	archivoTemp := "test_actualizar.json"
+	defer os.Remove(archivoTemp)
+
+	gestor, _ := NuevoGestorTareas(archivoTemp)
+	tarea, _ := gestor.CrearConDatos(DatosTarea{Titulo: "Tarea original", Prioridad: PrioridadBaja, Etiquetas: []string{"casa"}})
+
+	if !tarea.FechaActualizacion.IsZero() {
+		t.Error("Una tarea nueva no debería tener fecha de actualización")
+	}
+
+	// Test: Cambiar solo el título mantiene el resto
+	nuevoTitulo := "  Tarea renombrada  "
+	actualizada, err := gestor.Actualizar(tarea.ID, CambiosTarea{Titulo: &nuevoTitulo})
+	if err != nil {
+		t.Fatalf("Error al actualizar: %v", err)
+	}
+	if actualizada.Titulo != "Tarea renombrada" {
+		t.Errorf("Título esperado: 'Tarea renombrada', obtenido: '%s'", actualizada.Titulo)
+	}
+	if actualizada.Prioridad != PrioridadBaja || !actualizada.TieneEtiqueta("casa") {
+		t.Errorf("Los campos no indicados deberían mantenerse: %+v", actualizada)
+	}
+	if actualizada.FechaActualizacion.IsZero() {
+		t.Error("Actualizar debería registrar la fecha de actualización")
+	}
+
+	// Test: La validación se vuelve a aplicar
+	corto := "ab"
+	if _, err := gestor.Actualizar(tarea.ID, CambiosTarea{Titulo: &corto}); err == nil {
+		t.Error("Se esperaba error de validación para título muy corto")
+	}
+	invalida := Prioridad("crítica")
+	if _, err := gestor.Actualizar(tarea.ID, CambiosTarea{Prioridad: &invalida}); err == nil {
+		t.Error("Se esperaba error de validación para prioridad inválida")
+	}
+	sinCambios, _ := gestor.BuscarPorID(tarea.ID)
+	if sinCambios.Titulo != "Tarea renombrada" || sinCambios.Prioridad != PrioridadBaja {
+		t.Error("Una actualización inválida no debería modificar la tarea")
+	}
+
+	// Test: Quitar etiquetas y fecha de vencimiento
+	sinEtiquetas := []string{}
+	sinFecha := time.Time{}
+	actualizada, _ = gestor.Actualizar(tarea.ID, CambiosTarea{Etiquetas: &sinEtiquetas, FechaVencimiento: &sinFecha})
+	if len(actualizada.Etiquetas) != 0 || !actualizada.FechaVencimiento.IsZero() {
+		t.Errorf("Se esperaba quitar etiquetas y vencimiento: %+v", actualizada)
+	}
+
+	// Test: Completar y reabrir
+	si, no := true, false
+	actualizada, _ = gestor.Actualizar(tarea.ID, CambiosTarea{Completada: &si})
+	if !actualizada.Completada || actualizada.FechaCompletada.IsZero() {
+		t.Error("La tarea debería quedar completada con fecha de completado")
+	}
+	actualizada, _ = gestor.Actualizar(tarea.ID, CambiosTarea{Completada: &no})
+	if actualizada.Completada || !actualizada.FechaCompletada.IsZero() {
+		t.Error("Reabrir debería marcar la tarea pendiente y borrar la fecha de completado")
+	}
+	if err := gestor.Completar(tarea.ID); err != nil {
+		t.Errorf("Una tarea reabierta debería poder completarse de nuevo: %v", err)
+	}
+
+	// Test: Sin cambios o ID inexistente
+	if _, err := gestor.Actualizar(tarea.ID, CambiosTarea{}); err == nil {
+		t.Error("Se esperaba error si no se indica ningún cambio")
+	}
+	if _, err := gestor.Actualizar(999, CambiosTarea{Titulo: &nuevoTitulo}); err == nil {
+		t.Error("Se esperaba error para tarea inexistente")
+	}
+}
+
+// TestEliminarTarea prueba eliminar tareas
+func TestEliminarTarea(t *testing.T) {
+	archivoTemp := "test_eliminar.json"
+	defer os.Remove(archivoTemp)
+
+	gestor, _ := NuevoGestorTareas(archivoTemp)
+	
+	tarea1, _ := gestor.Crear("Tarea 1")
+	gestor.Crear("Tarea 2")
+	gestor.Crear("Tarea 3")
+
+	// Test: Eliminar tarea existente
+	err := gestor.Eliminar(tarea1.ID)
+	if err != nil {
+		t.Errorf("Error al eliminar tarea: %v", err)
+	}
+
+	// Verificamos que ya no existe
+	_, err = gestor.BuscarPorID(tarea1.ID)
+	if err == nil {
+		t.Error("La tarea eliminada no debería existir")
+	}
+
+	// Verificamos que quedan 2 tareas visibles y la eliminada en la papelera
+	if lista := gestor.Listar(); len(lista) != 2 {
+		t.Errorf("Deberían quedar 2 tareas, hay: %d", len(lista))
+	}
+	if papelera := gestor.ListarPapelera(); len(papelera) != 1 || papelera[0].ID != tarea1.ID {
+		t.Errorf("La tarea eliminada debería estar en la papelera, hay: %+v", papelera)
+	}
+
+	// Test: Eliminar una tarea que ya está en la papelera
+	if err := gestor.Eliminar(tarea1.ID); err == nil {
+		t.Error("Se esperaba error al eliminar una tarea de la papelera")
+	}
+
+	// Test: Eliminar tarea inexistente
+	err = gestor.Eliminar(999)
+	if err == nil {
+		t.Error("Se esperaba error para tarea inexistente")
+	}
+}
+
+// TestEstadisticas prueba las estadísticas
+func TestEstadisticas(t *testing.T) {
+	archivoTemp := "test_stats.json"
+	defer os.Remove(archivoTemp)
+
+	gestor, _ := NuevoGestorTareas(archivoTemp)
+
+	// Test: Estadísticas vacías
+	total, completadas, pendientes := gestor.Estadisticas()
+	if total != 0 || completadas != 0 || pendientes != 0 {
+		t.Error("Estadísticas iniciales deberían ser todas 0")
+	}
+
+	// Creamos tareas
+	t1, _ := gestor.Crear("Tarea 1")
+	gestor.Crear("Tarea 2")
+	t3, _ := gestor.Crear("Tarea 3")
+
+	// Completamos algunas
+	gestor.Completar(t1.ID)
+	gestor.Completar(t3.ID)
+
+	// Test: Estadísticas con datos
+	total, completadas, pendientes = gestor.Estadisticas()
+	
+	if total != 3 {
+		t.Errorf("Total esperado: 3, obtenido: %d", total)
+	}
+	if completadas != 2 {
+		t.Errorf("Completadas esperadas: 2, obtenidas: %d", completadas)
+	}
+	if pendientes != 1 {
+		t.Errorf("Pendientes esperadas: 1, obtenidas: %d", pendientes)
+	}
+}
+
+// TestPersistencia prueba guardar y cargar tareas
+func TestPersistencia(t *testing.T) {
+	archivoTemp := "test_persistencia.json"
+	defer os.Remove(archivoTemp)
+
+	// Creamos gestor y tareas
+	gestor1, _ := NuevoGestorTareas(archivoTemp)
+	gestor1.Crear("Tarea persistente 1")
+	gestor1.Crear("Tarea persistente 2")
+	gestor1.Completar(1)
+
+	// Guardamos
+	err := gestor1.Guardar()
+	if err != nil {
+		t.Fatalf("Error al guardar: %v", err)
+	}
+
+	// Creamos nuevo gestor y cargamos
+	gestor2, err := NuevoGestorTareas(archivoTemp)
+	if err != nil {
+		t.Fatalf("Error al crear segundo gestor: %v", err)
+	}
+
+	// Verificamos que se cargaron correctamente
+	if len(gestor2.tareas) != 2 {
+		t.Errorf("Se esperaban 2 tareas cargadas, se obtuvieron: %d", len(gestor2.tareas))
+	}
+
+	// Verificamos que la tarea completada sigue completada
+	tarea, _ := gestor2.BuscarPorID(1)
+	if !tarea.Completada {
+		t.Error("La tarea cargada debería estar completada")
+	}
+
+	// Verificamos que el próximo ID es correcto
+	nuevaTarea, _ := gestor2.Crear("Nueva tarea")
+	if nuevaTarea.ID != 3 {
+		t.Errorf("El próximo ID debería ser 3, es: %d", nuevaTarea.ID)
+	}
+}
+
+// TestListarPendientesYCompletadas prueba los filtros de listado
+func TestListarPendientesYCompletadas(t *testing.T) {
+	archivoTemp := "test_listar.json"
+	defer os.Remove(archivoTemp)
+
+	gestor, _ := NuevoGestorTareas(archivoTemp)
+	
+	t1, _ := gestor.Crear("Tarea 1")
+	gestor.Crear("Tarea 2")
+	t3, _ := gestor.Crear("Tarea 3")
+	gestor.Crear("Tarea 4")
+
+	// Completamos algunas
+	gestor.Completar(t1.ID)
+	gestor.Completar(t3.ID)
+
+	// Test: Listar pendientes
+	pendientes := gestor.ListarPendientes()
+	if len(pendientes) != 2 {
+		t.Errorf("Se esperaban 2 pendientes, se obtuvieron: %d", len(pendientes))
+	}
+
+	// Test: Listar completadas
+	completadas := gestor.ListarCompletadas()
+	if len(completadas) != 2 {
+		t.Errorf("Se esperaban 2 completadas, se obtuvieron: %d", len(completadas))
+	}
+}
+
+// TestFechaCreacion verifica que se guarde correctamente la fecha
+func TestFechaCreacion(t *testing.T) {
+	archivoTemp := "test_fecha.json"
+	defer os.Remove(archivoTemp)
+
+	gestor, _ := NuevoGestorTareas(archivoTemp)
+	
+	antes := time.Now()
+	tarea, _ := gestor.Crear("Tarea con fecha")
+	despues := time.Now()
+
+	// Verificamos que la fecha esté en el rango correcto
+	if tarea.FechaCreacion.Before(antes) || tarea.FechaCreacion.After(despues) {
+		t.Error("La fecha de creación no está en el rango esperado")
+	}
+}
+
+// TestValidarCamposOpcionales prueba las validaciones de los campos opcionales
+func TestValidarCamposOpcionales(t *testing.T) {
+	muchas := make([]string, 11)
+	for i := range muchas {
+		muchas[i] = fmt.Sprintf("e%d", i)
+	}
+
+	tests := []struct {
+		nombre    string
+		datos     DatosTarea
+		debeErrar bool
+	}{
+		{"solo título", DatosTarea{Titulo: "Tarea simple"}, false},
+		{"todos los campos", DatosTarea{Titulo: "Tarea completa", Descripcion: "Detalle", Prioridad: PrioridadUrgente, Etiquetas: []string{"casa", "Urgente"}}, false},
+		{"título inválido", DatosTarea{Titulo: "ab", Prioridad: PrioridadAlta}, true},
+		{"descripción larga", DatosTarea{Titulo: "Tarea", Descripcion: string(make([]byte, 1001))}, true},
+		{"prioridad desconocida", DatosTarea{Titulo: "Tarea", Prioridad: "crítica"}, true},
+		{"etiqueta con espacio", DatosTarea{Titulo: "Tarea", Etiquetas: []string{"dos palabras"}}, true},
+		{"etiqueta vacía", DatosTarea{Titulo: "Tarea", Etiquetas: []string{" "}}, true},
+		{"demasiadas etiquetas", DatosTarea{Titulo: "Tarea", Etiquetas: muchas}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			err := tt.datos.Validar()
+
+			if tt.debeErrar && err == nil {
+				t.Error("Se esperaba un error pero no se obtuvo ninguno")
+			}
+
+			if !tt.debeErrar && err != nil {
+				t.Errorf("No se esperaba error pero se obtuvo: %v", err)
+			}
+		})
+	}
+}
+
+// TestCrearConDatos prueba la creación de tareas con campos opcionales
+func TestCrearConDatos(t *testing.T) {
+	archivoTemp := "test_crear_datos.json"
+	defer os.Remove(archivoTemp)
+
+	gestor, _ := NuevoGestorTareas(archivoTemp)
+
+	vence := time.Date(2030, 1, 15, 18, 0, 0, 0, time.UTC)
+	tarea, err := gestor.CrearConDatos(DatosTarea{
+		Titulo:           "Preparar demo",
+		Descripcion:      "  Slides y entorno  ",
+		Prioridad:        PrioridadAlta,
+		FechaVencimiento: vence,
+		Etiquetas:        []string{"Trabajo", "demo", "trabajo"},
+	})
+	if err != nil {
+		t.Fatalf("Error al crear tarea: %v", err)
+	}
+
+	if tarea.Descripcion != "Slides y entorno" {
+		t.Errorf("Descripción esperada sin espacios, obtenida: '%s'", tarea.Descripcion)
+	}
+	if len(tarea.Etiquetas) != 2 || tarea.Etiquetas[0] != "trabajo" || tarea.Etiquetas[1] != "demo" {
+		t.Errorf("Etiquetas normalizadas esperadas [trabajo demo], obtenidas: %v", tarea.Etiquetas)
+	}
+
+	// Completar registra la fecha de completado
+	if !tarea.FechaCompletada.IsZero() {
+		t.Error("Una tarea nueva no debería tener fecha de completado")
+	}
+	gestor.Completar(tarea.ID)
+	completada, _ := gestor.BuscarPorID(tarea.ID)
+	if completada.FechaCompletada.IsZero() {
+		t.Error("Completar debería registrar la fecha de completado")
+	}
+
+	// Los campos sobreviven a guardar y cargar
+	gestor.Guardar()
+	recargado, _ := NuevoGestorTareas(archivoTemp)
+	cargada, _ := recargado.BuscarPorID(tarea.ID)
+	if cargada.Prioridad != PrioridadAlta || !cargada.FechaVencimiento.Equal(vence) || len(cargada.Etiquetas) != 2 {
+		t.Errorf("Los campos opcionales no se conservaron: %+v", cargada)
+	}
+}
+
+// TestCargaRetrocompatible verifica que se carguen archivos sin los campos nuevos
+func TestCargaRetrocompatible(t *testing.T) {
+	archivoTemp := "test_retrocompatible.json"
+	defer eliminarConRespaldos(archivoTemp)
+
+	antiguo := `[{"id": 1, "titulo": "Tarea antigua", "completada": true, "fecha_creacion": "2024-01-15T10:30:00Z"}]`
+	os.WriteFile(archivoTemp, []byte(antiguo), 0644)
+
+	gestor, err := NuevoGestorTareas(archivoTemp)
+	if err != nil {
+		t.Fatalf("Error al cargar archivo antiguo: %v", err)
+	}
+
+	tarea, _ := gestor.BuscarPorID(1)
+	if tarea.Titulo != "Tarea antigua" || !tarea.Completada {
+		t.Errorf("Tarea antigua mal cargada: %+v", tarea)
+	}
+	if tarea.Prioridad != "" || !tarea.FechaVencimiento.IsZero() || tarea.Etiquetas != nil {
+		t.Errorf("Los campos nuevos deberían quedar vacíos: %+v", tarea)
+	}
+
+	// Al guardar, los campos vacíos no se escriben
+	gestor.Guardar()
+	datos, _ := os.ReadFile(archivoTemp)
+	if strings.Contains(string(datos), "fecha_vencimiento") || strings.Contains(string(datos), "etiquetas") {
+		t.Errorf("No deberían escribirse campos opcionales vacíos: %s", datos)
+	}
+}
+
+// TestListarVencidasYPorEtiqueta prueba los nuevos listados
+func TestListarVencidasYPorEtiqueta(t *testing.T) {
+	archivoTemp := "test_vencidas.json"
+	defer os.Remove(archivoTemp)
+
+	gestor, _ := NuevoGestorTareas(archivoTemp)
+
+	ayer := time.Now().Add(-24 * time.Hour)
+	manana := time.Now().Add(24 * time.Hour)
+
+	gestor.CrearConDatos(DatosTarea{Titulo: "Vencida", FechaVencimiento: ayer, Etiquetas: []string{"casa"}})
+	gestor.CrearConDatos(DatosTarea{Titulo: "A tiempo", FechaVencimiento: manana, Etiquetas: []string{"trabajo"}})
+	gestor.CrearConDatos(DatosTarea{Titulo: "Vencida pero hecha", FechaVencimiento: ayer, Etiquetas: []string{"casa"}})
+	gestor.Crear("Sin fecha")
+	gestor.Completar(3)
+
+	vencidas := gestor.ListarVencidas()
+	if len(vencidas) != 1 || vencidas[0].ID != 1 {
+		t.Errorf("Se esperaba solo la tarea 1 vencida, se obtuvo: %+v", vencidas)
+	}
+
+	casa := gestor.ListarPorEtiqueta("CASA")
+	if len(casa) != 2 {
+		t.Errorf("Se esperaban 2 tareas con etiqueta casa, se obtuvieron: %d", len(casa))
+	}
+
+	// Modificar las etiquetas retornadas no altera el gestor
+	casa[0].Etiquetas[0] = "modificada"
+	if len(gestor.ListarPorEtiqueta("casa")) != 2 {
+		t.Error("Las etiquetas retornadas no deberían compartir memoria con el gestor")
+	}
+}
+
+// TestConcurrenciaConAutoguardado ejercita el gestor desde muchas goroutines
+// mientras el autoguardado escribe en disco. Ejecutar con: go test -race
+func TestConcurrenciaConAutoguardado(t *testing.T) {
+	archivoTemp := "test_concurrencia.json"
+	defer eliminarConRespaldos(archivoTemp)
+
+	gestor, _ := NuevoGestorTareas(archivoTemp)
+
+	// Autoguardado muy frecuente para que coincida con las mutaciones
+	detener := make(chan bool)
+	gestor.IniciarAutoguardado(time.Millisecond, detener)
+
+	const goroutines = 20
+	const tareasPorGoroutine = 25
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < tareasPorGoroutine; i++ {
+				tarea, err := gestor.Crear(fmt.Sprintf("Tarea %d-%d", g, i))
+				if err != nil {
+					t.Errorf("Error al crear tarea: %v", err)
+					return
+				}
+
+				// Mezclamos lecturas con escrituras
+				gestor.Listar()
+				gestor.ListarPendientes()
+				gestor.BuscarPorTexto("tarea")
+				gestor.Estadisticas()
+
+				if i%2 == 0 {
+					if err := gestor.Completar(tarea.ID); err != nil {
+						t.Errorf("Error al completar tarea %d: %v", tarea.ID, err)
+					}
+				}
+				if i%5 == 0 {
+					if err := gestor.Eliminar(tarea.ID); err != nil {
+						t.Errorf("Error al eliminar tarea %d: %v", tarea.ID, err)
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	detener <- true
+
+	// Cada goroutine elimina las iteraciones 0, 5, 10, 15 y 20
+	esperadas := goroutines * (tareasPorGoroutine - 5)
+	total, _, _ := gestor.Estadisticas()
+	if total != esperadas {
+		t.Errorf("Total esperado: %d, obtenido: %d", esperadas, total)
+	}
+
+	// Los IDs deben ser únicos aunque se hayan creado en paralelo
+	vistos := make(map[int]bool)
+	for _, tarea := range gestor.Listar() {
+		if vistos[tarea.ID] {
+			t.Errorf("ID duplicado: %d", tarea.ID)
+		}
+		vistos[tarea.ID] = true
+	}
+
+	// El archivo final debe reflejar el estado en memoria
+	if err := gestor.Guardar(); err != nil {
+		t.Fatalf("Error al guardar: %v", err)
+	}
+	recargado, err := NuevoGestorTareas(archivoTemp)
+	if err != nil {
+		t.Fatalf("Error al recargar: %v", err)
+	}
+	if total, _, _ := recargado.Estadisticas(); total != esperadas {
+		t.Errorf("Tras recargar se esperaban %d tareas, hay: %d", esperadas, total)
+	}
+}
+
+// TestLecturasRetornanCopias verifica que modificar lo retornado no altera el gestor
+func TestLecturasRetornanCopias(t *testing.T) {
+	archivoTemp := "test_copias.json"
+	defer os.Remove(archivoTemp)
+
+	gestor, _ := NuevoGestorTareas(archivoTemp)
+	gestor.Crear("Tarea original")
+
+	tarea, _ := gestor.BuscarPorID(1)
+	tarea.Titulo = "Modificada por fuera"
+
+	lista := gestor.Listar()
+	lista[0].Completada = true
+
+	original, _ := gestor.BuscarPorID(1)
+	if original.Titulo != "Tarea original" {
+		t.Errorf("BuscarPorID no debería exponer el estado interno, título: '%s'", original.Titulo)
+	}
+	if original.Completada {
+		t.Error("Listar no debería exponer el estado interno")
+	}
+}
+
+// Benchmark para crear tareas
+func BenchmarkCrearTarea(b *testing.B) {
+	archivoTemp := "bench_crear.json"
+	defer os.Remove(archivoTemp)
+
+	gestor, _ := NuevoGestorTareas(archivoTemp)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		gestor.Crear("Tarea de benchmark")
+	}
+}
+
+// Benchmark para buscar por ID
+func BenchmarkBuscarPorID(b *testing.B) {
+	archivoTemp := "bench_buscar.json"
+	defer os.Remove(archivoTemp)
+
+	gestor, _ := NuevoGestorTareas(archivoTemp)
+	
+	// Creamos 1000 tareas
+	for i := 0; i < 1000; i++ {
+		gestor.Crear("Tarea de prueba")
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		gestor.BuscarPorID(500)
+	}
+}
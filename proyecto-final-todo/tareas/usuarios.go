@@ -0,0 +1,263 @@
+package tareas
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrorPermiso indica que el usuario del gestor no puede modificar una
+// tarea porque no es su propietario ni está asignado a ella.
+//
+// Los llamadores pueden detectarlo con errors.As para, por ejemplo,
+// traducirlo a un 403 en la API HTTP.
+type ErrorPermiso struct {
+	// ID es la tarea que se intentó modificar.
+	ID int
+
+	// Usuario es el usuario al que se le negó el permiso.
+	Usuario string
+
+	// SoloPropietario indica que lo intentado está reservado al propietario
+	// (cambiar el propietario o los asignados), aunque el usuario esté asignado.
+	SoloPropietario bool
+}
+
+// Error implementa la interfaz error.
+func (e *ErrorPermiso) Error() string {
+	if e.SoloPropietario {
+		return fmt.Sprintf("el usuario %q no puede cambiar el propietario ni los asignados de la tarea %d: solo puede hacerlo su propietario", e.Usuario, e.ID)
+	}
+	return fmt.Sprintf("el usuario %q no puede modificar la tarea %d: no es su propietario ni está asignado a ella", e.Usuario, e.ID)
+}
+
+// ConUsuario indica en nombre de qué usuario actúa el gestor.
+//
+// El usuario queda como propietario de las tareas que crea el gestor, y las
+// modificaciones de tareas ajenas que no tiene asignadas se rechazan con
+// *ErrorPermiso. Sin esta opción el gestor no tiene usuario: las tareas se
+// crean sin propietario y no se comprueban permisos, como antes de existir
+// los propietarios. El nombre debe superar ValidarUsuario.
+//
+// Ejemplo:
+//
+//	gestor, err := NuevoGestorTareas("tareas.json", ConUsuario("ana"))
+//
+func ConUsuario(usuario string) Opcion {
+	return func(g *GestorTareas) {
+		g.usuario = normalizarUsuario(usuario)
+	}
+}
+
+// ComoUsuario retorna un gestor sobre las mismas tareas que actúa en nombre
+// de otro usuario.
+//
+// Ambos gestores comparten las tareas, el almacenamiento y el bloqueo, por
+// lo que lo que haga uno lo ven los demás; en cambio, cada usuario solo
+// puede deshacer y rehacer sus propias operaciones. Permite que un
+// servidor HTTP atienda cada petición en nombre del usuario autenticado sin
+// abrir un gestor por usuario. Con usuario vacío no se comprueban permisos.
+//
+// Ejemplo:
+//
+//	gestorDeLuis := gestor.ComoUsuario("luis")
+//	err := gestorDeLuis.Completar(3) // *ErrorPermiso si la tarea no es suya ni la tiene asignada
+//
+func (g *GestorTareas) ComoUsuario(usuario string) *GestorTareas {
+	return &GestorTareas{estadoGestor: g.estadoGestor, usuario: normalizarUsuario(usuario)}
+}
+
+// Usuario retorna el usuario en cuyo nombre actúa el gestor, o "" si no
+// tiene ninguno.
+func (g *GestorTareas) Usuario() string {
+	return g.usuario
+}
+
+// ValidarUsuario valida el nombre de un usuario.
+//
+// Como las listas, debe tener entre 1 y 30 caracteres y no puede contener
+// espacios, comas ni ':', para poder usarse en las consultas
+// (propietario:nombre) y en las listas de asignados separadas por comas.
+//
+func ValidarUsuario(nombre string) error {
+	nombre = strings.TrimSpace(nombre)
+	if nombre == "" {
+		return &ErrorValidacion{"el nombre de usuario no puede estar vacío"}
+	}
+	if len(nombre) > 30 {
+		return &ErrorValidacion{fmt.Sprintf("el nombre de usuario %q no puede exceder 30 caracteres", nombre)}
+	}
+	if strings.ContainsAny(nombre, " \t,:") {
+		return &ErrorValidacion{fmt.Sprintf("el nombre de usuario %q no puede contener espacios, comas ni ':'", nombre)}
+	}
+	return nil
+}
+
+// normalizarUsuario recorta y pasa a minúsculas el nombre de un usuario.
+func normalizarUsuario(nombre string) string {
+	return strings.ToLower(strings.TrimSpace(nombre))
+}
+
+// normalizarUsuarios normaliza una lista de usuarios igual que las
+// etiquetas: en minúsculas, sin repetir y nil si no queda ninguno.
+func normalizarUsuarios(usuarios []string) []string {
+	return normalizarEtiquetas(usuarios)
+}
+
+// EstaAsignada indica si la tarea está asignada al usuario dado (sin
+// distinguir mayúsculas).
+func (t Tarea) EstaAsignada(usuario string) bool {
+	return slices.Contains(t.Asignados, normalizarUsuario(usuario))
+}
+
+// PuedeModificar indica si el usuario dado puede modificar la tarea: es su
+// propietario o está asignado a ella. Las tareas sin propietario puede
+// modificarlas cualquiera, y el usuario vacío (sin identidad) cualquier tarea.
+func (t Tarea) PuedeModificar(usuario string) bool {
+	usuario = normalizarUsuario(usuario)
+	return usuario == "" || t.Propietario == "" || t.Propietario == usuario || t.EstaAsignada(usuario)
+}
+
+// ListarMias retorna las tareas de las que el usuario del gestor es
+// propietario o en las que está asignado, es decir, las que puede
+// modificar además de las que no tienen propietario. Sin usuario retorna
+// las tareas sin propietario.
+//
+// Ejemplo:
+//
+//	for _, tarea := range gestor.ComoUsuario("ana").ListarMias() {
+//		fmt.Println(tarea.Titulo)
+//	}
+//
+func (g *GestorTareas) ListarMias() []Tarea {
+	return g.listarSi(func(t Tarea) bool {
+		return t.Propietario == g.usuario || (g.usuario != "" && t.EstaAsignada(g.usuario))
+	})
+}
+
+// ListarPorPropietario retorna las tareas cuyo propietario es el usuario
+// indicado ("" para las tareas sin propietario).
+//
+func (g *GestorTareas) ListarPorPropietario(usuario string) []Tarea {
+	usuario = normalizarUsuario(usuario)
+	return g.listarSi(func(t Tarea) bool { return t.Propietario == usuario })
+}
+
+// ListarAsignadas retorna las tareas asignadas al usuario indicado.
+//
+// Ejemplo:
+//
+//	deLuis := gestor.ListarAsignadas("luis")
+//
+func (g *GestorTareas) ListarAsignadas(usuario string) []Tarea {
+	return g.listarSi(func(t Tarea) bool { return t.EstaAsignada(usuario) })
+}
+
+// Asignar añade usuarios a los asignados de una tarea, que desde entonces
+// pueden modificarla. Los ya asignados se ignoran.
+//
+// Solo el propietario de la tarea puede asignarla (o cualquiera, si no tiene
+// propietario). Puede deshacerse como una edición.
+//
+// Parámetros:
+//   - id: la tarea a asignar
+//   - usuarios: los usuarios a añadir, que deben superar ValidarUsuario
+//
+// Retorna:
+//   - *Tarea: copia de la tarea tal como quedó
+//   - error: ErrorNoEncontrada si la tarea no existe, *ErrorPermiso si el
+//     usuario del gestor no es su propietario, ErrorValidacion si algún
+//     nombre no es válido, o un error de escritura del diario
+//
+// Ejemplo:
+//
+//	tarea, err := gestor.Asignar(3, "luis", "marta")
+//
+func (g *GestorTareas) Asignar(id int, usuarios ...string) (*Tarea, error) {
+	for _, usuario := range usuarios {
+		if err := ValidarUsuario(usuario); err != nil {
+			return nil, err
+		}
+	}
+	return g.cambiarAsignados(id, func(asignados []string) []string {
+		return normalizarUsuarios(append(asignados, usuarios...))
+	})
+}
+
+// Desasignar quita usuarios de los asignados de una tarea. Los que no
+// estaban asignados se ignoran. Sigue las mismas reglas que Asignar.
+//
+func (g *GestorTareas) Desasignar(id int, usuarios ...string) (*Tarea, error) {
+	quitar := normalizarUsuarios(usuarios)
+	return g.cambiarAsignados(id, func(asignados []string) []string {
+		return normalizarUsuarios(slices.DeleteFunc(asignados, func(u string) bool {
+			return slices.Contains(quitar, u)
+		}))
+	})
+}
+
+// cambiarAsignados sustituye los asignados de la tarea id por los que
+// retorna cambiar a partir de los actuales, comprobando que el usuario del
+// gestor sea su propietario.
+func (g *GestorTareas) cambiarAsignados(id int, cambiar func(asignados []string) []string) (*Tarea, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	i := g.indiceVisible(id)
+	if i < 0 {
+		return nil, &ErrorNoEncontrada{ID: id}
+	}
+	if err := g.comprobarPropietario(i); err != nil {
+		return nil, err
+	}
+
+	actualizada := g.tareas[i].clonar()
+	actualizada.Asignados = cambiar(actualizada.Asignados)
+	if slices.Equal(actualizada.Asignados, g.tareas[i].Asignados) {
+		return &actualizada, nil
+	}
+	if err := g.reemplazarTarea(i, actualizada); err != nil {
+		return nil, err
+	}
+
+	actualizada = g.tareas[i].clonar()
+	return &actualizada, nil
+}
+
+// listarSi retorna copias de las tareas fuera de la papelera que cumplen la
+// condición, en orden de creación.
+func (g *GestorTareas) listarSi(condicion func(Tarea) bool) []Tarea {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var encontradas []Tarea
+	for _, tarea := range g.tareas {
+		if !tarea.EnPapelera() && condicion(tarea) {
+			encontradas = append(encontradas, tarea.clonar())
+		}
+	}
+	return encontradas
+}
+
+// comprobarPermiso retorna *ErrorPermiso si el usuario del gestor no puede
+// modificar la tarea de la posición i (ver Tarea.PuedeModificar). Una
+// posición negativa (sin tarea) siempre se permite. Debe llamarse con g.mu
+// tomado.
+func (g *GestorTareas) comprobarPermiso(i int) error {
+	if i < 0 || g.tareas[i].PuedeModificar(g.usuario) {
+		return nil
+	}
+	return &ErrorPermiso{ID: g.tareas[i].ID, Usuario: g.usuario}
+}
+
+// comprobarPropietario retorna *ErrorPermiso si el usuario del gestor no
+// puede cambiar el propietario ni los asignados de la tarea de la posición
+// i: solo puede su propietario, o cualquiera si no tiene. Debe llamarse con
+// g.mu tomado.
+func (g *GestorTareas) comprobarPropietario(i int) error {
+	tarea := g.tareas[i]
+	if g.usuario == "" || tarea.Propietario == "" || tarea.Propietario == g.usuario {
+		return nil
+	}
+	return &ErrorPermiso{ID: tarea.ID, Usuario: g.usuario, SoloPropietario: true}
+}
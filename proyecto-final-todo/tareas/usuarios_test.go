@@ -0,0 +1,279 @@
+// Tests de propietarios, asignados y permisos
+
+package tareas
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestPropietarioYAsignados verifica el propietario de las tareas nuevas,
+// los asignados y los listados por usuario
+func TestPropietarioYAsignados(t *testing.T) {
+	ana := nuevoGestorEnMemoria(t, ConUsuario(" Ana "))
+	luis := ana.ComoUsuario("luis")
+
+	if ana.Usuario() != "ana" || luis.Usuario() != "luis" {
+		t.Fatalf("Usuarios inesperados: %q y %q", ana.Usuario(), luis.Usuario())
+	}
+
+	ana.CrearConDatos(DatosTarea{Titulo: "Preparar informe", Asignados: []string{"Luis", "luis", "marta"}})
+	luis.Crear("Reservar sala")
+	ana.Crear("Revisar presupuesto")
+	ana.ComoUsuario("").Crear("Tarea sin propietario")
+
+	primera, _ := ana.BuscarPorID(1)
+	if primera.Propietario != "ana" || !reflect.DeepEqual(primera.Asignados, []string{"luis", "marta"}) {
+		t.Errorf("Propietario o asignados inesperados: %q %v", primera.Propietario, primera.Asignados)
+	}
+	if sinPropietario, _ := ana.BuscarPorID(4); sinPropietario.Propietario != "" {
+		t.Errorf("Sin usuario la tarea no debería tener propietario: %q", sinPropietario.Propietario)
+	}
+
+	tests := []struct {
+		nombre   string
+		obtenido []Tarea
+		esperado []int
+	}{
+		{"mías de ana", ana.ListarMias(), []int{1, 3}},
+		{"mías de luis", luis.ListarMias(), []int{1, 2}},
+		{"propietario luis", ana.ListarPorPropietario("LUIS"), []int{2}},
+		{"sin propietario", ana.ListarPorPropietario(""), []int{4}},
+		{"asignadas a marta", ana.ListarAsignadas("marta"), []int{1}},
+		{"asignadas a nadie", ana.ListarAsignadas("pedro"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nombre, func(t *testing.T) {
+			if ids := idsDe(tt.obtenido); !reflect.DeepEqual(ids, tt.esperado) {
+				t.Errorf("IDs = %v, se esperaba %v", ids, tt.esperado)
+			}
+		})
+	}
+
+	// Consultas por propietario y asignados
+	for consulta, esperado := range map[string][]int{
+		"propietario:ana":                 {1, 3},
+		"asignada:luis":                   {1},
+		"propietario:ana -asignado:marta": {3},
+	} {
+		resultados, err := ana.Buscar(consulta)
+		if err != nil {
+			t.Fatalf("Error en la consulta %q: %v", consulta, err)
+		}
+		if ids := idsDe(resultados); !reflect.DeepEqual(ids, esperado) {
+			t.Errorf("%q = %v, se esperaba %v", consulta, ids, esperado)
+		}
+	}
+
+	// Nombres de usuario inválidos
+	if _, err := ana.CrearConDatos(DatosTarea{Titulo: "Con asignado inválido", Asignados: []string{"luis garcía"}}); err == nil {
+		t.Error("Se esperaba error de validación para un usuario con espacios")
+	}
+}
+
+// TestPermisosDeModificacion verifica que solo el propietario y los
+// asignados puedan modificar una tarea
+func TestPermisosDeModificacion(t *testing.T) {
+	ana := nuevoGestorEnMemoria(t, ConUsuario("ana"))
+	luis := ana.ComoUsuario("luis")
+	marta := ana.ComoUsuario("marta")
+
+	ana.CrearConDatos(DatosTarea{Titulo: "Preparar informe", Asignados: []string{"luis"}})
+	ana.Crear("Revisar presupuesto")
+	ana.CrearConDatos(DatosTarea{Titulo: "Corregir gráficos", PadreID: 2})
+	ana.CrearLista("casa")
+
+	titulo := "Título cambiado"
+	operaciones := []struct {
+		nombre string
+		hacer  func(g *GestorTareas) error
+	}{
+		{"actualizar", func(g *GestorTareas) error { _, err := g.Actualizar(2, CambiosTarea{Titulo: &titulo}); return err }},
+		{"completar", func(g *GestorTareas) error { return g.Completar(2) }},
+		{"eliminar con subtareas", func(g *GestorTareas) error { _, err := g.EliminarConSubtareas(2); return err }},
+		{"dependencia", func(g *GestorTareas) error { return g.AgregarDependencia(2, 1) }},
+		{"mover", func(g *GestorTareas) error { return g.MoverALista(2, "casa") }},
+		{"subtarea", func(g *GestorTareas) error {
+			_, err := g.CrearConDatos(DatosTarea{Titulo: "Otra subtarea", PadreID: 2})
+			return err
+		}},
+		{"asignar", func(g *GestorTareas) error { _, err := g.Asignar(2, "luis"); return err }},
+	}
+
+	// Luis no es propietario de la tarea 2 ni está asignado
+	for _, op := range operaciones {
+		t.Run(op.nombre, func(t *testing.T) {
+			var permiso *ErrorPermiso
+			if err := op.hacer(luis); !errors.As(err, &permiso) || permiso.ID != 2 || permiso.Usuario != "luis" {
+				t.Errorf("Se esperaba ErrorPermiso de luis sobre la tarea 2, se obtuvo: %v", err)
+			}
+		})
+	}
+	if tarea, _ := ana.BuscarPorID(2); tarea.Titulo != "Revisar presupuesto" || tarea.Completada {
+		t.Errorf("Las operaciones rechazadas no deberían modificar la tarea: %+v", tarea)
+	}
+
+	// Un asignado puede editar y completar, pero no reasignar
+	if _, err := luis.Actualizar(1, CambiosTarea{Titulo: &titulo}); err != nil {
+		t.Errorf("Un asignado debería poder editar: %v", err)
+	}
+	if err := luis.Completar(1); err != nil {
+		t.Errorf("Un asignado debería poder completar: %v", err)
+	}
+	var permiso *ErrorPermiso
+	if _, err := luis.Asignar(1, "marta"); !errors.As(err, &permiso) || !permiso.SoloPropietario {
+		t.Errorf("Solo el propietario debería poder asignar, se obtuvo: %v", err)
+	}
+	propietario := "luis"
+	if _, err := luis.Actualizar(1, CambiosTarea{Propietario: &propietario}); !errors.As(err, &permiso) || !permiso.SoloPropietario {
+		t.Errorf("Solo el propietario debería poder cambiar el propietario, se obtuvo: %v", err)
+	}
+	if err := marta.Eliminar(1); !errors.As(err, &permiso) {
+		t.Errorf("Marta no debería poder eliminar la tarea 1: %v", err)
+	}
+
+	// El propietario asigna, desasigna y cede la tarea
+	if tarea, err := ana.Asignar(2, "marta", "Luis"); err != nil || !reflect.DeepEqual(tarea.Asignados, []string{"marta", "luis"}) {
+		t.Fatalf("Asignar = %+v, %v", tarea, err)
+	}
+	if err := luis.Completar(2); err != nil {
+		t.Errorf("Tras asignarla, luis debería poder completarla: %v", err)
+	}
+	if tarea, err := ana.Desasignar(2, "marta", "pedro"); err != nil || !reflect.DeepEqual(tarea.Asignados, []string{"luis"}) {
+		t.Errorf("Desasignar = %+v, %v", tarea, err)
+	}
+	if _, err := ana.Actualizar(2, CambiosTarea{Propietario: &propietario}); err != nil {
+		t.Fatalf("El propietario debería poder ceder la tarea: %v", err)
+	}
+	if _, err := ana.Desasignar(2, "luis"); !errors.As(err, &permiso) {
+		t.Errorf("Tras cederla, ana ya no debería poder reasignarla: %v", err)
+	}
+
+	// Deshacer revierte la cesión
+	ana.Deshacer()
+	if tarea, _ := ana.BuscarPorID(2); tarea.Propietario != "ana" {
+		t.Errorf("Deshacer debería devolver la tarea a ana: %q", tarea.Propietario)
+	}
+
+	// Sin usuario no se comprueban permisos
+	if err := ana.ComoUsuario("").Eliminar(1); err != nil {
+		t.Errorf("Sin usuario debería poder eliminarse cualquier tarea: %v", err)
+	}
+}
+
+// TestPropietarioPersistenteYRecurrente verifica que propietario y asignados
+// se guarden y pasen a la siguiente ocurrencia de una tarea recurrente
+func TestPropietarioPersistenteYRecurrente(t *testing.T) {
+	almacen := NuevoAlmacenamientoMemoria()
+	ana, _ := NuevoGestorTareas("", ConAlmacenamiento(almacen), ConUsuario("ana"))
+	ana.CrearConDatos(DatosTarea{
+		Titulo:      "Enviar resumen semanal",
+		Asignados:   []string{"luis"},
+		Recurrencia: &Recurrencia{Frecuencia: FrecuenciaSemanal},
+	})
+
+	siguiente, err := ana.ComoUsuario("luis").CompletarConSiguiente(1)
+	if err != nil || siguiente == nil {
+		t.Fatalf("Error al completar la tarea recurrente: %v", err)
+	}
+	if siguiente.Propietario != "ana" || !reflect.DeepEqual(siguiente.Asignados, []string{"luis"}) {
+		t.Errorf("La siguiente ocurrencia debería conservar propietario y asignados: %q %v", siguiente.Propietario, siguiente.Asignados)
+	}
+
+	if err := ana.Guardar(); err != nil {
+		t.Fatalf("Error al guardar: %v", err)
+	}
+	recargado, _ := NuevoGestorTareas("", ConAlmacenamiento(almacen))
+	if ids := idsDe(recargado.ListarAsignadas("luis")); !reflect.DeepEqual(ids, []int{1, 2}) {
+		t.Errorf("Tras recargar, luis debería tener asignadas las tareas 1 y 2: %v", ids)
+	}
+
+	// Las lecturas no exponen los asignados internos
+	tarea, _ := recargado.BuscarPorID(1)
+	tarea.Asignados[0] = "marta"
+	if original, _ := recargado.BuscarPorID(1); original.Asignados[0] != "luis" {
+		t.Error("Modificar la copia no debería alterar los asignados del gestor")
+	}
+}
+
+// TestHistorialPorUsuario verifica que cada usuario solo deshaga y rehaga
+// sus propias operaciones, y que no se revierta una tarea que otro usuario
+// cambió después
+func TestHistorialPorUsuario(t *testing.T) {
+	ana := nuevoGestorEnMemoria(t, ConUsuario("ana"))
+	luis := ana.ComoUsuario("luis")
+
+	ana.CrearConDatos(DatosTarea{Titulo: "Preparar informe", Asignados: []string{"luis"}})
+	luis.Crear("Reservar sala")
+
+	if op, err := luis.Deshacer(); err != nil || op.TareaID != 2 {
+		t.Fatalf("luis debería deshacer su propia creación: %v %v", op, err)
+	}
+	if _, err := luis.Deshacer(); !errors.Is(err, ErrNadaQueDeshacer) {
+		t.Errorf("luis no debería poder deshacer la operación de ana: %v", err)
+	}
+
+	// Una operación de ana no descarta lo que luis tiene pendiente de rehacer
+	ana.Crear("Revisar presupuesto")
+	if op, err := luis.Rehacer(); err != nil || op.TareaID != 2 {
+		t.Fatalf("luis debería poder rehacer su creación: %v %v", op, err)
+	}
+
+	// luis completa la tarea de ana: ana ya no puede deshacer su creación
+	if err := luis.Completar(1); err != nil {
+		t.Fatalf("Error al completar: %v", err)
+	}
+	if op, err := ana.Deshacer(); err != nil || op.TareaID != 3 {
+		t.Fatalf("ana debería deshacer su última creación: %v %v", op, err)
+	}
+	var conflicto *ErrorConflictoHistorial
+	if _, err := ana.Deshacer(); !errors.As(err, &conflicto) || conflicto.ID != 1 {
+		t.Fatalf("Se esperaba un conflicto con la tarea 1: %v", err)
+	}
+	if tarea, err := ana.BuscarPorID(1); err != nil || !tarea.Completada {
+		t.Errorf("El conflicto no debería modificar la tarea: %+v %v", tarea, err)
+	}
+
+	// Deshecho el cambio de luis, ana puede deshacer el suyo
+	if _, err := luis.Deshacer(); err != nil {
+		t.Fatalf("luis debería deshacer la completación: %v", err)
+	}
+	if _, err := ana.Deshacer(); err != nil {
+		t.Fatalf("Tras deshacer el cambio de luis, ana debería poder deshacer: %v", err)
+	}
+	if _, err := ana.BuscarPorID(1); err == nil {
+		t.Error("La tarea 1 debería haberse eliminado al deshacer su creación")
+	}
+}
+
+// TestPermisosDeListas verifica que renombrar o eliminar una lista exija
+// poder modificar todas sus tareas, sin cambiar ninguna si falta alguna
+func TestPermisosDeListas(t *testing.T) {
+	ana := nuevoGestorEnMemoria(t, ConUsuario("ana"))
+	luis := ana.ComoUsuario("luis")
+
+	ana.CrearLista("casa")
+	ana.CrearConDatos(DatosTarea{Titulo: "Pintar", Lista: "casa", Asignados: []string{"luis"}})
+	ana.CrearConDatos(DatosTarea{Titulo: "Regar", Lista: "casa"})
+
+	var permiso *ErrorPermiso
+	if err := luis.RenombrarLista("casa", "hogar"); !errors.As(err, &permiso) || permiso.ID != 2 {
+		t.Errorf("luis no debería poder renombrar una lista con tareas ajenas: %v", err)
+	}
+	if _, err := luis.EliminarLista("casa"); !errors.As(err, &permiso) {
+		t.Errorf("luis no debería poder eliminar una lista con tareas ajenas: %v", err)
+	}
+	for _, tarea := range ana.Listar() {
+		if tarea.Lista != "casa" {
+			t.Errorf("La tarea %d no debería haber cambiado de lista: %q", tarea.ID, tarea.Lista)
+		}
+	}
+
+	if err := ana.RenombrarLista("casa", "hogar"); err != nil {
+		t.Errorf("ana debería poder renombrar la lista de sus tareas: %v", err)
+	}
+	if _, err := luis.EliminarLista("hogar"); !errors.As(err, &permiso) {
+		t.Errorf("Tras renombrarla, luis sigue sin poder eliminarla: %v", err)
+	}
+}
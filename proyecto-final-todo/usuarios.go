@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cristianjonhson/GO-API/proyecto-final-todo/consola"
+	"github.com/cristianjonhson/GO-API/proyecto-final-todo/tareas"
+)
+
+// separarUsuarios interpreta una lista de usuarios separados por comas,
+// donde los precedidos de '-' son los que hay que quitar:
+// "luis, -marta" asigna a luis y desasigna a marta.
+func separarUsuarios(texto string) (asignar, quitar []string) {
+	for _, usuario := range strings.Split(texto, ",") {
+		usuario = strings.TrimSpace(usuario)
+		switch {
+		case usuario == "":
+		case strings.HasPrefix(usuario, "-"):
+			quitar = append(quitar, strings.TrimPrefix(usuario, "-"))
+		default:
+			asignar = append(asignar, usuario)
+		}
+	}
+	return asignar, quitar
+}
+
+// gestionarAsignados pregunta por una tarea y los usuarios a asignarle o
+// quitarle, y muestra cómo quedan sus asignados. Solo el propietario de la
+// tarea puede cambiarlos.
+func gestionarAsignados(entrada *consola.Entrada, gestor *tareas.GestorTareas) {
+	id := entrada.LeerEntero("👥 ID de la tarea: ", 0)
+	tarea, err := gestor.BuscarPorID(id)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		return
+	}
+	if len(tarea.Asignados) > 0 {
+		fmt.Printf("👥 Asignada a: %s\n", strings.Join(tarea.Asignados, ", "))
+	}
+
+	asignar, quitar := separarUsuarios(entrada.LeerLinea("👥 Usuarios separados por comas (-usuario para quitarlo): "))
+	if len(asignar) == 0 && len(quitar) == 0 {
+		fmt.Println("❌ Cancelado")
+		return
+	}
+	if len(asignar) > 0 {
+		if tarea, err = gestor.Asignar(id, asignar...); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			return
+		}
+	}
+	if len(quitar) > 0 {
+		if tarea, err = gestor.Desasignar(id, quitar...); err != nil {
+			fmt.Printf("❌ Error: %v\n", err)
+			return
+		}
+	}
+
+	asignados := "nadie"
+	if len(tarea.Asignados) > 0 {
+		asignados = strings.Join(tarea.Asignados, ", ")
+	}
+	fmt.Printf("✅ Tarea %d asignada a: %s\n", id, asignados)
+}